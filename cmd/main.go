@@ -2,17 +2,114 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
 
+	databaseInfra "Aicon-assignment/internal/infrastructure/database"
+	"Aicon-assignment/internal/infrastructure/migration"
 	"Aicon-assignment/internal/infrastructure/server"
 )
 
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up          未適用のマイグレーションをすべて適用する
+  down [N]    適用済みのマイグレーションを新しい順に N 件（デフォルト 1）取り消す
+  status      マイグレーションの適用状況を表示する
+  seed        サンプルデータを投入する`
+
 func main() {
 	ctx := context.Background()
 
-	server := server.NewServer()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// --demo を指定するとデータベースを使わずメモリ上のサンプルデータで起動する
+	demo := flag.Bool("demo", false, "run with in-memory sample data instead of a database")
+	flag.Parse()
+
+	var opts []server.Option
+	if *demo {
+		opts = append(opts, server.WithDemoMode())
+	}
+	server := server.NewServer(opts...)
 
 	if err := server.Run(ctx); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// migrate サブコマンド
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", migrateUsage)
+	}
+
+	dbHandler := databaseInfra.NewSqlHandler()
+	defer dbHandler.Close()
+	migrator, err := migration.NewMigrator(dbHandler.DB(), dbHandler.Driver())
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("✅ Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("↩️  Reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied at " + st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if st.Modified {
+				state += " (modified after it was applied)"
+			}
+			fmt.Printf("%04d_%-30s %s\n", st.Version, st.Name, state)
+		}
+		return nil
+
+	case "seed":
+		if err := migrator.Seed(ctx); err != nil {
+			return err
+		}
+		fmt.Println("✅ Seeded sample data")
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command: %s\n%s", args[0], migrateUsage)
+	}
+}
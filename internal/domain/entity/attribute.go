@@ -0,0 +1,327 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AttributeType はカテゴリー別の属性の値の型
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeInteger AttributeType = "integer"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+	AttributeTypeEnum    AttributeType = "enum" // Options のいずれかの文字列
+)
+
+// AttributeTypes は指定できる属性の型
+var AttributeTypes = []string{
+	string(AttributeTypeString),
+	string(AttributeTypeInteger),
+	string(AttributeTypeNumber),
+	string(AttributeTypeBoolean),
+	string(AttributeTypeEnum),
+}
+
+// AttributeDefinition はカテゴリーに属するアイテムが持てる属性（リファレンス番号、ムーブメントなど）の定義
+type AttributeDefinition struct {
+	Key      string        `json:"key"` // アイテムの attributes のキー
+	Type     AttributeType `json:"type"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options,omitempty"` // enum の選択肢
+	Unit     string        `json:"unit,omitempty"`    // 値の単位（mm、ct など）。表示用で、値の検証には使わない
+}
+
+// 属性のキー・選択肢・単位・文字列の値の最大文字数（データベースのカラムに合わせる）
+const (
+	MaxAttributeKeyLength    = 50
+	MaxAttributeOptionLength = 100
+	MaxAttributeUnitLength   = 20
+	MaxAttributeValueLength  = 200
+)
+
+// 属性のキーは英小文字で始まる英小文字・数字・アンダースコア
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Normalize は文字列を NormalizeText で正規化する。選択肢は enum の場合のみ残し、空のものと重複を取り除く
+func (d *AttributeDefinition) Normalize() {
+	d.Key = NormalizeText(d.Key)
+	d.Type = AttributeType(NormalizeText(string(d.Type)))
+	d.Unit = NormalizeText(d.Unit)
+
+	if d.Type != AttributeTypeEnum {
+		d.Options = nil
+		return
+	}
+	options := make([]string, 0, len(d.Options))
+	for _, option := range d.Options {
+		option = NormalizeText(option)
+		if option == "" || slices.Contains(options, option) {
+			continue
+		}
+		options = append(options, option)
+	}
+	d.Options = options
+}
+
+// validateAttributeDefinitions は属性の定義を検証する。フィールド名は "attributes[0].key" の形式
+func validateAttributeDefinitions(definitions []AttributeDefinition) ValidationErrors {
+	var errs ValidationErrors
+	seen := map[string]bool{}
+
+	for i, d := range definitions {
+		field := func(name string) string { return fmt.Sprintf("attributes[%d].%s", i, name) }
+
+		switch {
+		case d.Key == "":
+			errs = append(errs, RequiredError(field("key")))
+		case len(d.Key) > MaxAttributeKeyLength:
+			errs = append(errs, TooLongError(field("key"), MaxAttributeKeyLength))
+		case !attributeKeyPattern.MatchString(d.Key):
+			errs = append(errs, InvalidFormatError(field("key"), "a-z, 0-9, _"))
+		case seen[d.Key]:
+			errs = append(errs, DuplicateError(field("key")))
+		}
+		seen[d.Key] = true
+
+		if !slices.Contains(AttributeTypes, string(d.Type)) {
+			errs = append(errs, InvalidEnumError(field("type"), AttributeTypes))
+		}
+		if d.Type == AttributeTypeEnum && len(d.Options) == 0 {
+			errs = append(errs, RequiredError(field("options")))
+		}
+		for j, option := range d.Options {
+			if CharLength(option) > MaxAttributeOptionLength {
+				errs = append(errs, TooLongError(fmt.Sprintf("attributes[%d].options[%d]", i, j), MaxAttributeOptionLength))
+			}
+		}
+		if CharLength(d.Unit) > MaxAttributeUnitLength {
+			errs = append(errs, TooLongError(field("unit"), MaxAttributeUnitLength))
+		}
+	}
+
+	return errs
+}
+
+// Attributes はアイテムの属性の値。値は JSON と同じく string・float64・bool のいずれか
+type Attributes map[string]interface{}
+
+// MarshalJSON は属性が無い場合も null ではなく {} を返す
+func (a Attributes) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}(a))
+}
+
+// Keys は属性のキーを昇順で返す
+func (a Attributes) Keys() []string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Clone は a の複製を返す。属性が無い場合は nil
+func (a Attributes) Clone() Attributes {
+	if len(a) == 0 {
+		return nil
+	}
+	c := make(Attributes, len(a))
+	for key, value := range a {
+		c[key] = value
+	}
+	return c
+}
+
+// normalize は文字列を NormalizeText で正規化し、整数を float64 にそろえ、null と空文字列の値を取り除く
+func (a Attributes) normalize() Attributes {
+	if len(a) == 0 {
+		return nil
+	}
+	normalized := make(Attributes, len(a))
+	for key, value := range a {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			if v = NormalizeText(v); v == "" {
+				continue
+			}
+			normalized[key] = v
+		case int:
+			normalized[key] = float64(v)
+		case int64:
+			normalized[key] = float64(v)
+		default:
+			normalized[key] = value
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+// validateAttributes は属性の値を schema の定義で検証する。フィールド名は "attributes.movement" の形式
+func validateAttributes(schema []AttributeDefinition, attributes Attributes) ValidationErrors {
+	var errs ValidationErrors
+	defined := make(map[string]bool, len(schema))
+
+	for _, d := range schema {
+		defined[d.Key] = true
+		field := "attributes." + d.Key
+
+		value, ok := attributes[d.Key]
+		if !ok {
+			if d.Required {
+				errs = append(errs, RequiredError(field))
+			}
+			continue
+		}
+
+		switch d.Type {
+		case AttributeTypeString:
+			s, ok := value.(string)
+			if !ok {
+				errs = append(errs, InvalidTypeError(field, d.Type))
+			} else if CharLength(s) > MaxAttributeValueLength {
+				errs = append(errs, TooLongError(field, MaxAttributeValueLength))
+			}
+		case AttributeTypeEnum:
+			s, ok := value.(string)
+			if !ok {
+				errs = append(errs, InvalidTypeError(field, d.Type))
+			} else if !slices.Contains(d.Options, s) {
+				errs = append(errs, InvalidEnumError(field, d.Options))
+			}
+		case AttributeTypeInteger:
+			if f, ok := value.(float64); !ok || f != math.Trunc(f) || math.IsInf(f, 0) {
+				errs = append(errs, InvalidTypeError(field, d.Type))
+			}
+		case AttributeTypeNumber:
+			if f, ok := value.(float64); !ok || math.IsInf(f, 0) || math.IsNaN(f) {
+				errs = append(errs, InvalidTypeError(field, d.Type))
+			}
+		case AttributeTypeBoolean:
+			if _, ok := value.(bool); !ok {
+				errs = append(errs, InvalidTypeError(field, d.Type))
+			}
+		}
+	}
+
+	for _, key := range attributes.Keys() {
+		if !defined[key] {
+			errs = append(errs, UnknownFieldError("attributes."+key))
+		}
+	}
+
+	return errs
+}
+
+// ConformTo は a のうち schema で定義されていて値が定義に合う属性だけを残した複製と、取り除いた属性のキー（昇順）を返す。
+// 必須の属性が無いことは検証しない
+func (a Attributes) ConformTo(schema []AttributeDefinition) (Attributes, []string) {
+	kept := make(Attributes, len(a))
+	removed := []string{}
+	for _, key := range a.Keys() {
+		i := slices.IndexFunc(schema, func(d AttributeDefinition) bool { return d.Key == key })
+		if i < 0 || len(validateAttributes(schema[i:i+1], Attributes{key: a[key]})) > 0 {
+			removed = append(removed, key)
+			continue
+		}
+		kept[key] = a[key]
+	}
+	return kept.Clone(), removed
+}
+
+// AttributeValueString は属性の値を絞り込みで比較する文字列にする（36.0 は "36"、true は "true"）
+func AttributeValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ParseAttributeValue は絞り込みの値 raw を t の型で解釈し、AttributeValueString と同じ形式にする
+func ParseAttributeValue(t AttributeType, raw string) (string, error) {
+	raw = NormalizeText(raw)
+	switch t {
+	case AttributeTypeInteger, AttributeTypeNumber:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil || (t == AttributeTypeInteger && f != math.Trunc(f)) {
+			return "", fmt.Errorf("must be %s", t)
+		}
+		return AttributeValueString(f), nil
+	case AttributeTypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", fmt.Errorf("must be %s", t)
+		}
+		return AttributeValueString(b), nil
+	default:
+		return raw, nil
+	}
+}
+
+// ParseAttributes は文字列で受け取った属性の値（CSV の列など）を category の属性の定義（categories で引く）の型に変換する。
+// 空の値は取り除き、定義に無いキーは文字列のまま返す（Validate で unknown_field になる）。
+// 型に合わない値は取り除いて invalid_type のエラーにする
+func ParseAttributes(category string, raw map[string]string, categories CategoryLookup) (Attributes, ValidationErrors) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	types := make(map[string]AttributeType)
+	for _, d := range categoriesOrDefault(categories).AttributeSchema(NormalizeText(category)) {
+		types[d.Key] = d.Type
+	}
+
+	attributes := make(Attributes, len(raw))
+	var errs ValidationErrors
+	for key, value := range raw {
+		key, value = NormalizeText(key), NormalizeText(value)
+		if value == "" {
+			continue
+		}
+		t, ok := types[key]
+		if !ok {
+			attributes[key] = value
+			continue
+		}
+		parsed, err := ParseAttributeValue(t, value)
+		if err != nil {
+			errs = append(errs, InvalidTypeError("attributes."+key, t))
+			continue
+		}
+		switch t {
+		case AttributeTypeInteger, AttributeTypeNumber:
+			attributes[key], _ = strconv.ParseFloat(parsed, 64)
+		case AttributeTypeBoolean:
+			attributes[key] = parsed == "true"
+		default:
+			attributes[key] = parsed
+		}
+	}
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+	slices.SortFunc(errs, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+	return attributes, errs
+}
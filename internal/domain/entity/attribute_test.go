@@ -0,0 +1,324 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 時計の属性を定義したカテゴリーの参照先
+type stubAttributeLookup map[string][]AttributeDefinition
+
+func (s stubAttributeLookup) IsValidCategory(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+func (s stubAttributeLookup) CategoryNames() []string {
+	return []string{"時計", "バッグ"}
+}
+
+func (s stubAttributeLookup) AttributeSchema(name string) []AttributeDefinition {
+	return s[name]
+}
+
+func testAttributeSchema() []AttributeDefinition {
+	return []AttributeDefinition{
+		{Key: "reference_number", Type: AttributeTypeString, Required: true},
+		{Key: "movement", Type: AttributeTypeEnum, Options: []string{"自動巻き", "手巻き", "クォーツ"}},
+		{Key: "case_size", Type: AttributeTypeNumber, Unit: "mm"},
+		{Key: "year", Type: AttributeTypeInteger},
+		{Key: "box", Type: AttributeTypeBoolean},
+	}
+}
+
+func TestNewCategory_Attributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes []AttributeDefinition
+		expected   ValidationErrors
+	}{
+		{
+			name:       "正常系: 属性の定義",
+			attributes: testAttributeSchema(),
+		},
+		{
+			name: "異常系: キーが空・形式が不正・重複",
+			attributes: []AttributeDefinition{
+				{Key: "", Type: AttributeTypeString},
+				{Key: "Case-Size", Type: AttributeTypeNumber},
+				{Key: "size", Type: AttributeTypeNumber},
+				{Key: "size", Type: AttributeTypeString},
+			},
+			expected: ValidationErrors{
+				RequiredError("attributes[0].key"),
+				InvalidFormatError("attributes[1].key", "a-z, 0-9, _"),
+				DuplicateError("attributes[3].key"),
+			},
+		},
+		{
+			name: "異常系: 型が不正・enum の選択肢が無い",
+			attributes: []AttributeDefinition{
+				{Key: "color", Type: "color"},
+				{Key: "metal", Type: AttributeTypeEnum, Options: []string{" ", ""}},
+			},
+			expected: ValidationErrors{
+				InvalidEnumError("attributes[0].type", AttributeTypes),
+				RequiredError("attributes[1].options"),
+			},
+		},
+		{
+			name: "異常系: 単位が長すぎる",
+			attributes: []AttributeDefinition{
+				{Key: "size", Type: AttributeTypeNumber, Unit: strings.Repeat("m", MaxAttributeUnitLength+1)},
+			},
+			expected: ValidationErrors{TooLongError("attributes[0].unit", MaxAttributeUnitLength)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, err := NewCategory("時計", "時計", "Watches", nil, tt.attributes, 10, true)
+
+			if tt.expected == nil {
+				require.NoError(t, err)
+				assert.Len(t, category.Attributes, len(tt.attributes))
+				return
+			}
+			var validationErrs ValidationErrors
+			require.True(t, errors.As(err, &validationErrs))
+			assert.Equal(t, tt.expected, validationErrs)
+		})
+	}
+}
+
+func TestAttributeDefinition_Normalize(t *testing.T) {
+	d := AttributeDefinition{Key: " movement ", Type: "enum", Options: []string{"自動巻き", " 自動巻き", "", "ｸｫｰﾂ"}, Unit: " "}
+	d.Normalize()
+	assert.Equal(t, AttributeDefinition{Key: "movement", Type: AttributeTypeEnum, Options: []string{"自動巻き", "クォーツ"}}, d)
+
+	// enum 以外の選択肢は使わないため取り除く
+	d = AttributeDefinition{Key: "size", Type: AttributeTypeNumber, Options: []string{"36"}}
+	d.Normalize()
+	assert.Nil(t, d.Options)
+}
+
+func TestNewItem_Attributes(t *testing.T) {
+	categories := stubAttributeLookup{"時計": testAttributeSchema(), "バッグ": nil}
+
+	tests := []struct {
+		name       string
+		category   string
+		attributes Attributes
+		expected   Attributes
+		errs       ValidationErrors
+	}{
+		{
+			name:     "正常系: 定義どおりの属性",
+			category: "時計",
+			attributes: Attributes{
+				"reference_number": "116500LN", "movement": "自動巻き", "case_size": 40.0, "year": 2020, "box": true,
+			},
+			expected: Attributes{
+				"reference_number": "116500LN", "movement": "自動巻き", "case_size": 40.0, "year": 2020.0, "box": true,
+			},
+		},
+		{
+			name:       "正常系: null と空文字列の値は取り除く",
+			category:   "時計",
+			attributes: Attributes{"reference_number": " 116500LN ", "movement": "", "case_size": nil},
+			expected:   Attributes{"reference_number": "116500LN"},
+		},
+		{
+			name:       "異常系: 必須の属性が無い",
+			category:   "時計",
+			attributes: Attributes{"movement": "自動巻き"},
+			errs:       ValidationErrors{RequiredError("attributes.reference_number")},
+		},
+		{
+			name:     "異常系: 型と選択肢が合わない",
+			category: "時計",
+			attributes: Attributes{
+				"reference_number": 116500.0, "movement": "ソーラー", "case_size": "40mm", "year": 2020.5, "box": "yes",
+			},
+			errs: ValidationErrors{
+				InvalidTypeError("attributes.reference_number", AttributeTypeString),
+				InvalidEnumError("attributes.movement", []string{"自動巻き", "手巻き", "クォーツ"}),
+				InvalidTypeError("attributes.case_size", AttributeTypeNumber),
+				InvalidTypeError("attributes.year", AttributeTypeInteger),
+				InvalidTypeError("attributes.box", AttributeTypeBoolean),
+			},
+		},
+		{
+			name:       "異常系: 定義されていない属性",
+			category:   "バッグ",
+			attributes: Attributes{"size": 30.0, "color": "黒"},
+			errs: ValidationErrors{
+				UnknownFieldError("attributes.color"),
+				UnknownFieldError("attributes.size"),
+			},
+		},
+		{
+			name:       "異常系: カテゴリーが不正な場合は属性を検証しない",
+			category:   "家電",
+			attributes: Attributes{"color": "黒"},
+			errs:       ValidationErrors{InvalidEnumError("category", []string{"時計", "バッグ"})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := NewItem("ロレックス サブマリーナ", tt.category, "ROLEX", 1000000, "2023-01-15", tt.attributes, "", categories)
+
+			if tt.errs != nil {
+				var validationErrs ValidationErrors
+				require.True(t, errors.As(err, &validationErrs))
+				assert.Equal(t, tt.errs, validationErrs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, item.Attributes)
+		})
+	}
+}
+
+func TestAttributes_MarshalJSON(t *testing.T) {
+	var empty Attributes
+	data, err := empty.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+
+	data, err = Attributes{"case_size": 40.5, "box": true}.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"case_size": 40.5, "box": true}`, string(data))
+}
+
+func TestAttributes_ConformTo(t *testing.T) {
+	tests := []struct {
+		name            string
+		attributes      Attributes
+		schema          []AttributeDefinition
+		expected        Attributes
+		expectedRemoved []string
+	}{
+		{
+			name:            "正常系: 定義されていて値が合う属性だけを残す",
+			attributes:      Attributes{"reference_number": "116500LN", "movement": "自動巻き", "material": "gold"},
+			schema:          testAttributeSchema(),
+			expected:        Attributes{"reference_number": "116500LN", "movement": "自動巻き"},
+			expectedRemoved: []string{"material"},
+		},
+		{
+			name:            "正常系: 同じキーでも値が定義に合わなければ取り除く",
+			attributes:      Attributes{"movement": "ソーラー", "year": 2020.5, "box": true},
+			schema:          testAttributeSchema(),
+			expected:        Attributes{"box": true},
+			expectedRemoved: []string{"movement", "year"},
+		},
+		{
+			name:            "正常系: 属性を定義していないカテゴリーではすべて取り除く",
+			attributes:      Attributes{"movement": "自動巻き"},
+			expectedRemoved: []string{"movement"},
+		},
+		{
+			name:            "正常系: 属性が無い",
+			schema:          testAttributeSchema(),
+			expectedRemoved: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conformed, removed := tt.attributes.ConformTo(tt.schema)
+
+			assert.Equal(t, tt.expected, conformed)
+			assert.Equal(t, tt.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestParseAttributeValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		attrType  AttributeType
+		raw       string
+		expected  string
+		expectErr bool
+	}{
+		{name: "正常系: 文字列は正規化のみ", attrType: AttributeTypeString, raw: " 116500LN ", expected: "116500LN"},
+		{name: "正常系: 数値は AttributeValueString と同じ形式", attrType: AttributeTypeNumber, raw: "40.0", expected: "40"},
+		{name: "正常系: 小数", attrType: AttributeTypeNumber, raw: "0.50", expected: "0.5"},
+		{name: "正常系: 整数", attrType: AttributeTypeInteger, raw: "2020", expected: "2020"},
+		{name: "正常系: 真偽値", attrType: AttributeTypeBoolean, raw: "1", expected: "true"},
+		{name: "異常系: 数値でない", attrType: AttributeTypeNumber, raw: "40mm", expectErr: true},
+		{name: "異常系: 整数でない", attrType: AttributeTypeInteger, raw: "2020.5", expectErr: true},
+		{name: "異常系: 真偽値でない", attrType: AttributeTypeBoolean, raw: "yes", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := ParseAttributeValue(tt.attrType, tt.raw)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestParseAttributes(t *testing.T) {
+	categories := stubAttributeLookup{"時計": testAttributeSchema(), "バッグ": nil}
+
+	tests := []struct {
+		name     string
+		category string
+		raw      map[string]string
+		expected Attributes
+		errs     ValidationErrors
+	}{
+		{
+			name:     "正常系: 定義の型に合わせて変換する",
+			category: "時計",
+			raw:      map[string]string{"reference_number": "116500LN", "movement": "自動巻き", "case_size": "４０", "year": "2020", "box": "true"},
+			expected: Attributes{"reference_number": "116500LN", "movement": "自動巻き", "case_size": 40.0, "year": 2020.0, "box": true},
+		},
+		{
+			name:     "正常系: 空の値は取り除き、定義されていない属性は文字列のまま返す",
+			category: "バッグ",
+			raw:      map[string]string{"size": "30", "color": " "},
+			expected: Attributes{"size": "30"},
+		},
+		{
+			name:     "正常系: 空の場合は nil",
+			category: "時計",
+			raw:      map[string]string{"movement": ""},
+		},
+		{
+			name:     "異常系: 定義の型に合わない",
+			category: "時計",
+			raw:      map[string]string{"year": "2020.5", "case_size": "40mm", "box": "yes"},
+			errs: ValidationErrors{
+				InvalidTypeError("attributes.box", AttributeTypeBoolean),
+				InvalidTypeError("attributes.case_size", AttributeTypeNumber),
+				InvalidTypeError("attributes.year", AttributeTypeInteger),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, errs := ParseAttributes(tt.category, tt.raw, categories)
+
+			assert.Equal(t, tt.errs, errs)
+			if tt.errs == nil {
+				assert.Equal(t, tt.expected, attributes)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Brand はブランドカタログの1件。
+// 正式名のほか、別の表記（カタカナ、略称など）を別名として持ち、どれで入力されても同じブランドとして扱う
+type Brand struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	NameCanonical string    `json:"name_canonical"` // Name の CanonicalBrand。アイテムの brand_canonical にもこの値を使う
+	Country       string    `json:"country"`        // 原産国（ISO 3166-1 alpha-2）。不明な場合は空
+	Aliases       []string  `json:"aliases"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ブランド名と別名の最大文字数（データベースの VARCHAR(100) に合わせる）
+const MaxBrandAliasLength = 100
+
+func NewBrand(name, country string, aliases []string) (*Brand, error) {
+	brand := &Brand{
+		Name:      name,
+		Country:   country,
+		Aliases:   aliases,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	brand.Normalize()
+
+	if err := brand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return brand, nil
+}
+
+// ブランドのアップデート
+func (b *Brand) Update(name, country string, aliases []string) error {
+	b.Name = name
+	b.Country = country
+	b.Aliases = aliases
+	b.UpdatedAt = time.Now()
+	b.Normalize()
+
+	return b.Validate()
+}
+
+// Normalize は名前と別名を NormalizeText で正規化し、NameCanonical を求め直す。
+// 国コードは大文字にそろえ、別名は空のもの、正規形が名前や他の別名と同じものを取り除く
+func (b *Brand) Normalize() {
+	b.Name = NormalizeText(b.Name)
+	b.NameCanonical = CanonicalBrand(b.Name)
+	b.Country = strings.ToUpper(NormalizeText(b.Country))
+
+	seen := map[string]bool{b.NameCanonical: true}
+	aliases := make([]string, 0, len(b.Aliases))
+	for _, alias := range b.Aliases {
+		alias = NormalizeText(alias)
+		key := CanonicalBrand(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+	b.Aliases = aliases
+}
+
+// Keys は名前と別名の正規形を返す。カタログの照合にはこの値を使う
+func (b *Brand) Keys() []string {
+	keys := make([]string, 0, len(b.Aliases)+1)
+	keys = append(keys, b.NameCanonical)
+	for _, alias := range b.Aliases {
+		keys = append(keys, CanonicalBrand(alias))
+	}
+	return keys
+}
+
+// ブランドフィールドのバリデーション。問題があれば ValidationErrors を返す
+func (b *Brand) Validate() error {
+	var errs ValidationErrors
+
+	if b.Name == "" {
+		errs = append(errs, RequiredError("name"))
+	} else if CharLength(b.Name) > MaxBrandLength {
+		errs = append(errs, TooLongError("name", MaxBrandLength))
+	}
+
+	if b.Country != "" && !isValidCountryCode(b.Country) {
+		errs = append(errs, InvalidFormatError("country", "ISO 3166-1 alpha-2"))
+	}
+
+	for i, alias := range b.Aliases {
+		if CharLength(alias) > MaxBrandAliasLength {
+			errs = append(errs, TooLongError(fmt.Sprintf("aliases[%d]", i), MaxBrandAliasLength))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// 国コードのバリデーション（英大文字2文字）
+func isValidCountryCode(country string) bool {
+	if len(country) != 2 {
+		return false
+	}
+	for i := 0; i < len(country); i++ {
+		if country[i] < 'A' || country[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
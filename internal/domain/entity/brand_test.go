@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBrand(t *testing.T) {
+	tests := []struct {
+		name              string
+		brandName         string
+		country           string
+		aliases           []string
+		wantErr           bool
+		expectedErrors    ValidationErrors
+		expectedName      string
+		expectedCanonical string
+		expectedCountry   string
+		expectedAliases   []string
+	}{
+		{
+			name:              "正常系: 有効なブランド作成",
+			brandName:         "ROLEX",
+			country:           "CH",
+			aliases:           []string{"ロレックス"},
+			expectedName:      "ROLEX",
+			expectedCanonical: "ROLEX",
+			expectedCountry:   "CH",
+			expectedAliases:   []string{"ロレックス"},
+		},
+		{
+			name:              "正常系: 名前・国コード・別名を正規化する",
+			brandName:         "  Ｈｅｒｍèｓ ",
+			country:           "fr",
+			aliases:           []string{" ｴﾙﾒｽ ", "", "HERMES", "エルメス", "hermès paris"},
+			expectedName:      "Hermès",
+			expectedCanonical: "HERMES",
+			expectedCountry:   "FR",
+			expectedAliases:   []string{"エルメス", "hermès paris"},
+		},
+		{
+			name:              "正常系: 原産国は省略できる",
+			brandName:         "Apple",
+			expectedName:      "Apple",
+			expectedCanonical: "APPLE",
+			expectedAliases:   []string{},
+		},
+		{
+			name:           "異常系: 名前が空",
+			brandName:      " ",
+			wantErr:        true,
+			expectedErrors: ValidationErrors{RequiredError("name")},
+		},
+		{
+			name:           "異常系: 国コードの形式が不正",
+			brandName:      "ROLEX",
+			country:        "CHE",
+			wantErr:        true,
+			expectedErrors: ValidationErrors{InvalidFormatError("country", "ISO 3166-1 alpha-2")},
+		},
+		{
+			name:      "異常系: 名前と別名が100文字超過",
+			brandName: strings.Repeat("ロ", 101),
+			aliases:   []string{"ロレックス", strings.Repeat("レ", 101)},
+			wantErr:   true,
+			expectedErrors: ValidationErrors{
+				TooLongError("name", 100),
+				TooLongError("aliases[1]", 100),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brand, err := NewBrand(tt.brandName, tt.country, tt.aliases)
+
+			if tt.wantErr {
+				assert.Nil(t, brand)
+				var validationErrs ValidationErrors
+				require.True(t, errors.As(err, &validationErrs))
+				assert.Equal(t, tt.expectedErrors, validationErrs)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, brand.Name)
+			assert.Equal(t, tt.expectedCanonical, brand.NameCanonical)
+			assert.Equal(t, tt.expectedCountry, brand.Country)
+			assert.Equal(t, tt.expectedAliases, brand.Aliases)
+		})
+	}
+}
+
+func TestBrand_Keys(t *testing.T) {
+	brand, err := NewBrand("Christian Louboutin", "FR", []string{"ルブタン", "Louboutin"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"CHRISTIAN LOUBOUTIN", "ルブタン", "LOUBOUTIN"}, brand.Keys())
+}
@@ -0,0 +1,297 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Category はアイテムに指定できるカテゴリーの1件。
+// アイテムの category には Name の値を保存し、表示名は言語ごとに持つ。
+// カテゴリーは ParentID で木構造になり、アイテムは子を持たないカテゴリー（葉）にのみ属する。
+// Attributes はこのカテゴリーと子孫のカテゴリーのアイテムが持てる属性の定義
+type Category struct {
+	ID            int64                 `json:"id"`
+	Name          string                `json:"name"`
+	ParentID      *int64                `json:"parent_id"` // 親カテゴリーの ID。最上位の場合は nil
+	DisplayNameJa string                `json:"display_name_ja"`
+	DisplayNameEn string                `json:"display_name_en"`
+	Attributes    []AttributeDefinition `json:"attributes"`
+	SortOrder     int                   `json:"sort_order"` // 一覧・集計での表示順（小さい順）
+	Active        bool                  `json:"active"`     // false の場合は新しいアイテムに指定できない（既存のアイテムはそのまま）
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// カテゴリー名（データベースの VARCHAR(50) に合わせる）と表示名の最大文字数
+const (
+	MaxCategoryNameLength        = 50
+	MaxCategoryDisplayNameLength = 100
+)
+
+func NewCategory(name, displayNameJa, displayNameEn string, parentID *int64, attributes []AttributeDefinition, sortOrder int, active bool) (*Category, error) {
+	category := &Category{
+		Name:          name,
+		ParentID:      parentID,
+		DisplayNameJa: displayNameJa,
+		DisplayNameEn: displayNameEn,
+		Attributes:    attributes,
+		SortOrder:     sortOrder,
+		Active:        active,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	category.Normalize()
+
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// カテゴリーのアップデート
+func (c *Category) Update(name, displayNameJa, displayNameEn string, parentID *int64, attributes []AttributeDefinition, sortOrder int, active bool) error {
+	c.Name = name
+	c.ParentID = parentID
+	c.DisplayNameJa = displayNameJa
+	c.DisplayNameEn = displayNameEn
+	c.Attributes = attributes
+	c.SortOrder = sortOrder
+	c.Active = active
+	c.UpdatedAt = time.Now()
+	c.Normalize()
+
+	return c.Validate()
+}
+
+// Normalize は文字列のフィールドと属性の定義を正規化する
+func (c *Category) Normalize() {
+	c.Name = NormalizeText(c.Name)
+	c.DisplayNameJa = NormalizeText(c.DisplayNameJa)
+	c.DisplayNameEn = NormalizeText(c.DisplayNameEn)
+
+	attributes := make([]AttributeDefinition, len(c.Attributes))
+	for i, attribute := range c.Attributes {
+		attribute.Normalize()
+		attributes[i] = attribute
+	}
+	c.Attributes = attributes
+}
+
+// カテゴリーフィールドのバリデーション。問題があれば ValidationErrors を返す
+func (c *Category) Validate() error {
+	var errs ValidationErrors
+
+	if c.Name == "" {
+		errs = append(errs, RequiredError("name"))
+	} else if CharLength(c.Name) > MaxCategoryNameLength {
+		errs = append(errs, TooLongError("name", MaxCategoryNameLength))
+	}
+
+	if c.DisplayNameJa == "" {
+		errs = append(errs, RequiredError("display_name_ja"))
+	} else if CharLength(c.DisplayNameJa) > MaxCategoryDisplayNameLength {
+		errs = append(errs, TooLongError("display_name_ja", MaxCategoryDisplayNameLength))
+	}
+
+	if c.DisplayNameEn == "" {
+		errs = append(errs, RequiredError("display_name_en"))
+	} else if CharLength(c.DisplayNameEn) > MaxCategoryDisplayNameLength {
+		errs = append(errs, TooLongError("display_name_en", MaxCategoryDisplayNameLength))
+	}
+
+	if c.ParentID != nil && *c.ParentID <= 0 {
+		errs = append(errs, OutOfRangeError("parent_id", 1))
+	}
+
+	errs = append(errs, validateAttributeDefinitions(c.Attributes)...)
+
+	if c.SortOrder < 0 {
+		errs = append(errs, OutOfRangeError("sort_order", 0))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CategoryTree はカテゴリーの親子関係。兄弟の順序は NewCategoryTree に渡した順（表示順）になる
+type CategoryTree struct {
+	categories []*Category
+	byID       map[int64]*Category
+	byName     map[string]*Category
+	children   map[int64][]*Category // 最上位のカテゴリーはキー 0
+}
+
+// NewCategoryTree は表示順に並んだ categories から木を組み立てる。
+// 親が見つからないカテゴリーは最上位として扱う
+func NewCategoryTree(categories []*Category) *CategoryTree {
+	t := &CategoryTree{
+		categories: categories,
+		byID:       make(map[int64]*Category, len(categories)),
+		byName:     make(map[string]*Category, len(categories)),
+		children:   make(map[int64][]*Category),
+	}
+	for _, category := range categories {
+		t.byID[category.ID] = category
+		t.byName[category.Name] = category
+	}
+	for _, category := range categories {
+		t.children[t.parentKey(category)] = append(t.children[t.parentKey(category)], category)
+	}
+	return t
+}
+
+func (t *CategoryTree) parentKey(category *Category) int64 {
+	if category.ParentID == nil {
+		return 0
+	}
+	if _, ok := t.byID[*category.ParentID]; !ok {
+		return 0
+	}
+	return *category.ParentID
+}
+
+// Roots は最上位のカテゴリーを表示順で返す
+func (t *CategoryTree) Roots() []*Category {
+	return t.children[0]
+}
+
+// Children は id の子カテゴリーを表示順で返す
+func (t *CategoryTree) Children(id int64) []*Category {
+	return t.children[id]
+}
+
+// FindByID は id のカテゴリーを返す
+func (t *CategoryTree) FindByID(id int64) (*Category, bool) {
+	category, ok := t.byID[id]
+	return category, ok
+}
+
+// FindByName は名前が name のカテゴリーを返す
+func (t *CategoryTree) FindByName(name string) (*Category, bool) {
+	category, ok := t.byName[name]
+	return category, ok
+}
+
+// IsLeaf は id のカテゴリーが子を持たないかどうかを返す
+func (t *CategoryTree) IsLeaf(id int64) bool {
+	return len(t.children[id]) == 0
+}
+
+// Descendants は id のカテゴリーの子孫（自身を含まない）を深さ優先・表示順で返す
+func (t *CategoryTree) Descendants(id int64) []*Category {
+	var descendants []*Category
+	for _, child := range t.children[id] {
+		descendants = append(descendants, child)
+		descendants = append(descendants, t.Descendants(child.ID)...)
+	}
+	return descendants
+}
+
+// IsDescendant は id のカテゴリーが ancestorID の子孫かどうかを返す
+func (t *CategoryTree) IsDescendant(id, ancestorID int64) bool {
+	for _, descendant := range t.Descendants(ancestorID) {
+		if descendant.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignable はアイテムを category に属させられるかどうかを返す。
+// 子を持たず、自身と祖先がすべて有効なカテゴリーのみ指定できる
+func (t *CategoryTree) IsAssignable(category *Category) bool {
+	if !t.IsLeaf(category.ID) {
+		return false
+	}
+	for c, depth := category, 0; c != nil && depth <= len(t.categories); depth++ {
+		if !c.Active {
+			return false
+		}
+		parentID := t.parentKey(c)
+		if parentID == 0 {
+			break
+		}
+		c = t.byID[parentID]
+	}
+	return true
+}
+
+// AttributeSchema は category に属するアイテムが持てる属性の定義を返す。
+// 祖先のカテゴリーの定義を最上位から順に引き継ぎ、同じキーは子孫の定義で置き換える
+func (t *CategoryTree) AttributeSchema(category *Category) []AttributeDefinition {
+	var chain []*Category
+	for c, depth := category, 0; c != nil && depth <= len(t.categories); depth++ {
+		chain = append(chain, c)
+		parentID := t.parentKey(c)
+		if parentID == 0 {
+			break
+		}
+		c = t.byID[parentID]
+	}
+
+	var schema []AttributeDefinition
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, d := range chain[i].Attributes {
+			if j := slices.IndexFunc(schema, func(s AttributeDefinition) bool { return s.Key == d.Key }); j >= 0 {
+				schema[j] = d
+			} else {
+				schema = append(schema, d)
+			}
+		}
+	}
+	return schema
+}
+
+// AssignableNames はアイテムに指定できるカテゴリーの名前を深さ優先・表示順で返す
+func (t *CategoryTree) AssignableNames() []string {
+	names := []string{}
+	var walk func(categories []*Category)
+	walk = func(categories []*Category) {
+		for _, category := range categories {
+			if t.IsAssignable(category) {
+				names = append(names, category.Name)
+			}
+			walk(t.children[category.ID])
+		}
+	}
+	walk(t.Roots())
+	return names
+}
+
+// CategoryLookup はアイテムに指定できるカテゴリーを返す。
+// カテゴリーテーブルを読み込んだキャッシュ（usecase.CategoryRegistry）を、アイテムのバリデーションの引数で渡す
+type CategoryLookup interface {
+	// IsValidCategory は name が有効なカテゴリーかどうかを返す
+	IsValidCategory(name string) bool
+	// CategoryNames は有効なカテゴリーの名前を表示順で返す
+	CategoryNames() []string
+	// AttributeSchema は name のカテゴリーに属するアイテムが持てる属性の定義を返す
+	AttributeSchema(name string) []AttributeDefinition
+}
+
+// defaultCategories は CategoryLookup が渡されなかった場合に使う、ValidCategories だけのカテゴリー。属性は定義しない
+type defaultCategories struct{}
+
+func (defaultCategories) IsValidCategory(name string) bool {
+	return slices.Contains(ValidCategories, name)
+}
+
+func (defaultCategories) CategoryNames() []string {
+	return ValidCategories
+}
+
+func (defaultCategories) AttributeSchema(string) []AttributeDefinition {
+	return nil
+}
+
+// categoriesOrDefault は categories が nil の場合に ValidCategories のカテゴリーを返す
+func categoriesOrDefault(categories CategoryLookup) CategoryLookup {
+	if categories == nil {
+		return defaultCategories{}
+	}
+	return categories
+}
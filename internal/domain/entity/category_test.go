@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		categoryName   string
+		displayNameJa  string
+		displayNameEn  string
+		parentID       *int64
+		sortOrder      int
+		wantErr        bool
+		expectedErrors ValidationErrors
+		expected       Category
+	}{
+		{
+			name:          "正常系: 有効なカテゴリー作成",
+			categoryName:  "アート",
+			displayNameJa: "アート",
+			displayNameEn: "Art",
+			sortOrder:     60,
+			expected:      Category{Name: "アート", DisplayNameJa: "アート", DisplayNameEn: "Art", SortOrder: 60, Active: true},
+		},
+		{
+			name:          "正常系: 文字列を正規化する",
+			categoryName:  " ｱｰﾄ ",
+			displayNameJa: "アート　作品",
+			displayNameEn: " Ａｒｔ ",
+			expected:      Category{Name: "アート", DisplayNameJa: "アート 作品", DisplayNameEn: "Art", Active: true},
+		},
+		{
+			name:    "異常系: 必須項目がすべて空",
+			wantErr: true,
+			expectedErrors: ValidationErrors{
+				RequiredError("name"),
+				RequiredError("display_name_ja"),
+				RequiredError("display_name_en"),
+			},
+		},
+		{
+			name:          "異常系: 長さと表示順の違反",
+			categoryName:  strings.Repeat("車", 51),
+			displayNameJa: "車",
+			displayNameEn: strings.Repeat("a", 101),
+			sortOrder:     -1,
+			wantErr:       true,
+			expectedErrors: ValidationErrors{
+				TooLongError("name", MaxCategoryNameLength),
+				TooLongError("display_name_en", MaxCategoryDisplayNameLength),
+				OutOfRangeError("sort_order", 0),
+			},
+		},
+		{
+			name:           "異常系: 親カテゴリーのIDが0",
+			categoryName:   "版画",
+			displayNameJa:  "版画",
+			displayNameEn:  "Prints",
+			parentID:       new(int64),
+			wantErr:        true,
+			expectedErrors: ValidationErrors{OutOfRangeError("parent_id", 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, err := NewCategory(tt.categoryName, tt.displayNameJa, tt.displayNameEn, tt.parentID, nil, tt.sortOrder, true)
+
+			if tt.wantErr {
+				assert.Nil(t, category)
+				var validationErrs ValidationErrors
+				require.True(t, errors.As(err, &validationErrs))
+				assert.Equal(t, tt.expectedErrors, validationErrs)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.Name, category.Name)
+			assert.Equal(t, tt.expected.DisplayNameJa, category.DisplayNameJa)
+			assert.Equal(t, tt.expected.DisplayNameEn, category.DisplayNameEn)
+			assert.Equal(t, tt.expected.SortOrder, category.SortOrder)
+			assert.Equal(t, tt.expected.Active, category.Active)
+		})
+	}
+}
+
+func TestCategoryTree(t *testing.T) {
+	parentOf := func(id int64) *int64 { return &id }
+	// 時計(1) ─ 腕時計(2) ─ 機械式(4)
+	//         └ 懐中時計(3)
+	// 車(5, 無効) ─ 旧車(6)
+	// 孤児(7, 親が存在しない)
+	tree := NewCategoryTree([]*Category{
+		{ID: 1, Name: "時計", Active: true},
+		{ID: 2, Name: "腕時計", ParentID: parentOf(1), Active: true},
+		{ID: 3, Name: "懐中時計", ParentID: parentOf(1), Active: true},
+		{ID: 4, Name: "機械式", ParentID: parentOf(2), Active: true},
+		{ID: 5, Name: "車", Active: false},
+		{ID: 6, Name: "旧車", ParentID: parentOf(5), Active: true},
+		{ID: 7, Name: "孤児", ParentID: parentOf(99), Active: true},
+	})
+
+	names := func(categories []*Category) []string {
+		result := []string{}
+		for _, category := range categories {
+			result = append(result, category.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"時計", "車", "孤児"}, names(tree.Roots()))
+	assert.Equal(t, []string{"腕時計", "懐中時計"}, names(tree.Children(1)))
+	assert.Equal(t, []string{"腕時計", "機械式", "懐中時計"}, names(tree.Descendants(1)))
+	assert.True(t, tree.IsDescendant(4, 1))
+	assert.False(t, tree.IsDescendant(1, 4))
+	assert.False(t, tree.IsLeaf(2))
+	assert.True(t, tree.IsLeaf(4))
+
+	watch, ok := tree.FindByName("時計")
+	require.True(t, ok)
+	assert.False(t, tree.IsAssignable(watch), "子を持つカテゴリーには指定できない")
+	oldCar, ok := tree.FindByName("旧車")
+	require.True(t, ok)
+	assert.False(t, tree.IsAssignable(oldCar), "祖先が無効なカテゴリーには指定できない")
+
+	assert.Equal(t, []string{"機械式", "懐中時計", "孤児"}, tree.AssignableNames())
+}
+
+func TestCategoryTree_AttributeSchema(t *testing.T) {
+	parentOf := func(id int64) *int64 { return &id }
+	// 時計(1) ─ 腕時計(2) ─ 機械式(3)
+	tree := NewCategoryTree([]*Category{
+		{ID: 1, Name: "時計", Active: true, Attributes: []AttributeDefinition{
+			{Key: "reference_number", Type: AttributeTypeString},
+			{Key: "case_size", Type: AttributeTypeNumber, Unit: "mm"},
+		}},
+		{ID: 2, Name: "腕時計", ParentID: parentOf(1), Active: true, Attributes: []AttributeDefinition{
+			{Key: "band", Type: AttributeTypeString},
+		}},
+		{ID: 3, Name: "機械式", ParentID: parentOf(2), Active: true, Attributes: []AttributeDefinition{
+			{Key: "case_size", Type: AttributeTypeInteger, Required: true},
+		}},
+	})
+
+	mechanical, ok := tree.FindByName("機械式")
+	require.True(t, ok)
+	// 祖先の定義を引き継ぎ、同じキーは子孫の定義で置き換える
+	assert.Equal(t, []AttributeDefinition{
+		{Key: "reference_number", Type: AttributeTypeString},
+		{Key: "case_size", Type: AttributeTypeInteger, Required: true},
+		{Key: "band", Type: AttributeTypeString},
+	}, tree.AttributeSchema(mechanical))
+
+	watch, ok := tree.FindByName("時計")
+	require.True(t, ok)
+	assert.Len(t, tree.AttributeSchema(watch), 2)
+}
+
+// テスト用の固定のカテゴリー
+type stubCategoryLookup []string
+
+func (s stubCategoryLookup) IsValidCategory(name string) bool {
+	for _, category := range s {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s stubCategoryLookup) CategoryNames() []string {
+	return s
+}
+
+func (s stubCategoryLookup) AttributeSchema(name string) []AttributeDefinition {
+	return nil
+}
+
+func TestNewItem_CategoryLookup(t *testing.T) {
+	categories := stubCategoryLookup{"時計", "アート"}
+
+	_, err := NewItem("版画", "アート", "Brand", 10000, "2023-01-01", nil, "", categories)
+	assert.NoError(t, err)
+
+	_, err = NewItem("バーキン", "バッグ", "HERMÈS", 10000, "2023-01-01", nil, "", categories)
+	var validationErrs ValidationErrors
+	require.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, ValidationErrors{InvalidEnumError("category", []string{"時計", "アート"})}, validationErrs)
+
+	// 渡さない場合は ValidCategories で検証する
+	_, err = NewItem("版画", "アート", "Brand", 10000, "2023-01-01", nil, "", nil)
+	require.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, ValidationErrors{InvalidEnumError("category", ValidCategories)}, validationErrs)
+}
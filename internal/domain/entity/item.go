@@ -1,102 +1,160 @@
 package entity
 
 import (
-	"errors"
-	"strings"
 	"time"
 )
 
 type Item struct {
-	ID            int64     `json:"id"`
-	Name          string    `json:"name"`
-	Category      string    `json:"category"`
-	Brand         string    `json:"brand"`
-	PurchasePrice int       `json:"purchase_price"`
-	PurchaseDate  string    `json:"purchase_date"` // YYYY-MM-DD 形式
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	Category       string     `json:"category"`
+	Brand          string     `json:"brand"`
+	BrandCanonical string     `json:"brand_canonical"` // 表記ゆれを吸収したブランド名（CanonicalBrand）
+	BrandID        *int64     `json:"brand_id"`        // ブランドカタログの ID。カタログに無いブランドの場合は nil
+	PurchasePrice  int        `json:"purchase_price"`
+	PurchaseDate   string     `json:"purchase_date"` // YYYY-MM-DD 形式
+	Attributes     Attributes `json:"attributes"`    // カテゴリーごとに定義された属性の値（CategoryLookup.AttributeSchema）
+	Tags           Tags       `json:"tags"`          // カテゴリーをまたいでアイテムをまとめるタグ
+	Notes          string     `json:"notes"`         // 自由記述のメモ（改行を含められる）
+	Version        int64      `json:"version"`       // 楽観的ロック用。更新のたびに1増える
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // ゴミ箱に入っている場合のみ値を持つ
 }
 
-// カテゴリー定義
+// ItemField は部分更新の対象を表すフィールド名（JSON のキーと同じ）
+type ItemField string
+
+const (
+	ItemFieldName          ItemField = "name"
+	ItemFieldCategory      ItemField = "category"
+	ItemFieldBrand         ItemField = "brand"
+	ItemFieldPurchasePrice ItemField = "purchase_price"
+	ItemFieldPurchaseDate  ItemField = "purchase_date"
+	ItemFieldAttributes    ItemField = "attributes"
+	ItemFieldTags          ItemField = "tags"
+	ItemFieldNotes         ItemField = "notes"
+)
+
+// 名前とブランドの最大文字数（データベースの VARCHAR(100) に合わせる）とメモの最大文字数
+const (
+	MaxNameLength  = 100
+	MaxBrandLength = 100
+	MaxNotesLength = 1000
+)
+
+// 既定のカテゴリー（マイグレーション 0004_categories の初期データと同じ）。
+// バリデーションに CategoryLookup が渡されなかった場合に使う
 var ValidCategories = []string{"時計", "バッグ", "ジュエリー", "靴", "その他"}
 
-func NewItem(name, category, brand string, purchasePrice int, purchaseDate string) (*Item, error) {
+// NewItem はアイテムを正規化して検証する。カテゴリーと属性は categories で検証する（nil の場合は ValidCategories）
+func NewItem(name, category, brand string, purchasePrice int, purchaseDate string, attributes Attributes, notes string, categories CategoryLookup) (*Item, error) {
 	item := &Item{
-		Name:          strings.TrimSpace(name),
-		Category:      strings.TrimSpace(category),
-		Brand:         strings.TrimSpace(brand),
+		Name:          name,
+		Category:      category,
+		Brand:         brand,
 		PurchasePrice: purchasePrice,
-		PurchaseDate:  strings.TrimSpace(purchaseDate),
+		PurchaseDate:  purchaseDate,
+		Attributes:    attributes,
+		Notes:         notes,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
+	item.Normalize()
 
-	if err := item.Validate(); err != nil {
+	if err := item.Validate(categories); err != nil {
 		return nil, err
 	}
 
 	return item, nil
 }
 
-// アイテムフィールドのバリデーション
-func (i *Item) Validate() error {
-	var errs []string
+// Normalize は文字列のフィールドを NormalizeText（メモは改行を残す NormalizeMultilineText）で正規化し、
+// BrandCanonical を Brand から求め直す。
+// 属性は null と空文字列の値を取り除き、数値を float64 にそろえる。タグは NormalizeTags で正規化する
+func (i *Item) Normalize() {
+	i.Name = NormalizeText(i.Name)
+	i.Category = NormalizeText(i.Category)
+	i.Brand = NormalizeText(i.Brand)
+	i.BrandCanonical = CanonicalBrand(i.Brand)
+	i.PurchaseDate = NormalizeText(i.PurchaseDate)
+	i.Attributes = i.Attributes.normalize()
+	i.Tags = NormalizeTags(i.Tags)
+	i.Notes = NormalizeMultilineText(i.Notes)
+}
+
+// アイテムフィールドのバリデーション。カテゴリーと属性は categories で検証する（nil の場合は ValidCategories）。
+// 問題があれば ValidationErrors を返す
+func (i *Item) Validate(categories CategoryLookup) error {
+	var errs ValidationErrors
+	categories = categoriesOrDefault(categories)
 
 	if i.Name == "" {
-		errs = append(errs, "name is required")
-	} else if len(i.Name) > 100 {
-		errs = append(errs, "name must be 100 characters or less")
+		errs = append(errs, RequiredError("name"))
+	} else if CharLength(i.Name) > MaxNameLength {
+		errs = append(errs, TooLongError("name", MaxNameLength))
 	}
 
 	if i.Category == "" {
-		errs = append(errs, "category is required")
-	} else if !isValidCategory(i.Category) {
-		errs = append(errs, "category must be one of: 時計, バッグ, ジュエリー, 靴, その他")
+		errs = append(errs, RequiredError("category"))
+	} else if !categories.IsValidCategory(i.Category) {
+		errs = append(errs, InvalidEnumError("category", categories.CategoryNames()))
+	} else {
+		// 属性はカテゴリーの定義で検証するため、カテゴリーが正しい場合のみ検証する
+		errs = append(errs, validateAttributes(categories.AttributeSchema(i.Category), i.Attributes)...)
 	}
 
 	if i.Brand == "" {
-		errs = append(errs, "brand is required")
-	} else if len(i.Brand) > 100 {
-		errs = append(errs, "brand must be 100 characters or less")
+		errs = append(errs, RequiredError("brand"))
+	} else if CharLength(i.Brand) > MaxBrandLength {
+		errs = append(errs, TooLongError("brand", MaxBrandLength))
 	}
 
 	if i.PurchasePrice < 0 {
-		errs = append(errs, "purchase_price must be 0 or greater")
+		errs = append(errs, OutOfRangeError("purchase_price", 0))
 	}
 
 	if i.PurchaseDate == "" {
-		errs = append(errs, "purchase_date is required")
+		errs = append(errs, RequiredError("purchase_date"))
 	} else if !isValidDateFormat(i.PurchaseDate) {
-		errs = append(errs, "purchase_date must be in YYYY-MM-DD format")
+		errs = append(errs, InvalidFormatError("purchase_date", "YYYY-MM-DD"))
+	}
+
+	errs = append(errs, validateTags(i.Tags)...)
+
+	if CharLength(i.Notes) > MaxNotesLength {
+		errs = append(errs, TooLongError("notes", MaxNotesLength))
 	}
 
 	if len(errs) > 0 {
-		return errors.New(strings.Join(errs, ", "))
+		return errs
 	}
 
 	return nil
 }
 
-// アイテムフィールドのアップデート
-func (i *Item) Update(name, category, brand string, purchasePrice int, purchaseDate string) error {
-	i.Name = strings.TrimSpace(name)
-	i.Category = strings.TrimSpace(category)
-	i.Brand = strings.TrimSpace(brand)
+// SetTags はタグを正規化して置き換え、タグのみを検証する。問題があれば ValidationErrors を返す
+func (i *Item) SetTags(tags []string) error {
+	i.Tags = NormalizeTags(tags)
+	if errs := validateTags(i.Tags); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// アイテムフィールドのアップデート（タグは変更しない）。categories は Validate と同じ
+func (i *Item) Update(name, category, brand string, purchasePrice int, purchaseDate string, attributes Attributes, notes string, categories CategoryLookup) error {
+	i.Name = name
+	i.Category = category
+	i.Brand = brand
 	i.PurchasePrice = purchasePrice
-	i.PurchaseDate = strings.TrimSpace(purchaseDate)
+	i.PurchaseDate = purchaseDate
+	i.Attributes = attributes
+	i.Notes = notes
 	i.UpdatedAt = time.Now()
+	i.Normalize()
 
-	return i.Validate()
-}
-
-// カテゴリーのバリデーション
-func isValidCategory(category string) bool {
-	for _, valid := range ValidCategories {
-		if category == valid {
-			return true
-		}
-	}
-	return false
+	return i.Validate(categories)
 }
 
 // デート形式のバリデーション
@@ -104,8 +162,3 @@ func isValidDateFormat(dateStr string) bool {
 	_, err := time.Parse("2006-01-02", dateStr)
 	return err == nil
 }
-
-// カテゴリーの取得
-func GetValidCategories() []string {
-	return ValidCategories
-}
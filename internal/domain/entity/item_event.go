@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"reflect"
+	"time"
+)
+
+// アイテムの変更操作の種類
+const (
+	ItemEventCreate  = "create"
+	ItemEventUpdate  = "update"
+	ItemEventDelete  = "delete"
+	ItemEventRestore = "restore"
+)
+
+// ItemEvent はアイテムに対する1回の変更の監査記録
+type ItemEvent struct {
+	ID        int64                  `json:"id"`
+	ItemID    int64                  `json:"item_id"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	RequestID string                 `json:"request_id"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// FieldChange は1フィールドの変更前後の値。作成時の Before、削除時の After は nil
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DiffItems は before と after で値が異なるフィールドを返す。
+// どちらかが nil の場合は、もう一方の全フィールドを変更として扱う
+func DiffItems(before, after *Item) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	fields := []struct {
+		name  ItemField
+		value func(*Item) interface{}
+	}{
+		{ItemFieldName, func(i *Item) interface{} { return i.Name }},
+		{ItemFieldCategory, func(i *Item) interface{} { return i.Category }},
+		{ItemFieldBrand, func(i *Item) interface{} { return i.Brand }},
+		{"brand_canonical", func(i *Item) interface{} {
+			// 正規形はブランドの正式名の変更でも変わるため、brand とは別に記録する
+			if i.BrandCanonical == "" {
+				return nil
+			}
+			return i.BrandCanonical
+		}},
+		{ItemFieldPurchasePrice, func(i *Item) interface{} { return i.PurchasePrice }},
+		{ItemFieldPurchaseDate, func(i *Item) interface{} { return i.PurchaseDate }},
+		{ItemFieldAttributes, func(i *Item) interface{} {
+			// 属性が無い場合は変更前後の比較で {} と区別しないよう nil にする
+			if len(i.Attributes) == 0 {
+				return nil
+			}
+			return map[string]interface{}(i.Attributes.Clone())
+		}},
+		{ItemFieldTags, func(i *Item) interface{} {
+			if len(i.Tags) == 0 {
+				return nil
+			}
+			return []string(i.Tags.Clone())
+		}},
+		{ItemFieldNotes, func(i *Item) interface{} {
+			if i.Notes == "" {
+				return nil
+			}
+			return i.Notes
+		}},
+	}
+
+	for _, f := range fields {
+		var b, a interface{}
+		if before != nil {
+			b = f.value(before)
+		}
+		if after != nil {
+			a = f.value(after)
+		}
+		if !reflect.DeepEqual(b, a) {
+			changes[string(f.name)] = FieldChange{Before: b, After: a}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffItems(t *testing.T) {
+	base := &Item{
+		Name:          "ロレックス デイトナ",
+		Category:      "時計",
+		Brand:         "ROLEX",
+		PurchasePrice: 1500000,
+		PurchaseDate:  "2023-01-15",
+	}
+
+	tests := []struct {
+		name     string
+		before   *Item
+		after    *Item
+		expected map[string]FieldChange
+	}{
+		{
+			name:   "正常系: 変更したフィールドのみ",
+			before: base,
+			after: &Item{
+				Name:          "ロレックス デイトナ",
+				Category:      "時計",
+				Brand:         "ROLEX SA",
+				PurchasePrice: 0,
+				PurchaseDate:  "2023-01-15",
+			},
+			expected: map[string]FieldChange{
+				"brand":          {Before: "ROLEX", After: "ROLEX SA"},
+				"purchase_price": {Before: 1500000, After: 0},
+			},
+		},
+		{
+			name:   "正常系: 属性の変更",
+			before: &Item{Name: "ロレックス デイトナ", Attributes: Attributes{"movement": "自動巻き"}},
+			after:  &Item{Name: "ロレックス デイトナ", Attributes: Attributes{"movement": "自動巻き", "case_size": 40.0}},
+			expected: map[string]FieldChange{
+				"attributes": {
+					Before: map[string]interface{}{"movement": "自動巻き"},
+					After:  map[string]interface{}{"movement": "自動巻き", "case_size": 40.0},
+				},
+			},
+		},
+		{
+			name:   "正常系: タグの追加",
+			before: &Item{Name: "ロレックス デイトナ"},
+			after:  &Item{Name: "ロレックス デイトナ", Tags: Tags{"投資用"}},
+			expected: map[string]FieldChange{
+				"tags": {Before: nil, After: []string{"投資用"}},
+			},
+		},
+		{
+			name:   "正常系: メモの削除",
+			before: &Item{Name: "ロレックス デイトナ", Notes: "箱・保証書あり"},
+			after:  &Item{Name: "ロレックス デイトナ"},
+			expected: map[string]FieldChange{
+				"notes": {Before: "箱・保証書あり", After: nil},
+			},
+		},
+		{
+			name:     "正常系: 変更なし",
+			before:   base,
+			after:    base,
+			expected: map[string]FieldChange{},
+		},
+		{
+			name:   "正常系: 作成（before が nil）",
+			before: nil,
+			after:  base,
+			expected: map[string]FieldChange{
+				"name":           {Before: nil, After: "ロレックス デイトナ"},
+				"category":       {Before: nil, After: "時計"},
+				"brand":          {Before: nil, After: "ROLEX"},
+				"purchase_price": {Before: nil, After: 1500000},
+				"purchase_date":  {Before: nil, After: "2023-01-15"},
+			},
+		},
+		{
+			name:   "正常系: 削除（after が nil）",
+			before: base,
+			after:  nil,
+			expected: map[string]FieldChange{
+				"name":           {Before: "ロレックス デイトナ", After: nil},
+				"category":       {Before: "時計", After: nil},
+				"brand":          {Before: "ROLEX", After: nil},
+				"purchase_price": {Before: 1500000, After: nil},
+				"purchase_date":  {Before: "2023-01-15", After: nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DiffItems(tt.before, tt.after))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,7 +42,7 @@ func TestNewItem(t *testing.T) {
 		},
 		{
 			name:          "異常系: 名前が100文字超過",
-			itemName:      "ロレックス デイトナ 16520 18K イエローゴールド ブラック文字盤 自動巻き クロノグラフ メンズ 腕時計 1988年製 ヴィンテージ 希少 コレクション アイテム",
+			itemName:      strings.Repeat("時", 101),
 			category:      "時計",
 			brand:         "ROLEX",
 			purchasePrice: 1500000,
@@ -118,6 +120,24 @@ func TestNewItem(t *testing.T) {
 			wantErr:       true,
 			expectedErr:   "purchase_date must be in YYYY-MM-DD format",
 		},
+		{
+			name:          "正常系: 日本語の名前はバイト数ではなく文字数で数える",
+			itemName:      "ロレックス デイトナ 16520 18K イエローゴールド ブラック文字盤 自動巻き クロノグラフ メンズ 腕時計 1988年製 ヴィンテージ 希少 コレクション アイテム",
+			category:      "時計",
+			brand:         "ROLEX",
+			purchasePrice: 1500000,
+			purchaseDate:  "2023-01-15",
+			wantErr:       false,
+		},
+		{
+			name:          "正常系: 名前がちょうど100文字",
+			itemName:      strings.Repeat("時", 100),
+			category:      "時計",
+			brand:         "ROLEX",
+			purchasePrice: 1500000,
+			purchaseDate:  "2023-01-15",
+			wantErr:       false,
+		},
 		{
 			name:          "正常系: 購入価格が0",
 			itemName:      "ギフト品",
@@ -131,7 +151,7 @@ func TestNewItem(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			item, err := NewItem(tt.itemName, tt.category, tt.brand, tt.purchasePrice, tt.purchaseDate)
+			item, err := NewItem(tt.itemName, tt.category, tt.brand, tt.purchasePrice, tt.purchaseDate, nil, "", nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -157,9 +177,31 @@ func TestNewItem(t *testing.T) {
 	}
 }
 
+func TestNewItem_Normalize(t *testing.T) {
+	item, err := NewItem("  ＲＯＬＥＸ　ﾃﾞｲﾄﾅ\t16520 ", "ﾊﾞｯｸﾞ", " Hermès ", 1500000, "２０２３-０１-１５", nil, " ＯＨ済み\r\n箱　あり ", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ROLEX デイトナ 16520", item.Name)
+	assert.Equal(t, "バッグ", item.Category)
+	assert.Equal(t, "Hermès", item.Brand)
+	assert.Equal(t, "HERMES", item.BrandCanonical)
+	assert.Equal(t, "2023-01-15", item.PurchaseDate)
+	assert.Equal(t, "OH済み\n箱 あり", item.Notes)
+}
+
+func TestNewItem_Notes(t *testing.T) {
+	_, err := NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, strings.Repeat("あ", MaxNotesLength), nil)
+	assert.NoError(t, err)
+
+	_, err = NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, strings.Repeat("あ", MaxNotesLength+1), nil)
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, ValidationErrors{TooLongError("notes", MaxNotesLength)}, errs)
+}
+
 func TestItem_Update(t *testing.T) {
 	// 初期アイテムを作成
-	item, err := NewItem("初期アイテム", "時計", "初期ブランド", 100000, "2023-01-01")
+	item, err := NewItem("初期アイテム", "時計", "初期ブランド", 100000, "2023-01-01", nil, "", nil)
 	require.NoError(t, err)
 
 	originalUpdatedAt := item.UpdatedAt
@@ -208,7 +250,7 @@ func TestItem_Update(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := item.Update(tt.newName, tt.newCategory, tt.newBrand, tt.newPrice, tt.newDate)
+			err := item.Update(tt.newName, tt.newCategory, tt.newBrand, tt.newPrice, tt.newDate, nil, "", nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -265,7 +307,7 @@ func TestItem_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.item.Validate()
+			err := tt.item.Validate(nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -278,7 +320,7 @@ func TestItem_Validate(t *testing.T) {
 	}
 }
 
-func TestIsValidCategory(t *testing.T) {
+func TestDefaultCategories_IsValidCategory(t *testing.T) {
 	tests := []struct {
 		name     string
 		category string
@@ -296,7 +338,7 @@ func TestIsValidCategory(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isValidCategory(tt.category)
+			got := defaultCategories{}.IsValidCategory(tt.category)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -328,10 +370,65 @@ func TestIsValidDateFormat(t *testing.T) {
 	}
 }
 
-func TestGetValidCategories(t *testing.T) {
-	categories := GetValidCategories()
+func TestDefaultCategories_CategoryNames(t *testing.T) {
+	categories := defaultCategories{}.CategoryNames()
 	expected := []string{"時計", "バッグ", "ジュエリー", "靴", "その他"}
 
 	assert.Equal(t, expected, categories)
 	assert.Len(t, categories, 5)
 }
+
+func TestItem_Validate_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     Item
+		expected ValidationErrors
+	}{
+		{
+			name: "異常系: 必須項目がすべて空",
+			item: Item{},
+			expected: ValidationErrors{
+				{Field: "name", Code: ValidationRequired, Message: "name is required"},
+				{Field: "category", Code: ValidationRequired, Message: "category is required"},
+				{Field: "brand", Code: ValidationRequired, Message: "brand is required"},
+				{Field: "purchase_date", Code: ValidationRequired, Message: "purchase_date is required"},
+			},
+		},
+		{
+			name: "異常系: 長さ・選択肢・形式・範囲の違反",
+			item: Item{
+				Name:          strings.Repeat("a", 101),
+				Category:      "家電",
+				Brand:         "ROLEX",
+				PurchasePrice: -1,
+				PurchaseDate:  "2023/01/15",
+			},
+			expected: ValidationErrors{
+				{Field: "name", Code: ValidationTooLong, Params: map[string]interface{}{"max": 100}, Message: "name must be 100 characters or less"},
+				{Field: "category", Code: ValidationInvalidEnum, Params: map[string]interface{}{"allowed": ValidCategories}, Message: "category must be one of: 時計, バッグ, ジュエリー, 靴, その他"},
+				{Field: "purchase_price", Code: ValidationOutOfRange, Params: map[string]interface{}{"min": 0}, Message: "purchase_price must be 0 or greater"},
+				{Field: "purchase_date", Code: ValidationInvalidFormat, Params: map[string]interface{}{"format": "YYYY-MM-DD"}, Message: "purchase_date must be in YYYY-MM-DD format"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate(nil)
+
+			var validationErrs ValidationErrors
+			require.True(t, errors.As(err, &validationErrs))
+			assert.Equal(t, tt.expected, validationErrs)
+		})
+	}
+
+	t.Run("正常系: Error はメッセージを連結する", func(t *testing.T) {
+		err := (&Item{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchaseDate: "2023-01-15", PurchasePrice: -1}).Validate(nil)
+		assert.EqualError(t, err, "purchase_price must be 0 or greater")
+	})
+
+	t.Run("正常系: 問題が無ければ nil", func(t *testing.T) {
+		err := (&Item{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchaseDate: "2023-01-15"}).Validate(nil)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 検索語の最大文字数と、検索結果で一致した箇所の前後を切り出す長さ（文字数）
+const (
+	MaxSearchQueryLength = 100
+	searchFragmentLength = 60
+	searchFragmentBefore = 20
+)
+
+// 全文検索の対象のフィールドと関連度の重み（名前 > ブランド > メモ）
+var searchFields = []struct {
+	name   ItemField
+	weight float64
+	value  func(*Item) string
+}{
+	{ItemFieldName, 3, func(i *Item) string { return i.Name }},
+	{ItemFieldBrand, 2, func(i *Item) string { return i.Brand }},
+	{ItemFieldNotes, 1, func(i *Item) string { return i.Notes }},
+}
+
+// SearchMatch はアイテムが検索語に一致した結果
+type SearchMatch struct {
+	Score      float64
+	Highlights []Highlight
+}
+
+// Highlight はフィールドの一致した箇所を含む断片。長いフィールドは一致した箇所の前後を切り出し、省略した側に "…" を付ける
+type Highlight struct {
+	Field    string      `json:"field"`
+	Fragment string      `json:"fragment"`
+	Matches  []TextRange `json:"matches"` // Fragment の中で一致した範囲
+}
+
+// TextRange は文字列の範囲（文字単位。Start を含み End を含まない）
+type TextRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// FoldSearchText は全文検索で比較するために、英字を小文字に、カタカナをひらがなにそろえ、
+// ラテン文字のアクセント記号を取り除く（"ロレックス" と "ろれっくす"、"Hermès" と "hermes" が一致する）。
+// 1文字ずつ変換するため、結果の n 文字目は s の n 文字目に対応する。s は NormalizeText で正規化済みであること
+func FoldSearchText(s string) string {
+	return strings.Map(foldSearchRune, s)
+}
+
+func foldSearchRune(r rune) rune {
+	switch {
+	case r >= 'ァ' && r <= 'ヶ', r == 'ヽ', r == 'ヾ':
+		return r - ('ァ' - 'ぁ')
+	case r >= utf8.RuneSelf && unicode.Is(unicode.Latin, r):
+		// 合成済みの文字（è）を基底文字（e）と結合文字に分解し、結合文字が取り除ける場合のみ基底文字にする
+		decomposed := []rune(norm.NFD.String(string(r)))
+		if len(decomposed) > 1 && !slices.ContainsFunc(decomposed[1:], func(m rune) bool { return !unicode.Is(unicode.Mn, m) }) {
+			r = decomposed[0]
+		}
+	}
+	return unicode.ToLower(r)
+}
+
+// SearchTerms は検索文字列を NormalizeText で正規化して FoldSearchText で畳み込み、空白で区切った検索語を返す。
+// 重複する検索語は取り除く
+func SearchTerms(query string) []string {
+	var terms []string
+	for _, term := range strings.Fields(FoldSearchText(NormalizeText(query))) {
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// SearchText はアイテムの検索対象のフィールドを FoldSearchText で畳み込み、改行で区切ってつなげたもの。
+// MySQL の全文検索インデックス（items.search_text）に保存する
+func SearchText(item *Item) string {
+	values := make([]string, len(searchFields))
+	for i, f := range searchFields {
+		values[i] = FoldSearchText(f.value(item))
+	}
+	return strings.Join(values, "\n")
+}
+
+// MatchSearch は item の名前・ブランド・メモのいずれかにすべての検索語が含まれるかを調べ（部分一致）、
+// 関連度と一致した箇所を返す。関連度は検索語が現れるたびにフィールドの重みを加えたもので、
+// フィールド全体が検索語と一致する場合は重みを倍にする。含まれない検索語がある場合も、一致した箇所は返す
+func MatchSearch(item *Item, terms []string) (SearchMatch, bool) {
+	if len(terms) == 0 {
+		return SearchMatch{}, false
+	}
+
+	var match SearchMatch
+	found := make([]bool, len(terms))
+	for _, f := range searchFields {
+		value := f.value(item)
+		folded := FoldSearchText(value)
+
+		var ranges []TextRange
+		for i, term := range terms {
+			occurrences := findTerm(folded, term)
+			if len(occurrences) == 0 {
+				continue
+			}
+			found[i] = true
+			weight := f.weight
+			if folded == term {
+				weight *= 2
+			}
+			match.Score += weight * float64(len(occurrences))
+			ranges = append(ranges, occurrences...)
+		}
+		if len(ranges) > 0 {
+			match.Highlights = append(match.Highlights, highlight(string(f.name), value, mergeRanges(ranges)))
+		}
+	}
+
+	return match, !slices.Contains(found, false)
+}
+
+// findTerm は folded の中で term が現れる範囲をすべて返す（重なる場合も含む）
+func findTerm(folded, term string) []TextRange {
+	var ranges []TextRange
+	length := utf8.RuneCountInString(term)
+	for offset := 0; offset < len(folded); {
+		i := strings.Index(folded[offset:], term)
+		if i < 0 {
+			break
+		}
+		start := utf8.RuneCountInString(folded[:offset+i])
+		ranges = append(ranges, TextRange{Start: start, End: start + length})
+		// 次の1文字から探し直す
+		_, size := utf8.DecodeRuneInString(folded[offset+i:])
+		offset += i + size
+	}
+	return ranges
+}
+
+// mergeRanges は範囲を先頭順に並べ、重なるものや隣り合うものをまとめる
+func mergeRanges(ranges []TextRange) []TextRange {
+	slices.SortFunc(ranges, func(a, b TextRange) int { return a.Start - b.Start })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// highlight は value の最初に一致した箇所を含む断片を切り出す。範囲は断片の中の位置に直す
+func highlight(field, value string, ranges []TextRange) Highlight {
+	runes := []rune(value)
+	start, end := 0, len(runes)
+	if end > searchFragmentLength {
+		start = max(0, min(ranges[0].Start-searchFragmentBefore, len(runes)-searchFragmentLength))
+		end = start + searchFragmentLength
+	}
+
+	fragment := string(runes[start:end])
+	shift := -start
+	if start > 0 {
+		fragment = "…" + fragment
+		shift++
+	}
+	if end < len(runes) {
+		fragment += "…"
+	}
+
+	matches := []TextRange{}
+	for _, r := range ranges {
+		if r.End <= start || r.Start >= end {
+			continue
+		}
+		matches = append(matches, TextRange{Start: max(r.Start, start) + shift, End: min(r.End, end) + shift})
+	}
+	return Highlight{Field: field, Fragment: fragment, Matches: matches}
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldSearchText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "正常系: カタカナはひらがなに",
+			input:    "ロレックス デイトナ",
+			expected: "ろれっくす でいとな",
+		},
+		{
+			name:     "正常系: 英字は小文字にしてアクセント記号を取り除く",
+			input:    "HERMÈS Kelly",
+			expected: "hermes kelly",
+		},
+		{
+			name:     "正常系: 漢字と長音記号はそのまま",
+			input:    "腕時計 オーバーホール",
+			expected: "腕時計 おーばーほーる",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := FoldSearchText(tt.input)
+			assert.Equal(t, tt.expected, folded)
+			assert.Equal(t, CharLength(tt.input), CharLength(folded), "文字数は変わらない")
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"ろれっくす", "16520"}, SearchTerms("　ﾛﾚｯｸｽ  １６５２０ ろれっくす"))
+	assert.Nil(t, SearchTerms(" "))
+}
+
+func TestMatchSearch(t *testing.T) {
+	item := &Item{
+		Name:  "ロレックス デイトナ",
+		Brand: "ROLEX",
+		Notes: "2023年にオーバーホール済み。箱・保証書あり",
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		matched    bool
+		highlights []Highlight
+	}{
+		{
+			name:    "正常系: ひらがなでカタカナの名前に一致",
+			query:   "でいとな",
+			matched: true,
+			highlights: []Highlight{
+				{Field: "name", Fragment: "ロレックス デイトナ", Matches: []TextRange{{Start: 6, End: 10}}},
+			},
+		},
+		{
+			name:    "正常系: 複数の検索語がフィールドをまたいで一致",
+			query:   "rolex 保証書",
+			matched: true,
+			highlights: []Highlight{
+				{Field: "brand", Fragment: "ROLEX", Matches: []TextRange{{Start: 0, End: 5}}},
+				{Field: "notes", Fragment: "2023年にオーバーホール済み。箱・保証書あり", Matches: []TextRange{{Start: 18, End: 21}}},
+			},
+		},
+		{
+			name:    "正常系: 漢字の部分一致",
+			query:   "保証",
+			matched: true,
+			highlights: []Highlight{
+				{Field: "notes", Fragment: "2023年にオーバーホール済み。箱・保証書あり", Matches: []TextRange{{Start: 18, End: 20}}},
+			},
+		},
+		{
+			name:    "異常系: 一致しない検索語がある場合も一致した箇所は返す",
+			query:   "ロレックス サブマリーナ",
+			matched: false,
+			highlights: []Highlight{
+				{Field: "name", Fragment: "ロレックス デイトナ", Matches: []TextRange{{Start: 0, End: 5}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := MatchSearch(item, SearchTerms(tt.query))
+
+			assert.Equal(t, tt.matched, ok)
+			assert.Equal(t, tt.highlights, match.Highlights)
+		})
+	}
+}
+
+func TestMatchSearch_Score(t *testing.T) {
+	terms := SearchTerms("ロレックス")
+	inName, ok := MatchSearch(&Item{Name: "ロレックス デイトナ", Brand: "ROLEX"}, terms)
+	require.True(t, ok)
+	inNotes, ok := MatchSearch(&Item{Name: "腕時計", Brand: "不明", Notes: "ロレックスに似ている"}, terms)
+	require.True(t, ok)
+	exact, ok := MatchSearch(&Item{Name: "ロレックス", Brand: "ROLEX"}, terms)
+	require.True(t, ok)
+
+	assert.Greater(t, inName.Score, inNotes.Score, "名前の一致はメモの一致より上位")
+	assert.Greater(t, exact.Score, inName.Score, "フィールド全体の一致は部分一致より上位")
+}
+
+func TestMatchSearch_Fragment(t *testing.T) {
+	notes := strings.Repeat("あ", 50) + "保証書" + strings.Repeat("い", 50)
+
+	match, ok := MatchSearch(&Item{Name: "時計", Brand: "ROLEX", Notes: notes}, SearchTerms("保証書"))
+
+	require.True(t, ok)
+	require.Len(t, match.Highlights, 1)
+	h := match.Highlights[0]
+	assert.Equal(t, "…"+strings.Repeat("あ", 20)+"保証書"+strings.Repeat("い", 37)+"…", h.Fragment)
+	assert.Equal(t, []TextRange{{Start: 21, End: 24}}, h.Matches)
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// タグの最大文字数（データベースの VARCHAR(50) に合わせる）と、1つのアイテムに付けられるタグの数
+const (
+	MaxTagLength   = 50
+	MaxTagsPerItem = 20
+)
+
+// Tags はアイテムに付けた自由入力のタグ（"結婚祝い"、"投資用" など）。
+// カテゴリーをまたいでアイテムをまとめるために使い、NormalizeTags で正規化した順に並ぶ
+type Tags []string
+
+// MarshalJSON はタグが無い場合も null ではなく [] を返す
+func (t Tags) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(t))
+}
+
+// Clone は t の複製を返す。t が nil の場合は nil
+func (t Tags) Clone() Tags {
+	return slices.Clone(t)
+}
+
+// TagCount はタグごとのアイテム数（タグクラウド）
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag はタグを NormalizeText で正規化し、大文字小文字を区別しないよう小文字にそろえる
+func NormalizeTag(tag string) string {
+	return strings.ToLower(NormalizeText(tag))
+}
+
+// NormalizeTags は各タグを NormalizeTag で正規化し、空のものと重複を取り除いて辞書順に並べる。
+// タグが無い場合は nil を返す
+func NormalizeTags(tags []string) Tags {
+	var normalized Tags
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized
+}
+
+// validateTags は正規化済みのタグを検証する。フィールド名は "tags[0]" の形式
+func validateTags(tags Tags) ValidationErrors {
+	var errs ValidationErrors
+	if len(tags) > MaxTagsPerItem {
+		errs = append(errs, TooManyError("tags", MaxTagsPerItem))
+	}
+	for i, tag := range tags {
+		if CharLength(tag) > MaxTagLength {
+			errs = append(errs, TooLongError(fmt.Sprintf("tags[%d]", i), MaxTagLength))
+		}
+	}
+	return errs
+}
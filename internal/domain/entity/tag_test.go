@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		expected Tags
+	}{
+		{
+			name:     "正常系: 正規化して重複を取り除き、辞書順に並べる",
+			tags:     []string{" 投資用 ", "Wedding　Gifts", "wedding gifts", "ＳＡＬＥ", ""},
+			expected: Tags{"sale", "wedding gifts", "投資用"},
+		},
+		{
+			name:     "正常系: 空のタグのみ",
+			tags:     []string{" ", ""},
+			expected: nil,
+		},
+		{
+			name:     "正常系: nil",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeTags(tt.tags))
+		})
+	}
+}
+
+func TestItem_SetTags(t *testing.T) {
+	tooMany := make([]string, MaxTagsPerItem+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%02d", i)
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected Tags
+		errs     ValidationErrors
+	}{
+		{
+			name:     "正常系: タグを置き換える",
+			tags:     []string{"売却予定", "投資用"},
+			expected: Tags{"売却予定", "投資用"},
+		},
+		{
+			name: "異常系: タグが長すぎる",
+			tags: []string{"a", strings.Repeat("b", MaxTagLength+1)},
+			errs: ValidationErrors{TooLongError("tags[1]", MaxTagLength)},
+		},
+		{
+			name: "異常系: タグが多すぎる",
+			tags: tooMany,
+			errs: ValidationErrors{TooManyError("tags", MaxTagsPerItem)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &Item{Tags: Tags{"結婚祝い"}}
+			err := item.SetTags(tt.tags)
+
+			if tt.errs != nil {
+				var validationErrs ValidationErrors
+				require.True(t, errors.As(err, &validationErrs))
+				assert.Equal(t, tt.errs, validationErrs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, item.Tags)
+		})
+	}
+}
+
+func TestTags_MarshalJSON(t *testing.T) {
+	var empty Tags
+	data, err := empty.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+
+	data, err = Tags{"投資用"}.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["投資用"]`, string(data))
+}
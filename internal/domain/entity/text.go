@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText は入力された文字列を保存する形に正規化する。
+// NFKC で全角英数字・半角カナなどの互換文字を統一し、連続する空白（全角スペース、タブ、改行を含む）を
+// 半角スペース1つにまとめて前後の空白を取り除く
+func NormalizeText(s string) string {
+	return strings.Join(strings.FieldsFunc(norm.NFKC.String(s), unicode.IsSpace), " ")
+}
+
+// NormalizeMultilineText は NormalizeText と同じく NFKC で正規化するが、改行は残す（メモなど複数行の入力用）。
+// 行ごとに連続する空白を半角スペース1つにまとめ、前後の空行を取り除く
+func NormalizeMultilineText(s string) string {
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(norm.NFKC.String(s))
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.FieldsFunc(line, unicode.IsSpace), " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// CanonicalBrand はブランド名の表記ゆれを吸収した正規形を返す。
+// NormalizeText に加えてラテン文字のアクセント記号を取り除き、大文字にそろえる（"Hermès" → "HERMES"）。
+// 濁点・半濁点も結合文字だが、取り除くと別の語になるためかなの結合文字は残す
+func CanonicalBrand(brand string) string {
+	decomposed := norm.NFD.String(NormalizeText(brand))
+
+	var (
+		b     strings.Builder
+		latin bool // 直前の基底文字がラテン文字か
+	)
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			if latin {
+				continue
+			}
+		} else {
+			latin = unicode.Is(unicode.Latin, r)
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.ToUpper(norm.NFC.String(b.String()))
+}
+
+// CharLength は s の文字数を返す。
+// データベースの VARCHAR(n) と同じくコードポイント単位で数える（バイト数ではない）
+func CharLength(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "正常系: 全角英数字は半角に",
+			input:    "ＲＯＬＥＸ １６５２０",
+			expected: "ROLEX 16520",
+		},
+		{
+			name:     "正常系: 半角カナは全角に",
+			input:    "ﾃﾞｲﾄﾅ",
+			expected: "デイトナ",
+		},
+		{
+			name:     "正常系: 連続する空白と全角スペースは1つにまとめる",
+			input:    " ロレックス　\t デイトナ\n",
+			expected: "ロレックス デイトナ",
+		},
+		{
+			name:     "正常系: アクセント記号は残す",
+			input:    "HERMÈS",
+			expected: "HERMÈS",
+		},
+		{
+			name:     "正常系: 空文字",
+			input:    "　",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeText(tt.input))
+		})
+	}
+}
+
+func TestNormalizeMultilineText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "正常系: 改行は残して行ごとに空白をまとめる",
+			input:    "ＯＨ済み　 2023年\r\n箱・保証書 あり\n",
+			expected: "OH済み 2023年\n箱・保証書 あり",
+		},
+		{
+			name:     "正常系: 前後の空行を取り除く",
+			input:    "\n\n　\nﾒﾓ\n\n",
+			expected: "メモ",
+		},
+		{
+			name:     "正常系: 空文字",
+			input:    " \n ",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeMultilineText(tt.input))
+		})
+	}
+}
+
+func TestCanonicalBrand(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "正常系: アクセント記号を取り除く",
+			input:    "HERMÈS",
+			expected: "HERMES",
+		},
+		{
+			name:     "正常系: 小文字と全角",
+			input:    "ｈｅｒｍèｓ",
+			expected: "HERMES",
+		},
+		{
+			name:     "正常系: 結合文字で書かれたアクセント",
+			input:    "Hermès",
+			expected: "HERMES",
+		},
+		{
+			name:     "正常系: 記号と空白は残す",
+			input:    " Tiffany  &  Co. ",
+			expected: "TIFFANY & CO.",
+		},
+		{
+			name:     "正常系: 日本語はそのまま（濁点は落とさない）",
+			input:    "ｴﾙﾒｽ バーキン",
+			expected: "エルメス バーキン",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanonicalBrand(tt.input))
+		})
+	}
+}
+
+func TestCharLength(t *testing.T) {
+	assert.Equal(t, 5, CharLength("ROLEX"))
+	assert.Equal(t, 4, CharLength("デイトナ"))
+	assert.Equal(t, 6, CharLength("HERMÈS"))
+}
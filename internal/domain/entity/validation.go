@@ -0,0 +1,234 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationCode はバリデーションエラーの種類を表す機械可読なコード
+type ValidationCode string
+
+const (
+	ValidationRequired      ValidationCode = "required"
+	ValidationTooLong       ValidationCode = "too_long"
+	ValidationInvalidEnum   ValidationCode = "invalid_enum"
+	ValidationInvalidFormat ValidationCode = "invalid_format"
+	ValidationOutOfRange    ValidationCode = "out_of_range"
+	ValidationNotRegistered ValidationCode = "not_registered"
+	ValidationCircularRef   ValidationCode = "circular_reference"
+	ValidationInvalidType   ValidationCode = "invalid_type"
+	ValidationUnknownField  ValidationCode = "unknown_field"
+	ValidationDuplicate     ValidationCode = "duplicate"
+	ValidationTooMany       ValidationCode = "too_many"
+	ValidationOutOfBounds   ValidationCode = "out_of_bounds"
+	ValidationInvalidRange  ValidationCode = "invalid_range"
+	ValidationConflict      ValidationCode = "conflict"
+	ValidationInvalid       ValidationCode = "invalid"
+	ValidationMismatch      ValidationCode = "mismatch"
+	ValidationMalformed     ValidationCode = "malformed"
+	ValidationInUse         ValidationCode = "in_use"
+)
+
+// FieldError は1つのフィールドのバリデーションエラー。
+// Params にはコードごとの条件（too_long の max など）が入り、フロントエンドでのメッセージ生成に使える。
+// 単独で ErrInvalidInput や ErrInUse に包むと、そのエラーの理由（問題詳細の detail）になる
+type FieldError struct {
+	Field   string                 `json:"field"`
+	Code    ValidationCode         `json:"code"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Message string                 `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors はフィールドごとのバリデーションエラーの一覧
+type ValidationErrors []FieldError
+
+// Error はすべてのメッセージを ", " で連結する
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
+// RequiredError は field が未入力であることを表す
+func RequiredError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationRequired,
+		Message: fmt.Sprintf("%s is required", field),
+	}
+}
+
+// TooLongError は field が max 文字を超えていることを表す
+func TooLongError(field string, max int) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationTooLong,
+		Params:  map[string]interface{}{"max": max},
+		Message: fmt.Sprintf("%s must be %d characters or less", field, max),
+	}
+}
+
+// InvalidEnumError は field が allowed のいずれでもないことを表す
+func InvalidEnumError(field string, allowed []string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInvalidEnum,
+		Params:  map[string]interface{}{"allowed": allowed},
+		Message: fmt.Sprintf("%s must be one of: %s", field, strings.Join(allowed, ", ")),
+	}
+}
+
+// InvalidFormatError は field が format の形式でないことを表す
+func InvalidFormatError(field string, format string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInvalidFormat,
+		Params:  map[string]interface{}{"format": format},
+		Message: fmt.Sprintf("%s must be in %s format", field, format),
+	}
+}
+
+// OutOfRangeError は field が min を下回っていることを表す
+func OutOfRangeError(field string, min int) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationOutOfRange,
+		Params:  map[string]interface{}{"min": min},
+		Message: fmt.Sprintf("%s must be %d or greater", field, min),
+	}
+}
+
+// NotRegisteredError は field の値がカタログに登録されていないことを表す。
+// suggestions には候補となる登録済みの値を入れる（無ければ空）
+func NotRegisteredError(field string, suggestions []string) FieldError {
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	return FieldError{
+		Field:   field,
+		Code:    ValidationNotRegistered,
+		Params:  map[string]interface{}{"suggestions": suggestions},
+		Message: fmt.Sprintf("%s is not registered", field),
+	}
+}
+
+// CircularReferenceError は field の値が自身または自身の子孫を指していることを表す
+func CircularReferenceError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationCircularRef,
+		Message: fmt.Sprintf("%s must not refer to itself or its descendants", field),
+	}
+}
+
+// InvalidTypeError は field の値の型が t ではないことを表す
+func InvalidTypeError(field string, t AttributeType) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInvalidType,
+		Params:  map[string]interface{}{"type": string(t)},
+		Message: fmt.Sprintf("%s must be %s", field, t),
+	}
+}
+
+// UnknownFieldError は field が定義されていないことを表す
+func UnknownFieldError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationUnknownField,
+		Message: fmt.Sprintf("%s is not defined", field),
+	}
+}
+
+// DuplicateError は field の値が他の要素と重複していることを表す
+func DuplicateError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationDuplicate,
+		Message: fmt.Sprintf("%s is duplicated", field),
+	}
+}
+
+// TooManyError は field の要素が max 個を超えていることを表す
+func TooManyError(field string, max int) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationTooMany,
+		Params:  map[string]interface{}{"max": max},
+		Message: fmt.Sprintf("%s must have %d items or fewer", field, max),
+	}
+}
+
+// OutOfBoundsError は field が min 以上 max 以下の範囲に無いことを表す
+func OutOfBoundsError(field string, min, max int) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationOutOfBounds,
+		Params:  map[string]interface{}{"min": min, "max": max},
+		Message: fmt.Sprintf("%s must be between %d and %d", field, min, max),
+	}
+}
+
+// InvalidRangeError は範囲の始まりの field が終わりの other を超えていることを表す
+func InvalidRangeError(field, other string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInvalidRange,
+		Params:  map[string]interface{}{"other": other},
+		Message: fmt.Sprintf("%s must not be greater than %s", field, other),
+	}
+}
+
+// ConflictError は field を other と同時に指定できないことを表す
+func ConflictError(field, other string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationConflict,
+		Params:  map[string]interface{}{"other": other},
+		Message: fmt.Sprintf("%s cannot be combined with %s", field, other),
+	}
+}
+
+// InvalidError は field の値が解釈できない、または改ざんされていることを表す
+func InvalidError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInvalid,
+		Message: fmt.Sprintf("%s is invalid", field),
+	}
+}
+
+// MismatchError は field の値が other と一致しないことを表す
+func MismatchError(field, other string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationMismatch,
+		Params:  map[string]interface{}{"other": other},
+		Message: fmt.Sprintf("%s does not match %s", field, other),
+	}
+}
+
+// MalformedError は field（CSV の本文など）の line 行目の形式が不正であることを表す
+func MalformedError(field string, line int) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationMalformed,
+		Params:  map[string]interface{}{"line": line},
+		Message: fmt.Sprintf("%s is malformed at line %d", field, line),
+	}
+}
+
+// InUseError は field（アイテムや子カテゴリー）が属しているため、削除や変更ができないことを表す
+func InUseError(field string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    ValidationInUse,
+		Message: fmt.Sprintf("%s still belong to it", field),
+	}
+}
@@ -7,6 +7,12 @@ var (
 	ErrInvalidInput   = errors.New("invalid input")
 	ErrDatabaseError  = errors.New("database error")
 	ErrDuplicateEntry = errors.New("duplicate entry")
+	// ErrVersionMismatch は楽観的ロックのバージョンが一致しなかったことを表す
+	ErrVersionMismatch  = errors.New("version mismatch")
+	ErrBrandNotFound    = errors.New("brand not found")
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrInUse は他のデータから参照されているため削除できないことを表す
+	ErrInUse = errors.New("in use")
 )
 
 func IsNotFoundError(err error) bool {
@@ -20,3 +26,7 @@ func IsDatabaseError(err error) bool {
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrInvalidInput)
 }
+
+func IsVersionMismatchError(err error) bool {
+	return errors.Is(err, ErrVersionMismatch)
+}
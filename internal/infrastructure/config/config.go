@@ -3,17 +3,57 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// DB_DRIVER に指定できるデータベース
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverSQLite   = "sqlite"
+	DBDriverPostgres = "postgres"
+)
+
 var (
+	// 使用するデータベース（デフォルト mysql）と、sqlite の場合のデータベースファイルのパス
+	DBDriver   string
+	SQLitePath string
+
 	DBUser     string
 	DBPassword string
 	DBHost     string
 	DBName     string
 	DBPort     string
+	// PostgreSQL の sslmode（デフォルト disable）
+	DBSSLMode string
+
+	// 起動時にスキーマのマイグレーションを適用するか（デフォルト true）と、サンプルデータを投入するか（デフォルト false）
+	DBAutoMigrate bool
+	DBSeed        bool
+
+	// カーソル方式ページングの署名鍵（未設定の場合は起動ごとにランダム生成）
+	CursorSecret string
+
+	// ゴミ箱の保存期間と、期限切れアイテムを物理削除する間隔（0 で無効）
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Accept-Language に対応する言語が無い場合のエラーメッセージの言語（デフォルト en）
+	DefaultLanguage string
+
+	// ブランドがカタログに無い場合の扱い（strict: エラーにする、lenient: 自由入力として受け付ける。デフォルト lenient）
+	BrandResolution string
+
+	// 他のインスタンスでのカテゴリーの変更を反映するため、カテゴリーのキャッシュを読み込み直す間隔（0 で無効）
+	CategoryRefreshInterval time.Duration
+
+	// カテゴリーの変更や他のインスタンスでの書き込みを反映するため、ファセットの索引を作り直す間隔（0 で無効）
+	ItemIndexRebuildInterval time.Duration
 )
 
 func init() {
@@ -22,11 +62,67 @@ func init() {
 		log.Println("⚠️  .envファイルが見つかりませんでした。")
 	}
 
+	DBDriver = getString("DB_DRIVER", DBDriverMySQL)
+	SQLitePath = getString("SQLITE_PATH", "items.db")
+
 	DBUser = os.Getenv("DB_USER")
 	DBPassword = os.Getenv("DB_PASSWORD")
 	DBHost = os.Getenv("DB_HOST")
 	DBPort = os.Getenv("DB_PORT")
 	DBName = os.Getenv("DB_NAME")
+	DBSSLMode = getString("DB_SSLMODE", "disable")
+
+	DBAutoMigrate = getBool("DB_AUTO_MIGRATE", true)
+	DBSeed = getBool("DB_SEED", false)
+
+	CursorSecret = os.Getenv("CURSOR_SECRET")
+
+	TrashRetention = getDuration("TRASH_RETENTION", 30*24*time.Hour)
+	TrashPurgeInterval = getDuration("TRASH_PURGE_INTERVAL", time.Hour)
+
+	DefaultLanguage = getString("DEFAULT_LANGUAGE", "en")
+
+	BrandResolution = getString("BRAND_RESOLUTION", "lenient")
+
+	CategoryRefreshInterval = getDuration("CATEGORY_REFRESH_INTERVAL", time.Minute)
+
+	ItemIndexRebuildInterval = getDuration("ITEM_INDEX_REBUILD_INTERVAL", 5*time.Minute)
+}
+
+// 環境変数を文字列として読み込む（未設定の場合は fallback）
+func getString(key string, fallback string) string {
+	if raw := os.Getenv(key); raw != "" {
+		return raw
+	}
+	return fallback
+}
+
+// 環境変数を time.Duration として読み込む（未設定・不正な値の場合は fallback）
+func getDuration(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("⚠️  %s の値が不正です（%v）。デフォルト値 %s を使用します。", key, err, fallback)
+		return fallback
+	}
+	return d
+}
+
+// 環境変数を bool として読み込む（未設定・不正な値の場合は fallback）
+func getBool(key string, fallback bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("⚠️  %s の値が不正です（%v）。デフォルト値 %t を使用します。", key, err, fallback)
+		return fallback
+	}
+	return b
 }
 
 // DB接続文字列を返す
@@ -36,3 +132,15 @@ func GetDSN() string {
 		DBUser, DBPassword, DBHost, DBPort, DBName,
 	)
 }
+
+// PostgreSQL の接続文字列を返す
+func GetPostgresDSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(DBUser, DBPassword),
+		Host:     net.JoinHostPort(DBHost, DBPort),
+		Path:     "/" + DBName,
+		RawQuery: url.Values{"sslmode": {DBSSLMode}}.Encode(),
+	}
+	return u.String()
+}
@@ -0,0 +1,394 @@
+package databaseInfra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/usecase"
+)
+
+// Handler は database.SqlHandler に、マイグレーションなどで必要な接続情報を加えたもの
+type Handler interface {
+	database.SqlHandler
+	DB() *sql.DB
+	Driver() string
+}
+
+// NewSqlHandler は config.DBDriver に応じたデータベースに接続する
+func NewSqlHandler() Handler {
+	var (
+		h   Handler
+		err error
+	)
+	switch config.DBDriver {
+	case config.DBDriverMySQL:
+		h, err = NewMySqlHandler(config.GetDSN())
+	case config.DBDriverSQLite:
+		h, err = NewSQLiteHandler(config.SQLitePath)
+	case config.DBDriverPostgres:
+		h, err = NewPostgresHandler(config.GetPostgresDSN())
+	default:
+		err = fmt.Errorf("unsupported DB_DRIVER: %q", config.DBDriver)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("❌ Failed to connect to database: %v", err))
+	}
+
+	fmt.Printf("✅ Successfully connected to the database! (%s)\n", h.Driver())
+	return h
+}
+
+// トランザクションを ctx に保持するためのキー
+type txKey struct{}
+
+// sql.DB と sql.Tx に共通する操作
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// handler は database/sql を使う SqlHandler の共通実装。
+// ドライバごとの差異は convertArgs、rebind、returningID、returningIDs と txOptions で吸収する
+type handler struct {
+	Conn   *sql.DB
+	driver string
+
+	// ドライバに渡す前に引数を変換する（nil の場合はそのまま渡す）
+	convertArgs func(args []interface{}) []interface{}
+	// ? のプレースホルダをドライバの形式に書き換える（nil の場合はそのまま渡す）
+	rebind func(statement string) string
+	// Insert で LastInsertId の代わりに RETURNING id を使う
+	returningID bool
+	// InsertMany で LastInsertId と RowsAffected の代わりに RETURNING id を使う
+	returningIDs bool
+	// usecase.TxOptions を database/sql のオプションに変換する
+	txOptions func(opt usecase.TxOptions) (*sql.TxOptions, error)
+}
+
+func (h *handler) DB() *sql.DB {
+	return h.Conn
+}
+
+func (h *handler) Driver() string {
+	return h.driver
+}
+
+func (h *handler) args(args []interface{}) []interface{} {
+	if h.convertArgs == nil {
+		return args
+	}
+	return h.convertArgs(args)
+}
+
+func (h *handler) statement(statement string) string {
+	if h.rebind == nil {
+		return statement
+	}
+	return h.rebind(statement)
+}
+
+// ctx にトランザクションがあればそれを、無ければコネクションプールを返す
+func (h *handler) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlTx); ok && tx.handler == h {
+		return tx.tx
+	}
+	return h.Conn
+}
+
+func (h *handler) Execute(ctx context.Context, statement string, args ...interface{}) (database.Result, error) {
+	return execute(ctx, h.executor(ctx), h.statement(statement), h.args(args))
+}
+
+func (h *handler) Query(ctx context.Context, statement string, args ...interface{}) (database.Rows, error) {
+	return query(ctx, h.executor(ctx), h.statement(statement), h.args(args))
+}
+
+func (h *handler) QueryRow(ctx context.Context, statement string, args ...interface{}) database.Row {
+	return &sqlRow{row: h.executor(ctx).QueryRowContext(ctx, h.statement(statement), h.args(args)...)}
+}
+
+func (h *handler) Insert(ctx context.Context, statement string, args ...interface{}) (int64, error) {
+	return h.insert(ctx, h.executor(ctx), statement, args)
+}
+
+// insert は INSERT 文を実行し、採番された id を返す
+func (h *handler) insert(ctx context.Context, exec executor, statement string, args []interface{}) (int64, error) {
+	if h.returningID {
+		var id int64
+		err := exec.QueryRowContext(ctx, h.statement(strings.TrimSpace(statement)+" RETURNING id"), h.args(args)...).Scan(&id)
+		return id, err
+	}
+
+	result, err := exec.ExecContext(ctx, h.statement(statement), h.args(args)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (h *handler) InsertMany(ctx context.Context, statement string, args ...interface{}) ([]int64, error) {
+	return h.insertMany(ctx, h.executor(ctx), statement, args)
+}
+
+// insertMany は複数行の INSERT 文を実行し、採番された id を行の順に返す
+func (h *handler) insertMany(ctx context.Context, exec executor, statement string, args []interface{}) ([]int64, error) {
+	if h.returningIDs {
+		rows, err := exec.QueryContext(ctx, h.statement(strings.TrimSpace(statement)+" RETURNING id"), h.args(args)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		// RETURNING の順序は保証されないが、1つの文の中では id は行の順に採番される
+		slices.Sort(ids)
+		return ids, nil
+	}
+
+	// MySQL（InnoDB）は行数が文から分かる INSERT に連続した id をまとめて割り当て、
+	// LastInsertId はその最初の id を返す（auto_increment_increment が 1 の場合）
+	result, err := exec.ExecContext(ctx, h.statement(statement), h.args(args)...)
+	if err != nil {
+		return nil, err
+	}
+	first, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = first + int64(i)
+	}
+	return ids, nil
+}
+
+func (h *handler) Begin(ctx context.Context, opts ...usecase.TxOptions) (database.Tx, error) {
+	var opt usecase.TxOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	// 既にトランザクション内であればセーブポイントで入れ子にする
+	if parent, ok := ctx.Value(txKey{}).(*sqlTx); ok && parent.handler == h {
+		if opt != (usecase.TxOptions{}) {
+			return nil, errors.New("transaction options cannot be changed in a nested transaction")
+		}
+		nested := &sqlTx{
+			handler:   h,
+			tx:        parent.tx,
+			depth:     parent.depth + 1,
+			savepoint: fmt.Sprintf("sp_%d", parent.depth+1),
+		}
+		if _, err := parent.tx.ExecContext(ctx, "SAVEPOINT "+nested.savepoint); err != nil {
+			return nil, err
+		}
+		return nested, nil
+	}
+
+	txOpts, err := h.txOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := h.Conn.BeginTx(ctx, txOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{handler: h, tx: tx}, nil
+}
+
+func (h *handler) WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...usecase.TxOptions) (err error) {
+	tx, err := h.Begin(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+			return
+		}
+		err = tx.Commit(ctx)
+	}()
+
+	return fn(tx.Context(ctx))
+}
+
+func (h *handler) Close() error {
+	if h.Conn != nil {
+		return h.Conn.Close()
+	}
+	return nil
+}
+
+// defaultTxOptions は分離レベルと読み取り専用をそのまま database/sql に渡す
+func defaultTxOptions(opt usecase.TxOptions) (*sql.TxOptions, error) {
+	isolation, err := isolationLevel(opt.Isolation)
+	if err != nil {
+		return nil, err
+	}
+	return &sql.TxOptions{Isolation: isolation, ReadOnly: opt.ReadOnly}, nil
+}
+
+// isolationLevel は usecase.IsolationLevel を database/sql の分離レベルに変換する
+func isolationLevel(level usecase.IsolationLevel) (sql.IsolationLevel, error) {
+	switch level {
+	case usecase.IsolationDefault:
+		return sql.LevelDefault, nil
+	case usecase.IsolationReadUncommitted:
+		return sql.LevelReadUncommitted, nil
+	case usecase.IsolationReadCommitted:
+		return sql.LevelReadCommitted, nil
+	case usecase.IsolationRepeatableRead:
+		return sql.LevelRepeatableRead, nil
+	case usecase.IsolationSerializable:
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unsupported isolation level: %d", level)
+	}
+}
+
+// sqlTx はトランザクション、または入れ子の場合はその中のセーブポイント
+type sqlTx struct {
+	handler   *handler
+	tx        *sql.Tx
+	depth     int    // 0 が最上位のトランザクション
+	savepoint string // 入れ子の場合のセーブポイント名
+	done      bool
+}
+
+func (t *sqlTx) Execute(ctx context.Context, statement string, args ...interface{}) (database.Result, error) {
+	return execute(ctx, t.tx, t.handler.statement(statement), t.handler.args(args))
+}
+
+func (t *sqlTx) Query(ctx context.Context, statement string, args ...interface{}) (database.Rows, error) {
+	return query(ctx, t.tx, t.handler.statement(statement), t.handler.args(args))
+}
+
+func (t *sqlTx) QueryRow(ctx context.Context, statement string, args ...interface{}) database.Row {
+	return &sqlRow{row: t.tx.QueryRowContext(ctx, t.handler.statement(statement), t.handler.args(args)...)}
+}
+
+func (t *sqlTx) Insert(ctx context.Context, statement string, args ...interface{}) (int64, error) {
+	return t.handler.insert(ctx, t.tx, statement, args)
+}
+
+func (t *sqlTx) InsertMany(ctx context.Context, statement string, args ...interface{}) ([]int64, error) {
+	return t.handler.insertMany(ctx, t.tx, statement, args)
+}
+
+func (t *sqlTx) Context(parent context.Context) context.Context {
+	return context.WithValue(parent, txKey{}, t)
+}
+
+func (t *sqlTx) Commit(ctx context.Context) error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.savepoint != "" {
+		_, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint)
+		return err
+	}
+	return t.tx.Commit()
+}
+
+// Rollback は入れ子の場合、セーブポイントまで巻き戻してから解放する（巻き戻しだけではセーブポイントが残る）。
+// 取り消しは ctx がキャンセルされた後（fn が ctx.Err() で失敗した場合など）にも実行する
+func (t *sqlTx) Rollback(ctx context.Context) error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.savepoint != "" {
+		ctx = context.WithoutCancel(ctx)
+		if _, err := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint); err != nil {
+			return err
+		}
+		_, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint)
+		return err
+	}
+	return t.tx.Rollback()
+}
+
+func execute(ctx context.Context, exec executor, statement string, args []interface{}) (database.Result, error) {
+	result, err := exec.ExecContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlResult{result: result}, nil
+}
+
+func query(ctx context.Context, exec executor, statement string, args []interface{}) (database.Rows, error) {
+	rows, err := exec.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlRows{rows: rows}, nil
+}
+
+type sqlResult struct {
+	result sql.Result
+}
+
+func (r *sqlResult) LastInsertId() (int64, error) {
+	return r.result.LastInsertId()
+}
+
+func (r *sqlResult) RowsAffected() (int64, error) {
+	return r.result.RowsAffected()
+}
+
+type sqlRows struct {
+	rows *sql.Rows
+}
+
+func (r *sqlRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *sqlRows) Scan(dest ...interface{}) error {
+	return r.rows.Scan(dest...)
+}
+
+func (r *sqlRows) Close() error {
+	return r.rows.Close()
+}
+
+func (r *sqlRows) Err() error {
+	return r.rows.Err()
+}
+
+type sqlRow struct {
+	row *sql.Row
+}
+
+func (r *sqlRow) Scan(dest ...interface{}) error {
+	return r.row.Scan(dest...)
+}
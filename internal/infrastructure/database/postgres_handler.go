@@ -0,0 +1,92 @@
+package databaseInfra
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"Aicon-assignment/internal/infrastructure/config"
+)
+
+// PostgresHandler は PostgreSQL（pgx）を使う SqlHandler。
+// リポジトリの ? プレースホルダは $1, $2, ... に書き換え、採番された id は RETURNING id で取得する
+type PostgresHandler struct {
+	handler
+}
+
+// NewPostgresHandler は dsn の PostgreSQL に接続する
+func NewPostgresHandler(dsn string) (*PostgresHandler, error) {
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return newPostgresHandler(conn), nil
+}
+
+func newPostgresHandler(conn *sql.DB) *PostgresHandler {
+	return &PostgresHandler{handler{
+		Conn:         conn,
+		driver:       config.DBDriverPostgres,
+		rebind:       Rebind,
+		returningID:  true,
+		returningIDs: true,
+		txOptions:    defaultTxOptions,
+	}}
+}
+
+// Rebind は statement の ? プレースホルダを PostgreSQL の $1, $2, ... に書き換える。
+// 文字列リテラル、引用符付きの識別子、コメントの中の ? はそのまま残す
+func Rebind(statement string) string {
+	if !strings.Contains(statement, "?") {
+		return statement
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		quote byte // 現在の引用符（' "）、引用符の外では 0
+	)
+	b.Grow(len(statement) + 8)
+
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+
+		if quote != 0 {
+			// '' や "" によるエスケープは、閉じてすぐ開き直したものとして扱えば正しく読み進められる
+			if c == quote {
+				quote = 0
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"':
+			quote = c
+			b.WriteByte(c)
+		case c == '-' && i+1 < len(statement) && statement[i+1] == '-':
+			end := strings.IndexByte(statement[i:], '\n')
+			if end < 0 {
+				end = len(statement) - i
+			}
+			b.WriteString(statement[i : i+end])
+			i += end - 1
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package databaseInfra
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			name:      "正常系: ? を順に $n に置き換える",
+			statement: "SELECT id FROM items WHERE category = ? AND purchase_price >= ? LIMIT ? OFFSET ?",
+			expected:  "SELECT id FROM items WHERE category = $1 AND purchase_price >= $2 LIMIT $3 OFFSET $4",
+		},
+		{
+			name:      "正常系: プレースホルダが無ければそのまま",
+			statement: "SELECT COUNT(*) FROM items",
+			expected:  "SELECT COUNT(*) FROM items",
+		},
+		{
+			name:      "正常系: 文字列リテラルと引用符付き識別子の中の ? は置き換えない",
+			statement: `SELECT 'why?', 'it''s ?', "col?" FROM items WHERE id = ?`,
+			expected:  `SELECT 'why?', 'it''s ?', "col?" FROM items WHERE id = $1`,
+		},
+		{
+			name:      "正常系: コメントの中の ? は置き換えない",
+			statement: "SELECT id -- id = ?\nFROM items WHERE id = ?",
+			expected:  "SELECT id -- id = ?\nFROM items WHERE id = $1",
+		},
+		{
+			name:      "正常系: 日本語を含む文",
+			statement: "UPDATE items SET name = ? WHERE name = 'ロレックス？' AND id = ?",
+			expected:  "UPDATE items SET name = $1 WHERE name = 'ロレックス？' AND id = $2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Rebind(tt.statement))
+		})
+	}
+}
+
+func TestPostgresHandler_Insert(t *testing.T) {
+	ctx := context.Background()
+
+	newHandler := func(t *testing.T) (*PostgresHandler, sqlmock.Sqlmock) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return newPostgresHandler(db), mock
+	}
+
+	t.Run("正常系: RETURNING id で採番された id を返す", func(t *testing.T) {
+		h, mock := newHandler(t)
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO items (name, brand) VALUES ($1, $2) RETURNING id")).
+			WithArgs("デイトナ", "ROLEX").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+		id, err := h.Insert(ctx, "\n        INSERT INTO items (name, brand) VALUES (?, ?)\n    ", "デイトナ", "ROLEX")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: トランザクション内でも書き換えて実行する", func(t *testing.T) {
+		h, mock := newHandler(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET name = $1 WHERE id = $2")).
+			WithArgs("x", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO items (name) VALUES ($1) RETURNING id")).
+			WithArgs("y").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectCommit()
+
+		err := h.WithTx(ctx, func(ctx context.Context) error {
+			if _, err := h.Execute(ctx, "UPDATE items SET name = ? WHERE id = ?", "x", 1); err != nil {
+				return err
+			}
+			id, err := h.Insert(ctx, "INSERT INTO items (name) VALUES (?)", "y")
+			assert.Equal(t, int64(7), id)
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPostgresHandler_InsertMany(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	h := newPostgresHandler(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO items (name) VALUES ($1), ($2), ($3) RETURNING id")).
+		WithArgs("a", "b", "c").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12).AddRow(10).AddRow(11))
+
+	ids, err := h.InsertMany(context.Background(), "INSERT INTO items (name) VALUES (?), (?), (?)", "a", "b", "c")
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10, 11, 12}, ids, "行の順（採番順）に並べて返す")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
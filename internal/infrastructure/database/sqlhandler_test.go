@@ -0,0 +1,283 @@
+package databaseInfra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/usecase"
+)
+
+func newMockHandler(t *testing.T) (*MySqlHandler, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return newMySqlHandler(db), mock
+}
+
+func TestMySqlHandler_WithTx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		fn          func(ctx context.Context, h *MySqlHandler) error
+		expectedErr error
+	}{
+		{
+			name: "正常系: fn が成功するとコミットされる",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE items").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				_, err := h.Execute(ctx, "UPDATE items SET name = ?", "x")
+				return err
+			},
+		},
+		{
+			name: "異常系: fn がエラーを返すとロールバックされる",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE items").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				if _, err := h.Execute(ctx, "UPDATE items SET name = ?", "x"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			expectedErr: errFailed,
+		},
+		{
+			name: "正常系: 入れ子の WithTx はセーブポイントを解放する",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				return h.WithTx(ctx, func(ctx context.Context) error { return nil })
+			},
+		},
+		{
+			name: "正常系: 入れ子の失敗はセーブポイントまで戻して解放し、外側はコミットできる",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				err := h.WithTx(ctx, func(ctx context.Context) error { return errFailed })
+				if !errors.Is(err, errFailed) {
+					return errors.New("nested error was not returned")
+				}
+				return nil
+			},
+		},
+		{
+			name: "正常系: 入れ子の ctx がキャンセルされてもセーブポイントまで戻す",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				nestedCtx, cancel := context.WithCancel(ctx)
+				err := h.WithTx(nestedCtx, func(ctx context.Context) error {
+					cancel()
+					return ctx.Err()
+				})
+				if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "rollback failed") {
+					return fmt.Errorf("unexpected nested error: %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "正常系: 2段の入れ子は深さごとのセーブポイントを使う",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				return h.WithTx(ctx, func(ctx context.Context) error {
+					return h.WithTx(ctx, func(ctx context.Context) error { return nil })
+				})
+			},
+		},
+		{
+			name: "異常系: 入れ子で分離レベルを変更しようとするとエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context, h *MySqlHandler) error {
+				return h.WithTx(ctx, func(ctx context.Context) error { return nil },
+					usecase.TxOptions{Isolation: usecase.IsolationSerializable})
+			},
+			expectedErr: errors.New("transaction options cannot be changed in a nested transaction"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, mock := newMockHandler(t)
+			tt.setupMock(mock)
+
+			err := h.WithTx(context.Background(), func(ctx context.Context) error {
+				return tt.fn(ctx, h)
+			})
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMySqlHandler_WithTx_Panic(t *testing.T) {
+	t.Run("異常系: パニック時はロールバックしてから再度パニックする", func(t *testing.T) {
+		h, mock := newMockHandler(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = h.WithTx(context.Background(), func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: 入れ子でのパニックはセーブポイントと外側の両方を戻す", func(t *testing.T) {
+		h, mock := newMockHandler(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = h.WithTx(context.Background(), func(ctx context.Context) error {
+				return h.WithTx(ctx, func(ctx context.Context) error {
+					panic("boom")
+				})
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMySqlHandler_Begin_Isolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     usecase.TxOptions
+		expected sql.TxOptions
+		wantErr  bool
+	}{
+		{
+			name:     "正常系: デフォルト",
+			opts:     usecase.TxOptions{},
+			expected: sql.TxOptions{Isolation: sql.LevelDefault},
+		},
+		{
+			name:     "正常系: READ COMMITTED",
+			opts:     usecase.TxOptions{Isolation: usecase.IsolationReadCommitted},
+			expected: sql.TxOptions{Isolation: sql.LevelReadCommitted},
+		},
+		{
+			name:     "正常系: SERIALIZABLE かつ読み取り専用",
+			opts:     usecase.TxOptions{Isolation: usecase.IsolationSerializable, ReadOnly: true},
+			expected: sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true},
+		},
+		{
+			name:    "異常系: 未知の分離レベル",
+			opts:    usecase.TxOptions{Isolation: usecase.IsolationLevel(99)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isolationLevel(tt.opts.Isolation)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.Isolation, got)
+
+			h, mock := newMockHandler(t)
+			mock.ExpectBegin()
+			mock.ExpectCommit()
+			tx, err := h.Begin(context.Background(), tt.opts)
+			require.NoError(t, err)
+			assert.NoError(t, tx.Commit(context.Background()))
+			assert.ErrorIs(t, tx.Commit(context.Background()), sql.ErrTxDone)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMySqlHandler_Insert(t *testing.T) {
+	h, mock := newMockHandler(t)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items (name) VALUES (?)")).
+		WithArgs("デイトナ").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	id, err := h.Insert(context.Background(), "INSERT INTO items (name) VALUES (?)", "デイトナ")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySqlHandler_InsertMany(t *testing.T) {
+	t.Run("正常系: 最初の id から影響を受けた行数ぶんの連続した id を返す", func(t *testing.T) {
+		h, mock := newMockHandler(t)
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items (name) VALUES (?), (?), (?)")).
+			WithArgs("a", "b", "c").
+			WillReturnResult(sqlmock.NewResult(5, 3))
+
+		ids, err := h.InsertMany(context.Background(), "INSERT INTO items (name) VALUES (?), (?), (?)", "a", "b", "c")
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{5, 6, 7}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: 実行エラー", func(t *testing.T) {
+		h, mock := newMockHandler(t)
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items (name) VALUES (?)")).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := h.InsertMany(context.Background(), "INSERT INTO items (name) VALUES (?)", "a")
+
+		assert.Error(t, err)
+	})
+}
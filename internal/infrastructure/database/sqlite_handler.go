@@ -0,0 +1,89 @@
+package databaseInfra
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	"Aicon-assignment/internal/usecase"
+)
+
+// SQLite に保存する日時の形式。CURRENT_TIMESTAMP（UTC, "YYYY-MM-DD HH:MM:SS"）と
+// 文字列として大小比較できるよう、time.Time の引数は UTC のこの形式に揃える
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999"
+
+// SQLiteHandler は SQLite（pure Go の modernc.org/sqlite）を使う SqlHandler。
+// MySQL を用意できない開発環境や CI での利用を想定している
+type SQLiteHandler struct {
+	handler
+}
+
+// NewSQLiteHandler は path の SQLite データベースを開く。":memory:" を指定するとメモリ上に作成する
+func NewSQLiteHandler(path string) (*SQLiteHandler, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path)
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite は書き込みを1つずつしか受け付けないため、接続を1本に制限して SQLITE_BUSY を避ける。
+	// メモリ上のデータベースは接続ごとに別物になるため、接続を使い回す必要もある
+	conn.SetMaxOpenConns(1)
+	conn.SetMaxIdleConns(1)
+	conn.SetConnMaxLifetime(0)
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// 複数行の INSERT では LastInsertId が最後の行の id を返すため、InsertMany は RETURNING id（SQLite 3.35 以降）を使う
+	return &SQLiteHandler{handler{
+		Conn:         conn,
+		driver:       config.DBDriverSQLite,
+		convertArgs:  sqliteArgs,
+		returningIDs: true,
+		txOptions:    sqliteTxOptions,
+	}}, nil
+}
+
+// time.Time の引数を sqliteTimeFormat の文字列に変換する
+func sqliteArgs(args []interface{}) []interface{} {
+	converted, copied := args, false
+	for i, arg := range args {
+		var t time.Time
+		switch v := arg.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v == nil {
+				continue
+			}
+			t = *v
+		default:
+			continue
+		}
+
+		// 元の引数を書き換えないよう、最初の変換時にコピーする
+		if !copied {
+			converted, copied = append([]interface{}(nil), args...), true
+		}
+		converted[i] = t.UTC().Format(sqliteTimeFormat)
+	}
+	return converted
+}
+
+// SQLite のトランザクションは常に SERIALIZABLE 相当のため、要求された分離レベルはそれで満たされる
+func sqliteTxOptions(opt usecase.TxOptions) (*sql.TxOptions, error) {
+	if _, err := isolationLevel(opt.Isolation); err != nil {
+		return nil, err
+	}
+	return &sql.TxOptions{ReadOnly: opt.ReadOnly}, nil
+}
@@ -0,0 +1,199 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Migration は1つのバージョンのスキーマ変更
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // 空の場合はロールバック不可
+	Checksum string // Up スクリプトの SHA-256（適用後の改変検知に使用）
+}
+
+// マイグレーションファイル名の形式: 0001_create_items.up.sql / 0001_create_items.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load は fsys 直下のマイグレーションファイルを読み込み、バージョンの昇順で返す
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(body)
+			m.Checksum = checksum(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func checksum(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitStatements はスクリプトを ; 区切りの文に分割する。
+// 文字列リテラル・引用符付き識別子・コメント・トリガー本体・PostgreSQL のドル引用（$$ ... $$）の中の ; は
+// 区切りとして扱わない
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      rune // 現在の引用符（' " `）、引用符の外では 0
+	)
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			current.WriteRune(r)
+			if r == '\\' && quote != '`' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current.WriteRune(r)
+		case r == '$' && dollarTag(runes[i:]) != nil:
+			// 関数本体などのドル引用は、閉じるタグまでそのまま読み進める
+			tag := dollarTag(runes[i:])
+			current.WriteString(string(tag))
+			i += len(tag)
+			for i < len(runes) && !hasRunePrefix(runes[i:], tag) {
+				current.WriteRune(runes[i])
+				i++
+			}
+			if i < len(runes) {
+				current.WriteString(string(tag))
+				i += len(tag) - 1
+			}
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			// 行末までのコメントは読み飛ばす
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// ブロックコメントは */ まで読み飛ばす
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i++
+			current.WriteRune(' ')
+		case r == ';' && inTriggerBody(current.String()):
+			// CREATE TRIGGER の本体（BEGIN ... END）の中の ; は文の区切りではない
+			current.WriteRune(r)
+		case r == ';':
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// stmt が BEGIN ... END の本体を持つ CREATE TRIGGER（SQLite）で、まだ本体の END に達していないかを判定する。
+// PostgreSQL の CREATE TRIGGER ... EXECUTE FUNCTION は本体を持たないため対象外
+func inTriggerBody(stmt string) bool {
+	fields := strings.Fields(strings.ToUpper(stmt))
+	if len(fields) < 2 || fields[0] != "CREATE" {
+		return false
+	}
+	// CREATE TEMP TRIGGER などの修飾子を許容する
+	isTrigger := fields[1] == "TRIGGER" || (len(fields) > 2 && fields[2] == "TRIGGER")
+	if !isTrigger || !slices.Contains(fields, "BEGIN") {
+		return false
+	}
+	return fields[len(fields)-1] != "END"
+}
+
+// runes の先頭がドル引用の開始（$$ または $tag$）であればそのタグを返す。
+// $1 のようなプレースホルダは対象外
+func dollarTag(runes []rune) []rune {
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '$':
+			return runes[:i+1]
+		case r == '_' || unicode.IsLetter(r) || (i > 1 && unicode.IsDigit(r)):
+			continue
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func hasRunePrefix(runes, prefix []rune) bool {
+	return len(runes) >= len(prefix) && slices.Equal(runes[:len(prefix)], prefix)
+}
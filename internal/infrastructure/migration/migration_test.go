@@ -0,0 +1,269 @@
+package migration
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/infrastructure/config"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsys        fstest.MapFS
+		expected    []Migration
+		expectedErr string
+	}{
+		{
+			name: "正常系: バージョン順に up と down をまとめて読み込む",
+			fsys: fstest.MapFS{
+				"0002_add_notes.up.sql":   {Data: []byte("ALTER TABLE items ADD notes TEXT;")},
+				"0001_baseline.up.sql":    {Data: []byte("CREATE TABLE items (id INT);")},
+				"0001_baseline.down.sql":  {Data: []byte("DROP TABLE items;")},
+				"0002_add_notes.down.sql": {Data: []byte("ALTER TABLE items DROP notes;")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "baseline", Up: "CREATE TABLE items (id INT);", Down: "DROP TABLE items;", Checksum: checksum([]byte("CREATE TABLE items (id INT);"))},
+				{Version: 2, Name: "add_notes", Up: "ALTER TABLE items ADD notes TEXT;", Down: "ALTER TABLE items DROP notes;", Checksum: checksum([]byte("ALTER TABLE items ADD notes TEXT;"))},
+			},
+		},
+		{
+			name: "正常系: down が無いマイグレーションも読み込める",
+			fsys: fstest.MapFS{
+				"0001_baseline.up.sql": {Data: []byte("CREATE TABLE items (id INT);")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "baseline", Up: "CREATE TABLE items (id INT);", Checksum: checksum([]byte("CREATE TABLE items (id INT);"))},
+			},
+		},
+		{
+			name: "異常系: up が無い",
+			fsys: fstest.MapFS{
+				"0001_baseline.down.sql": {Data: []byte("DROP TABLE items;")},
+			},
+			expectedErr: "has no up script",
+		},
+		{
+			name: "異常系: 同じバージョンで名前が異なる",
+			fsys: fstest.MapFS{
+				"0001_baseline.up.sql": {Data: []byte("CREATE TABLE items (id INT);")},
+				"0001_other.up.sql":    {Data: []byte("CREATE TABLE others (id INT);")},
+			},
+			expectedErr: "duplicate migration version 1",
+		},
+		{
+			name: "異常系: ファイル名の形式が不正",
+			fsys: fstest.MapFS{
+				"baseline.sql": {Data: []byte("CREATE TABLE items (id INT);")},
+			},
+			expectedErr: "invalid migration file name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.fsys)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, migrations)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMySQL, config.DBDriverSQLite, config.DBDriverPostgres} {
+		t.Run("正常系: 埋め込みのマイグレーションがすべて読み込める: "+driver, func(t *testing.T) {
+			m, err := NewMigrator(nil, driver)
+			require.NoError(t, err)
+
+			migrations, err := Load(m.migrations)
+			require.NoError(t, err)
+			require.NotEmpty(t, migrations)
+			assert.Equal(t, int64(1), migrations[0].Version)
+			for _, mig := range migrations {
+				assert.NotEmpty(t, mig.Down, "%04d_%s has no down script", mig.Version, mig.Name)
+			}
+		})
+	}
+
+	t.Run("正常系: すべてのデータベースで同じバージョンのマイグレーションを持つ", func(t *testing.T) {
+		versions := func(driver string) []string {
+			m, err := NewMigrator(nil, driver)
+			require.NoError(t, err)
+			migrations, err := Load(m.migrations)
+			require.NoError(t, err)
+			var names []string
+			for _, mig := range migrations {
+				names = append(names, fmt.Sprintf("%04d_%s", mig.Version, mig.Name))
+			}
+			return names
+		}
+		assert.Equal(t, versions(config.DBDriverMySQL), versions(config.DBDriverSQLite))
+		assert.Equal(t, versions(config.DBDriverMySQL), versions(config.DBDriverPostgres))
+	})
+
+	t.Run("異常系: 未対応のドライバ", func(t *testing.T) {
+		_, err := NewMigrator(nil, "oracle")
+		assert.Error(t, err)
+	})
+}
+
+// 0001_baseline より前に sql/init.sql で作成していた items の定義
+const legacyItemsDDL = `CREATE TABLE IF NOT EXISTS items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'Item name',
+    category VARCHAR(50) NOT NULL COMMENT 'Item category: 時計, バッグ, ジュエリー, 靴, その他',
+    brand VARCHAR(100) NOT NULL COMMENT 'Brand name',
+    purchase_price INT NOT NULL DEFAULT 0 COMMENT 'Purchase price in yen',
+    purchase_date DATE NOT NULL COMMENT 'Purchase date in YYYY-MM-DD format',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp',
+
+    INDEX idx_category (category),
+    INDEX idx_brand (brand),
+    INDEX idx_purchase_date (purchase_date),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Table for managing valuable items and collections'`
+
+var (
+	ddlColumnPattern = regexp.MustCompile(`(?m)^\s+([a-z_]+) [A-Z]`)
+	ddlIndexPattern  = regexp.MustCompile(`(?m)^\s+INDEX ([a-z_]+) \(`)
+)
+
+func TestMySQLMigrations_LegacyItems(t *testing.T) {
+	m, err := NewMigrator(nil, config.DBDriverMySQL)
+	require.NoError(t, err)
+	migrations, err := Load(m.migrations)
+	require.NoError(t, err)
+
+	var baseline string
+	for _, stmt := range splitStatements(migrations[0].Up) {
+		if strings.HasPrefix(stmt, "CREATE TABLE IF NOT EXISTS items (") {
+			baseline = stmt
+		}
+	}
+	require.NotEmpty(t, baseline)
+
+	// init.sql で作成した items は 0001_baseline の CREATE TABLE IF NOT EXISTS では変更されないため、
+	// 0001_baseline にあって init.sql に無い列と索引は、後続のマイグレーションが無い場合だけ追加する必要がある
+	missing := func(pattern *regexp.Regexp) []string {
+		legacy := make(map[string]bool)
+		for _, match := range pattern.FindAllStringSubmatch(legacyItemsDDL, -1) {
+			legacy[match[1]] = true
+		}
+		var names []string
+		for _, match := range pattern.FindAllStringSubmatch(baseline, -1) {
+			if !legacy[match[1]] {
+				names = append(names, match[1])
+			}
+		}
+		return names
+	}
+	addedLater := func(check, add string) bool {
+		for _, mig := range migrations[1:] {
+			if strings.Contains(mig.Up, check) && strings.Contains(mig.Up, add) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("正常系: init.sql に無い列を information_schema で確かめてから追加する", func(t *testing.T) {
+		columns := missing(ddlColumnPattern)
+		assert.Equal(t, []string{"version", "deleted_at"}, columns)
+		for _, column := range columns {
+			assert.True(t, addedLater("COLUMN_NAME = '"+column+"'", "ADD COLUMN "+column+" "), "no migration adds items.%s", column)
+		}
+	})
+
+	t.Run("正常系: init.sql に無い索引を information_schema で確かめてから追加する", func(t *testing.T) {
+		indexes := missing(ddlIndexPattern)
+		assert.Equal(t, []string{"idx_deleted_at"}, indexes)
+		for _, index := range indexes {
+			assert.True(t, addedLater("INDEX_NAME = '"+index+"'", "ADD INDEX "+index+" "), "no migration adds index %s", index)
+		}
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "正常系: ; で分割し空の文は除く",
+			script:   "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n;",
+			expected: []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:     "正常系: 最後の ; が無くてもよい",
+			script:   "DROP TABLE a",
+			expected: []string{"DROP TABLE a"},
+		},
+		{
+			name:     "正常系: 文字列リテラル内の ; は区切らない",
+			script:   "INSERT INTO a VALUES ('x;y', \"p;q\", 'it\\'s;');",
+			expected: []string{"INSERT INTO a VALUES ('x;y', \"p;q\", 'it\\'s;')"},
+		},
+		{
+			name:     "正常系: コメント内の ; は区切らず、コメントは取り除く",
+			script:   "-- first; comment\nCREATE TABLE a (id INT); /* block; comment */ DROP TABLE b;",
+			expected: []string{"CREATE TABLE a (id INT)", "DROP TABLE b"},
+		},
+		{
+			name:     "正常系: 引用符付き識別子内の ; は区切らない",
+			script:   "SELECT `a;b` FROM t;",
+			expected: []string{"SELECT `a;b` FROM t"},
+		},
+		{
+			name: "正常系: トリガー本体の ; は区切らない",
+			script: "CREATE TRIGGER trg AFTER UPDATE ON items FOR EACH ROW BEGIN\n" +
+				"    UPDATE items SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;\nEND;\nDROP TABLE a;",
+			expected: []string{
+				"CREATE TRIGGER trg AFTER UPDATE ON items FOR EACH ROW BEGIN\n    UPDATE items SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;\nEND",
+				"DROP TABLE a",
+			},
+		},
+		{
+			name: "正常系: ドル引用の関数本体の ; は区切らない",
+			script: "CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n    NEW.a := 1;\n    RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\n" +
+				"SELECT $body$ x; $$ $body$;",
+			expected: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n    NEW.a := 1;\n    RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+				"SELECT $body$ x; $$ $body$",
+			},
+		},
+		{
+			name: "正常系: 本体を持たないトリガーは ; で区切る",
+			script: "CREATE TRIGGER trg BEFORE UPDATE ON items FOR EACH ROW EXECUTE FUNCTION f();\n" +
+				"UPDATE items SET name = $1 WHERE id = $2;",
+			expected: []string{
+				"CREATE TRIGGER trg BEFORE UPDATE ON items FOR EACH ROW EXECUTE FUNCTION f()",
+				"UPDATE items SET name = $1 WHERE id = $2",
+			},
+		},
+		{
+			name:     "正常系: 日本語を含む文",
+			script:   "INSERT INTO items (name) VALUES ('ロレックス；デイトナ');",
+			expected: []string{"INSERT INTO items (name) VALUES ('ロレックス；デイトナ')"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitStatements(tt.script))
+		})
+	}
+}
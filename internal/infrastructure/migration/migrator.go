@@ -0,0 +1,430 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"Aicon-assignment/internal/infrastructure/config"
+	databaseInfra "Aicon-assignment/internal/infrastructure/database"
+)
+
+//go:embed mysql sqlite postgres
+var files embed.FS
+
+// 複数のアプリケーションインスタンスが同時に起動しても競合しないよう取得する名前付きロック
+const lockName = "aicon_schema_migrations"
+
+// dialect はデータベースごとのマイグレーション管理の差異
+type dialect struct {
+	name                  string // 埋め込みファイルのディレクトリ名
+	createMigrationsTable string
+	// lock は conn 上で排他ロックを取得し、unlock は ok（fn が成功したか）に応じて解放する
+	lock   func(ctx context.Context, conn *sql.Conn, timeout time.Duration) error
+	unlock func(ctx context.Context, conn *sql.Conn, ok bool) error
+	// ? のプレースホルダをデータベースの形式に書き換える（nil の場合はそのまま）
+	rebind func(query string) string
+}
+
+func (d dialect) bind(query string) string {
+	if d.rebind == nil {
+		return query
+	}
+	return d.rebind(query)
+}
+
+// MySQL では GET_LOCK による名前付きロックで排他する。DDL は暗黙的にコミットされるためトランザクションは使わない
+var mysqlDialect = dialect{
+	name: config.DBDriverMySQL,
+	createMigrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	lock: func(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(timeout.Seconds())).Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return ErrLockTimeout
+		}
+		return nil
+	},
+	unlock: func(ctx context.Context, conn *sql.Conn, _ bool) error {
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName)
+		return err
+	},
+}
+
+// SQLite では BEGIN IMMEDIATE で書き込みロックを取り、すべての変更を1つのトランザクションで適用する。
+// SQLite の DDL はトランザクションに含められるため、失敗した場合は何も適用されない
+var sqliteDialect = dialect{
+	name: config.DBDriverSQLite,
+	createMigrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+	lock: func(ctx context.Context, conn *sql.Conn, _ time.Duration) error {
+		_, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+		return err
+	},
+	unlock: func(ctx context.Context, conn *sql.Conn, ok bool) error {
+		if ok {
+			_, err := conn.ExecContext(ctx, "COMMIT")
+			return err
+		}
+		_, err := conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	},
+}
+
+// PostgreSQL ではアドバイザリロックで排他し、SQLite と同じくすべての変更を1つのトランザクションで適用する
+var postgresDialect = dialect{
+	name: config.DBDriverPostgres,
+	createMigrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+	lock: func(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+		// pg_advisory_lock は待ち時間を指定できないため、pg_try_advisory_lock を繰り返す
+		deadline := time.Now().Add(timeout)
+		for {
+			var acquired bool
+			if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockName).Scan(&acquired); err != nil {
+				return err
+			}
+			if acquired {
+				break
+			}
+			if time.Now().After(deadline) {
+				return ErrLockTimeout
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+
+		if _, err := conn.ExecContext(ctx, "BEGIN"); err != nil {
+			_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+			return err
+		}
+		return nil
+	},
+	unlock: func(ctx context.Context, conn *sql.Conn, ok bool) error {
+		end := "ROLLBACK"
+		if ok {
+			end = "COMMIT"
+		}
+		_, err := conn.ExecContext(ctx, end)
+		if _, unlockErr := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName); err == nil {
+			err = unlockErr
+		}
+		return err
+	},
+	rebind: databaseInfra.Rebind,
+}
+
+var (
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	ErrUnknownVersion   = errors.New("database has a migration unknown to this build")
+	ErrLockTimeout      = errors.New("timed out waiting for the migration lock")
+	ErrIrreversible     = errors.New("migration has no down script")
+)
+
+// Status は1つのマイグレーションの適用状況
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // 適用後にファイルが変更されている
+}
+
+// Migrator は埋め込まれたマイグレーションを schema_migrations で管理しながら適用する
+type Migrator struct {
+	db          *sql.DB
+	dialect     dialect
+	migrations  fs.FS
+	seeds       fs.FS
+	lockTimeout time.Duration
+}
+
+// NewMigrator は driver（config.DBDriver の値）用の埋め込みマイグレーションを使う Migrator を生成する
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	var d dialect
+	switch driver {
+	case config.DBDriverMySQL:
+		d = mysqlDialect
+	case config.DBDriverSQLite:
+		d = sqliteDialect
+	case config.DBDriverPostgres:
+		d = postgresDialect
+	default:
+		return nil, fmt.Errorf("unsupported driver for migrations: %q", driver)
+	}
+
+	migrations, err := fs.Sub(files, d.name+"/migrations")
+	if err != nil {
+		return nil, err
+	}
+	seeds, err := fs.Sub(files, d.name+"/seeds")
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, d, migrations, seeds), nil
+}
+
+func newMigrator(db *sql.DB, d dialect, migrations, seeds fs.FS) *Migrator {
+	return &Migrator{
+		db:          db,
+		dialect:     d,
+		migrations:  migrations,
+		seeds:       seeds,
+		lockTimeout: 30 * time.Second,
+	}
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Up は未適用のマイグレーションをバージョン順にすべて適用し、適用したものを返す。
+// MySQL の DDL は暗黙的にコミットされるため、途中で失敗したマイグレーションは記録されず、
+// 部分的に適用された変更は手動で戻す必要がある（SQLite と PostgreSQL では全体がロールバックされる）
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	migrations, err := Load(m.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.verify(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := execScript(ctx, conn, mig.Up); err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx,
+				m.dialect.bind("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)"),
+				mig.Version, mig.Name, mig.Checksum,
+			); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down は適用済みのマイグレーションを新しい順に steps 件だけ取り消し、取り消したものを返す
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be 1 or greater")
+	}
+
+	migrations, err := Load(m.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.verify(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("%w: %04d_%s", ErrIrreversible, mig.Version, mig.Name)
+			}
+			if err := execScript(ctx, conn, mig.Down); err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, m.dialect.bind("DELETE FROM schema_migrations WHERE version = ?"), mig.Version); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status はすべてのマイグレーションの適用状況をバージョン順に返す
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := Load(m.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool)
+		for _, mig := range migrations {
+			known[mig.Version] = true
+			status := Status{Version: mig.Version, Name: mig.Name}
+			if a, ok := applied[mig.Version]; ok {
+				appliedAt := a.appliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Modified = a.checksum != mig.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		// このビルドに含まれないバージョンも表示する
+		for version, a := range applied {
+			if known[version] {
+				continue
+			}
+			appliedAt := a.appliedAt
+			statuses = append(statuses, Status{Version: version, Name: a.name, Applied: true, AppliedAt: &appliedAt})
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Seed は動作確認用のサンプルデータを投入する。
+// seed スクリプトは何度実行しても重複しないように書かれている前提
+func (m *Migrator) Seed(ctx context.Context) error {
+	entries, err := fs.ReadDir(m.seeds, ".")
+	if err != nil {
+		return err
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			body, err := fs.ReadFile(m.seeds, entry.Name())
+			if err != nil {
+				return err
+			}
+			if err := execScript(ctx, conn, string(body)); err != nil {
+				return fmt.Errorf("seed %s failed: %w", entry.Name(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// verify は適用済みのマイグレーションがこのビルドのファイルと一致しているかを検証する
+func (m *Migrator) verify(ctx context.Context, conn *sql.Conn, migrations []Migration) (map[int64]appliedMigration, error) {
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	for version, a := range applied {
+		mig, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrUnknownVersion, version, a.name)
+		}
+		if mig.Checksum != a.checksum {
+			return nil, fmt.Errorf("%w: %04d_%s has been modified after it was applied", ErrChecksumMismatch, version, mig.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var (
+			version int64
+			a       appliedMigration
+		)
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// withLock は1本のコネクション上でロックを取得し、schema_migrations を用意してから fn を実行する。
+// ロックはコネクション単位なので、fn 内の文はすべて conn で実行する必要がある
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.dialect.lock(ctx, conn, m.lockTimeout); err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := m.dialect.unlock(context.Background(), conn, err == nil); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createMigrationsTable); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package migration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"0001_baseline.up.sql":    {Data: []byte("CREATE TABLE items (id INT);")},
+	"0001_baseline.down.sql":  {Data: []byte("DROP TABLE items;")},
+	"0002_add_notes.up.sql":   {Data: []byte("ALTER TABLE items ADD notes TEXT;")},
+	"0002_add_notes.down.sql": {Data: []byte("ALTER TABLE items DROP notes;")},
+}
+
+var testSeeds = fstest.MapFS{
+	"0001_sample.sql": {Data: []byte("INSERT INTO items (id) SELECT 1 WHERE NOT EXISTS (SELECT 1 FROM items);")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return newMigrator(db, mysqlDialect, testMigrations, testSeeds), mock
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+		WithArgs(lockName, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).
+		WithArgs(lockName).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func appliedRows(versions ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	migrations, _ := Load(testMigrations)
+	for _, v := range versions {
+		for _, m := range migrations {
+			if m.Version == v {
+				rows.AddRow(m.Version, m.Name, m.Checksum, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			}
+		}
+	}
+	return rows
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(mock sqlmock.Sqlmock)
+		expectedApply []int64
+		expectedErr   error
+	}{
+		{
+			name: "正常系: 未適用のマイグレーションだけを順に適用する",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+					WillReturnRows(appliedRows(1))
+				mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE items ADD notes TEXT")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(int64(2), "add_notes", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectUnlock(mock)
+			},
+			expectedApply: []int64{2},
+		},
+		{
+			name: "正常系: すべて適用済みなら何もしない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+					WillReturnRows(appliedRows(1, 2))
+				expectUnlock(mock)
+			},
+		},
+		{
+			name: "異常系: 適用済みのファイルが変更されている",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+						AddRow(1, "baseline", "0000", time.Now()))
+				expectUnlock(mock)
+			},
+			expectedErr: ErrChecksumMismatch,
+		},
+		{
+			name: "異常系: データベースにこのビルドが知らないバージョンがある",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+					WillReturnRows(appliedRows(1, 2).AddRow(3, "future", "ffff", time.Now()))
+				expectUnlock(mock)
+			},
+			expectedErr: ErrUnknownVersion,
+		},
+		{
+			name: "異常系: ロックを取得できない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+					WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+			},
+			expectedErr: ErrLockTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := newTestMigrator(t)
+			tt.setupMock(mock)
+
+			applied, err := m.Up(context.Background())
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				var versions []int64
+				for _, a := range applied {
+					versions = append(versions, a.Version)
+				}
+				assert.Equal(t, tt.expectedApply, versions)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Run("正常系: 新しい順に指定件数だけ取り消す", func(t *testing.T) {
+		m, mock := newTestMigrator(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+			WillReturnRows(appliedRows(1, 2))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE items DROP notes")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = ?")).
+			WithArgs(int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectUnlock(mock)
+
+		reverted, err := m.Down(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, int64(2), reverted[0].Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: steps が 0", func(t *testing.T) {
+		m, _ := newTestMigrator(t)
+
+		_, err := m.Down(context.Background(), 0)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	t.Run("正常系: 適用済み・未適用・変更ありを返す", func(t *testing.T) {
+		m, mock := newTestMigrator(t)
+		expectLock(mock)
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+				AddRow(1, "baseline", "0000", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		expectUnlock(mock)
+
+		statuses, err := m.Status(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		assert.True(t, statuses[0].Applied)
+		assert.True(t, statuses[0].Modified)
+		assert.False(t, statuses[1].Applied)
+		assert.Nil(t, statuses[1].AppliedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Seed(t *testing.T) {
+	t.Run("正常系: seed スクリプトをロック内で実行する", func(t *testing.T) {
+		m, mock := newTestMigrator(t)
+		expectLock(mock)
+		mock.ExpectExec("INSERT INTO items").WillReturnResult(sqlmock.NewResult(1, 1))
+		expectUnlock(mock)
+
+		err := m.Seed(context.Background())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Up_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	m := newMigrator(db, postgresDialect, testMigrations, testSeeds)
+
+	t.Run("正常系: アドバイザリロックとトランザクションの中で適用し、プレースホルダを書き換える", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock(hashtext($1))")).
+			WithArgs(lockName).
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(true))
+		mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+			WillReturnRows(appliedRows(1))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE items ADD notes TEXT")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)")).
+			WithArgs(int64(2), "add_notes", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("COMMIT").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).
+			WithArgs(lockName).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		applied, err := m.Up(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, applied, 1)
+		assert.Equal(t, int64(2), applied[0].Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: 失敗した場合はロールバックしてロックを解放する", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock(hashtext($1))")).
+			WithArgs(lockName).
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(true))
+		mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+			WillReturnRows(appliedRows(1))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE items ADD notes TEXT")).WillReturnError(assert.AnError)
+		mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).
+			WithArgs(lockName).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := m.Up(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
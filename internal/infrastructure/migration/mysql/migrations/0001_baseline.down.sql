@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS item_events;
+DROP TABLE IF EXISTS items;
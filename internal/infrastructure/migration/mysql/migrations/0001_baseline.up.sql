@@ -0,0 +1,37 @@
+-- 既存の init.sql で作成済みの環境でもそのまま適用できるよう IF NOT EXISTS を付けている
+
+-- Create items table for managing valuable items and collections
+CREATE TABLE IF NOT EXISTS items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'Item name',
+    category VARCHAR(50) NOT NULL COMMENT 'Item category: 時計, バッグ, ジュエリー, 靴, その他',
+    brand VARCHAR(100) NOT NULL COMMENT 'Brand name',
+    purchase_price INT NOT NULL DEFAULT 0 COMMENT 'Purchase price in yen',
+    purchase_date DATE NOT NULL COMMENT 'Purchase date in YYYY-MM-DD format',
+    version BIGINT NOT NULL DEFAULT 1 COMMENT 'Optimistic lock version, incremented on every update',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp',
+    deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT 'Soft delete timestamp, NULL while the item is active',
+    
+    INDEX idx_category (category),
+    INDEX idx_brand (brand),
+    INDEX idx_purchase_date (purchase_date),
+    INDEX idx_created_at (created_at),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Table for managing valuable items and collections';
+
+-- Create item_events table for the per-item audit trail
+CREATE TABLE IF NOT EXISTS item_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id BIGINT NOT NULL COMMENT 'Target item ID (kept after the item is purged)',
+    action VARCHAR(20) NOT NULL COMMENT 'create, update, delete, restore',
+    actor VARCHAR(100) NOT NULL COMMENT 'Who made the change',
+    request_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'Request ID of the API call that made the change',
+    changes JSON NOT NULL COMMENT 'Changed fields as {"field": {"before": ..., "after": ...}}',
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'When the change was made',
+
+    INDEX idx_item_id (item_id, id),
+    INDEX idx_actor (actor),
+    INDEX idx_action (action),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Audit trail of changes to items';
@@ -0,0 +1,3 @@
+ALTER TABLE items
+    DROP INDEX idx_brand_canonical,
+    DROP COLUMN brand_canonical;
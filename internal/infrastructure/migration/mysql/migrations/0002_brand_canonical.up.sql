@@ -0,0 +1,8 @@
+-- 表記ゆれを吸収したブランド名（entity.CanonicalBrand）。ブランドでの絞り込みはこの列で比較する
+ALTER TABLE items
+    ADD COLUMN brand_canonical VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'Brand name normalized for matching (NFKC, no diacritics, upper case)' AFTER brand,
+    ADD INDEX idx_brand_canonical (brand_canonical);
+
+-- 既存のデータは大文字にそろえるだけの近似値で埋める（アクセント記号などは次の更新で正規形になる）。
+-- updated_at を明示的に指定して ON UPDATE CURRENT_TIMESTAMP で更新日時が変わらないようにする
+UPDATE items SET brand_canonical = UPPER(TRIM(brand)), updated_at = updated_at;
@@ -0,0 +1,8 @@
+ALTER TABLE items
+    DROP FOREIGN KEY fk_items_brand,
+    DROP INDEX idx_brand_id,
+    DROP COLUMN brand_id,
+    MODIFY COLUMN brand_canonical VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'Brand name normalized for matching (NFKC, no diacritics, upper case)';
+
+DROP TABLE IF EXISTS brand_aliases;
+DROP TABLE IF EXISTS brands;
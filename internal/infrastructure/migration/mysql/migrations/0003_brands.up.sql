@@ -0,0 +1,93 @@
+-- ブランドカタログ。正式名と別名はそれぞれ正規形（entity.CanonicalBrand）で一意にする。
+-- 正規形はアプリケーションで求めた値をそのまま比較するため、照合順序は utf8mb4_bin にする
+-- （utf8mb4_unicode_ci では「ハ」と「バ」のように濁点の有無が区別されない）
+CREATE TABLE IF NOT EXISTS brands (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'Official brand name',
+    name_canonical VARCHAR(100) NOT NULL COLLATE utf8mb4_bin COMMENT 'Brand name normalized for matching',
+    country CHAR(2) NOT NULL DEFAULT '' COMMENT 'Country of origin (ISO 3166-1 alpha-2), empty if unknown',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp',
+
+    UNIQUE INDEX uq_name_canonical (name_canonical)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Brand catalog';
+
+CREATE TABLE IF NOT EXISTS brand_aliases (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    brand_id BIGINT NOT NULL COMMENT 'Brand the alias belongs to',
+    alias VARCHAR(100) NOT NULL COMMENT 'Alternative spelling (katakana, abbreviation, ...)',
+    alias_canonical VARCHAR(100) NOT NULL COLLATE utf8mb4_bin COMMENT 'Alias normalized for matching',
+
+    UNIQUE INDEX uq_alias_canonical (alias_canonical),
+    INDEX idx_brand_id (brand_id),
+    CONSTRAINT fk_brand_aliases_brand FOREIGN KEY (brand_id) REFERENCES brands (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Alternative names of brands';
+
+-- brand_canonical も brands と同じく正規形のまま比較する
+ALTER TABLE items
+    MODIFY COLUMN brand_canonical VARCHAR(100) NOT NULL DEFAULT '' COLLATE utf8mb4_bin COMMENT 'Brand name normalized for matching (NFKC, no diacritics, upper case)',
+    ADD COLUMN brand_id BIGINT NULL DEFAULT NULL COMMENT 'Catalog brand, NULL if the brand is not in the catalog' AFTER brand_canonical,
+    ADD INDEX idx_brand_id (brand_id),
+    ADD CONSTRAINT fk_items_brand FOREIGN KEY (brand_id) REFERENCES brands (id);
+
+-- 初期カタログ。正規形は entity.CanonicalBrand で求めた値
+INSERT INTO brands (name, name_canonical, country) VALUES
+    ('ROLEX', 'ROLEX', 'CH'),
+    ('OMEGA', 'OMEGA', 'CH'),
+    ('HERMÈS', 'HERMES', 'FR'),
+    ('LOUIS VUITTON', 'LOUIS VUITTON', 'FR'),
+    ('CHANEL', 'CHANEL', 'FR'),
+    ('Cartier', 'CARTIER', 'FR'),
+    ('Christian Louboutin', 'CHRISTIAN LOUBOUTIN', 'FR'),
+    ('GUCCI', 'GUCCI', 'IT'),
+    ('PRADA', 'PRADA', 'IT'),
+    ('Tiffany & Co.', 'TIFFANY & CO.', 'US'),
+    ('Apple', 'APPLE', 'US');
+
+INSERT INTO brand_aliases (brand_id, alias, alias_canonical)
+    SELECT id, 'ロレックス', 'ロレックス' FROM brands WHERE name_canonical = 'ROLEX'
+    UNION ALL
+    SELECT id, 'オメガ', 'オメガ' FROM brands WHERE name_canonical = 'OMEGA'
+    UNION ALL
+    SELECT id, 'エルメス', 'エルメス' FROM brands WHERE name_canonical = 'HERMES'
+    UNION ALL
+    SELECT id, 'ルイ・ヴィトン', 'ルイ・ヴィトン' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'ルイヴィトン', 'ルイヴィトン' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'LV', 'LV' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'シャネル', 'シャネル' FROM brands WHERE name_canonical = 'CHANEL'
+    UNION ALL
+    SELECT id, 'カルティエ', 'カルティエ' FROM brands WHERE name_canonical = 'CARTIER'
+    UNION ALL
+    SELECT id, 'クリスチャン・ルブタン', 'クリスチャン・ルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'クリスチャンルブタン', 'クリスチャンルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'ルブタン', 'ルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'Louboutin', 'LOUBOUTIN' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'グッチ', 'グッチ' FROM brands WHERE name_canonical = 'GUCCI'
+    UNION ALL
+    SELECT id, 'プラダ', 'プラダ' FROM brands WHERE name_canonical = 'PRADA'
+    UNION ALL
+    SELECT id, 'ティファニー', 'ティファニー' FROM brands WHERE name_canonical = 'TIFFANY & CO.'
+    UNION ALL
+    SELECT id, 'Tiffany', 'TIFFANY' FROM brands WHERE name_canonical = 'TIFFANY & CO.'
+    UNION ALL
+    SELECT id, 'アップル', 'アップル' FROM brands WHERE name_canonical = 'APPLE';
+
+-- 既存のアイテムを、ブランド名の正規形が正式名または別名と一致するブランドに紐づける。
+-- 0002 で埋めた brand_canonical は大文字にそろえただけの近似値のため、大文字にした元の値同士でも比較する。
+-- 紐づいたアイテムの brand_canonical は正式名の正規形にそろえ、ブランドでの絞り込みで同じブランドとして扱われるようにする
+-- updated_at を明示的に指定して ON UPDATE CURRENT_TIMESTAMP で更新日時が変わらないようにする
+UPDATE items SET brand_id = (SELECT id FROM brands WHERE brands.name_canonical = items.brand_canonical), updated_at = updated_at
+WHERE brand_id IS NULL;
+UPDATE items SET brand_id = (SELECT brand_id FROM brand_aliases WHERE brand_aliases.alias_canonical = items.brand_canonical), updated_at = updated_at
+WHERE brand_id IS NULL;
+UPDATE items SET brand_id = (SELECT id FROM brands WHERE UPPER(brands.name) = UPPER(TRIM(items.brand))), updated_at = updated_at
+WHERE brand_id IS NULL;
+UPDATE items SET brand_canonical = (SELECT name_canonical FROM brands WHERE brands.id = items.brand_id), updated_at = updated_at
+WHERE brand_id IS NOT NULL;
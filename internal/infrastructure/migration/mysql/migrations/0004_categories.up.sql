@@ -0,0 +1,26 @@
+-- アイテムのカテゴリー。items.category には categories.name の値を保存する。
+-- 名前はアプリケーションで正規化した値をそのまま比較するため、照合順序は utf8mb4_bin にする
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL COLLATE utf8mb4_bin COMMENT 'Value stored in items.category',
+    display_name_ja VARCHAR(100) NOT NULL COMMENT 'Display name in Japanese',
+    display_name_en VARCHAR(100) NOT NULL COMMENT 'Display name in English',
+    sort_order INT NOT NULL DEFAULT 0 COMMENT 'Display order (ascending)',
+    active BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'Whether new items can use the category',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT 'Record creation timestamp',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Record update timestamp',
+
+    UNIQUE INDEX uq_name (name),
+    INDEX idx_sort_order (sort_order, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Item categories';
+
+ALTER TABLE items
+    MODIFY COLUMN category VARCHAR(50) NOT NULL COMMENT 'Item category (categories.name)';
+
+-- これまで固定だったカテゴリー
+INSERT INTO categories (name, display_name_ja, display_name_en, sort_order) VALUES
+    ('時計', '時計', 'Watches', 10),
+    ('バッグ', 'バッグ', 'Bags', 20),
+    ('ジュエリー', 'ジュエリー', 'Jewelry', 30),
+    ('靴', '靴', 'Shoes', 40),
+    ('その他', 'その他', 'Others', 50);
@@ -0,0 +1,6 @@
+-- カテゴリーの階層。parent_id が NULL のカテゴリーが最上位になり、アイテムは子を持たないカテゴリーにのみ属する。
+-- 子を持つカテゴリーは削除できないよう、親の削除は外部キーでも制限する
+ALTER TABLE categories
+    ADD COLUMN parent_id BIGINT NULL DEFAULT NULL COMMENT 'Parent category, NULL for top-level categories' AFTER name,
+    ADD INDEX idx_parent_id (parent_id),
+    ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS item_attributes;
+ALTER TABLE items DROP COLUMN attributes;
+DROP TABLE IF EXISTS category_attributes;
@@ -0,0 +1,37 @@
+-- カテゴリーごとの属性の定義。子孫のカテゴリーは祖先の定義を引き継ぐ
+CREATE TABLE IF NOT EXISTS category_attributes (
+    category_id BIGINT NOT NULL COMMENT 'Category the attribute is defined on',
+    attr_key VARCHAR(50) NOT NULL COLLATE utf8mb4_bin COMMENT 'Key in items.attributes',
+    attr_type VARCHAR(20) NOT NULL COMMENT 'string, integer, number, boolean or enum',
+    required BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Whether items in the category must have the attribute',
+    options JSON NULL DEFAULT NULL COMMENT 'Allowed values of an enum attribute',
+    unit VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'Unit of the value for display (mm, ct, ...)',
+    position INT NOT NULL DEFAULT 0 COMMENT 'Display order within the category',
+
+    PRIMARY KEY (category_id, attr_key),
+    CONSTRAINT fk_category_attributes_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Attribute schemas of categories';
+
+ALTER TABLE items
+    ADD COLUMN attributes JSON NULL DEFAULT NULL COMMENT 'Category-specific attributes, NULL if none' AFTER purchase_date;
+
+-- 属性での絞り込み用の索引。値は entity.AttributeValueString の文字列で、items.attributes と同じ内容を保つ
+CREATE TABLE IF NOT EXISTS item_attributes (
+    item_id BIGINT NOT NULL COMMENT 'Item the value belongs to',
+    attr_key VARCHAR(50) NOT NULL COLLATE utf8mb4_bin COMMENT 'Key in items.attributes',
+    attr_value VARCHAR(200) NOT NULL COLLATE utf8mb4_bin COMMENT 'Value formatted for matching',
+
+    PRIMARY KEY (item_id, attr_key),
+    INDEX idx_key_value (attr_key, attr_value),
+    CONSTRAINT fk_item_attributes_item FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Index of item attributes for filtering';
+
+-- 既定のカテゴリーの属性。既存のアイテムに影響しないよう、すべて任意にする
+INSERT INTO category_attributes (category_id, attr_key, attr_type, options, unit, position)
+SELECT id, 'reference_number', 'string', NULL, '', 1 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'movement', 'enum', '["自動巻き","手巻き","クォーツ"]', '', 2 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'case_size', 'number', NULL, 'mm', 3 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'metal', 'enum', '["プラチナ","ゴールド","シルバー","その他"]', '', 1 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'carat', 'number', NULL, 'ct', 2 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'stone', 'string', NULL, '', 3 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'size', 'number', NULL, 'cm', 1 FROM categories WHERE name = '靴';
@@ -0,0 +1,2 @@
+ALTER TABLE items DROP INDEX ft_search_text;
+ALTER TABLE items DROP COLUMN search_text, DROP COLUMN notes;
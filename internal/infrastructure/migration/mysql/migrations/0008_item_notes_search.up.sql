@@ -0,0 +1,10 @@
+-- アイテムの自由記述のメモと、全文検索用の列
+ALTER TABLE items
+    ADD COLUMN notes VARCHAR(1000) NOT NULL DEFAULT '' COMMENT 'Free-form notes' AFTER attributes,
+    ADD COLUMN search_text TEXT COLLATE utf8mb4_bin NULL DEFAULT NULL COMMENT 'Name, brand and notes folded by entity.SearchText, NULL until filled' AFTER notes;
+
+-- 全文検索のインデックス。ngram パーサーは既定のストップワード（"a"、"i" など）を含む ngram を索引しないため、
+-- ストップワードを無効にして作成する。既存のアイテムの search_text はアプリケーションの起動時に埋める
+SET SESSION innodb_ft_enable_stopword = OFF;
+ALTER TABLE items ADD FULLTEXT INDEX ft_search_text (search_text) WITH PARSER ngram;
+SET SESSION innodb_ft_enable_stopword = ON;
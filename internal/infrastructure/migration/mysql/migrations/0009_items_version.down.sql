@@ -0,0 +1 @@
+-- version は 0001_baseline で作成した環境にもある列のため、取り消しでは削除しない
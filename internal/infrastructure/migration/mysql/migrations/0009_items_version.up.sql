@@ -0,0 +1,12 @@
+-- 楽観ロックのバージョン（entity.Item.Version）。
+-- 0001_baseline より前の init.sql で作成した items には無く、0001_baseline の CREATE TABLE IF NOT EXISTS では
+-- 追加されないため、information_schema で列が無いことを確かめてから追加する（MySQL には ADD COLUMN IF NOT EXISTS が無い）
+SET @ddl := IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'items' AND COLUMN_NAME = 'version') = 0,
+    'ALTER TABLE items ADD COLUMN version BIGINT NOT NULL DEFAULT 1 COMMENT ''Optimistic lock version, incremented on every update'' AFTER purchase_date',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,21 @@
+-- ゴミ箱（論理削除）の日時。0009_items_version と同じく、init.sql で作成した items には無いため、
+-- information_schema で列と索引が無いことを確かめてから追加する
+SET @ddl := IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'items' AND COLUMN_NAME = 'deleted_at') = 0,
+    'ALTER TABLE items ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL COMMENT ''Soft delete timestamp, NULL while the item is active'' AFTER updated_at',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl := IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'items' AND INDEX_NAME = 'idx_deleted_at') = 0,
+    'ALTER TABLE items ADD INDEX idx_deleted_at (deleted_at)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
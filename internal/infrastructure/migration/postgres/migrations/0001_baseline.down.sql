@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS item_events;
+DROP TABLE IF EXISTS items;
+DROP FUNCTION IF EXISTS items_touch_updated_at();
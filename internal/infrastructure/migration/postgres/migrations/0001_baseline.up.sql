@@ -0,0 +1,53 @@
+-- mysql/migrations/0001_baseline.up.sql と同じスキーマの PostgreSQL 版
+
+-- MySQL の utf8mb4_unicode_ci に合わせ、文字列の比較で大文字小文字を区別しない
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TABLE IF NOT EXISTS items (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name CITEXT NOT NULL,
+    category CITEXT NOT NULL,
+    brand CITEXT NOT NULL,
+    purchase_price INTEGER NOT NULL DEFAULT 0,
+    purchase_date DATE NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_items_category ON items (category);
+CREATE INDEX IF NOT EXISTS idx_items_brand ON items (brand);
+CREATE INDEX IF NOT EXISTS idx_items_purchase_date ON items (purchase_date);
+CREATE INDEX IF NOT EXISTS idx_items_created_at ON items (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
+
+-- MySQL の ON UPDATE CURRENT_TIMESTAMP の代わり。updated_at を明示的に変更した更新では何もしない
+CREATE OR REPLACE FUNCTION items_touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    IF NEW.updated_at = OLD.updated_at THEN
+        NEW.updated_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_items_updated_at ON items;
+CREATE TRIGGER trg_items_updated_at
+BEFORE UPDATE ON items
+FOR EACH ROW EXECUTE FUNCTION items_touch_updated_at();
+
+CREATE TABLE IF NOT EXISTS item_events (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    item_id BIGINT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_events_item_id ON item_events (item_id, id);
+CREATE INDEX IF NOT EXISTS idx_item_events_actor ON item_events (actor);
+CREATE INDEX IF NOT EXISTS idx_item_events_action ON item_events (action);
+CREATE INDEX IF NOT EXISTS idx_item_events_created_at ON item_events (created_at);
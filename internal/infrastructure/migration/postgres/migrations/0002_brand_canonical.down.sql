@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_items_brand_canonical;
+ALTER TABLE items DROP COLUMN IF EXISTS brand_canonical;
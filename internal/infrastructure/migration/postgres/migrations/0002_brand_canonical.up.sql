@@ -0,0 +1,10 @@
+-- mysql/migrations/0002_brand_canonical.up.sql の PostgreSQL 版
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS brand_canonical VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_items_brand_canonical ON items (brand_canonical);
+
+-- 既存のデータを埋める間は updated_at を更新するトリガーを止めておく
+ALTER TABLE items DISABLE TRIGGER trg_items_updated_at;
+UPDATE items SET brand_canonical = UPPER(TRIM(brand));
+ALTER TABLE items ENABLE TRIGGER trg_items_updated_at;
@@ -0,0 +1,87 @@
+-- mysql/migrations/0003_brands.up.sql の PostgreSQL 版
+
+CREATE TABLE IF NOT EXISTS brands (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    name_canonical VARCHAR(100) NOT NULL UNIQUE,
+    country CHAR(2) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS brand_aliases (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    brand_id BIGINT NOT NULL REFERENCES brands (id) ON DELETE CASCADE,
+    alias VARCHAR(100) NOT NULL,
+    alias_canonical VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_brand_aliases_brand_id ON brand_aliases (brand_id);
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS brand_id BIGINT NULL DEFAULT NULL REFERENCES brands (id);
+
+CREATE INDEX IF NOT EXISTS idx_items_brand_id ON items (brand_id);
+
+-- 初期カタログ。正規形は entity.CanonicalBrand で求めた値
+INSERT INTO brands (name, name_canonical, country) VALUES
+    ('ROLEX', 'ROLEX', 'CH'),
+    ('OMEGA', 'OMEGA', 'CH'),
+    ('HERMÈS', 'HERMES', 'FR'),
+    ('LOUIS VUITTON', 'LOUIS VUITTON', 'FR'),
+    ('CHANEL', 'CHANEL', 'FR'),
+    ('Cartier', 'CARTIER', 'FR'),
+    ('Christian Louboutin', 'CHRISTIAN LOUBOUTIN', 'FR'),
+    ('GUCCI', 'GUCCI', 'IT'),
+    ('PRADA', 'PRADA', 'IT'),
+    ('Tiffany & Co.', 'TIFFANY & CO.', 'US'),
+    ('Apple', 'APPLE', 'US');
+
+INSERT INTO brand_aliases (brand_id, alias, alias_canonical)
+    SELECT id, 'ロレックス', 'ロレックス' FROM brands WHERE name_canonical = 'ROLEX'
+    UNION ALL
+    SELECT id, 'オメガ', 'オメガ' FROM brands WHERE name_canonical = 'OMEGA'
+    UNION ALL
+    SELECT id, 'エルメス', 'エルメス' FROM brands WHERE name_canonical = 'HERMES'
+    UNION ALL
+    SELECT id, 'ルイ・ヴィトン', 'ルイ・ヴィトン' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'ルイヴィトン', 'ルイヴィトン' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'LV', 'LV' FROM brands WHERE name_canonical = 'LOUIS VUITTON'
+    UNION ALL
+    SELECT id, 'シャネル', 'シャネル' FROM brands WHERE name_canonical = 'CHANEL'
+    UNION ALL
+    SELECT id, 'カルティエ', 'カルティエ' FROM brands WHERE name_canonical = 'CARTIER'
+    UNION ALL
+    SELECT id, 'クリスチャン・ルブタン', 'クリスチャン・ルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'クリスチャンルブタン', 'クリスチャンルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'ルブタン', 'ルブタン' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'Louboutin', 'LOUBOUTIN' FROM brands WHERE name_canonical = 'CHRISTIAN LOUBOUTIN'
+    UNION ALL
+    SELECT id, 'グッチ', 'グッチ' FROM brands WHERE name_canonical = 'GUCCI'
+    UNION ALL
+    SELECT id, 'プラダ', 'プラダ' FROM brands WHERE name_canonical = 'PRADA'
+    UNION ALL
+    SELECT id, 'ティファニー', 'ティファニー' FROM brands WHERE name_canonical = 'TIFFANY & CO.'
+    UNION ALL
+    SELECT id, 'Tiffany', 'TIFFANY' FROM brands WHERE name_canonical = 'TIFFANY & CO.'
+    UNION ALL
+    SELECT id, 'アップル', 'アップル' FROM brands WHERE name_canonical = 'APPLE';
+
+-- 既存のアイテムを、ブランド名の正規形が正式名または別名と一致するブランドに紐づける。
+-- 0002 で埋めた brand_canonical は大文字にそろえただけの近似値のため、大文字にした元の値同士でも比較する。
+-- 紐づいたアイテムの brand_canonical は正式名の正規形にそろえ、ブランドでの絞り込みで同じブランドとして扱われるようにする
+-- 紐づけの間は updated_at を更新するトリガーを止めておく
+ALTER TABLE items DISABLE TRIGGER trg_items_updated_at;
+UPDATE items SET brand_id = (SELECT id FROM brands WHERE brands.name_canonical = items.brand_canonical)
+WHERE brand_id IS NULL;
+UPDATE items SET brand_id = (SELECT brand_id FROM brand_aliases WHERE brand_aliases.alias_canonical = items.brand_canonical)
+WHERE brand_id IS NULL;
+UPDATE items SET brand_id = (SELECT id FROM brands WHERE UPPER(brands.name) = UPPER(TRIM(items.brand)))
+WHERE brand_id IS NULL;
+UPDATE items SET brand_canonical = (SELECT name_canonical FROM brands WHERE brands.id = items.brand_id)
+WHERE brand_id IS NOT NULL;
+ALTER TABLE items ENABLE TRIGGER trg_items_updated_at;
@@ -0,0 +1,22 @@
+-- mysql/migrations/0004_categories.up.sql の PostgreSQL 版
+
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    display_name_ja VARCHAR(100) NOT NULL,
+    display_name_en VARCHAR(100) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_sort_order ON categories (sort_order, id);
+
+-- これまで固定だったカテゴリー
+INSERT INTO categories (name, display_name_ja, display_name_en, sort_order) VALUES
+    ('時計', '時計', 'Watches', 10),
+    ('バッグ', 'バッグ', 'Bags', 20),
+    ('ジュエリー', 'ジュエリー', 'Jewelry', 30),
+    ('靴', '靴', 'Shoes', 40),
+    ('その他', 'その他', 'Others', 50);
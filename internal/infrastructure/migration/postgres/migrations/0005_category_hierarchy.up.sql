@@ -0,0 +1,5 @@
+-- mysql/migrations/0005_category_hierarchy.up.sql の PostgreSQL 版
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id BIGINT NULL DEFAULT NULL REFERENCES categories (id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
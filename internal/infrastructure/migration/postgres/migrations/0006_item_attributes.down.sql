@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_item_attributes_key_value;
+DROP TABLE IF EXISTS item_attributes;
+ALTER TABLE items DROP COLUMN IF EXISTS attributes;
+DROP TABLE IF EXISTS category_attributes;
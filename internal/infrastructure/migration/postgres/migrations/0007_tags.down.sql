@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_item_tags_tag_id;
+DROP TABLE IF EXISTS item_tags;
+DROP TABLE IF EXISTS tags;
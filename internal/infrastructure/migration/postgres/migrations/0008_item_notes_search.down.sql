@@ -0,0 +1,2 @@
+ALTER TABLE items DROP COLUMN IF EXISTS search_text;
+ALTER TABLE items DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,4 @@
+-- mysql/migrations/0009_items_version.up.sql の PostgreSQL 版。
+-- PostgreSQL の items は常に 0001_baseline で作成されるため、列が既にある場合は何もしない
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
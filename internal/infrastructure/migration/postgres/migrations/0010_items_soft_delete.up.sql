@@ -0,0 +1,6 @@
+-- mysql/migrations/0010_items_soft_delete.up.sql の PostgreSQL 版。
+-- PostgreSQL の items は常に 0001_baseline で作成されるため、列と索引が既にある場合は何もしない
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS item_events;
+DROP TRIGGER IF EXISTS trg_items_updated_at;
+DROP TABLE IF EXISTS items;
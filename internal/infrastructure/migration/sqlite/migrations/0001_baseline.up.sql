@@ -0,0 +1,43 @@
+-- mysql/migrations/0001_baseline.up.sql と同じスキーマの SQLite 版
+
+CREATE TABLE IF NOT EXISTS items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL COLLATE NOCASE,
+    category TEXT NOT NULL COLLATE NOCASE,
+    brand TEXT NOT NULL COLLATE NOCASE,
+    purchase_price INTEGER NOT NULL DEFAULT 0,
+    purchase_date DATE NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_items_category ON items (category);
+CREATE INDEX IF NOT EXISTS idx_items_brand ON items (brand);
+CREATE INDEX IF NOT EXISTS idx_items_purchase_date ON items (purchase_date);
+CREATE INDEX IF NOT EXISTS idx_items_created_at ON items (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
+
+-- MySQL の ON UPDATE CURRENT_TIMESTAMP の代わり。updated_at を明示的に変更した更新では何もしない
+CREATE TRIGGER IF NOT EXISTS trg_items_updated_at
+AFTER UPDATE ON items
+FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE items SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TABLE IF NOT EXISTS item_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    changes TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_events_item_id ON item_events (item_id, id);
+CREATE INDEX IF NOT EXISTS idx_item_events_actor ON item_events (actor);
+CREATE INDEX IF NOT EXISTS idx_item_events_action ON item_events (action);
+CREATE INDEX IF NOT EXISTS idx_item_events_created_at ON item_events (created_at);
@@ -0,0 +1,17 @@
+-- mysql/migrations/0002_brand_canonical.up.sql の SQLite 版
+
+ALTER TABLE items ADD COLUMN brand_canonical TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_items_brand_canonical ON items (brand_canonical);
+
+-- 既存のデータを埋める間は updated_at を更新するトリガーを外しておく
+DROP TRIGGER IF EXISTS trg_items_updated_at;
+
+UPDATE items SET brand_canonical = UPPER(TRIM(brand));
+
+CREATE TRIGGER IF NOT EXISTS trg_items_updated_at
+AFTER UPDATE ON items
+FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+    UPDATE items SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_items_brand_id;
+ALTER TABLE items DROP COLUMN brand_id;
+
+DROP TABLE IF EXISTS brand_aliases;
+DROP TABLE IF EXISTS brands;
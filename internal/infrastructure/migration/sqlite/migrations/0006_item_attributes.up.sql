@@ -0,0 +1,34 @@
+-- mysql/migrations/0006_item_attributes.up.sql の SQLite 版
+
+CREATE TABLE IF NOT EXISTS category_attributes (
+    category_id INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    attr_key TEXT NOT NULL,
+    attr_type TEXT NOT NULL,
+    required INTEGER NOT NULL DEFAULT 0,
+    options TEXT NULL DEFAULT NULL,
+    unit TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (category_id, attr_key)
+);
+
+ALTER TABLE items ADD COLUMN attributes TEXT NULL DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS item_attributes (
+    item_id INTEGER NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    attr_key TEXT NOT NULL,
+    attr_value TEXT NOT NULL,
+
+    PRIMARY KEY (item_id, attr_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_attributes_key_value ON item_attributes (attr_key, attr_value);
+
+INSERT INTO category_attributes (category_id, attr_key, attr_type, options, unit, position)
+SELECT id, 'reference_number', 'string', NULL, '', 1 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'movement', 'enum', '["自動巻き","手巻き","クォーツ"]', '', 2 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'case_size', 'number', NULL, 'mm', 3 FROM categories WHERE name = '時計'
+UNION ALL SELECT id, 'metal', 'enum', '["プラチナ","ゴールド","シルバー","その他"]', '', 1 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'carat', 'number', NULL, 'ct', 2 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'stone', 'string', NULL, '', 3 FROM categories WHERE name = 'ジュエリー'
+UNION ALL SELECT id, 'size', 'number', NULL, 'cm', 1 FROM categories WHERE name = '靴';
@@ -0,0 +1,2 @@
+ALTER TABLE items DROP COLUMN search_text;
+ALTER TABLE items DROP COLUMN notes;
@@ -0,0 +1,5 @@
+-- mysql/migrations/0008_item_notes_search.up.sql の SQLite 版。
+-- 全文検索はプロセス内の索引で行うため、search_text は MySQL と同じ内容を保存するだけでインデックスは作らない
+
+ALTER TABLE items ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE items ADD COLUMN search_text TEXT NULL DEFAULT NULL;
@@ -0,0 +1,3 @@
+-- mysql/migrations/0009_items_version.up.sql の SQLite 版。
+-- SQLite の items は常に 0001_baseline で version を含めて作成されるため、何もしない
+-- （SQLite には ADD COLUMN IF NOT EXISTS が無く、バージョンを MySQL とそろえるためだけのマイグレーション）
@@ -0,0 +1,4 @@
+-- mysql/migrations/0010_items_soft_delete.up.sql の SQLite 版。
+-- SQLite の items は常に 0001_baseline で deleted_at を含めて作成されるため、索引がある場合は何もしない
+
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
@@ -0,0 +1,14 @@
+-- 動作確認用のサンプルデータ。items が空の場合のみ投入する。
+-- ブランドは 0003_brands で登録したカタログに紐づける
+INSERT INTO items (name, category, brand, brand_canonical, brand_id, purchase_price, purchase_date)
+SELECT name, category, brand, brand_canonical,
+    (SELECT id FROM brands WHERE brands.name_canonical = sample.brand_canonical),
+    purchase_price, purchase_date
+FROM (
+    SELECT 'ロレックス デイトナ' AS name, '時計' AS category, 'ROLEX' AS brand, 'ROLEX' AS brand_canonical, 1500000 AS purchase_price, '2023-01-15' AS purchase_date
+    UNION ALL SELECT 'エルメス バーキン', 'バッグ', 'HERMÈS', 'HERMES', 2000000, '2023-02-20'
+    UNION ALL SELECT 'ティファニー ネックレス', 'ジュエリー', 'Tiffany & Co.', 'TIFFANY & CO.', 300000, '2023-03-10'
+    UNION ALL SELECT 'ルブタン パンプス', '靴', 'Christian Louboutin', 'CHRISTIAN LOUBOUTIN', 150000, '2023-04-05'
+    UNION ALL SELECT 'アップルウォッチ', 'その他', 'Apple', 'APPLE', 50000, '2023-05-12'
+) AS sample
+WHERE NOT EXISTS (SELECT 1 FROM items);
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/usecase"
+)
+
+// CategoryRefresher はカテゴリーのキャッシュを定期的に読み込み直し、他のインスタンスでの変更を反映する
+type CategoryRefresher struct {
+	registry *usecase.CategoryRegistry
+	interval time.Duration
+}
+
+func NewCategoryRefresher(registry *usecase.CategoryRegistry, interval time.Duration) *CategoryRefresher {
+	return &CategoryRefresher{
+		registry: registry,
+		interval: interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに読み込み直す。interval が 0 以下の場合は何もしない
+func (r *CategoryRefresher) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.registry.Load(ctx); err != nil {
+				fmt.Printf("❌ Failed to refresh categories: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/usecase"
+)
+
+// IndexRebuilder はファセットの索引を定期的に作り直し、他のインスタンスでの書き込みなど、このインスタンスの usecase を経由しない変更を反映する
+type IndexRebuilder struct {
+	itemUsecase usecase.ItemUsecase
+	interval    time.Duration
+}
+
+func NewIndexRebuilder(itemUsecase usecase.ItemUsecase, interval time.Duration) *IndexRebuilder {
+	return &IndexRebuilder{
+		itemUsecase: itemUsecase,
+		interval:    interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに作り直す。interval が 0 以下の場合は何もしない
+func (r *IndexRebuilder) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.itemUsecase.RebuildIndex(ctx); err != nil {
+				fmt.Printf("❌ Failed to rebuild item index: %v\n", err)
+			}
+		}
+	}
+}
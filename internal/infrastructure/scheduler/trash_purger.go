@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/usecase"
+)
+
+// TrashPurger はゴミ箱の保存期間を過ぎたアイテムを定期的に物理削除する
+type TrashPurger struct {
+	itemUsecase usecase.ItemUsecase
+	retention   time.Duration
+	interval    time.Duration
+}
+
+func NewTrashPurger(itemUsecase usecase.ItemUsecase, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		itemUsecase: itemUsecase,
+		retention:   retention,
+		interval:    interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに削除を実行する。interval が 0 以下の場合は何もしない
+func (p *TrashPurger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		fmt.Println("🗑️  Trash purger is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	// 起動直後にも一度実行する
+	p.purge(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	purged, err := p.itemUsecase.PurgeTrash(ctx, p.retention)
+	if err != nil {
+		fmt.Printf("❌ Failed to purge trash: %v\n", err)
+		return
+	}
+	if purged > 0 {
+		fmt.Printf("🗑️  Purged %d item(s) trashed more than %s ago\n", purged, p.retention)
+	}
+}
@@ -10,36 +10,164 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"Aicon-assignment/internal/infrastructure/config"
 	databaseInfra "Aicon-assignment/internal/infrastructure/database"
+	"Aicon-assignment/internal/infrastructure/migration"
+	"Aicon-assignment/internal/infrastructure/scheduler"
+	auditController "Aicon-assignment/internal/interfaces/controller/audit"
+	brandController "Aicon-assignment/internal/interfaces/controller/brands"
+	categoryController "Aicon-assignment/internal/interfaces/controller/categories"
 	itemController "Aicon-assignment/internal/interfaces/controller/items"
 	"Aicon-assignment/internal/interfaces/controller/system"
 	itemDatabase "Aicon-assignment/internal/interfaces/database"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/interfaces/problem"
 	"Aicon-assignment/internal/usecase"
 )
 
 // サーバー用の構造体
-type Server struct{}
+type Server struct {
+	demo bool
+}
+
+// Option はサーバーの起動オプション
+type Option func(*Server)
+
+// WithDemoMode はデータベースを使わず、サンプルデータを入れたメモリ上のリポジトリで起動する
+func WithDemoMode() Option {
+	return func(s *Server) {
+		s.demo = true
+	}
+}
 
-func NewServer() *Server {
-	return &Server{}
+func NewServer(opts ...Option) *Server {
+	s := &Server{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // サーバー起動
 func (s *Server) Run(ctx context.Context) error {
 	e := echo.New()
+	// ハンドラが返したエラーは application/problem+json で返す
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
 
 	// 依存性注入
-	dbHandler := databaseInfra.NewSqlHandler()
-	defer dbHandler.Close()
+	var (
+		itemRepo      usecase.ItemRepository
+		itemEventRepo usecase.ItemEventRepository
+		brandRepo     usecase.BrandRepository
+		categoryRepo  usecase.CategoryRepository
+		transactor    usecase.Transactor
+	)
+	if s.demo {
+		store := memory.NewStore()
+		itemRepo = memory.NewItemRepository(store)
+		itemEventRepo = memory.NewItemEventRepository(store)
+		brandRepo = memory.NewBrandRepository(store)
+		categoryRepo = memory.NewCategoryRepository(store)
+		transactor = store
 
-	itemRepo := &itemDatabase.ItemRepository{
-		SqlHandler: dbHandler,
+		if err := memory.SeedSampleBrands(ctx, brandRepo); err != nil {
+			return fmt.Errorf("failed to seed demo data: %w", err)
+		}
+		if err := memory.SeedSampleItems(ctx, itemRepo, brandRepo); err != nil {
+			return fmt.Errorf("failed to seed demo data: %w", err)
+		}
+		fmt.Println("🧪 Running in demo mode (in-memory data, not persisted)")
+	} else {
+		dbHandler := databaseInfra.NewSqlHandler()
+		defer dbHandler.Close()
+
+		migrator, err := migration.NewMigrator(dbHandler.DB(), dbHandler.Driver())
+		if err != nil {
+			return err
+		}
+		if err := s.prepareDatabase(ctx, migrator); err != nil {
+			return err
+		}
+
+		// MySQL は FULLTEXT インデックスで、それ以外はプロセス内の索引で全文検索する
+		databaseItemRepo := &itemDatabase.ItemRepository{SqlHandler: dbHandler, FullText: dbHandler.Driver() == config.DBDriverMySQL}
+		if _, err := databaseItemRepo.FillBrandCanonical(ctx); err != nil {
+			return fmt.Errorf("failed to fill brand canonical: %w", err)
+		}
+		if databaseItemRepo.FullText {
+			if _, err := databaseItemRepo.FillSearchText(ctx); err != nil {
+				return fmt.Errorf("failed to fill search text: %w", err)
+			}
+		}
+		itemRepo = databaseItemRepo
+		itemEventRepo = &itemDatabase.ItemEventRepository{SqlHandler: dbHandler}
+		brandRepo = &itemDatabase.BrandRepository{SqlHandler: dbHandler}
+		categoryRepo = &itemDatabase.CategoryRepository{SqlHandler: dbHandler}
+		transactor = dbHandler
+	}
+
+	// アイテムのカテゴリーはカテゴリーテーブルのキャッシュで検証する（WithCategoryRegistry で渡す）
+	categoryRegistry := usecase.NewCategoryRegistry(categoryRepo)
+	if err := categoryRegistry.Load(ctx); err != nil {
+		return err
 	}
 
-	itemUsecase := usecase.NewItemUsecase(itemRepo)
+	brandResolution := usecase.BrandResolutionMode(config.BrandResolution)
+	if brandResolution != usecase.BrandResolutionStrict && brandResolution != usecase.BrandResolutionLenient {
+		fmt.Printf("⚠️  BRAND_RESOLUTION の値 %q には対応していません。lenient を使用します。\n", config.BrandResolution)
+		brandResolution = usecase.BrandResolutionLenient
+	}
+
+	// ファセットの索引はアイテム・ブランド・カテゴリーの usecase で共有し、それぞれの書き込みを反映する
+	itemIndex := memory.NewItemIndex()
+	itemUsecase := usecase.NewItemUsecase(itemRepo,
+		usecase.WithCursorSecret([]byte(config.CursorSecret)),
+		usecase.WithTransactor(transactor),
+		usecase.WithAuditTrail(itemEventRepo),
+		usecase.WithBrandCatalog(brandRepo, brandResolution),
+		usecase.WithCategoryRegistry(categoryRegistry),
+		usecase.WithItemIndex(itemIndex),
+	)
+	indexed, err := itemUsecase.RebuildIndex(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Indexed %d items\n", indexed)
+	auditUsecase := usecase.NewAuditUsecase(itemEventRepo)
+	brandUsecase := usecase.NewBrandUsecase(brandRepo, itemRepo, transactor,
+		usecase.WithBrandAuditTrail(itemEventRepo),
+		usecase.WithBrandItemIndex(itemIndex),
+	)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo, itemRepo, categoryRegistry, transactor,
+		usecase.WithCategoryAuditTrail(itemEventRepo),
+		usecase.WithCategoryItemIndex(itemIndex),
+	)
+
+	// ゴミ箱の定期削除、カテゴリーのキャッシュの読み込み直しとファセットの索引の作り直し
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	go scheduler.NewTrashPurger(itemUsecase, config.TrashRetention, config.TrashPurgeInterval).Run(backgroundCtx)
+	go scheduler.NewCategoryRefresher(categoryRegistry, config.CategoryRefreshInterval).Run(backgroundCtx)
+	go scheduler.NewIndexRebuilder(itemUsecase, config.ItemIndexRebuildInterval).Run(backgroundCtx)
 
 	systemHandler := system.NewSystemHandler()
 	itemHandler := itemController.NewItemHandler(itemUsecase)
+	auditHandler := auditController.NewAuditHandler(auditUsecase)
+	brandHandler := brandController.NewBrandHandler(brandUsecase)
+	categoryHandler := categoryController.NewCategoryHandler(categoryUsecase)
+
+	// 監査記録用にリクエストIDと操作者を ctx へ設定
+	e.Use(auditController.RequestIDMiddleware())
+	e.Use(auditController.ContextMiddleware())
+
+	// エラーメッセージの言語を Accept-Language から決める
+	defaultLanguage, ok := i18n.Parse(config.DefaultLanguage)
+	if !ok {
+		fmt.Printf("⚠️  DEFAULT_LANGUAGE の値 %q には対応していません。en を使用します。\n", config.DefaultLanguage)
+		defaultLanguage = i18n.English
+	}
+	e.Use(i18n.Middleware(defaultLanguage))
 
 	// ヘルスチェック
 	e.GET("/health", func(c echo.Context) error {
@@ -50,17 +178,78 @@ func (s *Server) Run(ctx context.Context) error {
 	// アイテムに関するエンドポイント
 	itemsGroup := e.Group("/items")
 	{
-		itemsGroup.GET("", itemHandler.GetItems)                  // GET /items
-		itemsGroup.POST("", itemHandler.CreateItem)               // POST /items
-		itemsGroup.GET("/:id", itemHandler.GetItem)               // GET /items/{id}
-		itemsGroup.PATCH("/:id", itemHandler.UpdateItemPartially) //Update /items/{id}
-		itemsGroup.DELETE("/:id", itemHandler.DeleteItem)         // DELETE /items/{id}
-		itemsGroup.GET("/summary", itemHandler.GetSummary)        // GET /items/summary (bonus)
+		itemsGroup.GET("", itemHandler.GetItems)                       // GET /items
+		itemsGroup.POST("", itemHandler.CreateItem)                    // POST /items
+		itemsGroup.GET("/:id", itemHandler.GetItem)                    // GET /items/{id}
+		itemsGroup.PUT("/:id", itemHandler.ReplaceItem)                // PUT /items/{id}
+		itemsGroup.PATCH("/:id", itemHandler.UpdateItemPartially)      //Update /items/{id}
+		itemsGroup.DELETE("/:id", itemHandler.DeleteItem)              // DELETE /items/{id}
+		itemsGroup.GET("/summary", itemHandler.GetSummary)             // GET /items/summary (bonus)
+		itemsGroup.GET("/search", itemHandler.SearchItems)             // GET /items/search?q=...
+		itemsGroup.GET("/facets", itemHandler.GetFacets)               // GET /items/facets
+		itemsGroup.POST("/facets/rebuild", itemHandler.RebuildIndex)   // POST /items/facets/rebuild
+		itemsGroup.POST("/import", itemHandler.ImportItems)            // POST /items/import
+		itemsGroup.GET("/export", itemHandler.ExportItems)             // GET /items/export?format=csv|jsonl|xlsx
+		itemsGroup.POST("/batch", itemHandler.BatchItems)              // POST /items/batch
+		itemsGroup.GET("/trash", itemHandler.GetTrashedItems)          // GET /items/trash
+		itemsGroup.POST("/:id/restore", itemHandler.RestoreItem)       // POST /items/{id}/restore
+		itemsGroup.GET("/:id/history", auditHandler.GetItemHistory)    // GET /items/{id}/history
+		itemsGroup.POST("/:id/tags", itemHandler.AddItemTags)          // POST /items/{id}/tags
+		itemsGroup.DELETE("/:id/tags/:tag", itemHandler.RemoveItemTag) // DELETE /items/{id}/tags/{tag}
+	}
+
+	// タグクラウド
+	e.GET("/tags", itemHandler.GetTagCloud) // GET /tags
+
+	// 監査記録
+	e.GET("/audit", auditHandler.GetEvents) // GET /audit
+
+	// ブランドカタログ
+	brandsGroup := e.Group("/brands")
+	{
+		brandsGroup.GET("", brandHandler.GetBrands)            // GET /brands
+		brandsGroup.POST("", brandHandler.CreateBrand)         // POST /brands
+		brandsGroup.GET("/resolve", brandHandler.ResolveBrand) // GET /brands/resolve?name=...
+		brandsGroup.GET("/:id", brandHandler.GetBrand)         // GET /brands/{id}
+		brandsGroup.PUT("/:id", brandHandler.ReplaceBrand)     // PUT /brands/{id}
+		brandsGroup.DELETE("/:id", brandHandler.DeleteBrand)   // DELETE /brands/{id}
+	}
+
+	// カテゴリー
+	categoriesGroup := e.Group("/categories")
+	{
+		categoriesGroup.GET("", categoryHandler.GetCategories)         // GET /categories
+		categoriesGroup.POST("", categoryHandler.CreateCategory)       // POST /categories
+		categoriesGroup.GET("/:id", categoryHandler.GetCategory)       // GET /categories/{id}
+		categoriesGroup.PUT("/:id", categoryHandler.ReplaceCategory)   // PUT /categories/{id}
+		categoriesGroup.DELETE("/:id", categoryHandler.DeleteCategory) // DELETE /categories/{id}?reassign_to=...
 	}
 
 	return s.startWithGracefulShutdown(ctx, e)
 }
 
+// 設定に応じてマイグレーションの適用とサンプルデータの投入を行う
+func (s *Server) prepareDatabase(ctx context.Context, migrator *migration.Migrator) error {
+	if config.DBAutoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		for _, m := range applied {
+			fmt.Printf("✅ Applied migration %04d_%s\n", m.Version, m.Name)
+		}
+	}
+
+	if config.DBSeed {
+		if err := migrator.Seed(ctx); err != nil {
+			return fmt.Errorf("failed to seed database: %w", err)
+		}
+		fmt.Println("✅ Seeded sample data")
+	}
+
+	return nil
+}
+
 func (s *Server) startWithGracefulShutdown(ctx context.Context, e *echo.Echo) error {
 	go func() {
 		port := ":8080"
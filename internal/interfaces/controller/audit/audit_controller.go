@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// 操作者を受け取るリクエストヘッダ
+const HeaderActor = "X-Actor"
+
+// 監査記録に保存できる長さ（item_events の actor・request_id 列の桁数）
+const (
+	MaxActorLength     = 100
+	MaxRequestIDLength = 64
+)
+
+// クライアントから受け取るリクエストIDとして使える形式
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:+/=-]+$`)
+
+type AuditHandler struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+func NewAuditHandler(auditUsecase usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{
+		auditUsecase: auditUsecase,
+	}
+}
+
+// RequestIDMiddleware はレスポンスの X-Request-Id にリクエストIDを設定する。
+// クライアントが指定した値は監査記録に保存できる長さの英数字と記号（. _ : + / = -）だけの場合に使い、
+// それ以外の場合は新しく生成する
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Generator: newRequestID,
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			if !validRequestID(requestID) {
+				c.Response().Header().Set(echo.HeaderXRequestID, newRequestID())
+			}
+		},
+	})
+}
+
+// ContextMiddleware は操作者とリクエストIDを監査記録用に ctx へ設定する。
+// リクエストIDは RequestIDMiddleware が設定した X-Request-Id を使う。
+// 操作者は制御文字などを取り除き、監査記録に保存できる長さに切り詰める
+func ContextMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = ""
+			}
+
+			ctx := c.Request().Context()
+			ctx = usecase.WithActor(ctx, sanitizeActor(c.Request().Header.Get(HeaderActor)))
+			ctx = usecase.WithRequestID(ctx, requestID)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func validRequestID(requestID string) bool {
+	return len(requestID) <= MaxRequestIDLength && requestIDPattern.MatchString(requestID)
+}
+
+// sanitizeActor は不正な UTF-8 と表示できない文字を取り除き、前後の空白を除いて MaxActorLength 文字に切り詰める
+func sanitizeActor(actor string) string {
+	actor = strings.Map(func(r rune) rune {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(actor, ""))
+	actor = strings.TrimSpace(actor)
+	if runes := []rune(actor); len(runes) > MaxActorLength {
+		actor = strings.TrimSpace(string(runes[:MaxActorLength]))
+	}
+	return actor
+}
+
+// GetItemHistory は GET /items/{id}/history
+func (h *AuditHandler) GetItemHistory(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
+	}
+
+	criteria, err := parseAuditCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	list, err := h.auditUsecase.GetItemHistory(c.Request().Context(), id, criteria)
+	return h.respond(c, list, err)
+}
+
+// GetEvents は GET /audit
+func (h *AuditHandler) GetEvents(c echo.Context) error {
+	criteria, err := parseAuditCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	if raw := c.QueryParam("item_id"); raw != "" {
+		itemID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, errors.New("item_id must be an integer"))
+		}
+		criteria.ItemID = &itemID
+	}
+
+	list, err := h.auditUsecase.ListEvents(c.Request().Context(), criteria)
+	return h.respond(c, list, err)
+}
+
+func (h *AuditHandler) respond(c echo.Context, list *usecase.ItemEventList, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// クエリパラメータから監査記録の取得条件を組み立てる
+func parseAuditCriteria(c echo.Context) (usecase.AuditCriteria, error) {
+	criteria := usecase.AuditCriteria{
+		Actor:  c.QueryParam("actor"),
+		Action: c.QueryParam("action"),
+	}
+
+	var err error
+	if criteria.From, err = optionalTimeParam(c, "from"); err != nil {
+		return criteria, err
+	}
+	if criteria.To, err = optionalTimeParam(c, "to"); err != nil {
+		return criteria, err
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		if criteria.Limit, err = strconv.Atoi(raw); err != nil {
+			return criteria, fmt.Errorf("limit must be an integer")
+		}
+		// usecase は 0 を「指定なし」とみなすため、明示的に指定された limit=0 もここで範囲外にする
+		if err := usecase.ValidateLimit(criteria.Limit); err != nil {
+			return criteria, err
+		}
+	}
+	if raw := c.QueryParam("offset"); raw != "" {
+		if criteria.Offset, err = strconv.Atoi(raw); err != nil {
+			return criteria, fmt.Errorf("offset must be an integer")
+		}
+	}
+
+	return criteria, nil
+}
+
+// RFC 3339 の日時、または YYYY-MM-DD（その日の 0 時）を受け付ける
+func optionalTimeParam(c echo.Context, name string) (*time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		return &t, nil
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD", name)
+}
@@ -0,0 +1,114 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	controller "Aicon-assignment/internal/interfaces/controller/audit"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メモリ上のリポジトリを使い、ミドルウェアから監査記録の取得までを通して検証する。
+// POST /touch はアイテム1の価格を更新して監査記録を残す
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	store := memory.NewStore()
+	itemRepo := memory.NewItemRepository(store)
+	itemEventRepo := memory.NewItemEventRepository(store)
+	itemUsecase := usecase.NewItemUsecase(itemRepo, usecase.WithTransactor(store), usecase.WithAuditTrail(itemEventRepo))
+	h := controller.NewAuditHandler(usecase.NewAuditUsecase(itemEventRepo))
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	e.Use(controller.RequestIDMiddleware())
+	e.Use(controller.ContextMiddleware())
+	e.POST("/touch", func(c echo.Context) error {
+		price := 1600000
+		_, err := itemUsecase.UpdateItemPartially(c.Request().Context(), 1, usecase.UpdateItemInput{PurchasePrice: usecase.Set(price)}, []int64{usecase.AnyVersion})
+		if err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/items/:id/history", h.GetItemHistory)
+
+	require.NoError(t, memory.SeedSampleItems(context.Background(), itemRepo, memory.NewBrandRepository(store)))
+	return e
+}
+
+func TestContextMiddleware(t *testing.T) {
+	tests := []struct {
+		name              string
+		actor             string
+		requestID         string
+		expectedActor     string
+		expectedRequestID string // 空の場合は新しく生成されたこと（32桁の16進数）を確かめる
+	}{
+		{
+			name:              "正常系: 指定した操作者とリクエストIDを記録する",
+			actor:             "田中 太郎",
+			requestID:         "req-123",
+			expectedActor:     "田中 太郎",
+			expectedRequestID: "req-123",
+		},
+		{
+			name:          "正常系: 操作者とリクエストIDが無い場合は anonymous と生成したIDを記録する",
+			expectedActor: usecase.AnonymousActor,
+		},
+		{
+			name:          "正常系: 長すぎる操作者は切り詰め、長すぎるリクエストIDは生成し直す",
+			actor:         strings.Repeat("あ", controller.MaxActorLength+1),
+			requestID:     strings.Repeat("a", controller.MaxRequestIDLength+1),
+			expectedActor: strings.Repeat("あ", controller.MaxActorLength),
+		},
+		{
+			name:          "正常系: 操作者の制御文字と前後の空白は取り除き、使えない文字を含むリクエストIDは生成し直す",
+			actor:         " tanaka\x00\x1b[31m ",
+			requestID:     "req 123<script>",
+			expectedActor: "tanaka[31m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(t)
+			req := httptest.NewRequest(http.MethodPost, "/touch", nil)
+			if tt.actor != "" {
+				req.Header.Set(controller.HeaderActor, tt.actor)
+			}
+			if tt.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+			requestID := rec.Header().Get(echo.HeaderXRequestID)
+			if tt.expectedRequestID != "" {
+				assert.Equal(t, tt.expectedRequestID, requestID)
+			} else {
+				assert.Regexp(t, `^[0-9a-f]{32}$`, requestID)
+			}
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1/history", nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			var list usecase.ItemEventList
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			require.Len(t, list.Events, 1)
+			assert.Equal(t, tt.expectedActor, list.Events[0].Actor)
+			assert.Equal(t, requestID, list.Events[0].RequestID, "監査記録にはレスポンスと同じリクエストIDを残す")
+		})
+	}
+}
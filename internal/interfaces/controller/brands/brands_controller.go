@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BrandHandler struct {
+	brandUsecase usecase.BrandUsecase
+}
+
+func NewBrandHandler(brandUsecase usecase.BrandUsecase) *BrandHandler {
+	return &BrandHandler{
+		brandUsecase: brandUsecase,
+	}
+}
+
+// BrandList は GET /brands のレスポンス
+type BrandList struct {
+	Brands []*entity.Brand `json:"brands"`
+}
+
+// GetBrands は GET /brands
+func (h *BrandHandler) GetBrands(c echo.Context) error {
+	brands, err := h.brandUsecase.ListBrands(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, BrandList{Brands: brands})
+}
+
+// GetBrand は GET /brands/{id}
+func (h *BrandHandler) GetBrand(c echo.Context) error {
+	id, err := brandID(c)
+	if err != nil {
+		return err
+	}
+
+	brand, err := h.brandUsecase.GetBrand(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, brand)
+}
+
+// CreateBrand は POST /brands
+func (h *BrandHandler) CreateBrand(c echo.Context) error {
+	var input usecase.BrandInput
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	brand, err := h.brandUsecase.CreateBrand(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, brand)
+}
+
+// ReplaceBrand は PUT /brands/{id}。名前・原産国・別名をすべて置き換える
+func (h *BrandHandler) ReplaceBrand(c echo.Context) error {
+	id, err := brandID(c)
+	if err != nil {
+		return err
+	}
+
+	var input usecase.BrandInput
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	brand, err := h.brandUsecase.ReplaceBrand(c.Request().Context(), id, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, brand)
+}
+
+// DeleteBrand は DELETE /brands/{id}。アイテムが紐づいている場合は 409
+func (h *BrandHandler) DeleteBrand(c echo.Context) error {
+	id, err := brandID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.brandUsecase.DeleteBrand(c.Request().Context(), id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResolveBrand は GET /brands/resolve?name=...。
+// 一致するブランドが無い場合も 200 で、表記の近いブランドを suggestions に返す
+func (h *BrandHandler) ResolveBrand(c echo.Context) error {
+	name := c.QueryParam("name")
+	if name == "" {
+		return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, errors.New("name is required"))
+	}
+
+	resolution, err := h.brandUsecase.ResolveBrand(c.Request().Context(), name)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resolution)
+}
+
+func brandID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, problem.New(http.StatusBadRequest, i18n.InvalidBrandID)
+	}
+	return id, nil
+}
@@ -0,0 +1,230 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	controller "Aicon-assignment/internal/interfaces/controller/brands"
+	itemController "Aicon-assignment/internal/interfaces/controller/items"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メモリ上のリポジトリを使い、ルーティングからレスポンスまでを通して検証する。
+// ファセットの索引はアイテムとブランドの usecase で共有する
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	store := memory.NewStore()
+	brandRepo := memory.NewBrandRepository(store)
+	itemRepo := memory.NewItemRepository(store)
+	index := memory.NewItemIndex()
+	h := controller.NewBrandHandler(usecase.NewBrandUsecase(brandRepo, itemRepo, store, usecase.WithBrandItemIndex(index)))
+	itemUsecase := usecase.NewItemUsecase(itemRepo, usecase.WithTransactor(store), usecase.WithBrandCatalog(brandRepo, usecase.BrandResolutionLenient), usecase.WithItemIndex(index))
+	itemHandler := itemController.NewItemHandler(itemUsecase)
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	e.Use(i18n.Middleware(i18n.English))
+	e.GET("/brands", h.GetBrands)
+	e.POST("/brands", h.CreateBrand)
+	e.GET("/brands/resolve", h.ResolveBrand)
+	e.GET("/brands/:id", h.GetBrand)
+	e.PUT("/brands/:id", h.ReplaceBrand)
+	e.DELETE("/brands/:id", h.DeleteBrand)
+	e.GET("/items", itemHandler.GetItems)
+	e.GET("/items/facets", itemHandler.GetFacets)
+
+	ctx := context.Background()
+	require.NoError(t, memory.SeedSampleBrands(ctx, brandRepo))
+	require.NoError(t, memory.SeedSampleItems(ctx, itemRepo, brandRepo))
+	_, err := itemUsecase.RebuildIndex(ctx)
+	require.NoError(t, err)
+	return e
+}
+
+func doRequest(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBrandHandler_CRUD(t *testing.T) {
+	e := newTestServer(t)
+
+	rec := doRequest(e, http.MethodPost, "/brands", `{"name":"Patek Philippe","country":"ch","aliases":["パテック・フィリップ","パテックフィリップ"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created entity.Brand
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "PATEK PHILIPPE", created.NameCanonical)
+	assert.Equal(t, "CH", created.Country)
+	target := "/brands/" + strconv.FormatInt(created.ID, 10)
+
+	rec = doRequest(e, http.MethodGet, target, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(e, http.MethodPut, target, `{"name":"Patek Philippe","country":"CH","aliases":["パテック"]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var updated entity.Brand
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, []string{"パテック"}, updated.Aliases)
+
+	rec = doRequest(e, http.MethodGet, "/brands", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list controller.BrandList
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list.Brands, 12)
+
+	rec = doRequest(e, http.MethodDelete, target, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = doRequest(e, http.MethodGet, target, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBrandHandler_Errors(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "異常系: 不正なID",
+			method:         http.MethodGet,
+			target:         "/brands/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_brand_id",
+		},
+		{
+			name:           "異常系: 存在しないブランド",
+			method:         http.MethodGet,
+			target:         "/brands/9999",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "brand_not_found",
+		},
+		{
+			name:           "異常系: 名前が空",
+			method:         http.MethodPost,
+			target:         "/brands",
+			body:           `{"name":"","country":"JP"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "validation_failed",
+		},
+		{
+			name:           "異常系: 別名が他のブランドと重複",
+			method:         http.MethodPost,
+			target:         "/brands",
+			body:           `{"name":"Rolex Japan","aliases":["ﾛﾚｯｸｽ"]}`,
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "duplicate_entry",
+		},
+		{
+			name:           "異常系: アイテムが紐づいているブランドは削除できない",
+			method:         http.MethodDelete,
+			target:         "/brands/1",
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "in_use",
+		},
+		{
+			name:           "異常系: 解決する名前が無い",
+			method:         http.MethodGet,
+			target:         "/brands/resolve",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_query_parameter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(e, tt.method, tt.target, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var res problem.Details
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedCode, res.Code)
+		})
+	}
+}
+
+func TestBrandHandler_ResolveBrand(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedBrand       string
+		expectedSuggestions []string
+	}{
+		{name: "正常系: 正式名", query: "Rolex", expectedBrand: "ROLEX", expectedSuggestions: []string{}},
+		{name: "正常系: 別名", query: "ルイヴィトン", expectedBrand: "LOUIS VUITTON", expectedSuggestions: []string{}},
+		{name: "正常系: アクセント記号の無い表記", query: "hermes", expectedBrand: "HERMÈS", expectedSuggestions: []string{}},
+		{name: "正常系: 一致しない場合は候補", query: "シャネク", expectedSuggestions: []string{"CHANEL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(e, http.MethodGet, "/brands/resolve?name="+url.QueryEscape(tt.query), "")
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var res usecase.BrandResolution
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			if tt.expectedBrand != "" {
+				require.NotNil(t, res.Brand)
+				assert.Equal(t, tt.expectedBrand, res.Brand.Name)
+			} else {
+				assert.Nil(t, res.Brand)
+			}
+			assert.Equal(t, tt.expectedSuggestions, res.Suggestions)
+		})
+	}
+}
+
+func TestBrandHandler_ReplaceBrand_ItemIndex(t *testing.T) {
+	e := newTestServer(t)
+	total := func(target string) int {
+		t.Helper()
+		rec := doRequest(e, http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res struct {
+			Total int `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res.Total
+	}
+
+	rec := doRequest(e, http.MethodGet, "/brands/resolve?name="+url.QueryEscape("ロレックス"), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resolution usecase.BrandResolution
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resolution))
+	require.NotNil(t, resolution.Brand)
+	linked := total("/items?brand=ROLEX")
+	require.Positive(t, linked)
+
+	// 正式名を変更すると、リンクしたアイテムはファセットの索引でも新しい正式名で絞り込める
+	rec = doRequest(e, http.MethodPut, "/brands/"+strconv.FormatInt(resolution.Brand.ID, 10), `{"name":"Rolex SA","country":"CH","aliases":["ロレックス"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	assert.Equal(t, linked, total("/items?brand="+url.QueryEscape("Rolex SA")))
+	assert.Equal(t, linked, total("/items/facets?brand="+url.QueryEscape("Rolex SA")))
+}
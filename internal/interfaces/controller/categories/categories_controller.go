@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryHandler struct {
+	categoryUsecase usecase.CategoryUsecase
+}
+
+func NewCategoryHandler(categoryUsecase usecase.CategoryUsecase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUsecase: categoryUsecase,
+	}
+}
+
+// CategoryList は GET /categories のレスポンス
+type CategoryList struct {
+	Categories []*entity.Category `json:"categories"`
+}
+
+// GetCategories は GET /categories。無効なカテゴリーも含めて表示順で返す
+func (h *CategoryHandler) GetCategories(c echo.Context) error {
+	categories, err := h.categoryUsecase.ListCategories(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, CategoryList{Categories: categories})
+}
+
+// GetCategory は GET /categories/{id}
+func (h *CategoryHandler) GetCategory(c echo.Context) error {
+	id, err := categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	category, err := h.categoryUsecase.GetCategory(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// CreateCategory は POST /categories
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	var input usecase.CategoryInput
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	category, err := h.categoryUsecase.CreateCategory(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, category)
+}
+
+// ReplaceCategory は PUT /categories/{id}。名前を変更した場合は属するアイテムも新しい名前になる
+func (h *CategoryHandler) ReplaceCategory(c echo.Context) error {
+	id, err := categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	var input usecase.CategoryInput
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	category, err := h.categoryUsecase.ReplaceCategory(c.Request().Context(), id, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory は DELETE /categories/{id}?reassign_to=...。
+// アイテムが属している場合、reassign_to が無ければ 409、あればそのカテゴリーへ移動してから削除し、
+// 移動したアイテムと取り除いた属性を 200 で返す
+func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
+	id, err := categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	reassignTo := c.QueryParam("reassign_to")
+	deletion, err := h.categoryUsecase.DeleteCategory(c.Request().Context(), id, reassignTo)
+	if err != nil {
+		return err
+	}
+
+	if reassignTo == "" {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusOK, deletion)
+}
+
+func categoryID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, problem.New(http.StatusBadRequest, i18n.InvalidCategoryID)
+	}
+	return id, nil
+}
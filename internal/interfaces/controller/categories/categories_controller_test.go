@@ -0,0 +1,327 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	controller "Aicon-assignment/internal/interfaces/controller/categories"
+	itemController "Aicon-assignment/internal/interfaces/controller/items"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メモリ上のリポジトリを使い、カテゴリーの変更がアイテムの登録・集計に反映されるところまで通して検証する
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	store := memory.NewStore()
+	categoryRepo := memory.NewCategoryRepository(store)
+	registry := usecase.NewCategoryRegistry(categoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	itemRepo := memory.NewItemRepository(store)
+	itemEventRepo := memory.NewItemEventRepository(store)
+	index := memory.NewItemIndex()
+	h := controller.NewCategoryHandler(usecase.NewCategoryUsecase(categoryRepo, itemRepo, registry, store,
+		usecase.WithCategoryAuditTrail(itemEventRepo),
+		usecase.WithCategoryItemIndex(index),
+	))
+	itemHandler := itemController.NewItemHandler(usecase.NewItemUsecase(itemRepo,
+		usecase.WithTransactor(store),
+		usecase.WithAuditTrail(itemEventRepo),
+		usecase.WithCategoryRegistry(registry),
+		usecase.WithItemIndex(index),
+	))
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	e.Use(i18n.Middleware(i18n.English))
+	e.GET("/categories", h.GetCategories)
+	e.POST("/categories", h.CreateCategory)
+	e.GET("/categories/:id", h.GetCategory)
+	e.PUT("/categories/:id", h.ReplaceCategory)
+	e.DELETE("/categories/:id", h.DeleteCategory)
+	e.GET("/items", itemHandler.GetItems)
+	e.POST("/items", itemHandler.CreateItem)
+	e.PATCH("/items/:id", itemHandler.UpdateItemPartially)
+	e.GET("/items/summary", itemHandler.GetSummary)
+	e.GET("/items/facets", itemHandler.GetFacets)
+	return e
+}
+
+func doRequest(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCategoryHandler_Lifecycle(t *testing.T) {
+	e := newTestServer(t)
+	item := `{"name":"版画","category":"アート","brand":"Unknown","purchase_price":10000,"purchase_date":"2023-01-01"}`
+
+	// 登録前のカテゴリーはアイテムに指定できない
+	rec := doRequest(e, http.MethodPost, "/items", item)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(e, http.MethodPost, "/categories", `{"name":"アート","display_name_ja":"アート","display_name_en":"Art","sort_order":45}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created entity.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.True(t, created.Active)
+	target := "/categories/" + strconv.FormatInt(created.ID, 10)
+
+	rec = doRequest(e, http.MethodGet, "/categories", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list controller.CategoryList
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Categories, 6)
+	assert.Equal(t, "アート", list.Categories[4].Name)
+
+	rec = doRequest(e, http.MethodPost, "/items", item)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = doRequest(e, http.MethodGet, "/items/summary", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var summary usecase.CategorySummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Categories["アート"])
+
+	// 無効にすると新しいアイテムには指定できないが、集計には残る
+	rec = doRequest(e, http.MethodPut, target, `{"name":"アート","display_name_ja":"アート","display_name_en":"Art","sort_order":45,"active":false}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = doRequest(e, http.MethodPost, "/items", item)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(e, http.MethodGet, "/items/summary", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Categories["アート"])
+
+	// アイテムが属している間は移動先を指定しないと削除できない
+	rec = doRequest(e, http.MethodDelete, target, "")
+	require.Equal(t, http.StatusConflict, rec.Code)
+	var res problem.Details
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "in_use", res.Code)
+
+	rec = doRequest(e, http.MethodDelete, target+"?reassign_to=その他", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var deletion usecase.CategoryDeletion
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deletion))
+	assert.Equal(t, "その他", deletion.ReassignedTo)
+	assert.Len(t, deletion.Items, 1)
+
+	rec = doRequest(e, http.MethodGet, "/items/summary", "")
+	summary = usecase.CategorySummary{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.NotContains(t, summary.Categories, "アート")
+	assert.Equal(t, 1, summary.Categories["その他"])
+}
+
+func TestCategoryHandler_Hierarchy(t *testing.T) {
+	e := newTestServer(t)
+
+	// 時計(1) の下に腕時計を作る
+	rec := doRequest(e, http.MethodPost, "/categories", `{"name":"腕時計","parent_id":1,"display_name_ja":"腕時計","display_name_en":"Wristwatches"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var child entity.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &child))
+	require.NotNil(t, child.ParentID)
+	assert.Equal(t, int64(1), *child.ParentID)
+
+	// アイテムは子を持たないカテゴリーにのみ属する
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"デイトナ","category":"時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"デイトナ","category":"腕時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"バーキン","category":"バッグ","brand":"HERMÈS","purchase_price":2000000,"purchase_date":"2023-02-20"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// 親カテゴリーでの絞り込みは子孫のカテゴリーを含む
+	rec = doRequest(e, http.MethodGet, "/items?category="+url.QueryEscape("時計"), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list itemController.ItemListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "デイトナ", list.Items[0].Name)
+
+	// 集計は祖先へ積み上げる
+	rec = doRequest(e, http.MethodGet, "/items/summary", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var summary usecase.CategorySummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Categories["時計"])
+	assert.Equal(t, 1, summary.Categories["腕時計"])
+	assert.Equal(t, 2, summary.Total)
+	require.NotEmpty(t, summary.Tree)
+	assert.Equal(t, "時計", summary.Tree[0].Name)
+	assert.Equal(t, 0, summary.Tree[0].Count)
+	assert.Equal(t, 1, summary.Tree[0].Total)
+	require.Len(t, summary.Tree[0].Children, 1)
+	assert.Equal(t, "腕時計", summary.Tree[0].Children[0].Name)
+
+	// アイテムが属するカテゴリーの下には子を作れず、子を持つカテゴリーは削除できない
+	rec = doRequest(e, http.MethodPost, "/categories", `{"name":"ケリー","parent_id":2,"display_name_ja":"ケリー","display_name_en":"Kelly"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = doRequest(e, http.MethodDelete, "/categories/1", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// 自身の子孫を親にはできない
+	rec = doRequest(e, http.MethodPut, "/categories/1", `{"name":"時計","parent_id":`+strconv.FormatInt(child.ID, 10)+`,"display_name_ja":"時計","display_name_en":"Watches","sort_order":10}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var res problem.Details
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, entity.ValidationCircularRef, res.Errors[0].Code)
+}
+
+func TestCategoryHandler_Errors(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "異常系: 不正なID",
+			method:         http.MethodGet,
+			target:         "/categories/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_category_id",
+		},
+		{
+			name:           "異常系: 存在しないカテゴリー",
+			method:         http.MethodGet,
+			target:         "/categories/9999",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "category_not_found",
+		},
+		{
+			name:           "異常系: 表示名が空",
+			method:         http.MethodPost,
+			target:         "/categories",
+			body:           `{"name":"車"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "validation_failed",
+		},
+		{
+			name:           "異常系: 名前が既存のカテゴリーと重複",
+			method:         http.MethodPost,
+			target:         "/categories",
+			body:           `{"name":"時計","display_name_ja":"時計","display_name_en":"Watches"}`,
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "duplicate_entry",
+		},
+		{
+			name:           "異常系: 移動先が存在しないカテゴリー",
+			method:         http.MethodDelete,
+			target:         "/categories/1?reassign_to=家電",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "validation_failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(e, tt.method, tt.target, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var res problem.Details
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedCode, res.Code)
+		})
+	}
+}
+
+func TestCategoryHandler_Attributes(t *testing.T) {
+	e := newTestServer(t)
+
+	// 時計(1) の下の腕時計は時計の属性を引き継ぎ、バンドの属性を追加する
+	rec := doRequest(e, http.MethodPost, "/categories",
+		`{"name":"腕時計","parent_id":1,"display_name_ja":"腕時計","display_name_en":"Wristwatches",`+
+			`"attributes":[{"key":"band","type":"enum","required":true,"options":["レザー","メタル"]}]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var child entity.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &child))
+	assert.Equal(t, []entity.AttributeDefinition{
+		{Key: "band", Type: entity.AttributeTypeEnum, Required: true, Options: []string{"レザー", "メタル"}},
+	}, child.Attributes)
+
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"デイトナ","category":"腕時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, "必須の属性が無い")
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"デイトナ","category":"腕時計","brand":"ROLEX","purchase_price":1500000,"purchase_date":"2023-01-15",`+
+		`"attributes":{"band":"メタル","movement":"自動巻き"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	t.Run("異常系: 属性の定義が不正", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPost, "/categories",
+			`{"name":"アート","display_name_ja":"アート","display_name_en":"Art","attributes":[{"key":"Artist","type":"text"}]}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var res problem.Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Errors, 2)
+		assert.Equal(t, "attributes[0].key", res.Errors[0].Field)
+		assert.Equal(t, "attributes[0].type", res.Errors[1].Field)
+	})
+}
+
+func TestCategoryHandler_DeleteCategory_Attributes(t *testing.T) {
+	e := newTestServer(t)
+
+	// 時計(1) の movement を引き継ぐ懐中時計のアイテムを、属性を定義していないその他へ移動する
+	rec := doRequest(e, http.MethodPost, "/categories", `{"name":"懐中時計","parent_id":1,"display_name_ja":"懐中時計","display_name_en":"Pocket watches"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var category entity.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &category))
+	rec = doRequest(e, http.MethodPost, "/items", `{"name":"ハンターケース","category":"懐中時計","brand":"Waltham","purchase_price":80000,"purchase_date":"2023-02-01",`+
+		`"attributes":{"movement":"手巻き"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var item entity.Item
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+
+	rec = doRequest(e, http.MethodDelete, "/categories/"+strconv.FormatInt(category.ID, 10)+"?reassign_to=その他", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var deletion usecase.CategoryDeletion
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deletion))
+	assert.Equal(t, []usecase.ReassignedItem{{ID: item.ID, Version: item.Version + 1, RemovedAttributes: []string{"movement"}}}, deletion.Items)
+
+	// ファセットの索引にも移動が反映される
+	rec = doRequest(e, http.MethodGet, "/items/facets", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var facets itemController.FacetResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &facets))
+	assert.Equal(t, []usecase.FacetCount{{Value: "その他", Count: 1}}, facets.Facets.Category)
+
+	// 移動後のアイテムは移動先の属性の定義で更新できる
+	req := httptest.NewRequest(http.MethodPatch, "/items/"+strconv.FormatInt(item.ID, 10), strings.NewReader(`{"purchase_price":90000}`))
+	req.Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	req.Header.Set("If-Match", `"`+strconv.FormatInt(item.Version+1, 10)+`"`)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated entity.Item
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "その他", updated.Category)
+	assert.Empty(t, updated.Attributes)
+	assert.Equal(t, 90000, updated.PurchasePrice)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BatchRequest は一括操作（POST /items/batch）のリクエスト
+type BatchRequest struct {
+	// Mode は atomic（デフォルト）または partial
+	Mode       string                  `json:"mode"`
+	Operations []BatchOperationRequest `json:"operations"`
+}
+
+// BatchOperationRequest は一括操作の1件。Item は create では作成の内容（POST /items と同じ）、
+// update では JSON Merge Patch（PATCH /items/{id} と同じ）として読む
+type BatchOperationRequest struct {
+	Op      string          `json:"op"`
+	ID      int64           `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Item    json.RawMessage `json:"item,omitempty"`
+}
+
+// BatchResponse は一括操作の結果。partial をデータベースのエラーで中断した場合は、その問題詳細を Error に含める
+type BatchResponse struct {
+	*usecase.BatchReport
+	Results []BatchResultResponse `json:"results"`
+	Error   *problem.Details      `json:"error,omitempty"`
+}
+
+// BatchResultResponse は操作ごとの結果。失敗した操作には単独の API と同じ問題詳細を Error に含める
+type BatchResultResponse struct {
+	*usecase.BatchResult
+	Error *problem.Details `json:"error,omitempty"`
+}
+
+// BatchItems はアイテムの作成・更新・削除をまとめて実行し、操作ごとの結果を返す
+func (h *ItemHandler) BatchItems(c echo.Context) error {
+	var req BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	operations := make([]usecase.BatchOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		operations[i] = usecase.BatchOperation{Op: op.Op, ID: op.ID, Version: op.Version}
+		if len(op.Item) == 0 {
+			continue
+		}
+		var err error
+		switch op.Op {
+		case usecase.BatchOpCreate:
+			err = json.Unmarshal(op.Item, &operations[i].Create)
+		case usecase.BatchOpUpdate:
+			err = json.Unmarshal(op.Item, &operations[i].Update)
+		}
+		if err != nil {
+			return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+		}
+	}
+
+	report, err := h.itemUsecase.BatchItems(c.Request().Context(), operations, usecase.BatchOptions{Mode: req.Mode})
+	if err != nil && report == nil {
+		return err
+	}
+
+	lang := i18n.FromContext(c)
+	results := make([]BatchResultResponse, len(report.Results))
+	for i, result := range report.Results {
+		results[i] = BatchResultResponse{BatchResult: result}
+		if result.Err != nil {
+			details := problem.From(result.Err, lang)
+			results[i].Error = &details
+		}
+	}
+
+	response := BatchResponse{BatchReport: report, Results: results}
+	if err != nil {
+		// 中断までに反映した操作が分かるよう、エラーのステータスで結果も返す
+		c.Logger().Error(err)
+		details := problem.From(err, lang)
+		details.Instance = c.Request().URL.Path
+		details.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+		response.Error = &details
+		return c.JSON(details.Status, response)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
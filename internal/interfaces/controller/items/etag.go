@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	errPreconditionRequired = problem.New(http.StatusPreconditionRequired, i18n.PreconditionRequired)
+	errPreconditionFailed   = problem.New(http.StatusPreconditionFailed, i18n.PreconditionFailed)
+)
+
+// アイテムのバージョンから強い ETag を生成する
+func itemETag(item *entity.Item) string {
+	return fmt.Sprintf(`"%d"`, item.Version)
+}
+
+func setETag(c echo.Context, item *entity.Item) {
+	c.Response().Header().Set("ETag", itemETag(item))
+}
+
+// If-Match ヘッダから期待するバージョンの一覧を取り出す（RFC 9110 ではいずれかの ETag と一致すれば条件を満たす）。
+// "*" は usecase.AnyVersion、弱い ETag や解釈できない値は一致しないものとして扱う
+func ifMatchVersions(c echo.Context) ([]int64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" {
+		return nil, errPreconditionRequired
+	}
+	if header == "*" {
+		return []int64{usecase.AnyVersion}, nil
+	}
+
+	var versions []int64
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, errPreconditionFailed
+	}
+
+	return versions, nil
+}
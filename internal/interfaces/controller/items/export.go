@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/interfaces/xlsx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 書き出しの形式
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+	exportFormatXLSX  = "xlsx"
+)
+
+// 書き出しの形式ごとの Content-Type
+var exportContentTypes = map[string]string{
+	exportFormatCSV:   "text/csv; charset=utf-8",
+	exportFormatJSONL: "application/x-ndjson",
+	exportFormatXLSX:  xlsx.ContentType,
+}
+
+// CSV と XLSX の列。name から tags までは取り込み（POST /items/import）の列名と同じ
+var exportColumns = []string{
+	"id", "name", "category", "brand", "purchase_price", "purchase_date", "notes", "tags", "attributes", "created_at", "updated_at",
+}
+
+// ExportItems は一覧と同じ条件に一致するすべてのアイテムを CSV・JSON Lines・XLSX のファイルとして返す。
+// アイテムはリポジトリから読むそばから書き出すため、件数によらずメモリに保持しない
+func (h *ItemHandler) ExportItems(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, fmt.Errorf("format must be one of: csv, jsonl, xlsx"))
+	}
+	bom := false
+	if v := c.QueryParam("bom"); v != "" {
+		var err error
+		if bom, err = strconv.ParseBool(v); err != nil {
+			return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, fmt.Errorf("bom must be a boolean"))
+		}
+	}
+	criteria, err := parseItemCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	// 条件の誤りなどをエラーレスポンスで返せるよう、レスポンスは最初のアイテムを書き出すときに始める
+	var exporter itemExporter
+	start := func() (err error) {
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="items-%s.%s"`, time.Now().Format("20060102"), format))
+		res.WriteHeader(http.StatusOK)
+		exporter, err = newItemExporter(format, res, bom)
+		return err
+	}
+
+	err = h.itemUsecase.ExportItems(c.Request().Context(), criteria, func(item *entity.Item) error {
+		if exporter == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return exporter.Write(item)
+	})
+	if err != nil && exporter == nil {
+		return err
+	}
+	// 該当が無い場合もヘッダー行だけのファイルを返す
+	if err == nil && exporter == nil {
+		err = start()
+	}
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err != nil {
+		// 送信を始めた後は、途中までのファイルを完全なものと誤認させないよう接続を切る
+		c.Logger().Error(err)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+// itemExporter はアイテムを1件ずつファイルの形式に書き出す
+type itemExporter interface {
+	Write(item *entity.Item) error
+	// Close は残りを書き出す（出力先は閉じない）
+	Close() error
+}
+
+func newItemExporter(format string, w io.Writer, bom bool) (itemExporter, error) {
+	switch format {
+	case exportFormatJSONL:
+		return &jsonlItemExporter{encoder: json.NewEncoder(w)}, nil
+	case exportFormatXLSX:
+		sheet, err := xlsx.NewWriter(w, "items")
+		if err != nil {
+			return nil, err
+		}
+		header := make([]any, len(exportColumns))
+		for i, column := range exportColumns {
+			header[i] = column
+		}
+		if err := sheet.WriteRow(header...); err != nil {
+			return nil, err
+		}
+		return &xlsxItemExporter{sheet: sheet}, nil
+	default:
+		// Excel が UTF-8 として開けるよう、指定があれば先頭に BOM を付ける
+		if bom {
+			if _, err := w.Write(utf8BOM); err != nil {
+				return nil, err
+			}
+		}
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportColumns); err != nil {
+			return nil, err
+		}
+		return &csvItemExporter{writer: writer}, nil
+	}
+}
+
+// jsonlItemExporter はアイテムを1行に1つ、API と同じ JSON で書き出す
+type jsonlItemExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonlItemExporter) Write(item *entity.Item) error {
+	return e.encoder.Encode(item)
+}
+
+func (e *jsonlItemExporter) Close() error {
+	return nil
+}
+
+type csvItemExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvItemExporter) Write(item *entity.Item) error {
+	attributes, err := exportAttributes(item)
+	if err != nil {
+		return err
+	}
+	return e.writer.Write([]string{
+		strconv.FormatInt(item.ID, 10),
+		csvCell(item.Name),
+		csvCell(item.Category),
+		csvCell(item.Brand),
+		strconv.Itoa(item.PurchasePrice),
+		item.PurchaseDate,
+		csvCell(item.Notes),
+		csvCell(strings.Join(item.Tags, importTagSeparator)),
+		csvCell(attributes),
+		item.CreatedAt.Format(time.RFC3339),
+		item.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// 表計算ソフトがセルの先頭にあると数式として解釈する文字
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvCell は自由入力の値が Excel などで数式として実行されないよう、数式として解釈される値の先頭に ' を付ける。
+// 取り込み（csvImportSource）は先頭の ' を取り除いて読む
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvCellValue は csvCell で ' を付けた値を元に戻す
+func csvCellValue(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func (e *csvItemExporter) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// xlsxItemExporter は ID と購入価格を数値のセルとして書き出す
+type xlsxItemExporter struct {
+	sheet *xlsx.Writer
+}
+
+func (e *xlsxItemExporter) Write(item *entity.Item) error {
+	attributes, err := exportAttributes(item)
+	if err != nil {
+		return err
+	}
+	return e.sheet.WriteRow(
+		item.ID,
+		item.Name,
+		item.Category,
+		item.Brand,
+		item.PurchasePrice,
+		item.PurchaseDate,
+		item.Notes,
+		strings.Join(item.Tags, importTagSeparator),
+		attributes,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+}
+
+func (e *xlsxItemExporter) Close() error {
+	return e.sheet.Close()
+}
+
+// exportAttributes は属性を JSON の文字列にする。属性が無い場合は空文字列
+func exportAttributes(item *entity.Item) (string, error) {
+	if len(item.Attributes) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(item.Attributes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
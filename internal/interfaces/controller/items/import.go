@@ -0,0 +1,330 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// 文字コードの判定に読む本文の先頭のバイト数
+	importSniffSize = 4096
+	// 取り込む列の値で、属性を表す列名の接頭辞（attr.case_size など）
+	importAttributePrefix = "attr."
+	// tags 列でタグを区切る文字
+	importTagSeparator = "|"
+)
+
+var utf8BOM = []byte("\ufeff")
+
+// 取り込みの列名（entity.NormalizeText して小文字にしたもの）と読み替えるフィールド
+var importColumnAliases = map[string]string{
+	"name":           "name",
+	"名前":             "name",
+	"category":       "category",
+	"カテゴリー":          "category",
+	"カテゴリ":           "category",
+	"brand":          "brand",
+	"ブランド":           "brand",
+	"purchase_price": "purchase_price",
+	"購入価格":           "purchase_price",
+	"価格":             "purchase_price",
+	"purchase_date":  "purchase_date",
+	"購入日":            "purchase_date",
+	"notes":          "notes",
+	"メモ":             "notes",
+	"備考":             "notes",
+	"tags":           "tags",
+	"タグ":             "tags",
+}
+
+// 取り込みに必須の列
+var importRequiredFields = []string{"name", "category", "brand", "purchase_price", "purchase_date"}
+
+// 購入日として受け付ける書式。YYYY-MM-DD に揃えてから entity.NewItem で検証する
+var importDateLayouts = []string{"2006-01-02", "2006/01/02", "2006-1-2", "2006/1/2"}
+
+// 取り込みレスポンスの形式
+type ImportResponse struct {
+	*usecase.ImportReport
+	// 取り込まなかった列の名前
+	IgnoredColumns []string `json:"ignored_columns"`
+}
+
+// ImportItems は CSV（UTF-8 または Shift_JIS）の各行をアイテムとして登録し、行ごとの結果を返す。
+// best_effort では本文を1行ずつ読みながら登録するため、大きなファイルもメモリに載せない
+func (h *ItemHandler) ImportItems(c echo.Context) error {
+	body, err := importBodyReader(c)
+	if err != nil {
+		return err
+	}
+
+	options := usecase.ImportOptions{Mode: c.QueryParam("mode")}
+	if v := c.QueryParam("dry_run"); v != "" {
+		options.DryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, fmt.Errorf("dry_run must be a boolean"))
+		}
+	}
+
+	source, err := newCSVImportSource(body, importColumnMapping(c))
+	if err != nil {
+		return err
+	}
+
+	report, err := h.itemUsecase.ImportItems(c.Request().Context(), source, options)
+	if err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(c)
+	for _, row := range report.Rows {
+		if len(row.Errors) > 0 {
+			row.Errors = i18n.LocalizeFieldErrors(lang, row.Errors)
+		}
+	}
+
+	return c.JSON(http.StatusOK, ImportResponse{ImportReport: report, IgnoredColumns: source.ignored})
+}
+
+// importBodyReader は text/csv の本文を UTF-8 で読む Reader を返す。
+// 文字コードは Content-Type の charset で指定でき、省略した場合は本文の先頭から判定する
+func importBodyReader(c echo.Context) (io.Reader, error) {
+	mediaType, params, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != "text/csv" {
+		return nil, problem.New(http.StatusUnsupportedMediaType, i18n.UnsupportedImportFile)
+	}
+
+	body := bufio.NewReaderSize(c.Request().Body, importSniffSize)
+	shiftJIS := false
+	switch strings.ToLower(params["charset"]) {
+	case "":
+		head, _ := body.Peek(importSniffSize)
+		shiftJIS = !bytes.HasPrefix(head, utf8BOM) && !isUTF8Prefix(head)
+	case "utf-8", "utf8":
+	case "shift_jis", "shift-jis", "sjis", "windows-31j", "cp932", "x-sjis":
+		shiftJIS = true
+	default:
+		return nil, problem.New(http.StatusUnsupportedMediaType, i18n.UnsupportedImportFile)
+	}
+
+	if shiftJIS {
+		return transform.NewReader(body, japanese.ShiftJIS.NewDecoder()), nil
+	}
+	return body, nil
+}
+
+// isUTF8Prefix は b が UTF-8 として正しいかを返す。先頭だけを読んだため末尾で途切れた文字は正しいものとみなす
+func isUTF8Prefix(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			return !utf8.FullRune(b)
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+// importColumnMapping はクエリパラメータ map.<列名>=<フィールド> で指定された列の読み替えを返す
+func importColumnMapping(c echo.Context) map[string]string {
+	mapping := make(map[string]string)
+	for key, values := range c.QueryParams() {
+		if column, ok := strings.CutPrefix(key, "map."); ok && len(values) > 0 {
+			mapping[importColumnKey(column)] = values[0]
+		}
+	}
+	return mapping
+}
+
+func importColumnKey(column string) string {
+	return strings.ToLower(entity.NormalizeText(column))
+}
+
+// csvImportSource は CSV を1行ずつ usecase.ImportRow に読み替える usecase.ImportSource
+type csvImportSource struct {
+	reader *csv.Reader
+	// 列ごとのフィールド名（属性は attr.<キー>、取り込まない列は空）
+	fields  []string
+	ignored []string
+}
+
+// newCSVImportSource はヘッダー行を読んで各列のフィールドを決める。
+// 列は mapping（列名→フィールド）、別名、attr.<キー> の順に読み替え、必須の列が無い場合はエラーを返す
+func newCSVImportSource(r io.Reader, mapping map[string]string) (*csvImportSource, error) {
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, problem.Wrap(http.StatusBadRequest, i18n.InvalidCSVHeader, errors.New("header row is missing"))
+	}
+	if err != nil {
+		return nil, importReadError(err)
+	}
+	header[0] = strings.TrimPrefix(header[0], string(utf8BOM))
+
+	source := &csvImportSource{reader: reader, fields: make([]string, len(header)), ignored: []string{}}
+	columns := make(map[string]string)
+	for i, column := range header {
+		key := importColumnKey(column)
+		field, ok := mapping[key]
+		if !ok {
+			field = importColumnAliases[key]
+		}
+		if field == "" && strings.HasPrefix(key, importAttributePrefix) {
+			field = key
+		}
+		// map.<列名>= のように空のフィールドを指定した列は取り込まない
+		if !isImportField(field) {
+			if field != "" {
+				return nil, problem.Wrap(http.StatusBadRequest, i18n.InvalidCSVHeader, fmt.Errorf("map.%s: unknown field %q", column, field))
+			}
+			source.ignored = append(source.ignored, column)
+			continue
+		}
+		if other, ok := columns[field]; ok {
+			return nil, problem.Wrap(http.StatusBadRequest, i18n.InvalidCSVHeader, fmt.Errorf("columns %q and %q both map to %s", other, column, field))
+		}
+		columns[field] = column
+		source.fields[i] = field
+	}
+
+	var missing []string
+	for _, field := range importRequiredFields {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, problem.Wrap(http.StatusBadRequest, i18n.InvalidCSVHeader, fmt.Errorf("missing columns: %s", strings.Join(missing, ", ")))
+	}
+
+	return source, nil
+}
+
+func isImportField(field string) bool {
+	if slices.Contains(importRequiredFields, field) || field == "notes" || field == "tags" {
+		return true
+	}
+	key, ok := strings.CutPrefix(field, importAttributePrefix)
+	return ok && key != ""
+}
+
+func (s *csvImportSource) Next() (*usecase.ImportRow, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		return nil, importReadError(err)
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	row := &usecase.ImportRow{Line: line, Empty: true}
+	for i, field := range s.fields {
+		if i >= len(record) {
+			break
+		}
+		value := csvCellValue(record[i])
+		if strings.TrimSpace(value) != "" {
+			row.Empty = false
+		}
+
+		switch field {
+		case "":
+		case "name":
+			row.Input.Name = value
+		case "category":
+			row.Input.Category = value
+		case "brand":
+			row.Input.Brand = value
+		case "purchase_price":
+			price, fieldErr := parseImportPrice(value)
+			if fieldErr != nil {
+				row.Errors = append(row.Errors, *fieldErr)
+			}
+			row.Input.PurchasePrice = price
+		case "purchase_date":
+			row.Input.PurchaseDate = parseImportDate(value)
+		case "notes":
+			row.Input.Notes = value
+		case "tags":
+			row.Input.Tags = parseImportTags(value)
+		default:
+			if row.Attributes == nil {
+				row.Attributes = make(map[string]string)
+			}
+			row.Attributes[strings.TrimPrefix(field, importAttributePrefix)] = value
+		}
+	}
+
+	return row, nil
+}
+
+// importReadError は CSV の読み込みのエラーを返す。CSV の形式の誤りは不正な入力として扱う
+func importReadError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %w: %w", domainErrors.ErrInvalidInput, entity.MalformedError("body", parseErr.StartLine), parseErr.Err)
+	}
+	return err
+}
+
+// parseImportPrice は "1,500,000"、"¥1500000"、"1500000円" のような購入価格を整数にする
+func parseImportPrice(value string) (int, *entity.FieldError) {
+	value = entity.NormalizeText(value)
+	if value == "" {
+		fieldErr := entity.RequiredError("purchase_price")
+		return 0, &fieldErr
+	}
+	value = strings.NewReplacer(",", "", "¥", "", "円", "", " ", "").Replace(value)
+	price, err := strconv.Atoi(value)
+	if err != nil {
+		fieldErr := entity.InvalidTypeError("purchase_price", entity.AttributeTypeInteger)
+		return 0, &fieldErr
+	}
+	return price, nil
+}
+
+// parseImportDate は "2023/1/5" のような購入日を YYYY-MM-DD に揃える。読み替えられない値はそのまま返す
+func parseImportDate(value string) string {
+	value = entity.NormalizeText(value)
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return value
+}
+
+// parseImportTags は "|" で区切られたタグを返す
+func parseImportTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, importTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -1,12 +1,18 @@
 package controller
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/problem"
 	"Aicon-assignment/internal/usecase"
 
 	"github.com/labstack/echo/v4"
@@ -22,98 +28,368 @@ func NewItemHandler(itemUsecase usecase.ItemUsecase) *ItemHandler {
 	}
 }
 
-// エラーレスポンスの形式
-type ErrorResponse struct {
-	Error   string   `json:"error"`
-	Details []string `json:"details,omitempty"`
+// 一覧取得レスポンスの形式
+type ItemListResponse struct {
+	Items  []*entity.Item `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Next   *string        `json:"next"`
+	Prev   *string        `json:"prev"`
+}
+
+// カーソル方式の一覧取得レスポンスの形式
+type ItemCursorResponse struct {
+	Items      []*entity.Item `json:"items"`
+	Limit      int            `json:"limit"`
+	HasMore    bool           `json:"has_more"`
+	NextCursor *string        `json:"next_cursor"`
+	Next       *string        `json:"next"`
+}
+
+// 全文検索レスポンスの形式
+type SearchResponse struct {
+	Query  string               `json:"query"`
+	Items  []*usecase.SearchHit `json:"items"`
+	Total  int                  `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+	Next   *string              `json:"next"`
+	Prev   *string              `json:"prev"`
+}
+
+// ファセット付きの絞り込みレスポンスの形式
+type FacetResponse struct {
+	Items  []*entity.Item `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Next   *string        `json:"next"`
+	Prev   *string        `json:"prev"`
+	Facets usecase.Facets `json:"facets"`
+}
+
+// 索引の作り直しのレスポンスの形式
+type RebuildIndexResponse struct {
+	Indexed int `json:"indexed"`
 }
 
 func (h *ItemHandler) GetItems(c echo.Context) error {
-	items, err := h.itemUsecase.GetAllItems(c.Request().Context())
+	criteria, err := parseItemCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	// cursor が指定されているか pagination=cursor の場合はキーセット方式
+	if c.QueryParams().Has("cursor") || c.QueryParam("pagination") == "cursor" {
+		return h.getItemsByCursor(c, criteria)
+	}
+
+	list, err := h.itemUsecase.ListItems(c.Request().Context(), criteria)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve items",
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, items)
+	return c.JSON(http.StatusOK, newItemListResponse(c, list))
 }
 
-func (h *ItemHandler) GetItem(c echo.Context) error {
+// GetTrashedItems はゴミ箱にあるアイテムを一覧で返す
+func (h *ItemHandler) GetTrashedItems(c echo.Context) error {
+	criteria, err := parseItemCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	list, err := h.itemUsecase.ListTrashedItems(c.Request().Context(), criteria)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newItemListResponse(c, list))
+}
+
+// RestoreItem はゴミ箱にあるアイテムを元に戻す
+func (h *ItemHandler) RestoreItem(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "invalid item ID",
-		})
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
 	}
 
-	item, err := h.itemUsecase.GetItemByID(c.Request().Context(), id)
+	item, err := h.itemUsecase.RestoreItem(c.Request().Context(), id)
 	if err != nil {
 		if domainErrors.IsNotFoundError(err) {
-			return c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "item not found",
-			})
+			return problem.New(http.StatusNotFound, i18n.ItemNotFoundInTrash)
 		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve item",
-		})
+		return err
 	}
 
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
+
+// SearchItems は名前・ブランド・メモを全文検索し、関連度の高い順に一致した箇所とともに返す
+func (h *ItemHandler) SearchItems(c echo.Context) error {
+	criteria := usecase.SearchCriteria{Query: c.QueryParam("q")}
+	if limit, err := limitParam(c); err != nil {
+		return problem.InvalidQuery(err)
+	} else if limit != nil {
+		criteria.Limit = *limit
+	}
+	if offset, err := optionalIntParam(c, "offset"); err != nil {
+		return problem.Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, err)
+	} else if offset != nil {
+		criteria.Offset = *offset
+	}
+
+	result, err := h.itemUsecase.SearchItems(c.Request().Context(), criteria)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, SearchResponse{
+		Query:  result.Query,
+		Items:  result.Items,
+		Total:  result.Total,
+		Limit:  result.Limit,
+		Offset: result.Offset,
+		Next:   pageLink(c, result.Offset+result.Limit, result.Offset+result.Limit < result.Total),
+		Prev:   pageLink(c, max(result.Offset-result.Limit, 0), result.Offset > 0),
+	})
+}
+
+// GetFacets は一覧と同じ条件（と全文検索の q）で絞り込んだアイテムの1ページ分を、絞り込み結果全体のファセットとともに返す
+func (h *ItemHandler) GetFacets(c echo.Context) error {
+	itemCriteria, err := parseItemCriteria(c)
+	if err != nil {
+		return problem.InvalidQuery(err)
+	}
+
+	result, err := h.itemUsecase.GetFacets(c.Request().Context(), usecase.FacetCriteria{
+		ItemCriteria: itemCriteria,
+		Query:        c.QueryParam("q"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, FacetResponse{
+		Items:  result.Items,
+		Total:  result.Total,
+		Limit:  result.Limit,
+		Offset: result.Offset,
+		Next:   pageLink(c, result.Offset+result.Limit, result.Offset+result.Limit < result.Total),
+		Prev:   pageLink(c, max(result.Offset-result.Limit, 0), result.Offset > 0),
+		Facets: result.Facets,
+	})
+}
+
+// RebuildIndex はファセットの索引をデータベースから作り直す
+func (h *ItemHandler) RebuildIndex(c echo.Context) error {
+	indexed, err := h.itemUsecase.RebuildIndex(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, RebuildIndexResponse{Indexed: indexed})
+}
+
+func newItemListResponse(c echo.Context, list *usecase.ItemList) ItemListResponse {
+	return ItemListResponse{
+		Items:  list.Items,
+		Total:  list.Total,
+		Limit:  list.Limit,
+		Offset: list.Offset,
+		Next:   pageLink(c, list.Offset+list.Limit, list.Offset+list.Limit < list.Total),
+		Prev:   pageLink(c, max(list.Offset-list.Limit, 0), list.Offset > 0),
+	}
+}
+
+func (h *ItemHandler) getItemsByCursor(c echo.Context, criteria usecase.ItemCriteria) error {
+	page, err := h.itemUsecase.ListItemsByCursor(c.Request().Context(), criteria, c.QueryParam("cursor"))
+	if err != nil {
+		return err
+	}
+
+	res := ItemCursorResponse{
+		Items:   page.Items,
+		Limit:   page.Limit,
+		HasMore: page.HasMore,
+	}
+	if page.NextCursor != "" {
+		// 絞り込み条件はカーソルに含まれるため、次ページのリンクは cursor と limit のみ
+		next := fmt.Sprintf("%s?cursor=%s&limit=%d", c.Request().URL.Path, page.NextCursor, page.Limit)
+		res.NextCursor = &page.NextCursor
+		res.Next = &next
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// クエリパラメータから一覧取得条件を組み立てる
+func parseItemCriteria(c echo.Context) (usecase.ItemCriteria, error) {
+	criteria := usecase.ItemCriteria{
+		Category:         c.QueryParam("category"),
+		Brand:            c.QueryParam("brand"),
+		PurchaseDateFrom: c.QueryParam("purchase_date_from"),
+		PurchaseDateTo:   c.QueryParam("purchase_date_to"),
+		Sort:             c.QueryParam("sort"),
+		Order:            c.QueryParam("order"),
+	}
+
+	var err error
+	if criteria.MinPrice, err = optionalIntParam(c, "min_price"); err != nil {
+		return criteria, err
+	}
+	if criteria.MaxPrice, err = optionalIntParam(c, "max_price"); err != nil {
+		return criteria, err
+	}
+	if limit, err := limitParam(c); err != nil {
+		return criteria, err
+	} else if limit != nil {
+		criteria.Limit = *limit
+	}
+	if offset, err := optionalIntParam(c, "offset"); err != nil {
+		return criteria, err
+	} else if offset != nil {
+		criteria.Offset = *offset
+	}
+	criteria.Attributes = attributeParams(c)
+	// tag は複数指定でき、tag_match（all / any）で一致条件を選ぶ
+	criteria.Tags = c.QueryParams()["tag"]
+	criteria.TagMatch = c.QueryParam("tag_match")
+
+	return criteria, nil
+}
+
+// 属性での絞り込みのクエリパラメータ（attr.<キー>=<値>）の接頭辞
+const attributeParamPrefix = "attr."
+
+// attributeParams は attr.movement=自動巻き のようなクエリパラメータを属性のキーと値にする
+func attributeParams(c echo.Context) map[string]string {
+	var attributes map[string]string
+	for name, values := range c.QueryParams() {
+		key, ok := strings.CutPrefix(name, attributeParamPrefix)
+		if !ok || key == "" || len(values) == 0 {
+			continue
+		}
+		if attributes == nil {
+			attributes = map[string]string{}
+		}
+		attributes[key] = values[0]
+	}
+	return attributes
+}
+
+func optionalIntParam(c echo.Context, name string) (*int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &v, nil
+}
+
+// limitParam は limit のクエリパラメータを返す。usecase は 0 を「指定なし」とみなすため、
+// 明示的に指定された値はここで検証し、limit=0 も他の範囲外の値と同じ invalid_input にする
+func limitParam(c echo.Context) (*int, error) {
+	limit, err := optionalIntParam(c, "limit")
+	if err != nil || limit == nil {
+		return limit, err
+	}
+	if err := usecase.ValidateLimit(*limit); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+// 現在のクエリを保ったまま offset だけ差し替えたリンクを返す
+func pageLink(c echo.Context, offset int, ok bool) *string {
+	if !ok {
+		return nil
+	}
+	u := *c.Request().URL
+	q := u.Query()
+	q.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = q.Encode()
+	link := u.RequestURI()
+	return &link
+}
+
+func (h *ItemHandler) GetItem(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
+	}
+
+	item, err := h.itemUsecase.GetItemByID(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	setETag(c, item)
 	return c.JSON(http.StatusOK, item)
 }
 
 func (h *ItemHandler) CreateItem(c echo.Context) error {
 	var input usecase.CreateItemInput
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "invalid request format",
-		})
-	}
-
-	// バリデーション
-	if validationErrors := validateCreateItemInput(input); len(validationErrors) > 0 {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "validation failed",
-			Details: validationErrors,
-		})
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
 	}
 
 	item, err := h.itemUsecase.CreateItem(c.Request().Context(), input)
 	if err != nil {
-		if domainErrors.IsValidationError(err) {
-			return c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   "validation failed",
-				Details: []string{err.Error()},
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to create item",
-		})
+		return err
 	}
 
+	setETag(c, item)
 	return c.JSON(http.StatusCreated, item)
 }
 
+func (h *ItemHandler) ReplaceItem(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
+	}
+
+	versions, err := ifMatchVersions(c)
+	if err != nil {
+		return err
+	}
+
+	var input usecase.ReplaceItemInput
+	if err := c.Bind(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	item, err := h.itemUsecase.ReplaceItem(c.Request().Context(), id, input, versions)
+	if err != nil {
+		return err
+	}
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
+
 func (h *ItemHandler) DeleteItem(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "invalid item ID",
-		})
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
 	}
 
-	err = h.itemUsecase.DeleteItem(c.Request().Context(), id)
+	versions, err := ifMatchVersions(c)
 	if err != nil {
-		if domainErrors.IsNotFoundError(err) {
-			return c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "item not found",
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to delete item",
-		})
+		return err
+	}
+
+	if err := h.itemUsecase.DeleteItem(c.Request().Context(), id, versions); err != nil {
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -122,65 +398,124 @@ func (h *ItemHandler) DeleteItem(c echo.Context) error {
 func (h *ItemHandler) GetSummary(c echo.Context) error {
 	summary, err := h.itemUsecase.GetCategorySummary(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to retrieve summary",
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, summary)
 }
 
-func validateCreateItemInput(input usecase.CreateItemInput) []string {
-	var errs []string
+// PATCH で受け付ける Content-Type
+const mimeMergePatchJSON = "application/merge-patch+json"
+
+func (h *ItemHandler) UpdateItemPartially(c echo.Context) error {
+	ctx := c.Request().Context()
 
-	// Basic required field validation
-	if input.Name == "" {
-		errs = append(errs, "name is required")
+	// URLパラメータからidを取得
+	idParam := c.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
 	}
-	if input.Category == "" {
-		errs = append(errs, "category is required")
+
+	versions, err := ifMatchVersions(c)
+	if err != nil {
+		return err
 	}
-	if input.Brand == "" {
-		errs = append(errs, "brand is required")
+
+	// RFC 7396 の application/merge-patch+json と application/json を受け付ける
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mimeMergePatchJSON && mediaType != echo.MIMEApplicationJSON {
+		return problem.New(http.StatusUnsupportedMediaType, i18n.UnsupportedContentType)
 	}
-	if input.PurchaseDate == "" {
-		errs = append(errs, "purchase_date is required")
+
+	// リクエストBodyをデコード（パッチはJSONオブジェクトでなければならない）
+	var input usecase.UpdateItemInput
+	body := json.NewDecoder(c.Request().Body)
+	if err := body.Decode(&input); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
 	}
-	if input.PurchasePrice < 0 {
-		errs = append(errs, "purchase_price must be 0 or greater")
+
+	// Usecase呼び出し
+	updated, err := h.itemUsecase.UpdateItemPartially(ctx, id, input, versions)
+	if err != nil {
+		return err
 	}
 
-	return errs
+	setETag(c, updated)
+	return c.JSON(http.StatusOK, updated)
 }
 
-func (h *ItemHandler) UpdateItemPartially(c echo.Context) error {
-	ctx := c.Request().Context()
+// タグの追加リクエストの形式
+type AddTagsRequest struct {
+	Tags []string `json:"tags"`
+}
 
-	// URLパラメータからidを取得
-	idParam := c.Param("id")
-	id, err := strconv.ParseInt(idParam, 10, 64)
+// タグクラウドのレスポンスの形式
+type TagCloudResponse struct {
+	Tags []entity.TagCount `json:"tags"`
+}
+
+// AddItemTags はアイテムにタグを追加する。If-Match は必須
+func (h *ItemHandler) AddItemTags(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
 	}
 
-	// リクエストBodyをデコード
-	var input entity.Item
-	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	versions, err := ifMatchVersions(c)
+	if err != nil {
+		return err
 	}
 
-	// Usecase呼び出し
-	updated, err := h.itemUsecase.UpdateItemPartially(ctx, id, usecase.UpdateItemInput{})
-	if err != nil {
-		switch {
-		case errors.Is(err, domainErrors.ErrItemNotFound):
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "item not found"})
-		case errors.Is(err, domainErrors.ErrInvalidInput):
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid input"})
-		default:
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update item"})
+	var req AddTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
+	}
+
+	item, err := h.itemUsecase.AddItemTags(c.Request().Context(), id, req.Tags, versions)
+	if err != nil {
+		return err
+	}
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
+
+// RemoveItemTag はアイテムからタグを外す。If-Match は必須
+func (h *ItemHandler) RemoveItemTag(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, i18n.InvalidItemID)
+	}
+
+	versions, err := ifMatchVersions(c)
+	if err != nil {
+		return err
+	}
+
+	// パスに %2F などが含まれる場合、echo はエスケープされたままのパス（RawPath）から値を取り出す
+	tag := c.Param("tag")
+	if c.Request().URL.RawPath != "" {
+		if tag, err = url.PathUnescape(tag); err != nil {
+			return problem.New(http.StatusBadRequest, i18n.InvalidRequestFormat)
 		}
 	}
 
-	return c.JSON(http.StatusOK, updated)
+	item, err := h.itemUsecase.RemoveItemTags(c.Request().Context(), id, []string{tag}, versions)
+	if err != nil {
+		return err
+	}
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
+
+// GetTagCloud はタグごとのアイテム数を多い順に返す
+func (h *ItemHandler) GetTagCloud(c echo.Context) error {
+	counts, err := h.itemUsecase.GetTagCloud(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TagCloudResponse{Tags: counts})
 }
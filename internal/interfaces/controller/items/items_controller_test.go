@@ -0,0 +1,1242 @@
+package controller_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	controller "Aicon-assignment/internal/interfaces/controller/items"
+	"Aicon-assignment/internal/interfaces/i18n"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/interfaces/problem"
+	"Aicon-assignment/internal/usecase"
+)
+
+// メモリ上のリポジトリを使い、ルーティングからレスポンスまでを通して検証する
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+	return newTestServerWithBrandResolution(t, usecase.BrandResolutionLenient)
+}
+
+func newTestServerWithBrandResolution(t *testing.T, mode usecase.BrandResolutionMode) *echo.Echo {
+	t.Helper()
+	return newTestServerWithStore(t, memory.NewStore(), mode)
+}
+
+// newTestServerWithStore は store を使うサーバーを返す。opts は usecase の既定の設定に追加する
+func newTestServerWithStore(t *testing.T, store *memory.Store, mode usecase.BrandResolutionMode, opts ...usecase.ItemUsecaseOption) *echo.Echo {
+	t.Helper()
+
+	repo := memory.NewItemRepository(store)
+	brandRepo := memory.NewBrandRepository(store)
+	itemUsecase := usecase.NewItemUsecase(repo, append([]usecase.ItemUsecaseOption{
+		usecase.WithCursorSecret([]byte("test-secret")),
+		usecase.WithTransactor(store),
+		usecase.WithAuditTrail(memory.NewItemEventRepository(store)),
+		usecase.WithBrandCatalog(brandRepo, mode),
+		usecase.WithItemIndex(memory.NewItemIndex()),
+	}, opts...)...)
+	h := controller.NewItemHandler(itemUsecase)
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	e.Use(i18n.Middleware(i18n.English))
+	e.GET("/items", h.GetItems)
+	e.POST("/items", h.CreateItem)
+	e.GET("/items/summary", h.GetSummary)
+	e.GET("/items/search", h.SearchItems)
+	e.GET("/items/facets", h.GetFacets)
+	e.POST("/items/facets/rebuild", h.RebuildIndex)
+	e.POST("/items/import", h.ImportItems)
+	e.GET("/items/export", h.ExportItems)
+	e.POST("/items/batch", h.BatchItems)
+	e.GET("/items/trash", h.GetTrashedItems)
+	e.GET("/items/:id", h.GetItem)
+	e.PUT("/items/:id", h.ReplaceItem)
+	e.PATCH("/items/:id", h.UpdateItemPartially)
+	e.DELETE("/items/:id", h.DeleteItem)
+	e.POST("/items/:id/restore", h.RestoreItem)
+	e.POST("/items/:id/tags", h.AddItemTags)
+	e.DELETE("/items/:id/tags/:tag", h.RemoveItemTag)
+	e.GET("/tags", h.GetTagCloud)
+
+	require.NoError(t, memory.SeedSampleBrands(context.Background(), brandRepo))
+	require.NoError(t, memory.SeedSampleItems(context.Background(), repo, brandRepo))
+	_, err := itemUsecase.RebuildIndex(context.Background())
+	require.NoError(t, err)
+	return e
+}
+
+func doRequest(e *echo.Echo, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestItemHandler_CreateAndGet(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "正常系: アイテムを作成",
+			body:           `{"name":"ロレックス サブマリーナ","category":"時計","brand":"ROLEX","purchase_price":1200000,"purchase_date":"2023-05-01"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "異常系: 必須項目が不足",
+			body:           `{"name":"名前だけ"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: 不正なカテゴリー",
+			body:           `{"name":"テスト","category":"家電","brand":"Brand","purchase_price":1000,"purchase_date":"2023-05-01"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(e, http.MethodPost, "/items", tt.body, nil)
+			require.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			if rec.Code != http.StatusCreated {
+				return
+			}
+
+			var created entity.Item
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+			assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+
+			rec = doRequest(e, http.MethodGet, "/items/"+itoa(created.ID), "", nil)
+			require.Equal(t, http.StatusOK, rec.Code)
+			var found entity.Item
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &found))
+			assert.Equal(t, created.Name, found.Name)
+			assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+		})
+	}
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/9999", "", nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestItemHandler_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedFields map[string]entity.ValidationCode
+	}{
+		{
+			name:   "異常系: POST で必須項目が不足",
+			method: http.MethodPost,
+			target: "/items",
+			body:   `{"name":"名前だけ","purchase_price":-1}`,
+			expectedFields: map[string]entity.ValidationCode{
+				"category":       entity.ValidationRequired,
+				"brand":          entity.ValidationRequired,
+				"purchase_price": entity.ValidationOutOfRange,
+				"purchase_date":  entity.ValidationRequired,
+			},
+		},
+		{
+			name:   "異常系: PUT で不正なカテゴリーと日付",
+			method: http.MethodPut,
+			target: "/items/1",
+			body:   `{"name":"置き換え","category":"家電","brand":"ROLEX","purchase_price":1000,"purchase_date":"2023/01/01"}`,
+			expectedFields: map[string]entity.ValidationCode{
+				"category":      entity.ValidationInvalidEnum,
+				"purchase_date": entity.ValidationInvalidFormat,
+			},
+		},
+		{
+			name:   "異常系: PATCH で null にできない項目と長すぎる名前",
+			method: http.MethodPatch,
+			target: "/items/1",
+			body:   `{"purchase_price":null,"name":"` + strings.Repeat("a", 101) + `"}`,
+			expectedFields: map[string]entity.ValidationCode{
+				"purchase_price": entity.ValidationRequired,
+				"name":           entity.ValidationTooLong,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(t)
+
+			rec := doRequest(e, tt.method, tt.target, tt.body, map[string]string{"If-Match": "*"})
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+			var res problem.Details
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, "validation_failed", res.Code)
+			assert.Equal(t, "validation failed", res.Title)
+			fields := map[string]entity.ValidationCode{}
+			for _, fe := range res.Errors {
+				fields[fe.Field] = fe.Code
+				assert.NotEmpty(t, fe.Message)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestItemHandler_LocalizedErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		acceptLanguage string
+		expectedStatus int
+		expectedCode   string
+		expectedTitle  string
+		expectedDetail string
+	}{
+		{
+			name:           "正常系: Accept-Language が無ければデフォルトの英語",
+			method:         http.MethodGet,
+			target:         "/items/9999",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "item_not_found",
+			expectedTitle:  "item not found",
+		},
+		{
+			name:           "正常系: 日本語のメッセージ",
+			method:         http.MethodGet,
+			target:         "/items/9999",
+			acceptLanguage: "ja-JP,ja;q=0.9,en;q=0.8",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "item_not_found",
+			expectedTitle:  "アイテムが見つかりません",
+		},
+		{
+			name:           "正常系: 対応していない言語はデフォルトの英語",
+			method:         http.MethodGet,
+			target:         "/items/abc",
+			acceptLanguage: "fr-FR",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_item_id",
+			expectedTitle:  "invalid item ID",
+		},
+		{
+			name:           "正常系: バリデーションの詳細も日本語",
+			method:         http.MethodPost,
+			target:         "/items",
+			body:           `{"name":"テスト","category":"家電","brand":"ROLEX","purchase_price":1000}`,
+			acceptLanguage: "ja",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "validation_failed",
+			expectedTitle:  "入力内容に誤りがあります",
+			expectedDetail: "カテゴリーは時計、バッグ、ジュエリー、靴、その他のいずれかを指定してください、購入日は必須です",
+		},
+		{
+			name:           "正常系: バリデーションの詳細は英語なら従来どおり",
+			method:         http.MethodPost,
+			target:         "/items",
+			body:           `{"name":"テスト","category":"家電","brand":"ROLEX","purchase_price":1000}`,
+			acceptLanguage: "en-US",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "validation_failed",
+			expectedTitle:  "validation failed",
+			expectedDetail: "category must be one of: 時計, バッグ, ジュエリー, 靴, その他, purchase_date is required",
+		},
+		{
+			name:           "正常系: 不正な検索条件の詳細も日本語",
+			method:         http.MethodGet,
+			target:         "/items?limit=500",
+			acceptLanguage: "ja",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_input",
+			expectedTitle:  "入力内容が不正です",
+			expectedDetail: "limitは1以上100以下で指定してください",
+		},
+		{
+			name:           "正常系: 明示的な limit=0 も範囲外として扱う",
+			method:         http.MethodGet,
+			target:         "/items?limit=0",
+			acceptLanguage: "en",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_input",
+			expectedTitle:  "invalid input",
+			expectedDetail: "limit must be between 1 and 100",
+		},
+		{
+			name:           "正常系: If-Match が無い場合の 428 も日本語",
+			method:         http.MethodDelete,
+			target:         "/items/1",
+			acceptLanguage: "ja",
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedCode:   "precondition_required",
+			expectedTitle:  "If-Match ヘッダを指定してください",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(t)
+
+			headers := map[string]string{}
+			if tt.acceptLanguage != "" {
+				headers["Accept-Language"] = tt.acceptLanguage
+			}
+			rec := doRequest(e, tt.method, tt.target, tt.body, headers)
+			require.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+
+			var res problem.Details
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedCode, res.Code)
+			assert.Equal(t, tt.expectedTitle, res.Title)
+			if tt.expectedDetail != "" {
+				assert.Equal(t, tt.expectedDetail, res.Detail)
+			}
+		})
+	}
+}
+
+func TestItemHandler_ConditionalWrites(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		ifMatch        string
+		expectedStatus int
+	}{
+		{
+			name:           "正常系: PATCH で一致するバージョンを指定",
+			method:         http.MethodPatch,
+			body:           `{"purchase_price":2000000}`,
+			ifMatch:        `"1"`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: PATCH で古いバージョンを指定",
+			method:         http.MethodPatch,
+			body:           `{"purchase_price":2000000}`,
+			ifMatch:        `"2"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "正常系: PATCH で複数の ETag のいずれかが一致",
+			method:         http.MethodPatch,
+			body:           `{"purchase_price":2000000}`,
+			ifMatch:        `"3", W/"2", "1"`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: PATCH で複数の ETag のいずれも一致しない",
+			method:         http.MethodPatch,
+			body:           `{"purchase_price":2000000}`,
+			ifMatch:        `"2", "3"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "異常系: PATCH で If-Match なし",
+			method:         http.MethodPatch,
+			body:           `{"purchase_price":2000000}`,
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:           "正常系: PUT で If-Match: *",
+			method:         http.MethodPut,
+			body:           `{"name":"置き換え","category":"時計","brand":"ROLEX","purchase_price":1000,"purchase_date":"2023-01-01"}`,
+			ifMatch:        "*",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "正常系: DELETE で一致するバージョンを指定",
+			method:         http.MethodDelete,
+			ifMatch:        `"1"`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "異常系: DELETE で If-Match なし",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestServer(t)
+			headers := map[string]string{}
+			if tt.ifMatch != "" {
+				headers["If-Match"] = tt.ifMatch
+			}
+
+			rec := doRequest(e, tt.method, "/items/1", tt.body, headers)
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestItemHandler_DeleteAndRestore(t *testing.T) {
+	e := newTestServer(t)
+
+	rec := doRequest(e, http.MethodDelete, "/items/1", "", map[string]string{"If-Match": `"1"`})
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = doRequest(e, http.MethodGet, "/items/1", "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(e, http.MethodGet, "/items/trash", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trash controller.ItemListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trash))
+	assert.Equal(t, 1, trash.Total)
+
+	rec = doRequest(e, http.MethodPost, "/items/1/restore", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = doRequest(e, http.MethodGet, "/items/1", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestItemHandler_GetItems(t *testing.T) {
+	e := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{
+			name:           "正常系: 全件",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  5,
+		},
+		{
+			name:           "正常系: カテゴリーで絞り込み",
+			query:          "?category=時計",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  1,
+		},
+		{
+			name:           "正常系: 価格の範囲で絞り込み",
+			query:          "?min_price=1000000",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  2,
+		},
+		{
+			name:           "異常系: 数値でない価格",
+			query:          "?min_price=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(e, http.MethodGet, "/items"+tt.query, "", nil)
+			require.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var list controller.ItemListResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			assert.Equal(t, tt.expectedTotal, list.Total)
+		})
+	}
+}
+
+func TestItemHandler_GetSummary(t *testing.T) {
+	e := newTestServer(t)
+
+	rec := doRequest(e, http.MethodGet, "/items/summary", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var summary usecase.CategorySummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, 5, summary.Total)
+	assert.Equal(t, 1, summary.Categories["時計"])
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func TestItemHandler_BrandCatalog(t *testing.T) {
+	t.Run("正常系: 別名で登録したアイテムはカタログのブランドに紐づく", func(t *testing.T) {
+		e := newTestServer(t)
+
+		rec := doRequest(e, http.MethodPost, "/items",
+			`{"name":"サブマリーナ","category":"時計","brand":"ﾛﾚｯｸｽ","purchase_price":1200000,"purchase_date":"2023-05-01"}`, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var created entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Equal(t, "ロレックス", created.Brand)
+		assert.Equal(t, "ROLEX", created.BrandCanonical)
+		require.NotNil(t, created.BrandID)
+
+		// 正式名でも別名でも同じアイテムが絞り込まれる
+		for _, brand := range []string{"ROLEX", "rolex", "ロレックス"} {
+			rec = doRequest(e, http.MethodGet, "/items?brand="+url.QueryEscape(brand), "", nil)
+			require.Equal(t, http.StatusOK, rec.Code)
+			var list usecase.ItemList
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			assert.Equal(t, 2, list.Total, brand)
+		}
+	})
+
+	t.Run("正常系: lenient ではカタログに無いブランドも登録できる", func(t *testing.T) {
+		e := newTestServer(t)
+
+		rec := doRequest(e, http.MethodPost, "/items",
+			`{"name":"ノーチラス","category":"時計","brand":"Patek Philippe","purchase_price":5000000,"purchase_date":"2023-05-01"}`, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var created entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Nil(t, created.BrandID)
+		assert.Equal(t, "PATEK PHILIPPE", created.BrandCanonical)
+	})
+
+	t.Run("異常系: strict ではカタログに無いブランドを候補付きで拒否する", func(t *testing.T) {
+		e := newTestServerWithBrandResolution(t, usecase.BrandResolutionStrict)
+
+		rec := doRequest(e, http.MethodPost, "/items",
+			`{"name":"デイトナ","category":"時計","brand":"Rolx","purchase_price":1500000,"purchase_date":"2023-05-01"}`,
+			map[string]string{"Accept-Language": "ja"})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var res problem.Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "validation_failed", res.Code)
+		assert.Equal(t, "ブランドが登録されていません", res.Detail)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, entity.ValidationNotRegistered, res.Errors[0].Code)
+		assert.Equal(t, []interface{}{"ROLEX"}, res.Errors[0].Params["suggestions"])
+	})
+}
+
+func TestItemHandler_Attributes(t *testing.T) {
+	store := memory.NewStore()
+	registry := usecase.NewCategoryRegistry(memory.NewCategoryRepository(store))
+	require.NoError(t, registry.Load(context.Background()))
+	e := newTestServerWithStore(t, store, usecase.BrandResolutionLenient, usecase.WithCategoryRegistry(registry))
+
+	rec := doRequest(e, http.MethodPost, "/items",
+		`{"name":"サブマリーナ","category":"時計","brand":"ROLEX","purchase_price":1200000,"purchase_date":"2023-05-01",`+
+			`"attributes":{"reference_number":"126610LN","movement":"自動巻き","case_size":41}}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, map[string]interface{}{"reference_number": "126610LN", "movement": "自動巻き", "case_size": 41.0}, created["attributes"])
+
+	t.Run("正常系: 属性が無いアイテムは空のオブジェクト", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/2", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"attributes":{}`)
+	})
+
+	t.Run("正常系: 属性で絞り込む", func(t *testing.T) {
+		for _, query := range []string{"attr.movement=" + url.QueryEscape("自動巻き"), "attr.case_size=41.0", "category=" + url.QueryEscape("時計") + "&attr.reference_number=126610LN"} {
+			rec := doRequest(e, http.MethodGet, "/items?"+query, "", nil)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var list controller.ItemListResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			require.Equal(t, 1, list.Total, query)
+			assert.Equal(t, "サブマリーナ", list.Items[0].Name)
+		}
+	})
+
+	t.Run("異常系: 定義されていない属性での絞り込み", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items?attr.color=black", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系: 属性が定義に合わない", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPost, "/items",
+			`{"name":"スピードマスター","category":"時計","brand":"OMEGA","purchase_price":800000,"purchase_date":"2023-05-01",`+
+				`"attributes":{"movement":"ソーラー","color":"黒"}}`,
+			map[string]string{"Accept-Language": "ja"})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var res problem.Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Errors, 2)
+		assert.Equal(t, "attributes.movement", res.Errors[0].Field)
+		assert.Equal(t, entity.ValidationInvalidEnum, res.Errors[0].Code)
+		assert.Equal(t, "attributes.color", res.Errors[1].Field)
+		assert.Equal(t, entity.ValidationUnknownField, res.Errors[1].Code)
+	})
+
+	t.Run("正常系: PATCH で属性をキーごとに更新する", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPatch, "/items/"+itoa(int64(created["id"].(float64))),
+			`{"attributes":{"case_size":null,"movement":"手巻き"}}`, map[string]string{"If-Match": `"1"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var updated entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		assert.Equal(t, entity.Attributes{"reference_number": "126610LN", "movement": "手巻き"}, updated.Attributes)
+	})
+}
+
+func TestItemHandler_Tags(t *testing.T) {
+	e := newTestServer(t)
+
+	addTags := func(t *testing.T, id int64, body string, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		return doRequest(e, http.MethodPost, "/items/"+itoa(id)+"/tags", body, headers)
+	}
+	listNames := func(t *testing.T, query string) []string {
+		t.Helper()
+		rec := doRequest(e, http.MethodGet, "/items?"+query, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var list controller.ItemListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		names := []string{}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	t.Run("正常系: タグが無いアイテムは空の配列", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/1", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"tags":[]`)
+	})
+
+	t.Run("正常系: タグを追加するとバージョンが上がる", func(t *testing.T) {
+		rec := addTags(t, 1, `{"tags":["投資用"," Wedding Gifts "]}`, map[string]string{"If-Match": `"1"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var item entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+		assert.Equal(t, entity.Tags{"wedding gifts", "投資用"}, item.Tags)
+		assert.Equal(t, int64(2), item.Version)
+		assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+		// 付いているタグの追加は何もしない
+		rec = addTags(t, 1, `{"tags":["投資用"]}`, map[string]string{"If-Match": `"2"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+		rec = addTags(t, 2, `{"tags":["投資用"]}`, map[string]string{"If-Match": "*"})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	})
+
+	t.Run("正常系: タグで絞り込む", func(t *testing.T) {
+		investment := url.QueryEscape("投資用")
+		assert.Len(t, listNames(t, "tag="+investment), 2)
+		assert.Len(t, listNames(t, "tag="+investment+"&tag=wedding+gifts"), 1)
+		assert.Len(t, listNames(t, "tag="+investment+"&tag=wedding+gifts&tag_match=any"), 2)
+		assert.Empty(t, listNames(t, "tag=sale"))
+	})
+
+	t.Run("正常系: タグクラウド", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/tags", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"tags":[{"name":"投資用","count":2},{"name":"wedding gifts","count":1}]}`, rec.Body.String())
+	})
+
+	t.Run("正常系: タグを外す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodDelete, "/items/1/tags/"+url.PathEscape("Wedding Gifts"), "", map[string]string{"If-Match": `"2"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var item entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+		assert.Equal(t, entity.Tags{"投資用"}, item.Tags)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("異常系: If-Match が無い", func(t *testing.T) {
+		rec := addTags(t, 1, `{"tags":["売却予定"]}`, nil)
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+		rec = doRequest(e, http.MethodDelete, "/items/1/tags/"+url.PathEscape("投資用"), "", nil)
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	})
+
+	t.Run("異常系: If-Match が一致しない", func(t *testing.T) {
+		rec := addTags(t, 1, `{"tags":["売却予定"]}`, map[string]string{"If-Match": `"1"`})
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+		rec = doRequest(e, http.MethodDelete, "/items/1/tags/"+url.PathEscape("投資用"), "", map[string]string{"If-Match": `"2"`})
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	})
+
+	t.Run("異常系: 追加するタグが無い・長すぎる", func(t *testing.T) {
+		rec := addTags(t, 1, `{"tags":[" "]}`, map[string]string{"If-Match": `"3"`})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = addTags(t, 1, `{"tags":["`+strings.Repeat("a", entity.MaxTagLength+1)+`"]}`, map[string]string{"If-Match": `"3"`})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var res problem.Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, entity.ValidationTooLong, res.Errors[0].Code)
+	})
+
+	t.Run("異常系: 一致条件が不正", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items?tag=sale&tag_match=none", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		rec := addTags(t, 9999, `{"tags":["売却予定"]}`, map[string]string{"If-Match": "*"})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestItemHandler_SearchItems(t *testing.T) {
+	e := newTestServer(t)
+
+	search := func(t *testing.T, query string) controller.SearchResponse {
+		t.Helper()
+		rec := doRequest(e, http.MethodGet, "/items/search?"+query, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res controller.SearchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+
+	t.Run("正常系: メモを保存して検索できる", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPatch, "/items/1", `{"notes":"2023年にオーバーホール済み。\r\n箱・保証書あり"}`, map[string]string{"If-Match": `"1"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Body.String(), `"notes":"2023年にオーバーホール済み。\n箱・保証書あり"`)
+
+		res := search(t, "q="+url.QueryEscape("ﾎｼｮｳｼｮ"))
+		assert.Equal(t, 0, res.Total, "読みは変換しない")
+
+		res = search(t, "q="+url.QueryEscape("保証書 ろれっくす"))
+		require.Equal(t, 1, res.Total)
+		assert.Equal(t, "ロレックス デイトナ", res.Items[0].Name)
+		assert.Equal(t, []entity.Highlight{
+			{Field: "name", Fragment: "ロレックス デイトナ", Matches: []entity.TextRange{{Start: 0, End: 5}}},
+			{Field: "notes", Fragment: "2023年にオーバーホール済み。\n箱・保証書あり", Matches: []entity.TextRange{{Start: 19, End: 22}}},
+		}, res.Items[0].Highlights)
+	})
+
+	t.Run("正常系: ページングのリンクを返す", func(t *testing.T) {
+		res := search(t, "q=a&limit=1")
+		assert.Greater(t, res.Total, 1)
+		assert.Len(t, res.Items, 1)
+		require.NotNil(t, res.Next)
+		assert.Contains(t, *res.Next, "offset=1")
+		assert.Nil(t, res.Prev)
+	})
+
+	t.Run("異常系: 検索語が無い", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/search?q=+", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系: limit が数値でない", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/search?q=a&limit=x", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("異常系: limit=0 は既定値にせず範囲外とする", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/search?q=a&limit=0", "", nil)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var res problem.Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "invalid_input", res.Code)
+	})
+}
+
+func TestItemHandler_GetFacets(t *testing.T) {
+	e := newTestServer(t)
+
+	facets := func(t *testing.T, query string) controller.FacetResponse {
+		t.Helper()
+		rec := doRequest(e, http.MethodGet, "/items/facets?"+query, "", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res controller.FacetResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+
+	t.Run("正常系: 絞り込み結果全体のファセットを返す", func(t *testing.T) {
+		res := facets(t, "limit=2")
+
+		assert.Equal(t, 5, res.Total)
+		assert.Len(t, res.Items, 2)
+		require.NotNil(t, res.Next)
+		assert.Len(t, res.Facets.Category, 5)
+		assert.Equal(t, []usecase.FacetCount{{Value: "2023", Count: 5}}, res.Facets.PurchaseYear)
+		counts := []int{}
+		for _, bucket := range res.Facets.PriceRange {
+			counts = append(counts, bucket.Count)
+		}
+		assert.Equal(t, []int{1, 2, 0, 2, 0}, counts)
+	})
+
+	t.Run("正常系: 一覧の条件と検索語で絞り込む", func(t *testing.T) {
+		res := facets(t, "min_price=1000000&q="+url.QueryEscape("ろれっくす"))
+
+		require.Equal(t, 1, res.Total)
+		assert.Equal(t, "ロレックス デイトナ", res.Items[0].Name)
+		assert.Equal(t, []usecase.FacetCount{{Value: "ROLEX", Count: 1}}, res.Facets.Brand)
+	})
+
+	t.Run("正常系: 書き込みを反映する", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPost, "/items", `{"name":"オメガ スピードマスター","category":"時計","brand":"OMEGA","purchase_price":600000,"purchase_date":"2024-02-01"}`, nil)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		rec = doRequest(e, http.MethodDelete, "/items/2", "", map[string]string{"If-Match": `"1"`})
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+		res := facets(t, "")
+		assert.Equal(t, 5, res.Total)
+		assert.Equal(t, []usecase.FacetCount{{Value: "時計", Count: 2}}, res.Facets.Category[:1])
+		assert.Equal(t, []usecase.FacetCount{{Value: "2024", Count: 1}, {Value: "2023", Count: 4}}, res.Facets.PurchaseYear)
+	})
+
+	t.Run("正常系: 索引を作り直す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPost, "/items/facets/rebuild", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"indexed":5}`, rec.Body.String())
+	})
+
+	t.Run("異常系: 条件が不正", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/facets?sort=unknown", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = doRequest(e, http.MethodGet, "/items/facets?min_price=x", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestItemHandler_ImportItems(t *testing.T) {
+	importCSV := func(t *testing.T, e *echo.Echo, query, contentType, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		return doRequest(e, http.MethodPost, "/items/import"+query, body, map[string]string{echo.HeaderContentType: contentType})
+	}
+	decode := func(t *testing.T, rec *httptest.ResponseRecorder) controller.ImportResponse {
+		t.Helper()
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res controller.ImportResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+	total := func(t *testing.T, e *echo.Echo) int {
+		t.Helper()
+		rec := doRequest(e, http.MethodGet, "/items", "", nil)
+		var res controller.ItemListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res.Total
+	}
+
+	t.Run("正常系: Shift_JIS の日本語の列名を読み替えて登録する", func(t *testing.T) {
+		store := memory.NewStore()
+		registry := usecase.NewCategoryRegistry(memory.NewCategoryRepository(store))
+		require.NoError(t, registry.Load(context.Background()))
+		e := newTestServerWithStore(t, store, usecase.BrandResolutionLenient, usecase.WithCategoryRegistry(registry))
+		body, err := japanese.ShiftJIS.NewEncoder().String("名前,カテゴリー,ブランド,購入価格,購入日,タグ,attr.case_size,在庫\r\n" +
+			"オメガ スピードマスター,時計,OMEGA,\"￥600,000\",2024/2/1,新品|保証書付き,42,1\r\n")
+		require.NoError(t, err)
+
+		res := decode(t, importCSV(t, e, "", "text/csv", body))
+
+		assert.Equal(t, 1, res.Created)
+		assert.Equal(t, []string{"在庫"}, res.IgnoredColumns)
+		require.NotNil(t, res.Rows[0].ID)
+		rec := doRequest(e, http.MethodGet, "/items/"+itoa(*res.Rows[0].ID), "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var item entity.Item
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+		assert.Equal(t, "オメガ スピードマスター", item.Name)
+		assert.Equal(t, 600000, item.PurchasePrice)
+		assert.Equal(t, "2024-02-01", item.PurchaseDate)
+		assert.Equal(t, entity.Tags{"保証書付き", "新品"}, item.Tags)
+		assert.Equal(t, 42.0, item.Attributes["case_size"])
+	})
+
+	t.Run("正常系: atomic では失敗した行があれば何も登録せず、行ごとの結果を返す", func(t *testing.T) {
+		e := newTestServer(t)
+		body := "\ufeffname,category,brand,purchase_price,purchase_date\n" +
+			"オメガ スピードマスター,時計,OMEGA,600000,2024-02-01\n" +
+			",,,,\n" +
+			"不正な行,家電,OMEGA,高い,2024-02-01\n"
+
+		res := decode(t, importCSV(t, e, "", "text/csv; charset=utf-8", body))
+
+		assert.Equal(t, usecase.ImportModeAtomic, res.Mode)
+		assert.Equal(t, [3]int{0, 2, 1}, [3]int{res.Created, res.Skipped, res.Failed})
+		assert.Equal(t, usecase.ImportReasonRolledBack, res.Rows[0].Reason)
+		assert.Equal(t, usecase.ImportReasonEmptyRow, res.Rows[1].Reason)
+		assert.Equal(t, 4, res.Rows[2].Line)
+		fields := []string{}
+		for _, fe := range res.Rows[2].Errors {
+			fields = append(fields, fe.Field)
+		}
+		assert.ElementsMatch(t, []string{"purchase_price", "category"}, fields)
+		assert.Equal(t, 5, total(t, e))
+	})
+
+	t.Run("正常系: best_effort では正しい行だけを登録し、dry_run では登録しない", func(t *testing.T) {
+		e := newTestServer(t)
+		body := "名前,カテゴリ,ブランド,価格,購入日\n" +
+			"オメガ スピードマスター,時計,OMEGA,600000,2024-02-01\n" +
+			"不正な行,時計,OMEGA,600000,2024-13-01\n"
+
+		res := decode(t, importCSV(t, e, "?mode=best_effort&dry_run=true", "text/csv", body))
+		assert.Equal(t, [3]int{1, 0, 1}, [3]int{res.Created, res.Skipped, res.Failed})
+		assert.Equal(t, 5, total(t, e))
+
+		res = decode(t, importCSV(t, e, "?mode=best_effort", "text/csv", body))
+		assert.Equal(t, [3]int{1, 0, 1}, [3]int{res.Created, res.Skipped, res.Failed})
+		assert.Equal(t, 6, total(t, e))
+	})
+
+	t.Run("正常系: クエリパラメータで列を読み替え、エラーを Accept-Language の言語で返す", func(t *testing.T) {
+		e := newTestServer(t)
+		body := "品名,カテゴリー,ブランド,購入価格,購入日\n,時計,OMEGA,600000,2024-02-01\n"
+		rec := doRequest(e, http.MethodPost, "/items/import?map."+url.QueryEscape("品名")+"=name", body, map[string]string{
+			echo.HeaderContentType: "text/csv",
+			"Accept-Language":      "ja",
+		})
+
+		res := decode(t, rec)
+		require.Len(t, res.Rows[0].Errors, 1)
+		assert.Equal(t, "名前は必須です", res.Rows[0].Errors[0].Message)
+	})
+
+	t.Run("異常系: リクエストが不正", func(t *testing.T) {
+		e := newTestServer(t)
+		header := "name,category,brand,purchase_price,purchase_date\n"
+
+		tests := []struct {
+			name           string
+			query          string
+			contentType    string
+			body           string
+			expectedStatus int
+			expectedCode   string
+		}{
+			{name: "CSV でない", contentType: echo.MIMEApplicationJSON, body: "{}", expectedStatus: http.StatusUnsupportedMediaType, expectedCode: string(i18n.UnsupportedImportFile)},
+			{name: "対応していない文字コード", contentType: "text/csv; charset=euc-jp", body: header, expectedStatus: http.StatusUnsupportedMediaType, expectedCode: string(i18n.UnsupportedImportFile)},
+			{name: "必須の列が無い", contentType: "text/csv", body: "name,category\n", expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidCSVHeader)},
+			{name: "同じフィールドの列が複数ある", contentType: "text/csv", body: "名前,name,category,brand,purchase_price,purchase_date\n", expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidCSVHeader)},
+			{name: "読み替え先のフィールドが不正", query: "?map.name=title", contentType: "text/csv", body: header, expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidCSVHeader)},
+			{name: "空のファイル", contentType: "text/csv", body: "", expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidCSVHeader)},
+			{name: "dry_run が真偽値でない", query: "?dry_run=maybe", contentType: "text/csv", body: header, expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidQueryParameter)},
+			{name: "不正な方式", query: "?mode=all", contentType: "text/csv", body: header, expectedStatus: http.StatusBadRequest, expectedCode: string(i18n.InvalidInput)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := importCSV(t, e, tt.query, tt.contentType, tt.body)
+
+				assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+				var p problem.Details
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+				assert.Equal(t, tt.expectedCode, p.Code)
+			})
+		}
+		assert.Equal(t, 5, total(t, e))
+	})
+}
+
+func TestItemHandler_ExportItems(t *testing.T) {
+	e := newTestServer(t)
+
+	t.Run("正常系: 一覧と同じ条件で絞り込み、BOM 付きの CSV で返す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodPatch, "/items/1", `{"notes":"箱・保証書あり\n2023年購入"}`, map[string]string{"If-Match": `"1"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		rec = doRequest(e, http.MethodPost, "/items/1/tags", `{"tags":["新品","保証書付き"]}`, map[string]string{"If-Match": `"2"`})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		rec = doRequest(e, http.MethodGet, "/items/export?format=csv&bom=true&min_price=300000&sort=purchase_price&order=desc&limit=1", "", nil)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Regexp(t, `^attachment; filename="items-\d{8}\.csv"$`, rec.Header().Get(echo.HeaderContentDisposition))
+		body := rec.Body.String()
+		require.True(t, strings.HasPrefix(body, "\ufeff"))
+		records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(body, "\ufeff"))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4, "ページングは使わない")
+		assert.Equal(t, []string{"id", "name", "category", "brand", "purchase_price", "purchase_date", "notes", "tags", "attributes", "created_at", "updated_at"}, records[0])
+		assert.Equal(t, []string{"エルメス バーキン", "ロレックス デイトナ", "ティファニー ネックレス"}, []string{records[1][1], records[2][1], records[3][1]})
+		assert.Equal(t, []string{"1", "ロレックス デイトナ", "時計", "ROLEX", "1500000", "2023-01-15", "箱・保証書あり\n2023年購入", "保証書付き|新品", ""}, records[2][:9])
+	})
+
+	t.Run("正常系: 書き出した CSV はそのまま取り込める", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/export?category="+url.QueryEscape("時計"), "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, strings.HasPrefix(rec.Body.String(), "\ufeff"), "BOM はデフォルトで付けない")
+
+		rec = doRequest(e, http.MethodPost, "/items/import?dry_run=true", rec.Body.String(), map[string]string{echo.HeaderContentType: "text/csv"})
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res controller.ImportResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, 1, res.Created)
+		assert.Equal(t, []string{"id", "attributes", "created_at", "updated_at"}, res.IgnoredColumns)
+	})
+
+	t.Run("正常系: JSON Lines で返す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/export?format=jsonl&sort=name&order=asc", "", nil)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 5)
+		var item entity.Item
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &item))
+		assert.NotZero(t, item.ID)
+		assert.NotEmpty(t, item.Name)
+	})
+
+	t.Run("正常系: XLSX で返す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/export?format=xlsx&brand=rolex", "", nil)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", rec.Header().Get(echo.HeaderContentType))
+		zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		require.NoError(t, err)
+		var sheet string
+		for _, f := range zr.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				rc, err := f.Open()
+				require.NoError(t, err)
+				var buf bytes.Buffer
+				_, err = buf.ReadFrom(rc)
+				require.NoError(t, err)
+				sheet = buf.String()
+			}
+		}
+		assert.Contains(t, sheet, "ロレックス デイトナ")
+		assert.Contains(t, sheet, "<c><v>1500000</v></c>")
+		assert.NotContains(t, sheet, "エルメス")
+	})
+
+	t.Run("正常系: CSV では数式として解釈される値の先頭に ' を付け、取り込むと元に戻る", func(t *testing.T) {
+		e := newTestServer(t)
+		name := `=HYPERLINK("http://example.com","詳細")`
+		rec := doRequest(e, http.MethodPost, "/items", `{"name":`+strconv.Quote(name)+`,"category":"その他","brand":"Acme","purchase_price":1000,`+
+			`"purchase_date":"2023-01-01","notes":"@SUM(A1:A2)","tags":["-1+1"]}`, nil)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		rec = doRequest(e, http.MethodGet, "/items/export?brand=Acme", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "'"+name, records[1][1])
+		assert.Equal(t, "その他", records[1][2])
+		assert.Equal(t, "'@SUM(A1:A2)", records[1][6])
+		assert.Equal(t, "'-1+1", records[1][7])
+
+		rec = doRequest(e, http.MethodPost, "/items/import", rec.Body.String(), map[string]string{echo.HeaderContentType: "text/csv"})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		rec = doRequest(e, http.MethodGet, "/items/export?brand=Acme&format=jsonl", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 2, "書き出したアイテムと取り込んだアイテム")
+		for _, line := range lines {
+			var item entity.Item
+			require.NoError(t, json.Unmarshal([]byte(line), &item))
+			assert.Equal(t, name, item.Name)
+			assert.Equal(t, "@SUM(A1:A2)", item.Notes)
+			assert.Equal(t, entity.Tags{"-1+1"}, item.Tags)
+		}
+	})
+
+	t.Run("正常系: 該当が無い場合はヘッダー行だけを返す", func(t *testing.T) {
+		rec := doRequest(e, http.MethodGet, "/items/export?min_price=100000000", "", nil)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "id,name,category,brand,purchase_price,purchase_date,notes,tags,attributes,created_at,updated_at\n", rec.Body.String())
+	})
+
+	t.Run("異常系: リクエストが不正", func(t *testing.T) {
+		for _, query := range []string{"format=pdf", "bom=maybe", "sort=unknown", "min_price=x"} {
+			rec := doRequest(e, http.MethodGet, "/items/export?"+query, "", nil)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.Equal(t, problem.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType), query)
+		}
+	})
+}
+
+// failingEventRepository は action の監査記録の保存だけをデータベースのエラーにする
+type failingEventRepository struct {
+	usecase.ItemEventRepository
+	action string
+}
+
+func (r *failingEventRepository) Append(ctx context.Context, event *entity.ItemEvent) error {
+	if event.Action == r.action {
+		return domainErrors.ErrDatabaseError
+	}
+	return r.ItemEventRepository.Append(ctx, event)
+}
+
+func TestItemHandler_BatchItems(t *testing.T) {
+	batch := func(t *testing.T, e *echo.Echo, body string, headers map[string]string) controller.BatchResponse {
+		t.Helper()
+		rec := doRequest(e, http.MethodPost, "/items/batch", body, headers)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res controller.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+	statuses := func(res controller.BatchResponse) []string {
+		var s []string
+		for _, result := range res.Results {
+			s = append(s, result.Op+"/"+result.Status)
+		}
+		return s
+	}
+	getItem := func(t *testing.T, e *echo.Echo, id int64) *httptest.ResponseRecorder {
+		t.Helper()
+		return doRequest(e, http.MethodGet, "/items/"+strconv.FormatInt(id, 10), "", nil)
+	}
+	const operations = `[
+		{"op":"create","item":{"name":"サブマリーナ","category":"時計","brand":"ROLEX","purchase_price":1200000,"purchase_date":"2023-05-01","tags":["投資用"]}},
+		{"op":"update","id":1,"version":1,"item":{"notes":"箱・保証書あり","purchase_price":1450000}},
+		{"op":"delete","id":5,"version":1},
+		{"op":"create","item":{"name":"ケリー","category":"バッグ","brand":"HERMES","purchase_price":1800000,"purchase_date":"2023-06-01"}}
+	]`
+	const failingOperations = `[
+		{"op":"create","item":{"name":"サブマリーナ","category":"時計","brand":"ROLEX","purchase_price":1200000,"purchase_date":"2023-05-01"}},
+		{"op":"create","item":{"name":"","category":"時計","brand":"ROLEX","purchase_price":1000,"purchase_date":"2023-05-01"}},
+		{"op":"update","id":999,"version":1,"item":{"notes":"存在しない"}},
+		{"op":"delete","id":2,"version":3},
+		{"op":"delete","id":5,"version":1}
+	]`
+
+	t.Run("正常系: atomic ですべての操作を反映し、操作ごとの結果を返す", func(t *testing.T) {
+		e := newTestServer(t)
+
+		res := batch(t, e, `{"operations":`+operations+`}`, nil)
+
+		assert.Equal(t, usecase.BatchModeAtomic, res.Mode)
+		assert.Equal(t, []string{"create/succeeded", "update/succeeded", "delete/succeeded", "create/succeeded"}, statuses(res))
+		assert.Equal(t, 4, res.Succeeded)
+		require.NotNil(t, res.Results[0].Item)
+		assert.Equal(t, *res.Results[0].ID, res.Results[0].Item.ID)
+		assert.Equal(t, entity.Tags{"投資用"}, res.Results[0].Item.Tags)
+		assert.Greater(t, *res.Results[3].ID, *res.Results[0].ID, "作成は操作の順に採番する")
+		assert.Equal(t, "箱・保証書あり", res.Results[1].Item.Notes)
+		assert.Equal(t, int64(2), res.Results[1].Item.Version)
+		assert.Nil(t, res.Results[2].Item)
+		assert.Nil(t, res.Results[2].Error)
+
+		assert.Equal(t, http.StatusOK, getItem(t, e, *res.Results[3].ID).Code)
+		assert.Equal(t, http.StatusNotFound, getItem(t, e, 5).Code)
+		rec := doRequest(e, http.MethodGet, "/items/facets?category="+url.QueryEscape("時計"), "", nil)
+		assert.Contains(t, rec.Body.String(), `"total":2`, "索引にも反映する")
+	})
+
+	t.Run("正常系: atomic では失敗した操作があれば何も反映せず、失敗を Accept-Language の言語で返す", func(t *testing.T) {
+		e := newTestServer(t)
+
+		res := batch(t, e, `{"mode":"atomic","operations":`+failingOperations+`}`, map[string]string{"Accept-Language": "ja"})
+
+		assert.Equal(t, []string{"create/skipped", "create/failed", "update/failed", "delete/failed", "delete/skipped"}, statuses(res))
+		assert.Equal(t, [3]int{0, 3, 2}, [3]int{res.Succeeded, res.Failed, res.Skipped})
+		assert.Nil(t, res.Results[0].ID)
+		assert.Nil(t, res.Results[0].Error)
+		require.NotNil(t, res.Results[1].Error)
+		assert.Equal(t, "validation_failed", res.Results[1].Error.Code)
+		require.NotEmpty(t, res.Results[1].Error.Errors)
+		assert.Equal(t, "name", res.Results[1].Error.Errors[0].Field)
+		assert.Equal(t, "名前は必須です", res.Results[1].Error.Errors[0].Message)
+		assert.Equal(t, http.StatusNotFound, res.Results[2].Error.Status)
+		assert.Equal(t, "item_not_found", res.Results[2].Error.Code)
+		assert.Equal(t, "precondition_failed", res.Results[3].Error.Code)
+
+		assert.Equal(t, http.StatusOK, getItem(t, e, 5).Code, "削除を取り消す")
+		rec := doRequest(e, http.MethodGet, "/items", "", nil)
+		var list controller.ItemListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Equal(t, 5, list.Total)
+	})
+
+	t.Run("正常系: partial では失敗した操作だけを飛ばす", func(t *testing.T) {
+		e := newTestServer(t)
+
+		res := batch(t, e, `{"mode":"partial","operations":`+failingOperations+`}`, nil)
+
+		assert.Equal(t, []string{"create/succeeded", "create/failed", "update/failed", "delete/failed", "delete/succeeded"}, statuses(res))
+		assert.Equal(t, [3]int{2, 3, 0}, [3]int{res.Succeeded, res.Failed, res.Skipped})
+		assert.Equal(t, http.StatusOK, getItem(t, e, *res.Results[0].ID).Code)
+		assert.Equal(t, http.StatusNotFound, getItem(t, e, 5).Code)
+	})
+
+	t.Run("異常系: partial をデータベースのエラーで中断した場合は、反映した操作の結果もエラーのステータスで返す", func(t *testing.T) {
+		store := memory.NewStore()
+		e := newTestServerWithStore(t, store, usecase.BrandResolutionLenient,
+			usecase.WithAuditTrail(&failingEventRepository{ItemEventRepository: memory.NewItemEventRepository(store), action: entity.ItemEventDelete}))
+
+		rec := doRequest(e, http.MethodPost, "/items/batch", `{"mode":"partial","operations":`+operations+`}`, nil)
+
+		require.Equal(t, http.StatusInternalServerError, rec.Code, rec.Body.String())
+		var res controller.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, []string{"create/succeeded", "update/succeeded", "delete/failed", "create/succeeded"}, statuses(res))
+		require.NotNil(t, res.Error)
+		assert.Equal(t, "database_error", res.Error.Code)
+		assert.Equal(t, "database_error", res.Results[2].Error.Code)
+		assert.Equal(t, http.StatusOK, getItem(t, e, 5).Code, "中断した削除は取り消す")
+		rec = doRequest(e, http.MethodGet, "/items/facets?category="+url.QueryEscape("時計"), "", nil)
+		assert.Contains(t, rec.Body.String(), `"total":2`, "反映した作成は索引にも反映する")
+	})
+
+	t.Run("異常系: リクエストが不正な場合は何も実行しない", func(t *testing.T) {
+		e := newTestServer(t)
+		tests := []struct {
+			name         string
+			body         string
+			expectedCode string
+		}{
+			{name: "JSON でない", body: `operations`, expectedCode: "invalid_request_format"},
+			{name: "item の型が不正", body: `{"operations":[{"op":"create","item":{"purchase_price":"高い"}}]}`, expectedCode: "invalid_request_format"},
+			{name: "操作が無い", body: `{"operations":[]}`, expectedCode: "invalid_input"},
+			{name: "不正な方式", body: `{"mode":"all","operations":` + operations + `}`, expectedCode: "invalid_input"},
+			{name: "不正な操作の種類", body: `{"operations":[{"op":"create","item":{}},{"op":"upsert"}]}`, expectedCode: "invalid_input"},
+			{name: "バージョンが無い", body: `{"operations":[{"op":"delete","id":1}]}`, expectedCode: "invalid_input"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := doRequest(e, http.MethodPost, "/items/batch", tt.body, nil)
+
+				assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+				var p problem.Details
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+				assert.Equal(t, tt.expectedCode, p.Code)
+			})
+		}
+		assert.Equal(t, http.StatusOK, getItem(t, e, 1).Code)
+		rec := doRequest(e, http.MethodGet, "/items", "", nil)
+		assert.Contains(t, rec.Body.String(), `"total":5`)
+	})
+}
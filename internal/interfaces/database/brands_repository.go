@@ -0,0 +1,287 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// SELECT するカラム（scanBrand の順序と一致させる）
+const brandColumns = "id, name, name_canonical, country, created_at, updated_at"
+
+// BrandRepository はブランドと別名を brands / brand_aliases に保存する。
+// 複数の文を実行する書き込みは、呼び出し側（usecase）のトランザクション内で実行すること
+type BrandRepository struct {
+	SqlHandler
+}
+
+func (r *BrandRepository) FindAll(ctx context.Context) ([]*entity.Brand, error) {
+	query := `
+        SELECT ` + brandColumns + `
+        FROM brands
+        ORDER BY name_canonical, id
+    `
+
+	rows, err := r.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	brands := []*entity.Brand{}
+	byID := map[int64]*entity.Brand{}
+	for rows.Next() {
+		brand, err := scanBrand(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		brands = append(brands, brand)
+		byID[brand.ID] = brand
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	aliases, err := r.Query(ctx, `SELECT brand_id, alias FROM brand_aliases ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer aliases.Close()
+
+	for aliases.Next() {
+		var brandID int64
+		var alias string
+		if err := aliases.Scan(&brandID, &alias); err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		if brand, ok := byID[brandID]; ok {
+			brand.Aliases = append(brand.Aliases, alias)
+		}
+	}
+	if err = aliases.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return brands, nil
+}
+
+func (r *BrandRepository) FindByID(ctx context.Context, id int64) (*entity.Brand, error) {
+	query := `
+        SELECT ` + brandColumns + `
+        FROM brands
+        WHERE id = ?
+    `
+
+	brand, err := scanBrand(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	aliases, err := r.findAliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	brand.Aliases = aliases
+
+	return brand, nil
+}
+
+func (r *BrandRepository) FindByKey(ctx context.Context, key string) (*entity.Brand, error) {
+	query := `
+        SELECT id FROM brands WHERE name_canonical = ?
+        UNION ALL
+        SELECT brand_id FROM brand_aliases WHERE alias_canonical = ?
+    `
+
+	var id int64
+	if err := r.QueryRow(ctx, query, key, key).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+func (r *BrandRepository) Create(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	if err := r.checkUnique(ctx, brand); err != nil {
+		return nil, err
+	}
+
+	query := `
+        INSERT INTO brands (name, name_canonical, country)
+        VALUES (?, ?, ?)
+    `
+
+	id, err := r.Insert(ctx, query, brand.Name, brand.NameCanonical, brand.Country)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	if err := r.insertAliases(ctx, id, brand.Aliases); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Update は名前・原産国・別名を置き換える。紐づくアイテムの brand_canonical は呼び出し側が ItemRepository.Rebrand でそろえる
+func (r *BrandRepository) Update(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	// MySQL は値が変わらない UPDATE の影響行数を 0 と返すため、存在確認は先に行う
+	if _, err := r.FindByID(ctx, brand.ID); err != nil {
+		return nil, err
+	}
+	if err := r.checkUnique(ctx, brand); err != nil {
+		return nil, err
+	}
+
+	query := `
+        UPDATE brands
+        SET name = ?, name_canonical = ?, country = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	if _, err := r.Execute(ctx, query, brand.Name, brand.NameCanonical, brand.Country, brand.ID); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	if _, err := r.Execute(ctx, `DELETE FROM brand_aliases WHERE brand_id = ?`, brand.ID); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.insertAliases(ctx, brand.ID, brand.Aliases); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, brand.ID)
+}
+
+// Delete はアイテム（ゴミ箱にあるものを含む）から参照されていない場合のみブランドを削除する
+func (r *BrandRepository) Delete(ctx context.Context, id int64) error {
+	var count int
+	if err := r.QueryRow(ctx, `SELECT COUNT(*) FROM items WHERE brand_id = ?`, id).Scan(&count); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d items are linked to the brand", domainErrors.ErrInUse, count)
+	}
+
+	if _, err := r.Execute(ctx, `DELETE FROM brand_aliases WHERE brand_id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	result, err := r.Execute(ctx, `DELETE FROM brands WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return domainErrors.ErrBrandNotFound
+	}
+
+	return nil
+}
+
+func (r *BrandRepository) findAliases(ctx context.Context, brandID int64) ([]string, error) {
+	rows, err := r.Query(ctx, `SELECT alias FROM brand_aliases WHERE brand_id = ? ORDER BY id`, brandID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	aliases := []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		aliases = append(aliases, alias)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return aliases, nil
+}
+
+func (r *BrandRepository) insertAliases(ctx context.Context, brandID int64, aliases []string) error {
+	query := `
+        INSERT INTO brand_aliases (brand_id, alias, alias_canonical)
+        VALUES (?, ?, ?)
+    `
+	for _, alias := range aliases {
+		if _, err := r.Insert(ctx, query, brandID, alias, entity.CanonicalBrand(alias)); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+	}
+	return nil
+}
+
+// 名前と別名の正規形が他のブランドの名前・別名と重複する場合は ErrDuplicateEntry。
+// UNIQUE 制約はテーブルごとにしか効かないため、名前と別名をまたぐ重複はここで確認する
+func (r *BrandRepository) checkUnique(ctx context.Context, brand *entity.Brand) error {
+	keys := brand.Keys()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+	query := fmt.Sprintf(`
+        SELECT COUNT(*) FROM (
+            SELECT id AS brand_id FROM brands WHERE name_canonical IN (%s)
+            UNION ALL
+            SELECT brand_id FROM brand_aliases WHERE alias_canonical IN (%s)
+        ) matched
+        WHERE brand_id <> ?
+    `, placeholders, placeholders)
+
+	args := make([]interface{}, 0, len(keys)*2+1)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, brand.ID)
+
+	var count int
+	if err := r.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if count > 0 {
+		return domainErrors.ErrDuplicateEntry
+	}
+	return nil
+}
+
+func scanBrand(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Brand, error) {
+	var brand entity.Brand
+	var createdAt, updatedAt dbTime
+
+	err := scanner.Scan(
+		&brand.ID,
+		&brand.Name,
+		&brand.NameCanonical,
+		&brand.Country,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	brand.Aliases = []string{}
+	brand.CreatedAt = createdAt.Time
+	brand.UpdatedAt = updatedAt.Time
+
+	return &brand, nil
+}
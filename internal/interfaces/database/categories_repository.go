@@ -0,0 +1,298 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// SELECT するカラム（scanCategory の順序と一致させる）
+const categoryColumns = "id, name, parent_id, display_name_ja, display_name_en, sort_order, active, created_at, updated_at"
+
+// 属性の定義の SELECT するカラム（scanAttributeDefinition の順序と一致させる）
+const categoryAttributeColumns = "attr_key, attr_type, required, options, unit"
+
+// CategoryRepository はカテゴリーと属性の定義を categories / category_attributes に保存する。
+// 複数の文を実行する書き込みは、呼び出し側（usecase）のトランザクション内で実行すること
+type CategoryRepository struct {
+	SqlHandler
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	query := `
+        SELECT ` + categoryColumns + `
+        FROM categories
+        ORDER BY sort_order, id
+    `
+
+	rows, err := r.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	categories := []*entity.Category{}
+	byID := map[int64]*entity.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		categories = append(categories, category)
+		byID[category.ID] = category
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	attributes, err := r.Query(ctx, `SELECT category_id, `+categoryAttributeColumns+` FROM category_attributes ORDER BY category_id, position`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer attributes.Close()
+
+	for attributes.Next() {
+		var categoryID int64
+		definition, err := scanAttributeDefinition(attributes, &categoryID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		if category, ok := byID[categoryID]; ok {
+			category.Attributes = append(category.Attributes, definition)
+		}
+	}
+	if err = attributes.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return categories, nil
+}
+
+func (r *CategoryRepository) FindByID(ctx context.Context, id int64) (*entity.Category, error) {
+	query := `
+        SELECT ` + categoryColumns + `
+        FROM categories
+        WHERE id = ?
+    `
+
+	category, err := scanCategory(r.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainErrors.ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	attributes, err := r.findAttributes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	category.Attributes = attributes
+
+	return category, nil
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	if err := r.checkUnique(ctx, category); err != nil {
+		return nil, err
+	}
+
+	query := `
+        INSERT INTO categories (name, parent_id, display_name_ja, display_name_en, sort_order, active)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+	id, err := r.Insert(ctx, query, category.Name, category.ParentID, category.DisplayNameJa, category.DisplayNameEn, category.SortOrder, category.Active)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.insertAttributes(ctx, id, category.Attributes); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Update はカテゴリーを置き換える。名前が変わっても属するアイテムは移動しない（usecase がアイテムごとに移動する）
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	// MySQL は値が変わらない UPDATE の影響行数を 0 と返すため、存在確認は先に行う
+	if _, err := r.FindByID(ctx, category.ID); err != nil {
+		return nil, err
+	}
+	if err := r.checkUnique(ctx, category); err != nil {
+		return nil, err
+	}
+
+	query := `
+        UPDATE categories
+        SET name = ?, parent_id = ?, display_name_ja = ?, display_name_en = ?, sort_order = ?, active = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.Execute(ctx, query,
+		category.Name, category.ParentID, category.DisplayNameJa, category.DisplayNameEn, category.SortOrder, category.Active, category.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	// 属性の定義は置き換える
+	if _, err := r.Execute(ctx, `DELETE FROM category_attributes WHERE category_id = ?`, category.ID); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.insertAttributes(ctx, category.ID, category.Attributes); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, category.ID)
+}
+
+// Delete はカテゴリーを削除する。属するアイテム（ゴミ箱にあるものを含む）がある場合は ErrInUse
+func (r *CategoryRepository) Delete(ctx context.Context, id int64) error {
+	stored, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := r.CountItems(ctx, stored.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d items are in the category", domainErrors.ErrInUse, count)
+	}
+
+	if _, err := r.Execute(ctx, `DELETE FROM category_attributes WHERE category_id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if _, err := r.Execute(ctx, `DELETE FROM categories WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) CountItems(ctx context.Context, name string) (int, error) {
+	var count int
+	if err := r.QueryRow(ctx, `SELECT COUNT(*) FROM items WHERE category = ?`, name).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return count, nil
+}
+
+func (r *CategoryRepository) findAttributes(ctx context.Context, categoryID int64) ([]entity.AttributeDefinition, error) {
+	query := `SELECT ` + categoryAttributeColumns + ` FROM category_attributes WHERE category_id = ? ORDER BY position`
+	rows, err := r.Query(ctx, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	attributes := []entity.AttributeDefinition{}
+	for rows.Next() {
+		definition, err := scanAttributeDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		attributes = append(attributes, definition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return attributes, nil
+}
+
+// insertAttributes は属性の定義を定義順に保存する。enum の選択肢は JSON の配列で保存する
+func (r *CategoryRepository) insertAttributes(ctx context.Context, categoryID int64, attributes []entity.AttributeDefinition) error {
+	query := `
+        INSERT INTO category_attributes (category_id, attr_key, attr_type, required, options, unit, position)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+	for i, definition := range attributes {
+		var options *string
+		if len(definition.Options) > 0 {
+			encoded, err := json.Marshal(definition.Options)
+			if err != nil {
+				return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+			}
+			s := string(encoded)
+			options = &s
+		}
+		_, err := r.Execute(ctx, query,
+			categoryID, definition.Key, string(definition.Type), definition.Required, options, definition.Unit, i+1)
+		if err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+	}
+	return nil
+}
+
+// 名前が他のカテゴリーと重複する場合は ErrDuplicateEntry
+func (r *CategoryRepository) checkUnique(ctx context.Context, category *entity.Category) error {
+	var count int
+	query := `SELECT COUNT(*) FROM categories WHERE name = ? AND id <> ?`
+	if err := r.QueryRow(ctx, query, category.Name, category.ID).Scan(&count); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if count > 0 {
+		return domainErrors.ErrDuplicateEntry
+	}
+	return nil
+}
+
+func scanCategory(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.Category, error) {
+	var category entity.Category
+	var parentID sql.NullInt64
+	var createdAt, updatedAt dbTime
+
+	err := scanner.Scan(
+		&category.ID,
+		&category.Name,
+		&parentID,
+		&category.DisplayNameJa,
+		&category.DisplayNameEn,
+		&category.SortOrder,
+		&category.Active,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		category.ParentID = &parentID.Int64
+	}
+	category.Attributes = []entity.AttributeDefinition{}
+	category.CreatedAt = createdAt.Time
+	category.UpdatedAt = updatedAt.Time
+
+	return &category, nil
+}
+
+// scanAttributeDefinition は属性の定義を読み込む。prefix には categoryAttributeColumns より前に SELECT したカラムの読み込み先を渡す
+func scanAttributeDefinition(scanner interface {
+	Scan(dest ...interface{}) error
+}, prefix ...interface{}) (entity.AttributeDefinition, error) {
+	var definition entity.AttributeDefinition
+	var attrType string
+	var options sql.NullString
+
+	dest := append(prefix, &definition.Key, &attrType, &definition.Required, &options, &definition.Unit)
+	if err := scanner.Scan(dest...); err != nil {
+		return definition, err
+	}
+
+	definition.Type = entity.AttributeType(attrType)
+	if options.Valid && options.String != "" {
+		if err := json.Unmarshal([]byte(options.String), &definition.Options); err != nil {
+			return definition, err
+		}
+	}
+	return definition, nil
+}
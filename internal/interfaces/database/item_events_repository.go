@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+type ItemEventRepository struct {
+	SqlHandler
+}
+
+func (r *ItemEventRepository) Append(ctx context.Context, event *entity.ItemEvent) error {
+	query := `
+        INSERT INTO item_events (item_id, action, actor, request_id, changes, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `
+
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode changes: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	id, err := r.Insert(ctx, query,
+		event.ItemID,
+		event.Action,
+		event.Actor,
+		event.RequestID,
+		string(changes),
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	event.ID = id
+
+	return nil
+}
+
+func (r *ItemEventRepository) Find(ctx context.Context, criteria usecase.AuditCriteria) ([]*entity.ItemEvent, error) {
+	where, args := buildAuditWhere(criteria)
+	query := fmt.Sprintf(`
+        SELECT id, item_id, action, actor, request_id, changes, created_at
+        FROM item_events
+        %s
+        ORDER BY id DESC
+        LIMIT ? OFFSET ?
+    `, where)
+	args = append(args, criteria.Limit, criteria.Offset)
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var events []*entity.ItemEvent
+	for rows.Next() {
+		event, err := scanItemEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return events, nil
+}
+
+func (r *ItemEventRepository) Count(ctx context.Context, criteria usecase.AuditCriteria) (int, error) {
+	where, args := buildAuditWhere(criteria)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM item_events %s", where)
+
+	var count int
+	if err := r.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return count, nil
+}
+
+// 絞り込み条件から WHERE 句とプレースホルダ引数を組み立てる
+func buildAuditWhere(criteria usecase.AuditCriteria) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if criteria.ItemID != nil {
+		conditions = append(conditions, "item_id = ?")
+		args = append(args, *criteria.ItemID)
+	}
+	if criteria.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, criteria.Actor)
+	}
+	if criteria.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, criteria.Action)
+	}
+	if criteria.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *criteria.From)
+	}
+	if criteria.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *criteria.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + joinClauses(conditions, " AND "), args
+}
+
+func scanItemEvent(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ItemEvent, error) {
+	var event entity.ItemEvent
+	var changes []byte
+	var createdAt dbTime
+
+	err := scanner.Scan(
+		&event.ID,
+		&event.ItemID,
+		&event.Action,
+		&event.Actor,
+		&event.RequestID,
+		&changes,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &event.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes: %w", err)
+	}
+	event.CreatedAt = createdAt.Time
+
+	return &event, nil
+}
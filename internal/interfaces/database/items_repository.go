@@ -3,21 +3,49 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
 )
 
+// SELECT するカラム（scanItem の順序と一致させる）
+const itemColumns = "id, name, category, brand, brand_canonical, brand_id, purchase_price, purchase_date, attributes, notes, version, created_at, updated_at, deleted_at"
+
+// 並び替えキーとカラムの対応（ORDER BY に直接埋め込むためホワイトリストで管理）
+var sortColumns = map[string]string{
+	usecase.SortCreatedAt:     "created_at",
+	usecase.SortPurchaseDate:  "purchase_date",
+	usecase.SortPurchasePrice: "purchase_price",
+	usecase.SortName:          "name",
+	usecase.SortDeletedAt:     "deleted_at",
+}
+
 type ItemRepository struct {
 	SqlHandler
+
+	// FullText は全文検索に MySQL の FULLTEXT インデックス（ngram パーサー）を使う。
+	// false の場合はプロセス内の索引（searchIndex）で検索する
+	FullText bool
+
+	// SearchIndexTTL はプロセス内の索引が、他の ItemRepository（他のインスタンス）での書き込みを確かめる間隔。
+	// 0 の場合は DefaultSearchIndexTTL。この ItemRepository での書き込みは次の検索で必ず反映する
+	SearchIndexTTL time.Duration
+
+	index searchIndex
 }
 
 func (r *ItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
 	query := `
-        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at
+        SELECT ` + itemColumns + `
         FROM items
+        WHERE deleted_at IS NULL
         ORDER BY created_at DESC
     `
 
@@ -39,15 +67,284 @@ func (r *ItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *ItemRepository) FindByCriteria(ctx context.Context, criteria usecase.ItemCriteria) ([]*entity.Item, error) {
+	column, ok := sortColumns[criteria.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported sort key %q", domainErrors.ErrInvalidInput, criteria.Sort)
+	}
+	direction := "DESC"
+	if criteria.Order == usecase.OrderAsc {
+		direction = "ASC"
+	}
+
+	where, args := buildCriteriaWhere(criteria)
+	query := fmt.Sprintf(`
+        SELECT `+itemColumns+`
+        FROM items
+        %s
+        ORDER BY %s %s, id %s
+        LIMIT ? OFFSET ?
+    `, where, column, direction, direction)
+	args = append(args, criteria.Limit, criteria.Offset)
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var items []*entity.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, err
+	}
 
 	return items, nil
 }
 
+// 1回の問い合わせで読む ForEachByCriteria のアイテムの数
+const forEachBatchSize = 500
+
+// ForEachByCriteria は (並び替えのカラム, id) をキーにして forEachBatchSize 件ずつ読み、読んだ分ごとに fn を呼ぶ。
+// 結果セットを開いたまま fn やタグの読み込みを行わないため、接続が1本のデータベース（SQLite）でも使える
+func (r *ItemRepository) ForEachByCriteria(ctx context.Context, criteria usecase.ItemCriteria, fn func(item *entity.Item) error) error {
+	column, ok := sortColumns[criteria.Sort]
+	if !ok {
+		return fmt.Errorf("%w: unsupported sort key %q", domainErrors.ErrInvalidInput, criteria.Sort)
+	}
+	direction, comparator := "DESC", "<"
+	if criteria.Order == usecase.OrderAsc {
+		direction, comparator = "ASC", ">"
+	}
+
+	var last *entity.Item
+	for {
+		where, args := buildCriteriaWhere(criteria)
+		if last != nil {
+			key := sortValue(last, criteria.Sort)
+			where += fmt.Sprintf(" AND (%s %s ? OR (%s = ? AND id %s ?))", column, comparator, column, comparator)
+			args = append(args, key, key, last.ID)
+		}
+		query := fmt.Sprintf(`
+            SELECT `+itemColumns+`
+            FROM items
+            %s
+            ORDER BY %s %s, id %s
+            LIMIT ?
+        `, where, column, direction, direction)
+		args = append(args, forEachBatchSize)
+
+		items, err := r.queryItems(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < forEachBatchSize {
+			return nil
+		}
+		last = items[len(items)-1]
+	}
+}
+
+// sortValue は並び替えのカラムに保存されている item の値を返す
+func sortValue(item *entity.Item, sort string) interface{} {
+	switch sort {
+	case usecase.SortPurchaseDate:
+		return item.PurchaseDate
+	case usecase.SortPurchasePrice:
+		return item.PurchasePrice
+	case usecase.SortName:
+		return item.Name
+	case usecase.SortDeletedAt:
+		return item.DeletedAt
+	default:
+		return item.CreatedAt
+	}
+}
+
+// queryItems は query で読んだアイテムをタグとともに返す
+func (r *ItemRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]*entity.Item, error) {
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var items []*entity.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// InnoDB のセカンダリインデックスは主キーを含むため、idx_created_at がそのまま (created_at, id) の順序になる
+func (r *ItemRepository) FindByCursor(ctx context.Context, criteria usecase.ItemCriteria, after *usecase.CursorKey) ([]*entity.Item, bool, error) {
+	where, args := buildCriteriaWhere(criteria)
+
+	direction, comparator := "DESC", "<"
+	if criteria.Order == usecase.OrderAsc {
+		direction, comparator = "ASC", ">"
+	}
+
+	if after != nil {
+		where += fmt.Sprintf(" AND (created_at %s ? OR (created_at = ? AND id %s ?))", comparator, comparator)
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT `+itemColumns+`
+        FROM items
+        %s
+        ORDER BY created_at %s, id %s
+        LIMIT ?
+    `, where, direction, direction)
+	// 次ページの有無を判定するため 1 件多く取得する
+	args = append(args, criteria.Limit+1)
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var items []*entity.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	hasMore := len(items) > criteria.Limit
+	if hasMore {
+		items = items[:criteria.Limit]
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, false, err
+	}
+
+	return items, hasMore, nil
+}
+
+func (r *ItemRepository) CountByCriteria(ctx context.Context, criteria usecase.ItemCriteria) (int, error) {
+	where, args := buildCriteriaWhere(criteria)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM items %s", where)
+
+	var count int
+	if err := r.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return count, nil
+}
+
+// 絞り込み条件から WHERE 句とプレースホルダ引数を組み立てる。
+// ゴミ箱の状態で必ず絞り込むため、戻り値は常に WHERE から始まる
+func buildCriteriaWhere(criteria usecase.ItemCriteria) (string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	if criteria.Trashed {
+		conditions[0] = "deleted_at IS NOT NULL"
+	}
+	args := []interface{}{}
+
+	if len(criteria.Categories) > 0 {
+		placeholders := make([]string, len(criteria.Categories))
+		for i, category := range criteria.Categories {
+			placeholders[i] = "?"
+			args = append(args, category)
+		}
+		conditions = append(conditions, "category IN ("+joinClauses(placeholders, ", ")+")")
+	} else if criteria.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, criteria.Category)
+	}
+	if criteria.Brand != "" {
+		// 表記ゆれを吸収した正規形で比較する
+		conditions = append(conditions, "brand_canonical = ?")
+		args = append(args, criteria.BrandCanonical)
+	}
+	if criteria.PurchaseDateFrom != "" {
+		conditions = append(conditions, "purchase_date >= ?")
+		args = append(args, criteria.PurchaseDateFrom)
+	}
+	if criteria.PurchaseDateTo != "" {
+		conditions = append(conditions, "purchase_date <= ?")
+		args = append(args, criteria.PurchaseDateTo)
+	}
+	if criteria.MinPrice != nil {
+		conditions = append(conditions, "purchase_price >= ?")
+		args = append(args, *criteria.MinPrice)
+	}
+	if criteria.MaxPrice != nil {
+		conditions = append(conditions, "purchase_price <= ?")
+		args = append(args, *criteria.MaxPrice)
+	}
+	// 属性は item_attributes の索引で、指定したすべての属性が一致するアイテムに絞り込む
+	for _, key := range sortedKeys(criteria.Attributes) {
+		conditions = append(conditions, "id IN (SELECT item_id FROM item_attributes WHERE attr_key = ? AND attr_value = ?)")
+		args = append(args, key, criteria.Attributes[key])
+	}
+	// タグは item_tags で、いずれかのタグを持つアイテムに絞り込む。
+	// すべてのタグを持つ場合（TagMatchAll）は一致したタグの数がタグの数と同じアイテムに絞り込む
+	if len(criteria.Tags) > 0 {
+		placeholders := make([]string, len(criteria.Tags))
+		for i, tag := range criteria.Tags {
+			placeholders[i] = "?"
+			args = append(args, tag)
+		}
+		subquery := "SELECT it.item_id FROM item_tags it JOIN tags t ON t.id = it.tag_id WHERE t.name IN (" + joinClauses(placeholders, ", ") + ")"
+		if criteria.TagMatch != usecase.TagMatchAny {
+			subquery += " GROUP BY it.item_id HAVING COUNT(*) = ?"
+			args = append(args, len(criteria.Tags))
+		}
+		conditions = append(conditions, "id IN ("+subquery+")")
+	}
+
+	return "WHERE " + joinClauses(conditions, " AND "), args
+}
+
 func (r *ItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
 	query := `
-        SELECT id, name, category, brand, purchase_price, purchase_date, created_at, updated_at
+        SELECT ` + itemColumns + `
         FROM items
-        WHERE id = ?
+        WHERE id = ? AND deleted_at IS NULL
     `
 
 	row := r.QueryRow(ctx, query, id)
@@ -59,39 +356,281 @@ func (r *ItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item,
 		}
 		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
+	if err := r.attachTags(ctx, []*entity.Item{item}); err != nil {
+		return nil, err
+	}
 
 	return item, nil
 }
 
 func (r *ItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	defer r.index.invalidate()
+
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-        INSERT INTO items (name, category, brand, purchase_price, purchase_date)
-        VALUES (?, ?, ?, ?, ?)
+        INSERT INTO items (name, category, brand, brand_canonical, brand_id, purchase_price, purchase_date, attributes, notes, search_text)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
 
-	result, err := r.Execute(ctx, query,
+	id, err := r.Insert(ctx, query,
 		item.Name,
 		item.Category,
 		item.Brand,
+		item.BrandCanonical,
+		item.BrandID,
 		item.PurchasePrice,
 		item.PurchaseDate,
+		attributes,
+		item.Notes,
+		entity.SearchText(item),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
+	if err := r.syncAttributeIndex(ctx, id, item.Attributes); err != nil {
+		return nil, err
+	}
+	if err := r.syncTags(ctx, id, item.Tags); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// 複数行の INSERT 1回で使うプレースホルダの上限（SQLite の上限 32766 と MySQL の上限 65535 を超えないようにする）
+const maxInsertPlaceholders = 5000
+
+// itemInsertColumns は items に INSERT するカラム（itemInsertValues の順序と一致させる）
+var itemInsertColumns = []string{"name", "category", "brand", "brand_canonical", "brand_id", "purchase_price", "purchase_date", "attributes", "notes", "search_text"}
+
+// CreateMany は items を複数行の INSERT でまとめて作成し、属性の索引とタグも複数行の INSERT で書き込む。
+// 作成したアイテムは1件ずつではなく IN でまとめて読み直す
+func (r *ItemRepository) CreateMany(ctx context.Context, items []*entity.Item) ([]*entity.Item, error) {
+	defer r.index.invalidate()
+
+	if len(items) == 0 {
+		return []*entity.Item{}, nil
+	}
 
-	id, err := result.LastInsertId()
+	rows := make([][]interface{}, len(items))
+	for i, item := range items {
+		values, err := itemInsertValues(item)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = values
+	}
+	ids := make([]int64, 0, len(items))
+	err := insertRows("items", itemInsertColumns, rows, func(statement string, args []interface{}) error {
+		batchIDs, err := r.InsertMany(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, batchIDs...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to get last insert id: %s", domainErrors.ErrDatabaseError, err.Error())
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if len(ids) != len(items) {
+		return nil, fmt.Errorf("%w: inserted %d of %d items", domainErrors.ErrDatabaseError, len(ids), len(items))
 	}
 
-	return r.FindByID(ctx, id)
+	if err := r.insertAttributeIndexes(ctx, ids, items); err != nil {
+		return nil, err
+	}
+	if err := r.insertTags(ctx, ids, items); err != nil {
+		return nil, err
+	}
+
+	created := make([]*entity.Item, 0, len(ids))
+	for start := 0; start < len(ids); start += tagLoadBatchSize {
+		batch, err := r.findByIDs(ctx, ids[start:min(start+tagLoadBatchSize, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, batch...)
+	}
+	if len(created) != len(ids) {
+		return nil, fmt.Errorf("%w: created items not found", domainErrors.ErrDatabaseError)
+	}
+
+	return created, nil
 }
 
-func (r *ItemRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM items WHERE id = ?`
+// itemInsertValues は item を itemInsertColumns の順の値にする
+func itemInsertValues(item *entity.Item) ([]interface{}, error) {
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		item.Name,
+		item.Category,
+		item.Brand,
+		item.BrandCanonical,
+		item.BrandID,
+		item.PurchasePrice,
+		item.PurchaseDate,
+		attributes,
+		item.Notes,
+		entity.SearchText(item),
+	}, nil
+}
 
-	result, err := r.Execute(ctx, query, id)
+// insertAttributeIndexes は作成したアイテムの属性の索引を item_attributes に書き込む
+func (r *ItemRepository) insertAttributeIndexes(ctx context.Context, ids []int64, items []*entity.Item) error {
+	var rows [][]interface{}
+	for i, item := range items {
+		for _, key := range item.Attributes.Keys() {
+			rows = append(rows, []interface{}{ids[i], key, entity.AttributeValueString(item.Attributes[key])})
+		}
+	}
+	err := insertRows("item_attributes", []string{"item_id", "attr_key", "attr_value"}, rows, func(statement string, args []interface{}) error {
+		_, err := r.Execute(ctx, statement, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// insertTags は作成したアイテムにタグを付ける。まだ無いタグはまとめて作成する
+func (r *ItemRepository) insertTags(ctx context.Context, ids []int64, items []*entity.Item) error {
+	var names []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				names = append(names, tag)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	tagIDs, err := r.findTagIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+	var missing [][]interface{}
+	for _, name := range names {
+		if _, ok := tagIDs[name]; !ok {
+			missing = append(missing, []interface{}{name})
+		}
+	}
+	err = insertRows("tags", []string{"name"}, missing, func(statement string, args []interface{}) error {
+		created, err := r.InsertMany(ctx, statement, args...)
+		if err != nil {
+			return err
+		}
+		if len(created) != len(args) {
+			return fmt.Errorf("inserted %d of %d tags", len(created), len(args))
+		}
+		for i, id := range created {
+			tagIDs[args[i].(string)] = id
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	var rows [][]interface{}
+	for i, item := range items {
+		for _, tag := range item.Tags {
+			rows = append(rows, []interface{}{ids[i], tagIDs[tag]})
+		}
+	}
+	err = insertRows("item_tags", []string{"item_id", "tag_id"}, rows, func(statement string, args []interface{}) error {
+		_, err := r.Execute(ctx, statement, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// findTagIDs は names のうち既にあるタグの ID を名前ごとに返す
+func (r *ItemRepository) findTagIDs(ctx context.Context, names []string) (map[string]int64, error) {
+	ids := make(map[string]int64, len(names))
+	for start := 0; start < len(names); start += tagLoadBatchSize {
+		batch := names[start:min(start+tagLoadBatchSize, len(names))]
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, name := range batch {
+			placeholders[i] = "?"
+			args[i] = name
+		}
+
+		err := func() error {
+			rows, err := r.Query(ctx, `SELECT id, name FROM tags WHERE name IN (`+joinClauses(placeholders, ", ")+`)`, args...)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var id int64
+				var name string
+				if err := rows.Scan(&id, &name); err != nil {
+					return err
+				}
+				ids[name] = id
+			}
+			return rows.Err()
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+	}
+	return ids, nil
+}
+
+// insertRows は rows を table の columns に書き込む複数行の INSERT 文を組み立てて insert に渡す。
+// プレースホルダが maxInsertPlaceholders を超えないよう、行を分けて複数の文にする
+func insertRows(table string, columns []string, rows [][]interface{}, insert func(statement string, args []interface{}) error) error {
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		placeholders[i] = "?"
+	}
+	row := "(" + strings.Join(placeholders, ", ") + ")"
+	batchSize := max(maxInsertPlaceholders/len(columns), 1)
+
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+		values := make([]string, len(batch))
+		args := make([]interface{}, 0, len(batch)*len(columns))
+		for i, rowArgs := range batch {
+			values[i] = row
+			args = append(args, rowArgs...)
+		}
+		statement := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES " + strings.Join(values, ", ")
+		if err := insert(statement, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete は item のバージョンが version と一致する場合のみゴミ箱に移動する（論理削除）
+func (r *ItemRepository) Delete(ctx context.Context, id int64, version int64) error {
+	defer r.index.invalidate()
+
+	query := `
+        UPDATE items
+        SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+        WHERE id = ? AND version = ? AND deleted_at IS NULL
+    `
+
+	result, err := r.Execute(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
@@ -102,16 +641,292 @@ func (r *ItemRepository) Delete(ctx context.Context, id int64) error {
 	}
 
 	if rowsAffected == 0 {
-		return domainErrors.ErrItemNotFound
+		return r.conditionalWriteMiss(ctx, id)
 	}
 
 	return nil
 }
 
+// Restore はゴミ箱にあるアイテムを元に戻す
+func (r *ItemRepository) Restore(ctx context.Context, id int64) (*entity.Item, error) {
+	defer r.index.invalidate()
+
+	query := `
+        UPDATE items
+        SET deleted_at = NULL, version = version + 1
+        WHERE id = ? AND deleted_at IS NOT NULL
+    `
+
+	result, err := r.Execute(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return nil, domainErrors.ErrItemNotFound
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// FindByCategory はカテゴリーに属するアイテムをゴミ箱にあるものも含めて ID 順に返す
+func (r *ItemRepository) FindByCategory(ctx context.Context, category string) ([]*entity.Item, error) {
+	query := `
+        SELECT ` + itemColumns + `
+        FROM items
+        WHERE category = ?
+        ORDER BY id
+    `
+	return r.queryItems(ctx, query, category)
+}
+
+// Recategorize はアイテム（ゴミ箱にあるものを含む）を item.Category へ移動し、属性を item.Attributes に置き換える。
+// item.Version が DB 上のバージョンと一致する場合のみ更新する
+func (r *ItemRepository) Recategorize(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	defer r.index.invalidate()
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        UPDATE items
+        SET category = ?, attributes = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND version = ?
+    `
+
+	result, err := r.Execute(ctx, query, item.Category, attributes, item.ID, item.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		if _, err := r.findStored(ctx, item.ID); err != nil {
+			return nil, err
+		}
+		return nil, domainErrors.ErrVersionMismatch
+	}
+	if err := r.syncAttributeIndex(ctx, item.ID, item.Attributes); err != nil {
+		return nil, err
+	}
+
+	return r.findStored(ctx, item.ID)
+}
+
+// FindByBrand はブランドに紐づくアイテムをゴミ箱にあるものも含めて ID 順に返す
+func (r *ItemRepository) FindByBrand(ctx context.Context, brandID int64) ([]*entity.Item, error) {
+	query := `
+        SELECT ` + itemColumns + `
+        FROM items
+        WHERE brand_id = ?
+        ORDER BY id
+    `
+	return r.queryItems(ctx, query, brandID)
+}
+
+// Rebrand はアイテム（ゴミ箱にあるものを含む）の brand_canonical を item.BrandCanonical に置き換える。
+// item.Version が DB 上のバージョンと一致する場合のみ更新する
+func (r *ItemRepository) Rebrand(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	defer r.index.invalidate()
+
+	query := `
+        UPDATE items
+        SET brand_canonical = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND version = ?
+    `
+
+	result, err := r.Execute(ctx, query, item.BrandCanonical, item.ID, item.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		if _, err := r.findStored(ctx, item.ID); err != nil {
+			return nil, err
+		}
+		return nil, domainErrors.ErrVersionMismatch
+	}
+
+	return r.findStored(ctx, item.ID)
+}
+
+// findStored はゴミ箱にあるものも含めて ID でアイテムを取得する
+func (r *ItemRepository) findStored(ctx context.Context, id int64) (*entity.Item, error) {
+	items, err := r.queryItems(ctx, `SELECT `+itemColumns+` FROM items WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	return items[0], nil
+}
+
+// FillBrandCanonical はカタログに紐づいていないアイテムの brand_canonical を entity.CanonicalBrand の値にそろえ、
+// 書き換えた件数を返す。マイグレーション 0002_brand_canonical が大文字にしただけの近似値で埋めた値を正規形に直すため、起動時に呼ぶ
+// （紐づいたアイテムは 0003_brands でブランドの正式名の正規形にそろえている）
+func (r *ItemRepository) FillBrandCanonical(ctx context.Context) (int64, error) {
+	rows, err := r.Query(ctx, `SELECT id, brand, brand_canonical FROM items WHERE brand_id IS NULL`)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	type fill struct {
+		id        int64
+		current   string
+		canonical string
+	}
+	var fills []fill
+	for rows.Next() {
+		var id int64
+		var brand, current string
+		if err := rows.Scan(&id, &brand, &current); err != nil {
+			return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		if canonical := entity.CanonicalBrand(brand); canonical != current {
+			fills = append(fills, fill{id: id, current: current, canonical: canonical})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	// SQLite は接続が1つのため、更新の前に読み込みを閉じる
+	rows.Close()
+	if len(fills) > 0 {
+		defer r.index.invalidate()
+	}
+
+	// 読み込んだ後に更新されたアイテムは、更新時に正規形が設定されているため上書きしない。
+	// MySQL の ON UPDATE CURRENT_TIMESTAMP で更新日時が変わらないよう updated_at を明示的に指定する
+	query := `
+        UPDATE items
+        SET brand_canonical = ?, updated_at = updated_at
+        WHERE id = ? AND brand_canonical = ? AND brand_id IS NULL
+    `
+
+	var filled int64
+	for _, f := range fills {
+		result, err := r.Execute(ctx, query, f.canonical, f.id, f.current)
+		if err != nil {
+			return filled, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return filled, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		filled += n
+	}
+	return filled, nil
+}
+
+// PurgeDeletedBefore は before より前にゴミ箱に入ったアイテムを物理削除し、削除件数を返す
+func (r *ItemRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	defer r.index.invalidate()
+
+	index := `
+        DELETE FROM item_attributes
+        WHERE item_id IN (SELECT id FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?)
+    `
+	if _, err := r.Execute(ctx, index, before); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	tags := `
+        DELETE FROM item_tags
+        WHERE item_id IN (SELECT id FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?)
+    `
+	if _, err := r.Execute(ctx, tags, before); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	query := `DELETE FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := r.Execute(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return purged, nil
+}
+
+// Update は全フィールドを置き換える。item.Version が DB 上のバージョンと一致する場合のみ更新する
+func (r *ItemRepository) Update(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	defer r.index.invalidate()
+
+	attributes, err := encodeAttributes(item.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        UPDATE items
+        SET name = ?, category = ?, brand = ?, brand_canonical = ?, brand_id = ?, purchase_price = ?, purchase_date = ?, attributes = ?,
+            notes = ?, search_text = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND version = ? AND deleted_at IS NULL
+    `
+
+	result, err := r.Execute(ctx, query,
+		item.Name,
+		item.Category,
+		item.Brand,
+		item.BrandCanonical,
+		item.BrandID,
+		item.PurchasePrice,
+		item.PurchaseDate,
+		attributes,
+		item.Notes,
+		entity.SearchText(item),
+		item.ID,
+		item.Version,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if rowsAffected == 0 {
+		return nil, r.conditionalWriteMiss(ctx, item.ID)
+	}
+	if err := r.syncAttributeIndex(ctx, item.ID, item.Attributes); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, item.ID)
+}
+
+// 条件付き更新・削除が0件だった理由（存在しない or バージョン不一致）を判定する
+func (r *ItemRepository) conditionalWriteMiss(ctx context.Context, id int64) error {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return domainErrors.ErrVersionMismatch
+}
+
 func (r *ItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]int, error) {
 	query := `
         SELECT category, COUNT(*) as count
         FROM items
+        WHERE deleted_at IS NULL
         GROUP BY category
     `
 
@@ -138,71 +953,152 @@ func (r *ItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]i
 	return summary, nil
 }
 
+func (r *ItemRepository) GetTagCounts(ctx context.Context) ([]entity.TagCount, error) {
+	query := `
+        SELECT t.name, COUNT(*) AS count
+        FROM item_tags it
+        JOIN tags t ON t.id = it.tag_id
+        JOIN items i ON i.id = it.item_id
+        WHERE i.deleted_at IS NULL
+        GROUP BY t.name
+        ORDER BY count DESC, t.name
+    `
+
+	rows, err := r.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	counts := []entity.TagCount{}
+	for rows.Next() {
+		var count entity.TagCount
+		if err := rows.Scan(&count.Name, &count.Count); err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	return counts, nil
+}
+
 func scanItem(scanner interface {
 	Scan(dest ...interface{}) error
 }) (*entity.Item, error) {
 	var item entity.Item
-	var purchaseDate string
-	var createdAt, updatedAt time.Time
+	var purchaseDate dbDate
+	var createdAt, updatedAt dbTime
+	var deletedAt nullTime
+	var brandID sql.NullInt64
+	var attributes sql.NullString
 
 	err := scanner.Scan(
 		&item.ID,
 		&item.Name,
 		&item.Category,
 		&item.Brand,
+		&item.BrandCanonical,
+		&brandID,
 		&item.PurchasePrice,
 		&purchaseDate,
+		&attributes,
+		&item.Notes,
+		&item.Version,
 		&createdAt,
 		&updatedAt,
+		&deletedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	if purchaseDate != "" {
-		if parsedDate, err := time.Parse("2006-01-02", purchaseDate); err == nil {
-			item.PurchaseDate = parsedDate.Format("2006-01-02")
-		} else {
-			item.PurchaseDate = purchaseDate
+	item.PurchaseDate = purchaseDate.Value
+	if brandID.Valid {
+		item.BrandID = &brandID.Int64
+	}
+	if attributes.Valid && attributes.String != "" {
+		if err := json.Unmarshal([]byte(attributes.String), &item.Attributes); err != nil {
+			return nil, err
 		}
 	}
-
-	item.CreatedAt = createdAt
-	item.UpdatedAt = updatedAt
+	item.CreatedAt = createdAt.Time
+	item.UpdatedAt = updatedAt.Time
+	if deletedAt.Valid {
+		item.DeletedAt = &deletedAt.Time
+	}
 
 	return &item, nil
 }
 
-func (r *ItemRepository) UpdatePartially(ctx context.Context, id int64, item *entity.Item) (*entity.Item, error) {
-	// どのフィールドを更新するか動的に決定
+// UpdatePartially は item.Version が DB 上のバージョンと一致する場合のみ、指定フィールドを更新する
+func (r *ItemRepository) UpdatePartially(ctx context.Context, id int64, item *entity.Item, fields []entity.ItemField) (*entity.Item, error) {
+	defer r.index.invalidate()
+
+	// 指定されたフィールドのみ更新する（ゼロ値も書き込む）
 	setClauses := []string{}
 	args := []interface{}{}
+	syncAttributes, syncTags, searchText := false, false, false
 
-	if item.Name != "" {
-		setClauses = append(setClauses, "name = ?")
-		args = append(args, item.Name)
-	}
-	if item.Brand != "" {
-		setClauses = append(setClauses, "brand = ?")
-		args = append(args, item.Brand)
-	}
-	if item.PurchasePrice != 0 {
-		setClauses = append(setClauses, "purchase_price = ?")
-		args = append(args, item.PurchasePrice)
+	for _, field := range fields {
+		switch field {
+		case entity.ItemFieldName:
+			setClauses = append(setClauses, "name = ?")
+			args = append(args, item.Name)
+			searchText = true
+		case entity.ItemFieldCategory:
+			setClauses = append(setClauses, "category = ?")
+			args = append(args, item.Category)
+		case entity.ItemFieldBrand:
+			setClauses = append(setClauses, "brand = ?", "brand_canonical = ?", "brand_id = ?")
+			args = append(args, item.Brand, item.BrandCanonical, item.BrandID)
+			searchText = true
+		case entity.ItemFieldPurchasePrice:
+			setClauses = append(setClauses, "purchase_price = ?")
+			args = append(args, item.PurchasePrice)
+		case entity.ItemFieldPurchaseDate:
+			setClauses = append(setClauses, "purchase_date = ?")
+			args = append(args, item.PurchaseDate)
+		case entity.ItemFieldAttributes:
+			attributes, err := encodeAttributes(item.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			setClauses = append(setClauses, "attributes = ?")
+			args = append(args, attributes)
+			syncAttributes = true
+		case entity.ItemFieldNotes:
+			setClauses = append(setClauses, "notes = ?")
+			args = append(args, item.Notes)
+			searchText = true
+		case entity.ItemFieldTags:
+			// タグは item_tags に保存するため、items のカラムは更新しない
+			syncTags = true
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", domainErrors.ErrInvalidInput, field)
+		}
 	}
 
 	// 更新対象がない場合
-	if len(setClauses) == 0 {
-		return nil, fmt.Errorf("%w: no updatable fields provided", domainErrors.ErrDatabaseError)
+	if len(setClauses) == 0 && !syncTags {
+		return nil, fmt.Errorf("%w: no updatable fields provided", domainErrors.ErrInvalidInput)
+	}
+	// 全文検索の対象が変わる場合は、item の他の検索対象のフィールドと合わせて求め直す
+	if searchText {
+		setClauses = append(setClauses, "search_text = ?")
+		args = append(args, entity.SearchText(item))
 	}
 
-	// 更新時刻も更新
-	setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
+	// バージョンと更新時刻も更新
+	setClauses = append(setClauses, "version = version + 1", "updated_at = CURRENT_TIMESTAMP")
 
-	// SQL構築
-	query := fmt.Sprintf("UPDATE items SET %s WHERE id = ?",
+	// SQL構築（item.Version と一致する場合のみ更新する）
+	query := fmt.Sprintf("UPDATE items SET %s WHERE id = ? AND version = ? AND deleted_at IS NULL",
 		joinClauses(setClauses, ", "))
-	args = append(args, id)
+	args = append(args, id, item.Version)
 
 	// 実行
 	result, err := r.Execute(ctx, query, args...)
@@ -210,18 +1106,146 @@ func (r *ItemRepository) UpdatePartially(ctx context.Context, id int64, item *en
 		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
 
+	// version が必ず変わるため、0 件は存在しないかバージョン不一致を意味する
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
 	}
 	if rowsAffected == 0 {
-		return nil, domainErrors.ErrItemNotFound
+		return nil, r.conditionalWriteMiss(ctx, id)
+	}
+	if syncAttributes {
+		if err := r.syncAttributeIndex(ctx, id, item.Attributes); err != nil {
+			return nil, err
+		}
+	}
+	if syncTags {
+		if err := r.syncTags(ctx, id, item.Tags); err != nil {
+			return nil, err
+		}
 	}
 
 	// 更新後のデータを再取得
 	return r.FindByID(ctx, id)
 }
 
+// syncAttributeIndex は item_attributes の索引を attributes の内容で置き換える
+func (r *ItemRepository) syncAttributeIndex(ctx context.Context, id int64, attributes entity.Attributes) error {
+	if _, err := r.Execute(ctx, `DELETE FROM item_attributes WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	query := `INSERT INTO item_attributes (item_id, attr_key, attr_value) VALUES (?, ?, ?)`
+	for _, key := range attributes.Keys() {
+		if _, err := r.Execute(ctx, query, id, key, entity.AttributeValueString(attributes[key])); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+	}
+	return nil
+}
+
+// syncTags はアイテムに付いたタグを tags の内容で置き換える。tags に無いタグは作成する
+func (r *ItemRepository) syncTags(ctx context.Context, id int64, tags entity.Tags) error {
+	if _, err := r.Execute(ctx, `DELETE FROM item_tags WHERE item_id = ?`, id); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	for _, tag := range tags {
+		var tagID int64
+		err := r.QueryRow(ctx, `SELECT id FROM tags WHERE name = ?`, tag).Scan(&tagID)
+		if errors.Is(err, sql.ErrNoRows) {
+			tagID, err = r.Insert(ctx, `INSERT INTO tags (name) VALUES (?)`, tag)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+
+		if _, err := r.Execute(ctx, `INSERT INTO item_tags (item_id, tag_id) VALUES (?, ?)`, id, tagID); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+	}
+	return nil
+}
+
+// 1回の問い合わせで IN に並べるアイテムの数（SQLite のプレースホルダ数の上限を超えないようにする）
+const tagLoadBatchSize = 500
+
+// attachTags は items それぞれのタグを item_tags から読み込む
+func (r *ItemRepository) attachTags(ctx context.Context, items []*entity.Item) error {
+	byID := make(map[int64]*entity.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	for start := 0; start < len(items); start += tagLoadBatchSize {
+		batch := items[start:min(start+tagLoadBatchSize, len(items))]
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, item := range batch {
+			placeholders[i] = "?"
+			args[i] = item.ID
+		}
+
+		query := `
+            SELECT it.item_id, t.name
+            FROM item_tags it
+            JOIN tags t ON t.id = it.tag_id
+            WHERE it.item_id IN (` + joinClauses(placeholders, ", ") + `)
+            ORDER BY t.name
+        `
+		if err := r.scanTags(ctx, query, args, byID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ItemRepository) scanTags(ctx context.Context, query string, args []interface{}, byID map[int64]*entity.Item) error {
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int64
+		var tag string
+		if err := rows.Scan(&itemID, &tag); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		if item, ok := byID[itemID]; ok {
+			item.Tags = append(item.Tags, tag)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	return nil
+}
+
+// encodeAttributes は属性を items.attributes に保存する JSON にする。属性が無い場合は NULL
+func encodeAttributes(attributes entity.Attributes) (*string, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	s := string(encoded)
+	return &s, nil
+}
+
+// 条件の順序を一定にし、同じ絞り込みで同じ SQL になるようにする
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func joinClauses(clauses []string, sep string) string {
 	if len(clauses) == 0 {
 		return ""
@@ -0,0 +1,375 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// ngram パーサーの語の長さ（MySQL の ngram_token_size の既定値）。これより短い検索語は FULLTEXT インデックスで探せない
+const ngramTokenSize = 2
+
+// DefaultSearchIndexTTL はプロセス内の索引が、他のインスタンスでの書き込みを確かめる間隔の既定値
+const DefaultSearchIndexTTL = 5 * time.Second
+
+// Search は名前・ブランド・メモを全文検索する。
+// FullText の場合は MySQL の FULLTEXT インデックスを、それ以外はプロセス内の索引を使う
+func (r *ItemRepository) Search(ctx context.Context, criteria usecase.SearchCriteria) ([]*entity.Item, int, error) {
+	if r.FullText {
+		return r.searchFullText(ctx, criteria)
+	}
+
+	ttl := r.SearchIndexTTL
+	if ttl == 0 {
+		ttl = DefaultSearchIndexTTL
+	}
+	ids, total, err := r.index.search(ctx, r.SqlHandler, criteria, ttl)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := r.findByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// searchFullText は search_text の FULLTEXT インデックスを BOOLEAN MODE のフレーズ検索で探し、MATCH の関連度の順に返す。
+// ngram の長さより短い検索語は LIKE で絞り込む
+func (r *ItemRepository) searchFullText(ctx context.Context, criteria usecase.SearchCriteria) ([]*entity.Item, int, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+	var phrases []string
+	for _, term := range criteria.Terms {
+		// フレーズの中で特別な意味を持つのは " のみ
+		phrase := strings.ReplaceAll(term, `"`, "")
+		if utf8.RuneCountInString(phrase) < ngramTokenSize {
+			conditions = append(conditions, `search_text LIKE ?`)
+			args = append(args, "%"+escapeLike(term)+"%")
+			continue
+		}
+		phrases = append(phrases, `+"`+phrase+`"`)
+	}
+
+	relevance, relevanceArgs := "0", []interface{}{}
+	if len(phrases) > 0 {
+		against := strings.Join(phrases, " ")
+		conditions = append(conditions, "MATCH (search_text) AGAINST (? IN BOOLEAN MODE)")
+		args = append(args, against)
+		relevance = "MATCH (search_text) AGAINST (? IN BOOLEAN MODE)"
+		relevanceArgs = append(relevanceArgs, against)
+	}
+	where := "WHERE " + joinClauses(conditions, " AND ")
+
+	var total int
+	if err := r.QueryRow(ctx, "SELECT COUNT(*) FROM items "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	query := fmt.Sprintf(`
+        SELECT `+itemColumns+`
+        FROM items
+        %s
+        ORDER BY %s DESC, id DESC
+        LIMIT ? OFFSET ?
+    `, where, relevance)
+	args = append(append(args, relevanceArgs...), criteria.Limit, criteria.Offset)
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var items []*entity.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// LIKE のパターンで特別な意味を持つ文字をエスケープする（エスケープ文字は MySQL の既定の \）
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// FillSearchText は search_text が NULL のアイテム（マイグレーション前から存在するものやサンプルデータ）に
+// entity.SearchText の値を設定し、設定した件数を返す。全文検索に FULLTEXT インデックスを使う場合に起動時に呼ぶ
+func (r *ItemRepository) FillSearchText(ctx context.Context) (int64, error) {
+	rows, err := r.Query(ctx, `SELECT id, name, brand, notes FROM items WHERE search_text IS NULL`)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	var items []*entity.Item
+	for rows.Next() {
+		var item entity.Item
+		if err := rows.Scan(&item.ID, &item.Name, &item.Brand, &item.Notes); err != nil {
+			return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	// SQLite は接続が1つのため、更新の前に読み込みを閉じる
+	rows.Close()
+
+	var filled int64
+	for _, item := range items {
+		// 読み込んだ後に更新されたアイテムは、更新時に設定されているため上書きしない
+		result, err := r.Execute(ctx, `UPDATE items SET search_text = ? WHERE id = ? AND search_text IS NULL`, entity.SearchText(item), item.ID)
+		if err != nil {
+			return filled, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return filled, fmt.Errorf("%w: failed to get rows affected: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		filled += n
+	}
+	return filled, nil
+}
+
+// findByIDs はゴミ箱に無いアイテムを ids の順に取得する。存在しない id は読み飛ばす
+func (r *ItemRepository) findByIDs(ctx context.Context, ids []int64) ([]*entity.Item, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := `
+        SELECT ` + itemColumns + `
+        FROM items
+        WHERE id IN (` + joinClauses(placeholders, ", ") + `) AND deleted_at IS NULL
+    `
+
+	rows, err := r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*entity.Item, len(ids))
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		byID[item.ID] = item
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	items := make([]*entity.Item, 0, len(byID))
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			items = append(items, item)
+		}
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// searchIndex は MySQL 以外のデータベースで全文検索に使うプロセス内の索引。
+// ゴミ箱に無いアイテムの名前・ブランド・メモを FoldSearchText で畳み込み、1文字と2文字（bigram）ごとの転置索引で持つ。
+// 同じ ItemRepository での書き込みは invalidate で印を付け、次の検索で items の件数・バージョンの合計・最大の id を確かめて、
+// いずれかが変わっていれば作り直す（作成・更新・削除・復元はバージョンを、物理削除は件数を必ず変える）。
+// 他のインスタンスでの書き込みは、前回確かめてから ttl が過ぎた検索で同じように確かめる
+type searchIndex struct {
+	mu          sync.Mutex
+	fingerprint *[3]int64
+	checkedAt   time.Time   // fingerprint を最後に確かめた時刻
+	dirty       atomic.Bool // checkedAt の後に書き込みがあった
+	docs        map[int64]*entity.Item
+	postings    map[string][]int64 // 語を含むアイテムの id（昇順）
+}
+
+// invalidate は書き込みがあったことを記録し、次の検索で items が変わったかを確かめさせる。
+// 検索は mu を持ったままデータベースを読むため、接続を持った書き込みから mu を待たないよう atomic で持つ
+func (x *searchIndex) invalidate() {
+	x.dirty.Store(true)
+}
+
+func (x *searchIndex) search(ctx context.Context, h SqlHandler, criteria usecase.SearchCriteria, ttl time.Duration) ([]int64, int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.fingerprint == nil || x.dirty.Load() || time.Since(x.checkedAt) >= ttl {
+		// 確かめている間の書き込みも次の検索で確かめるよう、読み込む前に印を外す。
+		// 確定前の書き込みはここでは見えないため、その場合は ttl が過ぎた検索で反映する
+		checkedAt := time.Now()
+		x.dirty.Store(false)
+		if err := x.refresh(ctx, h); err != nil {
+			x.dirty.Store(true)
+			return nil, 0, err
+		}
+		x.checkedAt = checkedAt
+	}
+
+	type hit struct {
+		id    int64
+		score float64
+	}
+	var hits []hit
+	for _, id := range x.candidates(criteria.Terms) {
+		// 語の索引は候補の絞り込みにのみ使い、一致するかはフィールドの内容で確かめる
+		if match, ok := entity.MatchSearch(x.docs[id], criteria.Terms); ok {
+			hits = append(hits, hit{id: id, score: match.Score})
+		}
+	}
+	// 関連度の高い順、同じ場合は新しい（id の大きい）順
+	slices.SortFunc(hits, func(a, b hit) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.id, a.id)
+	})
+
+	var ids []int64
+	for i := criteria.Offset; i < len(hits) && i < criteria.Offset+criteria.Limit; i++ {
+		ids = append(ids, hits[i].id)
+	}
+	return ids, len(hits), nil
+}
+
+// refresh は items が変わっていれば索引を作り直す
+func (x *searchIndex) refresh(ctx context.Context, h SqlHandler) error {
+	var fingerprint [3]int64
+	err := h.QueryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(version), 0), COALESCE(MAX(id), 0) FROM items`).
+		Scan(&fingerprint[0], &fingerprint[1], &fingerprint[2])
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	if x.fingerprint != nil && *x.fingerprint == fingerprint {
+		return nil
+	}
+
+	rows, err := h.Query(ctx, `SELECT id, name, brand, notes FROM items WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+	defer rows.Close()
+
+	docs := make(map[int64]*entity.Item)
+	postings := make(map[string][]int64)
+	for rows.Next() {
+		var item entity.Item
+		if err := rows.Scan(&item.ID, &item.Name, &item.Brand, &item.Notes); err != nil {
+			return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+		}
+		docs[item.ID] = &item
+		// id の昇順に読み込むため、各語の id も昇順になる
+		for _, token := range searchTokens(entity.SearchText(&item)) {
+			postings[token] = append(postings[token], item.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrDatabaseError, err.Error())
+	}
+
+	x.fingerprint, x.docs, x.postings = &fingerprint, docs, postings
+	return nil
+}
+
+// candidates はすべての検索語の語を含むアイテムの id を返す
+func (x *searchIndex) candidates(terms []string) []int64 {
+	var ids []int64
+	for i, term := range terms {
+		for j, token := range termTokens(term) {
+			if i == 0 && j == 0 {
+				ids = x.postings[token]
+				continue
+			}
+			ids = intersectSorted(ids, x.postings[token])
+		}
+	}
+	return ids
+}
+
+// searchTokens は畳み込み済みの text に含まれる1文字と2文字の語を重複なく返す。空白・改行を含む語は作らない
+func searchTokens(text string) []string {
+	seen := make(map[string]struct{})
+	var tokens []string
+	add := func(token string) {
+		if _, ok := seen[token]; !ok {
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for i := range runes {
+			add(string(runes[i]))
+			if i+1 < len(runes) {
+				add(string(runes[i : i+2]))
+			}
+		}
+	}
+	return tokens
+}
+
+// termTokens は検索語を探すための語。1文字の検索語はその文字、それ以外は検索語に含まれる2文字の語
+func termTokens(term string) []string {
+	runes := []rune(term)
+	if len(runes) == 1 {
+		return []string{term}
+	}
+	tokens := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		tokens = append(tokens, string(runes[i:i+2]))
+	}
+	return tokens
+}
+
+// intersectSorted は昇順の a と b の両方に含まれる値を返す
+func intersectSorted(a, b []int64) []int64 {
+	var result []int64
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// テスト用に一時的に起動する PostgreSQL。
+// ネットワークに接続できない環境でも PostgreSQL のバックエンドを検証できるよう、
+// ローカルにインストールされた initdb と pg_ctl（POSTGRES_BIN のディレクトリ、無ければ PATH）で
+// 一時ディレクトリにクラスタを作成し、Unix ドメインソケットのみで待ち受ける
+var ephemeralPostgres struct {
+	once sync.Once
+	dsn  string
+	err  error
+	stop func()
+}
+
+// 一時的な PostgreSQL を起動できない環境（未インストール、root で実行など）。この場合はテストを省略する
+var errPostgresUnavailable = errors.New("ephemeral postgres is unavailable")
+
+// postgresDSN は TEST_POSTGRES_DSN、無ければ一時的に起動した PostgreSQL の接続文字列を返す
+func postgresDSN() (string, error) {
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		return dsn, nil
+	}
+
+	ephemeralPostgres.once.Do(func() {
+		ephemeralPostgres.dsn, ephemeralPostgres.stop, ephemeralPostgres.err = startEphemeralPostgres()
+	})
+	return ephemeralPostgres.dsn, ephemeralPostgres.err
+}
+
+// stopEphemeralPostgres は起動した PostgreSQL を停止し、データを削除する
+func stopEphemeralPostgres() {
+	if ephemeralPostgres.stop != nil {
+		ephemeralPostgres.stop()
+	}
+}
+
+func startEphemeralPostgres() (string, func(), error) {
+	initdb, err := postgresBinary("initdb")
+	if err != nil {
+		return "", nil, err
+	}
+	pgCtl, err := postgresBinary("pg_ctl")
+	if err != nil {
+		return "", nil, err
+	}
+	if os.Geteuid() == 0 {
+		return "", nil, fmt.Errorf("%w: initdb cannot be run as root", errPostgresUnavailable)
+	}
+
+	// ソケットのパスには長さの上限があるため、短いパスの一時ディレクトリを使う
+	dir, err := os.MkdirTemp("", "aicon-pg-")
+	if err != nil {
+		return "", nil, err
+	}
+	data := filepath.Join(dir, "data")
+
+	run := func(name string, args ...string) error {
+		out, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s: %w\n%s", filepath.Base(name), err, out)
+		}
+		return nil
+	}
+
+	if err := run(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-locale"); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	if err := run(pgCtl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-w",
+		"-o", fmt.Sprintf("-h '' -k %s -p 5432 -F", dir), "start"); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	stop := func() {
+		_ = run(pgCtl, "-D", data, "-m", "immediate", "-w", "stop")
+		os.RemoveAll(dir)
+	}
+	dsn := fmt.Sprintf("host=%s port=5432 user=postgres dbname=postgres sslmode=disable", dir)
+	return dsn, stop, nil
+}
+
+func postgresBinary(name string) (string, error) {
+	if dir := os.Getenv("POSTGRES_BIN"); dir != "" {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s is not installed", errPostgresUnavailable, name)
+	}
+	return path, nil
+}
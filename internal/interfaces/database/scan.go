@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// DATE / TIMESTAMP カラムの値はドライバによって time.Time・[]byte・string のいずれかで返るため、
+// どの形式でも読み込めるスキャナを用意する
+
+// 文字列で返る日時の形式（SQLite の CURRENT_TIMESTAMP など）
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02",
+}
+
+// nullTime は NULL を許容する TIMESTAMP カラムのスキャナ。文字列は UTC として解釈する
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *nullTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a timestamp", src)
+	}
+}
+
+func (t *nullTime) parse(s string) error {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as a timestamp", s)
+}
+
+// dbTime は NOT NULL の TIMESTAMP カラムのスキャナ
+type dbTime struct {
+	nullTime
+}
+
+func (t *dbTime) Scan(src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("unexpected NULL timestamp")
+	}
+	return t.nullTime.Scan(src)
+}
+
+// dbDate は DATE カラムを YYYY-MM-DD の文字列として読み込むスキャナ
+type dbDate struct {
+	Value string
+}
+
+func (d *dbDate) Scan(src interface{}) error {
+	var t dbTime
+	if err := t.Scan(src); err != nil {
+		return err
+	}
+	d.Value = t.Time.Format("2006-01-02")
+	return nil
+}
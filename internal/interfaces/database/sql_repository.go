@@ -1,14 +1,45 @@
 package database
 
-import "context"
+import (
+	"context"
 
+	"Aicon-assignment/internal/usecase"
+)
+
+// SqlHandler は SQL の実行手段。文のプレースホルダは ? で書き、データベースごとの形式への変換は実装側で行う
 type SqlHandler interface {
 	Execute(ctx context.Context, statement string, args ...interface{}) (Result, error)
 	Query(ctx context.Context, statement string, args ...interface{}) (Rows, error)
 	QueryRow(ctx context.Context, statement string, args ...interface{}) Row
+	// Insert は INSERT 文を実行し、採番された id を返す。
+	// LastInsertId に対応しないデータベース（PostgreSQL）では RETURNING id で取得する
+	Insert(ctx context.Context, statement string, args ...interface{}) (int64, error)
+	// InsertMany は複数行の INSERT 文（INSERT ... VALUES (...), (...)）を実行し、採番された id を行の順に返す
+	InsertMany(ctx context.Context, statement string, args ...interface{}) ([]int64, error)
+	// Begin はトランザクションを開始する。ctx が既にトランザクション内であればセーブポイントを作成する
+	Begin(ctx context.Context, opts ...usecase.TxOptions) (Tx, error)
+	// WithTx は fn をトランザクション内で実行する。fn に渡される ctx を使った
+	// Execute / Query / QueryRow はすべて同じトランザクションで実行され、
+	// fn がエラーを返すかパニックした場合はロールバックされる
+	WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...usecase.TxOptions) error
 	Close() error
 }
 
+// Tx は Begin で開始した作業単位
+type Tx interface {
+	Execute(ctx context.Context, statement string, args ...interface{}) (Result, error)
+	Query(ctx context.Context, statement string, args ...interface{}) (Rows, error)
+	QueryRow(ctx context.Context, statement string, args ...interface{}) Row
+	Insert(ctx context.Context, statement string, args ...interface{}) (int64, error)
+	InsertMany(ctx context.Context, statement string, args ...interface{}) ([]int64, error)
+	// Context は parent にこのトランザクションを関連付けた ctx を返す。
+	// 返された ctx で SqlHandler を使うリポジトリを呼ぶと、このトランザクションに参加する
+	Context(parent context.Context) context.Context
+	// Commit と Rollback は入れ子の場合、ctx でセーブポイントを解放・巻き戻す
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
 type Result interface {
 	LastInsertId() (int64, error)
 	RowsAffected() (int64, error)
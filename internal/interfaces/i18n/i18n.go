@@ -0,0 +1,157 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// Language はレスポンスのメッセージに使う言語
+type Language string
+
+const (
+	Japanese Language = "ja"
+	English  Language = "en"
+)
+
+// 言語を echo.Context に保持するためのキー
+const contextKey = "i18n.language"
+
+// Parse は "ja" や "en-US" のような言語タグから対応する言語を返す
+func Parse(tag string) (Language, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang := Language(strings.ToLower(primary))
+	if _, ok := catalog[lang]; !ok {
+		return "", false
+	}
+	return lang, true
+}
+
+// Negotiate は Accept-Language ヘッダから対応する言語のうち最も優先度の高いものを返す。
+// 対応する言語が無ければ fallback を返す
+func Negotiate(acceptLanguage string, fallback Language) Language {
+	type candidate struct {
+		lang Language
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		if lang, ok := Parse(tag); ok {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+	// q が同じ場合はヘッダに書かれた順を優先する
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// Middleware は Accept-Language からレスポンスの言語を決めて echo.Context に設定する
+func Middleware(fallback Language) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			lang := Negotiate(c.Request().Header.Get("Accept-Language"), fallback)
+			c.Set(contextKey, lang)
+			c.Response().Header().Set("Content-Language", string(lang))
+			c.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+			return next(c)
+		}
+	}
+}
+
+// FromContext は Middleware が設定した言語を返す（未設定の場合は英語）
+func FromContext(c echo.Context) Language {
+	if lang, ok := c.Get(contextKey).(Language); ok {
+		return lang
+	}
+	return English
+}
+
+// Message は code のメッセージを lang で返す。
+// lang に訳が無ければ英語、英語にも無ければ code をそのまま返す
+func Message(lang Language, code Code) string {
+	return lookup(lang, string(code))
+}
+
+// FieldMessage はバリデーションエラーのメッセージを lang で返す
+func FieldMessage(lang Language, fe entity.FieldError) string {
+	template := lookup(lang, "validation."+string(fe.Code))
+	if template == "validation."+string(fe.Code) {
+		return fe.Message
+	}
+
+	replacements := []string{"{field}", fieldLabel(lang, fe.Field)}
+	for name, value := range fe.Params {
+		replacements = append(replacements, "{"+name+"}", formatParam(lang, value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// LocalizeFieldErrors は errs のメッセージを lang に置き換えた複製を返す
+func LocalizeFieldErrors(lang Language, errs entity.ValidationErrors) entity.ValidationErrors {
+	localized := make(entity.ValidationErrors, len(errs))
+	for i, fe := range errs {
+		fe.Message = FieldMessage(lang, fe)
+		localized[i] = fe
+	}
+	return localized
+}
+
+func lookup(lang Language, key string) string {
+	if message, ok := catalog[lang][key]; ok {
+		return message
+	}
+	if message, ok := catalog[English][key]; ok {
+		return message
+	}
+	return key
+}
+
+// フィールド名の表示名（訳が無い場合はフィールド名のまま）。
+// "aliases[0]" の添字や "attributes.movement" のキーは表示名の後ろに残す
+func fieldLabel(lang Language, field string) string {
+	name, rest := field, ""
+	if i := strings.IndexAny(field, "[."); i >= 0 {
+		name, rest = field[:i], field[i:]
+	}
+	label, ok := catalog[lang]["field."+name]
+	if !ok {
+		return field
+	}
+	return label + rest
+}
+
+// JoinList は items を lang の区切り文字（ja は "、"、en は ", "）で連結する
+func JoinList(lang Language, items []string) string {
+	return strings.Join(items, lookup(lang, "list.separator"))
+}
+
+func formatParam(lang Language, value interface{}) string {
+	if list, ok := value.([]string); ok {
+		return JoinList(lang, list)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,214 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		fallback       Language
+		expected       Language
+	}{
+		{
+			name:           "正常系: ヘッダが無ければ fallback",
+			acceptLanguage: "",
+			fallback:       Japanese,
+			expected:       Japanese,
+		},
+		{
+			name:           "正常系: 地域付きのタグ",
+			acceptLanguage: "ja-JP",
+			fallback:       English,
+			expected:       Japanese,
+		},
+		{
+			name:           "正常系: q の大きい言語を優先",
+			acceptLanguage: "en;q=0.5, ja;q=0.8",
+			fallback:       English,
+			expected:       Japanese,
+		},
+		{
+			name:           "正常系: q が同じならヘッダの順",
+			acceptLanguage: "EN-us, ja",
+			fallback:       Japanese,
+			expected:       English,
+		},
+		{
+			name:           "正常系: 対応していない言語は飛ばす",
+			acceptLanguage: "fr-FR, de;q=0.9, ja;q=0.1",
+			fallback:       English,
+			expected:       Japanese,
+		},
+		{
+			name:           "正常系: q=0 の言語は使わない",
+			acceptLanguage: "ja;q=0",
+			fallback:       English,
+			expected:       English,
+		},
+		{
+			name:           "正常系: ワイルドカードのみは fallback",
+			acceptLanguage: "*",
+			fallback:       Japanese,
+			expected:       Japanese,
+		},
+		{
+			name:           "異常系: 不正な q は無視",
+			acceptLanguage: "ja;q=abc, en;q=0.1",
+			fallback:       Japanese,
+			expected:       English,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.acceptLanguage, tt.fallback))
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Language
+		code     Code
+		expected string
+	}{
+		{
+			name:     "正常系: 英語",
+			lang:     English,
+			code:     ItemNotFound,
+			expected: "item not found",
+		},
+		{
+			name:     "正常系: 日本語",
+			lang:     Japanese,
+			code:     ItemNotFound,
+			expected: "アイテムが見つかりません",
+		},
+		{
+			name:     "異常系: 未知の言語は英語",
+			lang:     Language("fr"),
+			code:     InvalidItemID,
+			expected: "invalid item ID",
+		},
+		{
+			name:     "異常系: 未知のコードはコードのまま",
+			lang:     Japanese,
+			code:     Code("unknown_error"),
+			expected: "unknown_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Message(tt.lang, tt.code))
+		})
+	}
+}
+
+// すべての言語がすべてのコードの訳を持っていること
+func TestCatalog_Complete(t *testing.T) {
+	for key := range catalog[English] {
+		assert.Contains(t, catalog[Japanese], key, "ja is missing %q", key)
+	}
+}
+
+func TestFieldMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Language
+		fe       entity.FieldError
+		expected string
+	}{
+		{
+			name:     "正常系: 英語はエンティティのメッセージと同じ",
+			lang:     English,
+			fe:       entity.TooLongError("name", 100),
+			expected: entity.TooLongError("name", 100).Message,
+		},
+		{
+			name:     "正常系: 必須",
+			lang:     Japanese,
+			fe:       entity.RequiredError("brand"),
+			expected: "ブランドは必須です",
+		},
+		{
+			name:     "正常系: 文字数",
+			lang:     Japanese,
+			fe:       entity.TooLongError("name", 100),
+			expected: "名前は100文字以内で入力してください",
+		},
+		{
+			name:     "正常系: 選択肢",
+			lang:     Japanese,
+			fe:       entity.InvalidEnumError("category", []string{"時計", "バッグ"}),
+			expected: "カテゴリーは時計、バッグのいずれかを指定してください",
+		},
+		{
+			name:     "正常系: 形式",
+			lang:     Japanese,
+			fe:       entity.InvalidFormatError("purchase_date", "YYYY-MM-DD"),
+			expected: "購入日はYYYY-MM-DD形式で入力してください",
+		},
+		{
+			name:     "正常系: 範囲",
+			lang:     Japanese,
+			fe:       entity.OutOfRangeError("purchase_price", 0),
+			expected: "購入価格は0以上で入力してください",
+		},
+		{
+			name:     "正常系: 未登録",
+			lang:     Japanese,
+			fe:       entity.NotRegisteredError("brand", []string{"ROLEX"}),
+			expected: "ブランドが登録されていません",
+		},
+		{
+			name:     "正常系: 添字付きのフィールド",
+			lang:     Japanese,
+			fe:       entity.TooLongError("aliases[1]", 100),
+			expected: "別名[1]は100文字以内で入力してください",
+		},
+		{
+			name:     "正常系: 属性のフィールド",
+			lang:     Japanese,
+			fe:       entity.InvalidTypeError("attributes.case_size", entity.AttributeTypeNumber),
+			expected: "属性.case_sizeはnumberで入力してください",
+		},
+		{
+			name:     "正常系: 定義されていない属性",
+			lang:     Japanese,
+			fe:       entity.UnknownFieldError("attributes.color"),
+			expected: "属性.colorは定義されていません",
+		},
+		{
+			name:     "正常系: 個数の上限",
+			lang:     Japanese,
+			fe:       entity.TooManyError("tags", 20),
+			expected: "タグは20個以内で指定してください",
+		},
+		{
+			name:     "正常系: 表示名の無いフィールドはフィールド名のまま",
+			lang:     Japanese,
+			fe:       entity.RequiredError("color"),
+			expected: "colorは必須です",
+		},
+		{
+			name:     "異常系: 未知のコードはエンティティのメッセージ",
+			lang:     Japanese,
+			fe:       entity.FieldError{Field: "name", Code: "unknown", Message: "name is odd"},
+			expected: "name is odd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FieldMessage(tt.lang, tt.fe))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package i18n
+
+// Code はエラーレスポンスのメッセージを表す機械可読なコード
+type Code string
+
+const (
+	InvalidItemID          Code = "invalid_item_id"
+	InvalidQueryParameter  Code = "invalid_query_parameter"
+	InvalidRequestFormat   Code = "invalid_request_format"
+	UnsupportedContentType Code = "unsupported_content_type"
+	ValidationFailed       Code = "validation_failed"
+	ItemNotFound           Code = "item_not_found"
+	ItemNotFoundInTrash    Code = "item_not_found_in_trash"
+	PreconditionRequired   Code = "precondition_required"
+	PreconditionFailed     Code = "precondition_failed"
+	InvalidInput           Code = "invalid_input"
+	DuplicateEntry         Code = "duplicate_entry"
+	DatabaseError          Code = "database_error"
+	InternalError          Code = "internal_error"
+	InvalidBrandID         Code = "invalid_brand_id"
+	BrandNotFound          Code = "brand_not_found"
+	InUse                  Code = "in_use"
+	InvalidCategoryID      Code = "invalid_category_id"
+	CategoryNotFound       Code = "category_not_found"
+	UnsupportedImportFile  Code = "unsupported_import_file"
+	InvalidCSVHeader       Code = "invalid_csv_header"
+
+	// ルーティングなど、echo が返す HTTP エラー
+	NotFound         Code = "not_found"
+	MethodNotAllowed Code = "method_not_allowed"
+)
+
+// メッセージカタログ。
+// エラーコードのほか、"validation.<ValidationCode>" にバリデーションメッセージのテンプレート、
+// "field.<フィールド名>" にフィールドの表示名を持つ。
+// テンプレートの {field} は表示名、{max} などは FieldError.Params の値に置き換える
+var catalog = map[Language]map[string]string{
+	English: {
+		string(InvalidItemID):          "invalid item ID",
+		string(InvalidQueryParameter):  "invalid query parameter",
+		string(InvalidRequestFormat):   "invalid request format",
+		string(UnsupportedContentType): "content type must be application/merge-patch+json or application/json",
+		string(ValidationFailed):       "validation failed",
+		string(ItemNotFound):           "item not found",
+		string(ItemNotFoundInTrash):    "item not found in trash",
+		string(PreconditionRequired):   "If-Match header is required",
+		string(PreconditionFailed):     "If-Match does not match the current item version",
+		string(InvalidInput):           "invalid input",
+		string(DuplicateEntry):         "duplicate entry",
+		string(DatabaseError):          "database error",
+		string(InternalError):          "internal server error",
+		string(InvalidBrandID):         "invalid brand ID",
+		string(BrandNotFound):          "brand not found",
+		string(InUse):                  "resource is in use",
+		string(InvalidCategoryID):      "invalid category ID",
+		string(CategoryNotFound):       "category not found",
+		string(UnsupportedImportFile):  "request body must be CSV (text/csv) encoded in UTF-8 or Shift_JIS",
+		string(InvalidCSVHeader):       "invalid CSV header",
+		string(NotFound):               "resource not found",
+		string(MethodNotAllowed):       "method not allowed",
+
+		"validation.required":           "{field} is required",
+		"validation.too_long":           "{field} must be {max} characters or less",
+		"validation.invalid_enum":       "{field} must be one of: {allowed}",
+		"validation.invalid_format":     "{field} must be in {format} format",
+		"validation.out_of_range":       "{field} must be {min} or greater",
+		"validation.not_registered":     "{field} is not registered",
+		"validation.circular_reference": "{field} must not refer to itself or its descendants",
+		"validation.invalid_type":       "{field} must be {type}",
+		"validation.unknown_field":      "{field} is not defined",
+		"validation.duplicate":          "{field} is duplicated",
+		"validation.too_many":           "{field} must have {max} items or fewer",
+		"validation.out_of_bounds":      "{field} must be between {min} and {max}",
+		"validation.invalid_range":      "{field} must not be greater than {other}",
+		"validation.conflict":           "{field} cannot be combined with {other}",
+		"validation.invalid":            "{field} is invalid",
+		"validation.mismatch":           "{field} does not match {other}",
+		"validation.malformed":          "{field} is malformed at line {line}",
+		"validation.in_use":             "{field} still belong to it",
+
+		"list.separator": ", ",
+	},
+	Japanese: {
+		string(InvalidItemID):          "アイテムIDが不正です",
+		string(InvalidQueryParameter):  "クエリパラメータが不正です",
+		string(InvalidRequestFormat):   "リクエストの形式が不正です",
+		string(UnsupportedContentType): "Content-Type は application/merge-patch+json または application/json を指定してください",
+		string(ValidationFailed):       "入力内容に誤りがあります",
+		string(ItemNotFound):           "アイテムが見つかりません",
+		string(ItemNotFoundInTrash):    "ゴミ箱にアイテムが見つかりません",
+		string(PreconditionRequired):   "If-Match ヘッダを指定してください",
+		string(PreconditionFailed):     "アイテムは他のリクエストで更新されています",
+		string(InvalidInput):           "入力内容が不正です",
+		string(DuplicateEntry):         "同じデータが既に登録されています",
+		string(DatabaseError):          "データベースの処理に失敗しました",
+		string(InternalError):          "サーバー内部でエラーが発生しました",
+		string(InvalidBrandID):         "ブランドIDが不正です",
+		string(BrandNotFound):          "ブランドが見つかりません",
+		string(InUse):                  "使用中のため削除できません",
+		string(InvalidCategoryID):      "カテゴリーIDが不正です",
+		string(CategoryNotFound):       "カテゴリーが見つかりません",
+		string(UnsupportedImportFile):  "UTF-8 または Shift_JIS の CSV（text/csv）を送信してください",
+		string(InvalidCSVHeader):       "CSV のヘッダー行が不正です",
+		string(NotFound):               "リソースが見つかりません",
+		string(MethodNotAllowed):       "このメソッドは使用できません",
+
+		"validation.required":           "{field}は必須です",
+		"validation.too_long":           "{field}は{max}文字以内で入力してください",
+		"validation.invalid_enum":       "{field}は{allowed}のいずれかを指定してください",
+		"validation.invalid_format":     "{field}は{format}形式で入力してください",
+		"validation.out_of_range":       "{field}は{min}以上で入力してください",
+		"validation.not_registered":     "{field}が登録されていません",
+		"validation.circular_reference": "{field}に自身または子孫のカテゴリーは指定できません",
+		"validation.invalid_type":       "{field}は{type}で入力してください",
+		"validation.unknown_field":      "{field}は定義されていません",
+		"validation.duplicate":          "{field}が重複しています",
+		"validation.too_many":           "{field}は{max}個以内で指定してください",
+		"validation.out_of_bounds":      "{field}は{min}以上{max}以下で指定してください",
+		"validation.invalid_range":      "{field}は{other}以下にしてください",
+		"validation.conflict":           "{field}は{other}と同時に指定できません",
+		"validation.invalid":            "{field}が不正です",
+		"validation.mismatch":           "{field}が{other}と一致しません",
+		"validation.malformed":          "{field}の{line}行目の形式が不正です",
+		"validation.in_use":             "{field}が属しています",
+
+		"field.name":            "名前",
+		"field.category":        "カテゴリー",
+		"field.brand":           "ブランド",
+		"field.purchase_price":  "購入価格",
+		"field.purchase_date":   "購入日",
+		"field.country":         "原産国",
+		"field.aliases":         "別名",
+		"field.display_name_ja": "表示名（日本語）",
+		"field.display_name_en": "表示名（英語）",
+		"field.sort_order":      "表示順",
+		"field.reassign_to":     "移動先のカテゴリー",
+		"field.parent_id":       "親カテゴリー",
+		"field.attributes":      "属性",
+		"field.tags":            "タグ",
+		"field.notes":           "メモ",
+		"field.items":           "アイテム",
+		"field.subcategories":   "子カテゴリー",
+		"field.body":            "本文",
+
+		"list.separator": "、",
+	},
+}
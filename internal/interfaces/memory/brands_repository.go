@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// BrandRepository は usecase.BrandRepository のメモリ上の実装
+type BrandRepository struct {
+	store *Store
+}
+
+func NewBrandRepository(store *Store) *BrandRepository {
+	return &BrandRepository{store: store}
+}
+
+func (r *BrandRepository) FindAll(ctx context.Context) ([]*entity.Brand, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	brands := make([]*entity.Brand, 0, len(r.store.brands))
+	for _, brand := range r.store.brands {
+		brands = append(brands, copyBrand(brand))
+	}
+	sort.Slice(brands, func(i, j int) bool {
+		if brands[i].NameCanonical != brands[j].NameCanonical {
+			return brands[i].NameCanonical < brands[j].NameCanonical
+		}
+		return brands[i].ID < brands[j].ID
+	})
+	return brands, nil
+}
+
+func (r *BrandRepository) FindByID(ctx context.Context, id int64) (*entity.Brand, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	brand, ok := r.store.brands[id]
+	if !ok {
+		return nil, domainErrors.ErrBrandNotFound
+	}
+	return copyBrand(brand), nil
+}
+
+func (r *BrandRepository) FindByKey(ctx context.Context, key string) (*entity.Brand, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, brand := range r.store.brands {
+		for _, k := range brand.Keys() {
+			if k == key {
+				return copyBrand(brand), nil
+			}
+		}
+	}
+	return nil, domainErrors.ErrBrandNotFound
+}
+
+func (r *BrandRepository) Create(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkUnique(brand); err != nil {
+		return nil, err
+	}
+
+	now := r.store.timestamp()
+	r.store.nextBrandID++
+	created := copyBrand(brand)
+	created.ID = r.store.nextBrandID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.brands[created.ID] = created
+
+	return copyBrand(created), nil
+}
+
+func (r *BrandRepository) Update(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.brands[brand.ID]
+	if !ok {
+		return nil, domainErrors.ErrBrandNotFound
+	}
+	if err := r.checkUnique(brand); err != nil {
+		return nil, err
+	}
+
+	stored.Name = brand.Name
+	stored.NameCanonical = brand.NameCanonical
+	stored.Country = brand.Country
+	stored.Aliases = append([]string{}, brand.Aliases...)
+	stored.UpdatedAt = r.store.timestamp()
+
+	return copyBrand(stored), nil
+}
+
+func (r *BrandRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.brands[id]; !ok {
+		return domainErrors.ErrBrandNotFound
+	}
+	// ゴミ箱にあるアイテムも外部キーで参照しているため削除できない
+	for _, item := range r.store.items {
+		if item.BrandID != nil && *item.BrandID == id {
+			return domainErrors.ErrInUse
+		}
+	}
+
+	delete(r.store.brands, id)
+	return nil
+}
+
+// SQL の実装の UNIQUE 制約と同じく、名前と別名の正規形が他のブランドと重複する場合は ErrDuplicateEntry。
+// 呼び出し側で store.mu の書き込みロックを取得していること
+func (r *BrandRepository) checkUnique(brand *entity.Brand) error {
+	for _, other := range r.store.brands {
+		if other.ID == brand.ID {
+			continue
+		}
+		for _, key := range other.Keys() {
+			for _, k := range brand.Keys() {
+				if key == k {
+					return domainErrors.ErrDuplicateEntry
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// CategoryRepository は usecase.CategoryRepository のメモリ上の実装
+type CategoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	categories := make([]*entity.Category, 0, len(r.store.categories))
+	for _, category := range r.store.categories {
+		categories = append(categories, copyCategory(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].ID < categories[j].ID
+	})
+	return categories, nil
+}
+
+func (r *CategoryRepository) FindByID(ctx context.Context, id int64) (*entity.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok {
+		return nil, domainErrors.ErrCategoryNotFound
+	}
+	return copyCategory(category), nil
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkUnique(category); err != nil {
+		return nil, err
+	}
+
+	now := r.store.timestamp()
+	r.store.nextCategoryID++
+	created := copyCategory(category)
+	created.ID = r.store.nextCategoryID
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.categories[created.ID] = created
+
+	return copyCategory(created), nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.categories[category.ID]
+	if !ok {
+		return nil, domainErrors.ErrCategoryNotFound
+	}
+	if err := r.checkUnique(category); err != nil {
+		return nil, err
+	}
+
+	stored.Name = category.Name
+	stored.ParentID = category.ParentID
+	stored.DisplayNameJa = category.DisplayNameJa
+	stored.DisplayNameEn = category.DisplayNameEn
+	stored.Attributes = copyCategory(category).Attributes
+	stored.SortOrder = category.SortOrder
+	stored.Active = category.Active
+	stored.UpdatedAt = r.store.timestamp()
+
+	return copyCategory(stored), nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.categories[id]
+	if !ok {
+		return domainErrors.ErrCategoryNotFound
+	}
+
+	if count := r.countItems(stored.Name); count > 0 {
+		return fmt.Errorf("%w: %d items are in the category", domainErrors.ErrInUse, count)
+	}
+
+	delete(r.store.categories, id)
+	return nil
+}
+
+func (r *CategoryRepository) CountItems(ctx context.Context, name string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.countItems(name), nil
+}
+
+// 呼び出し側で store.mu のロックを取得していること
+func (r *CategoryRepository) countItems(name string) int {
+	count := 0
+	for _, item := range r.store.items {
+		if item.Category == name {
+			count++
+		}
+	}
+	return count
+}
+
+// SQL の実装の UNIQUE 制約と同じく、名前が他のカテゴリーと重複する場合は ErrDuplicateEntry。
+// 呼び出し側で store.mu の書き込みロックを取得していること
+func (r *CategoryRepository) checkUnique(category *entity.Category) error {
+	for _, other := range r.store.categories {
+		if other.ID != category.ID && other.Name == category.Name {
+			return domainErrors.ErrDuplicateEntry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// ItemEventRepository は usecase.ItemEventRepository のメモリ上の実装
+type ItemEventRepository struct {
+	store *Store
+}
+
+func NewItemEventRepository(store *Store) *ItemEventRepository {
+	return &ItemEventRepository{store: store}
+}
+
+func (r *ItemEventRepository) Append(ctx context.Context, event *entity.ItemEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextEventID++
+	event.ID = r.store.nextEventID
+
+	stored := *event
+	stored.Changes = make(map[string]entity.FieldChange, len(event.Changes))
+	for field, change := range event.Changes {
+		stored.Changes[field] = change
+	}
+	r.store.events = append(r.store.events, &stored)
+
+	return nil
+}
+
+func (r *ItemEventRepository) Find(ctx context.Context, criteria usecase.AuditCriteria) ([]*entity.ItemEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// 新しい順（id の降順）に返す
+	var events []*entity.ItemEvent
+	skipped := 0
+	for i := len(r.store.events) - 1; i >= 0 && len(events) < criteria.Limit; i-- {
+		event := r.store.events[i]
+		if !matchesAudit(event, criteria) {
+			continue
+		}
+		if skipped < criteria.Offset {
+			skipped++
+			continue
+		}
+		c := *event
+		events = append(events, &c)
+	}
+	return events, nil
+}
+
+func (r *ItemEventRepository) Count(ctx context.Context, criteria usecase.AuditCriteria) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, event := range r.store.events {
+		if matchesAudit(event, criteria) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func matchesAudit(event *entity.ItemEvent, criteria usecase.AuditCriteria) bool {
+	if criteria.ItemID != nil && event.ItemID != *criteria.ItemID {
+		return false
+	}
+	if criteria.Actor != "" && event.Actor != criteria.Actor {
+		return false
+	}
+	if criteria.Action != "" && event.Action != criteria.Action {
+		return false
+	}
+	if criteria.From != nil && event.CreatedAt.Before(*criteria.From) {
+		return false
+	}
+	if criteria.To != nil && !event.CreatedAt.Before(*criteria.To) {
+		return false
+	}
+	return true
+}
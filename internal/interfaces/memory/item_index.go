@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// ItemIndex は usecase.ItemIndex のメモリ上の実装で、ゴミ箱に無いアイテムの複製を保持する。
+// 絞り込みと並び替えは ItemRepository と同じ規則で行う。並行に呼び出しても安全
+type ItemIndex struct {
+	// 作り直しを1つずつ実行するためのロック
+	rebuildMu sync.Mutex
+
+	mu    sync.RWMutex
+	items map[int64]*entity.Item
+	// 作り直しの読み込み中に Put・Remove されたアイテム（Remove は nil）。読み込み中でない場合は nil
+	pending map[int64]*entity.Item
+}
+
+func NewItemIndex() *ItemIndex {
+	return &ItemIndex{items: make(map[int64]*entity.Item)}
+}
+
+func (x *ItemIndex) Rebuild(load func() ([]*entity.Item, error)) (int, error) {
+	x.rebuildMu.Lock()
+	defer x.rebuildMu.Unlock()
+
+	x.mu.Lock()
+	x.pending = make(map[int64]*entity.Item)
+	x.mu.Unlock()
+
+	// 読み込みの間も検索と書き込みの反映は続ける
+	loaded, err := load()
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	pending := x.pending
+	x.pending = nil
+	if err != nil {
+		return 0, err
+	}
+
+	items := make(map[int64]*entity.Item, len(loaded))
+	for _, item := range loaded {
+		if item.DeletedAt == nil {
+			items[item.ID] = copyItem(item)
+		}
+	}
+	for id, item := range pending {
+		if item == nil {
+			delete(items, id)
+		} else if current, ok := items[id]; !ok || current.Version <= item.Version {
+			items[id] = item
+		}
+	}
+	x.items = items
+	return len(items), nil
+}
+
+func (x *ItemIndex) Put(item *entity.Item) {
+	if item.DeletedAt != nil {
+		x.Remove(item.ID)
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if current, ok := x.items[item.ID]; ok && current.Version > item.Version {
+		return
+	}
+	// 検索結果として返したアイテムを書き換えないよう、差し替えは常に新しい複製で行う
+	c := copyItem(item)
+	x.items[item.ID] = c
+	if x.pending != nil {
+		x.pending[item.ID] = c
+	}
+}
+
+func (x *ItemIndex) Remove(id int64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	delete(x.items, id)
+	if x.pending != nil {
+		x.pending[id] = nil
+	}
+}
+
+func (x *ItemIndex) Find(criteria usecase.FacetCriteria) []*entity.Item {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	items := make([]*entity.Item, 0, len(x.items))
+	for _, item := range x.items {
+		if !matches(item, criteria.ItemCriteria) {
+			continue
+		}
+		if len(criteria.Terms) > 0 {
+			if _, ok := entity.MatchSearch(item, criteria.Terms); !ok {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	sortItems(items, criteria.Sort, criteria.Order)
+	return items
+}
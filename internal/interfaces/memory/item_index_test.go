@@ -0,0 +1,94 @@
+package memory_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/usecase"
+)
+
+func TestItemIndex(t *testing.T) {
+	newItem := func(id int64, name, category string, version int64) *entity.Item {
+		return &entity.Item{
+			ID: id, Name: name, Category: category, Brand: "ROLEX", BrandCanonical: "ROLEX",
+			PurchasePrice: 1000000, PurchaseDate: "2023-01-15", Version: version,
+			CreatedAt: time.Date(2023, 1, 1, 0, 0, int(id), 0, time.UTC),
+		}
+	}
+	find := func(t *testing.T, index *memory.ItemIndex, criteria usecase.FacetCriteria) []string {
+		t.Helper()
+		criteria, err := criteria.Normalize()
+		require.NoError(t, err)
+		names := []string{}
+		for _, item := range index.Find(criteria) {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	t.Run("正常系: 一覧と同じ条件と検索語で絞り込み、並び替える", func(t *testing.T) {
+		index := memory.NewItemIndex()
+		count, err := index.Rebuild(func() ([]*entity.Item, error) {
+			return []*entity.Item{
+				newItem(1, "ロレックス デイトナ", "時計", 1),
+				newItem(2, "ロレックス サブマリーナ", "時計", 1),
+				newItem(3, "ロレックス ポーチ", "バッグ", 1),
+			}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		assert.Equal(t, []string{"ロレックス ポーチ", "ロレックス サブマリーナ", "ロレックス デイトナ"}, find(t, index, usecase.FacetCriteria{}))
+		assert.Equal(t, []string{"ロレックス デイトナ", "ロレックス サブマリーナ"}, find(t, index, usecase.FacetCriteria{
+			ItemCriteria: usecase.ItemCriteria{Category: "時計", Sort: usecase.SortCreatedAt, Order: usecase.OrderAsc},
+		}))
+		assert.Equal(t, []string{"ロレックス サブマリーナ"}, find(t, index, usecase.FacetCriteria{Query: "さぶまりーな"}))
+	})
+
+	t.Run("正常系: 古いバージョンやゴミ箱のアイテムは反映しない", func(t *testing.T) {
+		index := memory.NewItemIndex()
+		index.Put(newItem(1, "新しい名前", "時計", 2))
+		index.Put(newItem(1, "古い名前", "時計", 1))
+		trashed := newItem(2, "ゴミ箱", "時計", 1)
+		now := time.Now()
+		trashed.DeletedAt = &now
+		index.Put(trashed)
+
+		assert.Equal(t, []string{"新しい名前"}, find(t, index, usecase.FacetCriteria{}))
+
+		index.Remove(1)
+		assert.Empty(t, find(t, index, usecase.FacetCriteria{}))
+	})
+
+	t.Run("正常系: 作り直しの読み込み中の変更は読み込んだ内容より優先する", func(t *testing.T) {
+		index := memory.NewItemIndex()
+		_, err := index.Rebuild(func() ([]*entity.Item, error) {
+			// 読み込んだ後、作り直しが終わるまでに書き込みが確定した場合
+			index.Put(newItem(1, "更新後", "時計", 2))
+			index.Put(newItem(3, "追加", "時計", 1))
+			index.Remove(2)
+			return []*entity.Item{newItem(1, "更新前", "時計", 1), newItem(2, "削除", "時計", 1)}, nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"追加", "更新後"}, find(t, index, usecase.FacetCriteria{}))
+	})
+
+	t.Run("異常系: 読み込みに失敗した場合は以前の内容を保持する", func(t *testing.T) {
+		index := memory.NewItemIndex()
+		index.Put(newItem(1, "以前", "時計", 1))
+
+		_, err := index.Rebuild(func() ([]*entity.Item, error) {
+			return nil, errors.New("failed")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, []string{"以前"}, find(t, index, usecase.FacetCriteria{}))
+	})
+}
@@ -0,0 +1,532 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// ItemRepository は usecase.ItemRepository のメモリ上の実装。
+// テストやデモモードでの利用を想定しており、並行に呼び出しても安全
+type ItemRepository struct {
+	store *Store
+}
+
+func NewItemRepository(store *Store) *ItemRepository {
+	return &ItemRepository{store: store}
+}
+
+func (r *ItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := r.filter(func(item *entity.Item) bool { return item.DeletedAt == nil })
+	sortItems(items, usecase.SortCreatedAt, usecase.OrderDesc)
+	return items, nil
+}
+
+func (r *ItemRepository) FindByCriteria(ctx context.Context, criteria usecase.ItemCriteria) ([]*entity.Item, error) {
+	if _, ok := sortKeys[criteria.Sort]; !ok {
+		return nil, fmt.Errorf("%w: unsupported sort key %q", domainErrors.ErrInvalidInput, criteria.Sort)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := r.filter(func(item *entity.Item) bool { return matches(item, criteria) })
+	sortItems(items, criteria.Sort, criteria.Order)
+	return page(items, criteria.Offset, criteria.Limit), nil
+}
+
+func (r *ItemRepository) CountByCriteria(ctx context.Context, criteria usecase.ItemCriteria) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.filter(func(item *entity.Item) bool { return matches(item, criteria) })), nil
+}
+
+// ストアはすべてのアイテムをメモリ上に保持しているため、一致したアイテムの複製を並べてから fn を呼ぶ。
+// fn の中からリポジトリを呼べるよう、fn はロックを解放してから呼ぶ
+func (r *ItemRepository) ForEachByCriteria(ctx context.Context, criteria usecase.ItemCriteria, fn func(item *entity.Item) error) error {
+	if _, ok := sortKeys[criteria.Sort]; !ok {
+		return fmt.Errorf("%w: unsupported sort key %q", domainErrors.ErrInvalidInput, criteria.Sort)
+	}
+
+	r.store.mu.RLock()
+	items := r.filter(func(item *entity.Item) bool { return matches(item, criteria) })
+	r.store.mu.RUnlock()
+
+	sortItems(items, criteria.Sort, criteria.Order)
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ItemRepository) FindByCursor(ctx context.Context, criteria usecase.ItemCriteria, after *usecase.CursorKey) ([]*entity.Item, bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	asc := criteria.Order == usecase.OrderAsc
+	items := r.filter(func(item *entity.Item) bool {
+		if !matches(item, criteria) {
+			return false
+		}
+		if after == nil {
+			return true
+		}
+		// (created_at, id) が after より後（降順の場合は前）にあるものだけを対象にする
+		cmp := compareKey(item.CreatedAt, item.ID, after.CreatedAt, after.ID)
+		if asc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	sortItems(items, usecase.SortCreatedAt, criteria.Order)
+
+	hasMore := len(items) > criteria.Limit
+	return page(items, 0, criteria.Limit), hasMore, nil
+}
+
+// Search はゴミ箱に無いすべてのアイテムを entity.MatchSearch で調べ、関連度の高い順（同じ場合は id の大きい順）に返す
+func (r *ItemRepository) Search(ctx context.Context, criteria usecase.SearchCriteria) ([]*entity.Item, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	scores := make(map[int64]float64)
+	items := r.filter(func(item *entity.Item) bool {
+		if item.DeletedAt != nil {
+			return false
+		}
+		match, ok := entity.MatchSearch(item, criteria.Terms)
+		scores[item.ID] = match.Score
+		return ok
+	})
+	sort.Slice(items, func(i, j int) bool {
+		if scores[items[i].ID] != scores[items[j].ID] {
+			return scores[items[i].ID] > scores[items[j].ID]
+		}
+		return items[i].ID > items[j].ID
+	})
+	return page(items, criteria.Offset, criteria.Limit), len(items), nil
+}
+
+func (r *ItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	item, ok := r.store.items[id]
+	if !ok || item.DeletedAt != nil {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	return copyItem(item), nil
+}
+
+func (r *ItemRepository) Create(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.create(item), nil
+}
+
+// CreateMany は items を1回のロックでまとめて作成する
+func (r *ItemRepository) CreateMany(ctx context.Context, items []*entity.Item) ([]*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	created := make([]*entity.Item, len(items))
+	for i, item := range items {
+		created[i] = r.create(item)
+	}
+	return created, nil
+}
+
+// create は item を保存して複製を返す。
+// 呼び出し側で store.mu の書き込みロックを取得していること
+func (r *ItemRepository) create(item *entity.Item) *entity.Item {
+	now := r.store.timestamp()
+	r.store.nextItemID++
+	created := &entity.Item{
+		ID:             r.store.nextItemID,
+		Name:           item.Name,
+		Category:       item.Category,
+		Brand:          item.Brand,
+		BrandCanonical: item.BrandCanonical,
+		BrandID:        item.BrandID,
+		PurchasePrice:  item.PurchasePrice,
+		PurchaseDate:   item.PurchaseDate,
+		Attributes:     item.Attributes.Clone(),
+		Tags:           item.Tags.Clone(),
+		Notes:          item.Notes,
+		Version:        1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	r.store.items[created.ID] = created
+
+	return copyItem(created)
+}
+
+func (r *ItemRepository) Update(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, err := r.findForWrite(item.ID, item.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Name = item.Name
+	stored.Category = item.Category
+	stored.Brand = item.Brand
+	stored.BrandCanonical = item.BrandCanonical
+	stored.BrandID = item.BrandID
+	stored.PurchasePrice = item.PurchasePrice
+	stored.PurchaseDate = item.PurchaseDate
+	stored.Attributes = item.Attributes.Clone()
+	stored.Notes = item.Notes
+	r.touch(stored)
+
+	return copyItem(stored), nil
+}
+
+func (r *ItemRepository) UpdatePartially(ctx context.Context, id int64, item *entity.Item, fields []entity.ItemField) (*entity.Item, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no updatable fields provided", domainErrors.ErrInvalidInput)
+	}
+	for _, field := range fields {
+		switch field {
+		case entity.ItemFieldName, entity.ItemFieldCategory, entity.ItemFieldBrand,
+			entity.ItemFieldPurchasePrice, entity.ItemFieldPurchaseDate, entity.ItemFieldAttributes, entity.ItemFieldTags, entity.ItemFieldNotes:
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", domainErrors.ErrInvalidInput, field)
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, err := r.findForWrite(id, item.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		switch field {
+		case entity.ItemFieldName:
+			stored.Name = item.Name
+		case entity.ItemFieldCategory:
+			stored.Category = item.Category
+		case entity.ItemFieldBrand:
+			stored.Brand = item.Brand
+			stored.BrandCanonical = item.BrandCanonical
+			stored.BrandID = item.BrandID
+		case entity.ItemFieldPurchasePrice:
+			stored.PurchasePrice = item.PurchasePrice
+		case entity.ItemFieldPurchaseDate:
+			stored.PurchaseDate = item.PurchaseDate
+		case entity.ItemFieldAttributes:
+			stored.Attributes = item.Attributes.Clone()
+		case entity.ItemFieldTags:
+			stored.Tags = item.Tags.Clone()
+		case entity.ItemFieldNotes:
+			stored.Notes = item.Notes
+		}
+	}
+	r.touch(stored)
+
+	return copyItem(stored), nil
+}
+
+func (r *ItemRepository) Delete(ctx context.Context, id int64, version int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, err := r.findForWrite(id, version)
+	if err != nil {
+		return err
+	}
+
+	r.touch(stored)
+	deletedAt := stored.UpdatedAt
+	stored.DeletedAt = &deletedAt
+
+	return nil
+}
+
+func (r *ItemRepository) Restore(ctx context.Context, id int64) (*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.items[id]
+	if !ok || stored.DeletedAt == nil {
+		return nil, domainErrors.ErrItemNotFound
+	}
+
+	stored.DeletedAt = nil
+	r.touch(stored)
+
+	return copyItem(stored), nil
+}
+
+func (r *ItemRepository) FindByCategory(ctx context.Context, category string) ([]*entity.Item, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := r.filter(func(item *entity.Item) bool { return item.Category == category })
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+func (r *ItemRepository) Recategorize(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// ゴミ箱にあるアイテムも対象にするため findForWrite は使わない
+	stored, ok := r.store.items[item.ID]
+	if !ok {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	if stored.Version != item.Version {
+		return nil, domainErrors.ErrVersionMismatch
+	}
+
+	stored.Category = item.Category
+	stored.Attributes = item.Attributes.Clone()
+	r.touch(stored)
+
+	return copyItem(stored), nil
+}
+
+func (r *ItemRepository) FindByBrand(ctx context.Context, brandID int64) ([]*entity.Item, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := r.filter(func(item *entity.Item) bool { return item.BrandID != nil && *item.BrandID == brandID })
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+func (r *ItemRepository) Rebrand(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// ゴミ箱にあるアイテムも対象にするため findForWrite は使わない
+	stored, ok := r.store.items[item.ID]
+	if !ok {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	if stored.Version != item.Version {
+		return nil, domainErrors.ErrVersionMismatch
+	}
+
+	stored.BrandCanonical = item.BrandCanonical
+	r.touch(stored)
+
+	return copyItem(stored), nil
+}
+
+func (r *ItemRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, item := range r.store.items {
+		if item.DeletedAt != nil && item.DeletedAt.Before(before) {
+			delete(r.store.items, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *ItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	summary := make(map[string]int)
+	for _, item := range r.store.items {
+		if item.DeletedAt == nil {
+			summary[item.Category]++
+		}
+	}
+	return summary, nil
+}
+
+func (r *ItemRepository) GetTagCounts(ctx context.Context) ([]entity.TagCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byTag := make(map[string]int)
+	for _, item := range r.store.items {
+		if item.DeletedAt != nil {
+			continue
+		}
+		for _, tag := range item.Tags {
+			byTag[tag]++
+		}
+	}
+
+	counts := make([]entity.TagCount, 0, len(byTag))
+	for name, count := range byTag {
+		counts = append(counts, entity.TagCount{Name: name, Count: count})
+	}
+	// SQL の実装と同じく件数の多い順、同じ件数の場合は名前順
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts, nil
+}
+
+// 条件付き更新の対象を返す。存在しなければ ErrItemNotFound、バージョンが異なれば ErrVersionMismatch。
+// 呼び出し側で store.mu の書き込みロックを取得していること
+func (r *ItemRepository) findForWrite(id int64, version int64) (*entity.Item, error) {
+	stored, ok := r.store.items[id]
+	if !ok || stored.DeletedAt != nil {
+		return nil, domainErrors.ErrItemNotFound
+	}
+	if stored.Version != version {
+		return nil, domainErrors.ErrVersionMismatch
+	}
+	return stored, nil
+}
+
+// 更新のたびにバージョンと更新日時を進める
+func (r *ItemRepository) touch(item *entity.Item) {
+	item.Version++
+	item.UpdatedAt = r.store.timestamp()
+}
+
+// keep を満たすアイテムの複製を返す。呼び出し側で store.mu のロックを取得していること
+func (r *ItemRepository) filter(keep func(item *entity.Item) bool) []*entity.Item {
+	items := make([]*entity.Item, 0, len(r.store.items))
+	for _, item := range r.store.items {
+		if keep(item) {
+			items = append(items, copyItem(item))
+		}
+	}
+	return items
+}
+
+func matches(item *entity.Item, criteria usecase.ItemCriteria) bool {
+	if (item.DeletedAt != nil) != criteria.Trashed {
+		return false
+	}
+	// MySQL の照合順序（utf8mb4_unicode_ci）に合わせて大文字小文字を区別しない
+	if len(criteria.Categories) > 0 {
+		if !slices.ContainsFunc(criteria.Categories, func(category string) bool {
+			return strings.EqualFold(item.Category, category)
+		}) {
+			return false
+		}
+	} else if criteria.Category != "" && !strings.EqualFold(item.Category, criteria.Category) {
+		return false
+	}
+	if criteria.Brand != "" && item.BrandCanonical != criteria.BrandCanonical {
+		return false
+	}
+	if criteria.PurchaseDateFrom != "" && item.PurchaseDate < criteria.PurchaseDateFrom {
+		return false
+	}
+	if criteria.PurchaseDateTo != "" && item.PurchaseDate > criteria.PurchaseDateTo {
+		return false
+	}
+	if criteria.MinPrice != nil && item.PurchasePrice < *criteria.MinPrice {
+		return false
+	}
+	if criteria.MaxPrice != nil && item.PurchasePrice > *criteria.MaxPrice {
+		return false
+	}
+	// SQL の実装の item_attributes と同じく、AttributeValueString の文字列で比較する
+	for key, value := range criteria.Attributes {
+		v, ok := item.Attributes[key]
+		if !ok || entity.AttributeValueString(v) != value {
+			return false
+		}
+	}
+	if len(criteria.Tags) > 0 {
+		matched := 0
+		for _, tag := range criteria.Tags {
+			if slices.Contains(item.Tags, tag) {
+				matched++
+			}
+		}
+		if matched == 0 || criteria.TagMatch != usecase.TagMatchAny && matched < len(criteria.Tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// 並び替えキーごとの比較関数（負: a が前、正: b が前）
+var sortKeys = map[string]func(a, b *entity.Item) int{
+	usecase.SortCreatedAt:     func(a, b *entity.Item) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	usecase.SortPurchaseDate:  func(a, b *entity.Item) int { return strings.Compare(a.PurchaseDate, b.PurchaseDate) },
+	usecase.SortPurchasePrice: func(a, b *entity.Item) int { return a.PurchasePrice - b.PurchasePrice },
+	usecase.SortName:          func(a, b *entity.Item) int { return strings.Compare(a.Name, b.Name) },
+	usecase.SortDeletedAt: func(a, b *entity.Item) int {
+		return deletedAtOf(a).Compare(deletedAtOf(b))
+	},
+}
+
+func deletedAtOf(item *entity.Item) time.Time {
+	if item.DeletedAt == nil {
+		return time.Time{}
+	}
+	return *item.DeletedAt
+}
+
+// SQL の実装と同じく、同じ値の場合は id で順序を決める
+func sortItems(items []*entity.Item, key, order string) {
+	compare := sortKeys[key]
+	sort.Slice(items, func(i, j int) bool {
+		c := compare(items[i], items[j])
+		if c == 0 {
+			c = compareKey(time.Time{}, items[i].ID, time.Time{}, items[j].ID)
+		}
+		if order == usecase.OrderAsc {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+func compareKey(aTime time.Time, aID int64, bTime time.Time, bID int64) int {
+	if c := aTime.Compare(bTime); c != 0 {
+		return c
+	}
+	switch {
+	case aID < bID:
+		return -1
+	case aID > bID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func page(items []*entity.Item, offset, limit int) []*entity.Item {
+	if offset >= len(items) {
+		return []*entity.Item{}
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
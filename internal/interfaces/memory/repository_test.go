@@ -0,0 +1,41 @@
+package memory_test
+
+import (
+	"testing"
+
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/usecase"
+	"Aicon-assignment/internal/usecase/repositorytest"
+)
+
+func TestItemRepository(t *testing.T) {
+	repositorytest.RunItemRepositoryTests(t, func(t *testing.T) usecase.ItemRepository {
+		return memory.NewItemRepository(memory.NewStore())
+	})
+}
+
+func TestItemEventRepository(t *testing.T) {
+	repositorytest.RunItemEventRepositoryTests(t, func(t *testing.T) usecase.ItemEventRepository {
+		return memory.NewItemEventRepository(memory.NewStore())
+	})
+}
+
+func TestBrandRepository(t *testing.T) {
+	repositorytest.RunBrandRepositoryTests(t, func(t *testing.T) repositorytest.BrandRepositories {
+		store := memory.NewStore()
+		return repositorytest.BrandRepositories{
+			Brands: memory.NewBrandRepository(store),
+			Items:  memory.NewItemRepository(store),
+		}
+	})
+}
+
+func TestCategoryRepository(t *testing.T) {
+	repositorytest.RunCategoryRepositoryTests(t, func(t *testing.T) repositorytest.CategoryRepositories {
+		store := memory.NewStore()
+		return repositorytest.CategoryRepositories{
+			Categories: memory.NewCategoryRepository(store),
+			Items:      memory.NewItemRepository(store),
+		}
+	})
+}
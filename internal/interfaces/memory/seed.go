@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// デモモード用のサンプルデータ（migration の 0003_brands の初期カタログと seed と同じ内容）
+var sampleBrands = []struct {
+	Name    string
+	Country string
+	Aliases []string
+}{
+	{Name: "ROLEX", Country: "CH", Aliases: []string{"ロレックス"}},
+	{Name: "OMEGA", Country: "CH", Aliases: []string{"オメガ"}},
+	{Name: "HERMÈS", Country: "FR", Aliases: []string{"エルメス"}},
+	{Name: "LOUIS VUITTON", Country: "FR", Aliases: []string{"ルイ・ヴィトン", "ルイヴィトン", "LV"}},
+	{Name: "CHANEL", Country: "FR", Aliases: []string{"シャネル"}},
+	{Name: "Cartier", Country: "FR", Aliases: []string{"カルティエ"}},
+	{Name: "Christian Louboutin", Country: "FR", Aliases: []string{"クリスチャン・ルブタン", "クリスチャンルブタン", "ルブタン", "Louboutin"}},
+	{Name: "GUCCI", Country: "IT", Aliases: []string{"グッチ"}},
+	{Name: "PRADA", Country: "IT", Aliases: []string{"プラダ"}},
+	{Name: "Tiffany & Co.", Country: "US", Aliases: []string{"ティファニー", "Tiffany"}},
+	{Name: "Apple", Country: "US", Aliases: []string{"アップル"}},
+}
+
+var sampleItems = []entity.Item{
+	{Name: "ロレックス デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"},
+	{Name: "エルメス バーキン", Category: "バッグ", Brand: "HERMÈS", PurchasePrice: 2000000, PurchaseDate: "2023-02-20"},
+	{Name: "ティファニー ネックレス", Category: "ジュエリー", Brand: "Tiffany & Co.", PurchasePrice: 300000, PurchaseDate: "2023-03-10"},
+	{Name: "ルブタン パンプス", Category: "靴", Brand: "Christian Louboutin", PurchasePrice: 150000, PurchaseDate: "2023-04-05"},
+	{Name: "アップルウォッチ", Category: "その他", Brand: "Apple", PurchasePrice: 50000, PurchaseDate: "2023-05-12"},
+}
+
+// SeedSampleBrands はブランドカタログの初期データを repo に登録する
+func SeedSampleBrands(ctx context.Context, repo usecase.BrandRepository) error {
+	for _, sample := range sampleBrands {
+		brand, err := entity.NewBrand(sample.Name, sample.Country, sample.Aliases)
+		if err != nil {
+			return err
+		}
+		if _, err := repo.Create(ctx, brand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeedSampleItems はサンプルデータを repo に登録する。ブランドは brands のカタログにあれば紐づける
+func SeedSampleItems(ctx context.Context, repo usecase.ItemRepository, brands usecase.BrandRepository) error {
+	for _, item := range sampleItems {
+		item := item
+		item.Normalize()
+
+		brand, err := brands.FindByKey(ctx, item.BrandCanonical)
+		switch {
+		case err == nil:
+			item.BrandID = &brand.ID
+			item.BrandCanonical = brand.NameCanonical
+		case !errors.Is(err, domainErrors.ErrBrandNotFound):
+			return err
+		}
+
+		if _, err := repo.Create(ctx, &item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// Store はメモリ上に保持するデータ。同じ Store を使うリポジトリ同士でデータを共有し、
+// usecase.Transactor としても使用できる
+type Store struct {
+	mu             sync.RWMutex
+	items          map[int64]*entity.Item
+	events         []*entity.ItemEvent
+	nextItemID     int64
+	nextEventID    int64
+	brands         map[int64]*entity.Brand
+	nextBrandID    int64
+	categories     map[int64]*entity.Category
+	nextCategoryID int64
+
+	// トランザクションを1つずつ実行するためのロック
+	txMu sync.Mutex
+
+	// 現在時刻（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 既定のカテゴリー（マイグレーション 0004_categories と 0006_item_attributes の初期データと同じ）
+var defaultCategories = []entity.Category{
+	{Name: "時計", DisplayNameJa: "時計", DisplayNameEn: "Watches", SortOrder: 10, Active: true, Attributes: []entity.AttributeDefinition{
+		{Key: "reference_number", Type: entity.AttributeTypeString},
+		{Key: "movement", Type: entity.AttributeTypeEnum, Options: []string{"自動巻き", "手巻き", "クォーツ"}},
+		{Key: "case_size", Type: entity.AttributeTypeNumber, Unit: "mm"},
+	}},
+	{Name: "バッグ", DisplayNameJa: "バッグ", DisplayNameEn: "Bags", SortOrder: 20, Active: true},
+	{Name: "ジュエリー", DisplayNameJa: "ジュエリー", DisplayNameEn: "Jewelry", SortOrder: 30, Active: true, Attributes: []entity.AttributeDefinition{
+		{Key: "metal", Type: entity.AttributeTypeEnum, Options: []string{"プラチナ", "ゴールド", "シルバー", "その他"}},
+		{Key: "carat", Type: entity.AttributeTypeNumber, Unit: "ct"},
+		{Key: "stone", Type: entity.AttributeTypeString},
+	}},
+	{Name: "靴", DisplayNameJa: "靴", DisplayNameEn: "Shoes", SortOrder: 40, Active: true, Attributes: []entity.AttributeDefinition{
+		{Key: "size", Type: entity.AttributeTypeNumber, Unit: "cm"},
+	}},
+	{Name: "その他", DisplayNameJa: "その他", DisplayNameEn: "Others", SortOrder: 50, Active: true},
+}
+
+// NewStore は空の Store を返す。カテゴリーはデータベースのマイグレーションと同じく既定のものを持つ
+func NewStore() *Store {
+	s := &Store{
+		items:      make(map[int64]*entity.Item),
+		brands:     make(map[int64]*entity.Brand),
+		categories: make(map[int64]*entity.Category),
+		now:        time.Now,
+	}
+
+	now := s.timestamp()
+	for _, category := range defaultCategories {
+		s.nextCategoryID++
+		c := *copyCategory(&category)
+		c.ID = s.nextCategoryID
+		c.CreatedAt = now
+		c.UpdatedAt = now
+		s.categories[c.ID] = &c
+	}
+	return s
+}
+
+// 現在時刻。database/sql のドライバに合わせてモノトニック時計の値は持たせない
+func (s *Store) timestamp() time.Time {
+	return s.now().Round(0).Truncate(time.Microsecond)
+}
+
+// トランザクション内であることを ctx に保持するためのキー
+type txKey struct{}
+
+// snapshot はロールバック用に保存するデータの複製
+type snapshot struct {
+	items          map[int64]*entity.Item
+	events         []*entity.ItemEvent
+	nextItemID     int64
+	nextEventID    int64
+	brands         map[int64]*entity.Brand
+	nextBrandID    int64
+	categories     map[int64]*entity.Category
+	nextCategoryID int64
+}
+
+func (s *Store) snapshot() snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make(map[int64]*entity.Item, len(s.items))
+	for id, item := range s.items {
+		items[id] = copyItem(item)
+	}
+	brands := make(map[int64]*entity.Brand, len(s.brands))
+	for id, brand := range s.brands {
+		brands[id] = copyBrand(brand)
+	}
+	categories := make(map[int64]*entity.Category, len(s.categories))
+	for id, category := range s.categories {
+		categories[id] = copyCategory(category)
+	}
+	return snapshot{
+		items:          items,
+		events:         append([]*entity.ItemEvent(nil), s.events...),
+		nextItemID:     s.nextItemID,
+		nextEventID:    s.nextEventID,
+		brands:         brands,
+		nextBrandID:    s.nextBrandID,
+		categories:     categories,
+		nextCategoryID: s.nextCategoryID,
+	}
+}
+
+func (s *Store) restore(snap snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = snap.items
+	s.events = snap.events
+	s.nextItemID = snap.nextItemID
+	s.nextEventID = snap.nextEventID
+	s.brands = snap.brands
+	s.nextBrandID = snap.nextBrandID
+	s.categories = snap.categories
+	s.nextCategoryID = snap.nextCategoryID
+}
+
+// WithTx は fn を1つの作業単位として実行し、エラーまたはパニックの場合は fn の変更をすべて取り消す。
+// トランザクション同士は直列に実行される。トランザクション外からの読み取りは確定前の変更も参照する
+func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error, _ ...usecase.TxOptions) (err error) {
+	// 入れ子の場合は外側のロックを保持したまま、セーブポイントとして扱う
+	if ctx.Value(txKey{}) == nil {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, true)
+	}
+
+	snap := s.snapshot()
+	defer func() {
+		if p := recover(); p != nil {
+			s.restore(snap)
+			panic(p)
+		}
+		if err != nil {
+			s.restore(snap)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+func copyItem(item *entity.Item) *entity.Item {
+	c := *item
+	if item.DeletedAt != nil {
+		deletedAt := *item.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	if item.BrandID != nil {
+		brandID := *item.BrandID
+		c.BrandID = &brandID
+	}
+	c.Attributes = item.Attributes.Clone()
+	c.Tags = item.Tags.Clone()
+	return &c
+}
+
+// copyCategory は属性の定義（選択肢を含む）も複製する。属性が無い場合も JSON で [] になるよう空のスライスにする
+func copyCategory(category *entity.Category) *entity.Category {
+	c := *category
+	c.Attributes = make([]entity.AttributeDefinition, len(category.Attributes))
+	for i, definition := range category.Attributes {
+		definition.Options = slices.Clone(definition.Options)
+		c.Attributes[i] = definition
+	}
+	return &c
+}
+
+func copyBrand(brand *entity.Brand) *entity.Brand {
+	c := *brand
+	c.Aliases = append([]string{}, brand.Aliases...)
+	return &c
+}
@@ -0,0 +1,109 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/memory"
+	"Aicon-assignment/internal/usecase"
+)
+
+func TestStore_WithTx(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	t.Run("正常系: 成功時は変更が残る", func(t *testing.T) {
+		store := memory.NewStore()
+		repo := memory.NewItemRepository(store)
+
+		var id int64
+		err := store.WithTx(ctx, func(ctx context.Context) error {
+			id = createItem(t, ctx, repo, "残る").ID
+			return nil
+		})
+
+		require.NoError(t, err)
+		_, err = repo.FindByID(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("異常系: エラー時は作成と監査記録が取り消される", func(t *testing.T) {
+		store := memory.NewStore()
+		repo := memory.NewItemRepository(store)
+		events := memory.NewItemEventRepository(store)
+
+		var id int64
+		err := store.WithTx(ctx, func(ctx context.Context) error {
+			id = createItem(t, ctx, repo, "取り消される").ID
+			require.NoError(t, events.Append(ctx, &entity.ItemEvent{ItemID: id, Action: entity.ItemEventCreate}))
+			return errFailed
+		})
+
+		assert.ErrorIs(t, err, errFailed)
+		_, err = repo.FindByID(ctx, id)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+		count, err := events.Count(ctx, usecase.AuditCriteria{})
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("異常系: パニック時は変更を取り消してパニックを伝える", func(t *testing.T) {
+		store := memory.NewStore()
+		repo := memory.NewItemRepository(store)
+
+		var id int64
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = store.WithTx(ctx, func(ctx context.Context) error {
+				id = createItem(t, ctx, repo, "パニック").ID
+				panic("boom")
+			})
+		})
+
+		_, err := repo.FindByID(ctx, id)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+
+		// ロックが解放されていること
+		assert.NoError(t, store.WithTx(ctx, func(ctx context.Context) error { return nil }))
+	})
+
+	t.Run("正常系: 入れ子のロールバックは外側の変更を残す", func(t *testing.T) {
+		store := memory.NewStore()
+		repo := memory.NewItemRepository(store)
+
+		var outerID, innerID int64
+		err := store.WithTx(ctx, func(ctx context.Context) error {
+			outerID = createItem(t, ctx, repo, "外側").ID
+			innerErr := store.WithTx(ctx, func(ctx context.Context) error {
+				innerID = createItem(t, ctx, repo, "内側").ID
+				return errFailed
+			})
+			assert.ErrorIs(t, innerErr, errFailed)
+			return nil
+		})
+
+		require.NoError(t, err)
+		_, err = repo.FindByID(ctx, outerID)
+		assert.NoError(t, err)
+		_, err = repo.FindByID(ctx, innerID)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func createItem(t *testing.T, ctx context.Context, repo *memory.ItemRepository, name string) *entity.Item {
+	t.Helper()
+	item, err := repo.Create(ctx, &entity.Item{
+		Name:          name,
+		Category:      "その他",
+		Brand:         "Brand",
+		PurchasePrice: 1000,
+		PurchaseDate:  "2023-01-01",
+	})
+	require.NoError(t, err)
+	return item
+}
@@ -0,0 +1,189 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/i18n"
+)
+
+// MIMEApplicationProblemJSON は RFC 9457 の問題詳細の Content-Type
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// TypeBase は問題の種類を表す type URI の接頭辞。type は TypeBase + コード（"_" は "-"）になる
+const TypeBase = "/problems/"
+
+// Details は RFC 9457 の問題詳細。
+// Title と Detail は Accept-Language に応じた言語になり、Code は言語によらず同じ値になる
+type Details struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	RequestID string              `json:"request_id,omitempty"`
+	Code      string              `json:"code"`
+	Errors    []entity.FieldError `json:"errors,omitempty"`
+}
+
+// Error はドメインエラーに対応しない、HTTP 固有のエラー（不正なパスパラメータ、If-Match の不足など）。
+// ハンドラはこれをそのまま返し、HTTPErrorHandler がレスポンスに変換する
+type Error struct {
+	Status int
+	Code   i18n.Code
+	// Err があれば、そのメッセージを detail として返す
+	Err error
+}
+
+// New は status と code のエラーを返す
+func New(status int, code i18n.Code) *Error {
+	return &Error{Status: status, Code: code}
+}
+
+// Wrap は err のメッセージを detail に含めた status と code のエラーを返す
+func Wrap(status int, code i18n.Code, err error) *Error {
+	return &Error{Status: status, Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// InvalidQuery はクエリパラメータの解析のエラーを 400 invalid_query_parameter にする。
+// usecase と同じ検証で見つかった値の誤り（ErrInvalidInput）は、他の範囲外の値と同じレスポンスになるようそのまま返す
+func InvalidQuery(err error) error {
+	if errors.Is(err, domainErrors.ErrInvalidInput) {
+		return err
+	}
+	return Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, err)
+}
+
+// HTTPErrorHandler はハンドラが返したエラーを application/problem+json のレスポンスに変換する。
+// echo.Echo の HTTPErrorHandler に設定して使う
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	p := From(err, i18n.FromContext(c))
+	p.Instance = c.Request().URL.Path
+	p.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	if p.Status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(p.Status, p)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// From は err を lang の問題詳細に変換する（Instance と RequestID は設定しない）
+func From(err error, lang i18n.Language) Details {
+	var (
+		problemErr     *Error
+		validationErrs entity.ValidationErrors
+		httpErr        *echo.HTTPError
+	)
+
+	switch {
+	case errors.As(err, &problemErr):
+		p := newDetails(lang, problemErr.Status, problemErr.Code)
+		if problemErr.Err != nil {
+			p.Detail = problemErr.Err.Error()
+		}
+		return p
+	case errors.As(err, &validationErrs):
+		p := newDetails(lang, http.StatusBadRequest, i18n.ValidationFailed)
+		p.Errors = i18n.LocalizeFieldErrors(lang, validationErrs)
+		messages := make([]string, len(p.Errors))
+		for i, fe := range p.Errors {
+			messages[i] = fe.Message
+		}
+		p.Detail = i18n.JoinList(lang, messages)
+		return p
+	case errors.Is(err, domainErrors.ErrItemNotFound):
+		return newDetails(lang, http.StatusNotFound, i18n.ItemNotFound)
+	case errors.Is(err, domainErrors.ErrBrandNotFound):
+		return newDetails(lang, http.StatusNotFound, i18n.BrandNotFound)
+	case errors.Is(err, domainErrors.ErrCategoryNotFound):
+		return newDetails(lang, http.StatusNotFound, i18n.CategoryNotFound)
+	case errors.Is(err, domainErrors.ErrVersionMismatch):
+		return newDetails(lang, http.StatusPreconditionFailed, i18n.PreconditionFailed)
+	case errors.Is(err, domainErrors.ErrInvalidInput):
+		return withReason(newDetails(lang, http.StatusBadRequest, i18n.InvalidInput), lang, err)
+	case errors.Is(err, domainErrors.ErrInUse):
+		return withReason(newDetails(lang, http.StatusConflict, i18n.InUse), lang, err)
+	case errors.Is(err, domainErrors.ErrDuplicateEntry):
+		return newDetails(lang, http.StatusConflict, i18n.DuplicateEntry)
+	case errors.Is(err, domainErrors.ErrDatabaseError):
+		// データベースのエラーの内容はクライアントに返さない
+		return newDetails(lang, http.StatusInternalServerError, i18n.DatabaseError)
+	case errors.As(err, &httpErr):
+		return fromHTTPError(lang, httpErr)
+	default:
+		return newDetails(lang, http.StatusInternalServerError, i18n.InternalError)
+	}
+}
+
+func newDetails(lang i18n.Language, status int, code i18n.Code) Details {
+	return Details{
+		Type:   TypeBase + strings.ReplaceAll(string(code), "_", "-"),
+		Title:  i18n.Message(lang, code),
+		Status: status,
+		Code:   string(code),
+	}
+}
+
+// withReason は err が理由として包んでいる entity.FieldError を lang に訳して detail にする。
+// 理由を持たないエラーのメッセージは英語のままで内部の文脈も含むため、detail には使わない
+func withReason(p Details, lang i18n.Language, err error) Details {
+	var reason entity.FieldError
+	if errors.As(err, &reason) {
+		p.Detail = i18n.FieldMessage(lang, reason)
+	}
+	return p
+}
+
+// echo が返す HTTP エラー（存在しないルートなど）を変換する。
+// 対応するコードが無いものは、HTTP ステータス以上の意味を持たない about:blank として返す
+func fromHTTPError(lang i18n.Language, httpErr *echo.HTTPError) Details {
+	switch httpErr.Code {
+	case http.StatusNotFound:
+		return newDetails(lang, httpErr.Code, i18n.NotFound)
+	case http.StatusMethodNotAllowed:
+		return newDetails(lang, httpErr.Code, i18n.MethodNotAllowed)
+	}
+	if httpErr.Code >= http.StatusInternalServerError {
+		return newDetails(lang, httpErr.Code, i18n.InternalError)
+	}
+
+	p := Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(httpErr.Code),
+		Status: httpErr.Code,
+		Code:   strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_"),
+	}
+	if message, ok := httpErr.Message.(string); ok && message != p.Title {
+		p.Detail = message
+	}
+	return p
+}
@@ -0,0 +1,260 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/interfaces/i18n"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		lang     i18n.Language
+		expected Details
+	}{
+		{
+			name: "正常系: アイテムが見つからない",
+			err:  fmt.Errorf("%w: id=1", domainErrors.ErrItemNotFound),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/item-not-found",
+				Title:  "item not found",
+				Status: http.StatusNotFound,
+				Code:   "item_not_found",
+			},
+		},
+		{
+			name: "正常系: 不正な入力は理由を detail に含める",
+			err:  fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfBoundsError("limit", 1, 100)),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/invalid-input",
+				Title:  "invalid input",
+				Status: http.StatusBadRequest,
+				Detail: "limit must be between 1 and 100",
+				Code:   "invalid_input",
+			},
+		},
+		{
+			name: "正常系: 不正な入力の理由は Accept-Language の言語で返し、包んだ文脈は含めない",
+			err:  fmt.Errorf("line 3: %w", fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.TooManyError("rows", 10000))),
+			lang: i18n.Japanese,
+			expected: Details{
+				Type:   "/problems/invalid-input",
+				Title:  "入力内容が不正です",
+				Status: http.StatusBadRequest,
+				Detail: "rowsは10000個以内で指定してください",
+				Code:   "invalid_input",
+			},
+		},
+		{
+			name: "正常系: 理由を持たない不正な入力は detail を返さない",
+			err:  fmt.Errorf("%w: unsupported sort key %q", domainErrors.ErrInvalidInput, "color"),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/invalid-input",
+				Title:  "invalid input",
+				Status: http.StatusBadRequest,
+				Code:   "invalid_input",
+			},
+		},
+		{
+			name: "正常系: バリデーションエラーはフィールドごとの内容を返す",
+			err:  fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.ValidationErrors{entity.RequiredError("name")}),
+			lang: i18n.Japanese,
+			expected: Details{
+				Type:   "/problems/validation-failed",
+				Title:  "入力内容に誤りがあります",
+				Status: http.StatusBadRequest,
+				Detail: "名前は必須です",
+				Code:   "validation_failed",
+				Errors: []entity.FieldError{
+					{Field: "name", Code: entity.ValidationRequired, Message: "名前は必須です"},
+				},
+			},
+		},
+		{
+			name: "正常系: ブランドが見つからない",
+			err:  domainErrors.ErrBrandNotFound,
+			lang: i18n.Japanese,
+			expected: Details{
+				Type:   "/problems/brand-not-found",
+				Title:  "ブランドが見つかりません",
+				Status: http.StatusNotFound,
+				Code:   "brand_not_found",
+			},
+		},
+		{
+			name: "正常系: カテゴリーが見つからない",
+			err:  domainErrors.ErrCategoryNotFound,
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/category-not-found",
+				Title:  "category not found",
+				Status: http.StatusNotFound,
+				Code:   "category_not_found",
+			},
+		},
+		{
+			name: "正常系: 使用中は 409 で理由を detail に含める",
+			err:  fmt.Errorf("%w: %w", domainErrors.ErrInUse, entity.InUseError("items")),
+			lang: i18n.Japanese,
+			expected: Details{
+				Type:   "/problems/in-use",
+				Title:  "使用中のため削除できません",
+				Status: http.StatusConflict,
+				Detail: "アイテムが属しています",
+				Code:   "in_use",
+			},
+		},
+		{
+			name: "正常系: バージョンの不一致は 412",
+			err:  domainErrors.ErrVersionMismatch,
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/precondition-failed",
+				Title:  "If-Match does not match the current item version",
+				Status: http.StatusPreconditionFailed,
+				Code:   "precondition_failed",
+			},
+		},
+		{
+			name: "正常系: 重複は 409",
+			err:  fmt.Errorf("%w: name", domainErrors.ErrDuplicateEntry),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/duplicate-entry",
+				Title:  "duplicate entry",
+				Status: http.StatusConflict,
+				Code:   "duplicate_entry",
+			},
+		},
+		{
+			name: "正常系: データベースのエラーは内容を返さない",
+			err:  fmt.Errorf("%w: connection refused", domainErrors.ErrDatabaseError),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/database-error",
+				Title:  "database error",
+				Status: http.StatusInternalServerError,
+				Code:   "database_error",
+			},
+		},
+		{
+			name: "正常系: HTTP 固有のエラー",
+			err:  Wrap(http.StatusBadRequest, i18n.InvalidQueryParameter, errors.New("limit must be an integer")),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/invalid-query-parameter",
+				Title:  "invalid query parameter",
+				Status: http.StatusBadRequest,
+				Detail: "limit must be an integer",
+				Code:   "invalid_query_parameter",
+			},
+		},
+		{
+			name: "正常系: echo のルーティングのエラー",
+			err:  echo.ErrMethodNotAllowed,
+			lang: i18n.English,
+			expected: Details{
+				Type:   "/problems/method-not-allowed",
+				Title:  "method not allowed",
+				Status: http.StatusMethodNotAllowed,
+				Code:   "method_not_allowed",
+			},
+		},
+		{
+			name: "正常系: 対応するコードの無い HTTP エラーは about:blank",
+			err:  echo.NewHTTPError(http.StatusRequestEntityTooLarge, "body is too large"),
+			lang: i18n.English,
+			expected: Details{
+				Type:   "about:blank",
+				Title:  "Request Entity Too Large",
+				Status: http.StatusRequestEntityTooLarge,
+				Detail: "body is too large",
+				Code:   "request_entity_too_large",
+			},
+		},
+		{
+			name: "異常系: 想定外のエラーは 500",
+			err:  errors.New("unexpected"),
+			lang: i18n.Japanese,
+			expected: Details{
+				Type:   "/problems/internal-error",
+				Title:  "サーバー内部でエラーが発生しました",
+				Status: http.StatusInternalServerError,
+				Code:   "internal_error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, From(tt.err, tt.lang))
+		})
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Use(i18n.Middleware(i18n.English))
+	e.Match([]string{http.MethodGet, http.MethodHead}, "/items/:id", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+		return fmt.Errorf("%w: id=%s", domainErrors.ErrItemNotFound, c.Param("id"))
+	})
+
+	t.Run("正常系: problem+json で返す", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items/42?x=1", nil)
+		req.Header.Set("Accept-Language", "ja")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+		var res Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, Details{
+			Type:      "/problems/item-not-found",
+			Title:     "アイテムが見つかりません",
+			Status:    http.StatusNotFound,
+			Instance:  "/items/42",
+			RequestID: "req-1",
+			Code:      "item_not_found",
+		}, res)
+	})
+
+	t.Run("正常系: 存在しないルート", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		var res Details
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "not_found", res.Code)
+		assert.Equal(t, "/unknown", res.Instance)
+	})
+
+	t.Run("正常系: HEAD はボディを返さない", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodHead, "/items/42", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+}
@@ -0,0 +1,149 @@
+// Package xlsx はワークシートが1つの XLSX（Office Open XML のスプレッドシート）を書き出す。
+// 行は書き込むそばから出力するため、行数によらずメモリに保持しない
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	nsSpreadsheet   = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsRelationships = "http://schemas.openxmlformats.org/package/2006/relationships"
+	nsDocumentRels  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xmlHeader       = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	// MaxSheetNameLength はシート名の文字数の上限
+	MaxSheetNameLength = 31
+)
+
+// ContentType は XLSX の Content-Type
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Writer は行を1行ずつワークシートに書き込む。並行に呼び出してはならない
+type Writer struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+	err   error
+}
+
+// NewWriter は sheetName のワークシートを1つ持つ XLSX を w に書き出す Writer を返す。
+// 書き終えたら Close を呼ぶこと（Close は w を閉じない）
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	if sheetName == "" || len([]rune(sheetName)) > MaxSheetNameLength || strings.ContainsAny(sheetName, `:\/?*[]`) {
+		return nil, fmt.Errorf("xlsx: invalid sheet name %q", sheetName)
+	}
+
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="` + nsRelationships + `">` +
+			`<Relationship Id="rId1" Type="` + nsDocumentRels + `/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="` + nsSpreadsheet + `" xmlns:r="` + nsDocumentRels + `">` +
+			`<sheets><sheet name="` + escape(sheetName) + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="` + nsRelationships + `">` +
+			`<Relationship Id="rId1" Type="` + nsDocumentRels + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, xmlHeader+part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// ワークシートは zip の最後のエントリにして、行を書き込むそばから出力する
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(xmlHeader + `<worksheet xmlns="` + nsSpreadsheet + `"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow は values を1行として書き込む。値は次のように書き込む
+//   - string: 文字列（数値のような文字列も文字列のまま）
+//   - int, int64, float64: 数値
+//   - bool: 真偽値
+//   - time.Time: ISO 8601（RFC 3339）の文字列
+//   - nil: 空のセル
+func (w *Writer) WriteRow(values ...any) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.rows++
+	var b strings.Builder
+	b.WriteString(`<row r="` + strconv.Itoa(w.rows) + `">`)
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			b.WriteString(`<c/>`)
+		case string:
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escape(v) + `</t></is></c>`)
+		case int:
+			b.WriteString(`<c><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			b.WriteString(`<c><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			b.WriteString(`<c><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		case bool:
+			cell := "0"
+			if v {
+				cell = "1"
+			}
+			b.WriteString(`<c t="b"><v>` + cell + `</v></c>`)
+		case time.Time:
+			b.WriteString(`<c t="inlineStr"><is><t>` + v.Format(time.RFC3339) + `</t></is></c>`)
+		default:
+			w.err = fmt.Errorf("xlsx: unsupported cell value of type %T", value)
+			return w.err
+		}
+	}
+	b.WriteString(`</row>`)
+
+	_, w.err = w.sheet.WriteString(b.String())
+	return w.err
+}
+
+// Close はワークシートと XLSX の残りを書き出す
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if _, err := w.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// escape は s を XML のテキストや属性値として書けるようにする。XML で使えない文字は U+FFFD に置き換える
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/interfaces/xlsx"
+)
+
+// worksheet は書き出したワークシートの読み取り用
+type worksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("正常系: 値の型ごとにセルを書き出す", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := xlsx.NewWriter(&buf, "items")
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRow("id", "name", "note"))
+		require.NoError(t, w.WriteRow(int64(1), "ロレックス <デイトナ> & \"箱\"", "1行目\n2行目", 1500000, 40.5, true, nil, "00123",
+			time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC)))
+		require.NoError(t, w.Close())
+
+		files := readZip(t, buf.Bytes())
+		for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+			assert.Contains(t, files, name)
+		}
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="items"`)
+
+		var sheet worksheet
+		require.NoError(t, xml.Unmarshal([]byte(files["xl/worksheets/sheet1.xml"]), &sheet))
+		require.Len(t, sheet.Rows, 2)
+		assert.Equal(t, []int{1, 2}, []int{sheet.Rows[0].R, sheet.Rows[1].R})
+		cells := sheet.Rows[1].Cells
+		require.Len(t, cells, 9)
+		assert.Equal(t, "1", cells[0].V)
+		assert.Equal(t, "ロレックス <デイトナ> & \"箱\"", cells[1].Inline)
+		assert.Equal(t, "1行目\n2行目", cells[2].Inline)
+		assert.Equal(t, "1500000", cells[3].V)
+		assert.Equal(t, "40.5", cells[4].V)
+		assert.Equal(t, "b", cells[5].T)
+		assert.Equal(t, "1", cells[5].V)
+		assert.Equal(t, "", cells[6].V+cells[6].Inline)
+		assert.Equal(t, "inlineStr", cells[7].T, "数値のような文字列も文字列のまま")
+		assert.Equal(t, "2023-01-15T09:00:00Z", cells[8].Inline)
+	})
+
+	t.Run("正常系: 行が無くても開けるファイルになる", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := xlsx.NewWriter(&buf, "items")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		var sheet worksheet
+		require.NoError(t, xml.Unmarshal([]byte(readZip(t, buf.Bytes())["xl/worksheets/sheet1.xml"]), &sheet))
+		assert.Empty(t, sheet.Rows)
+	})
+
+	t.Run("異常系: 不正なシート名", func(t *testing.T) {
+		for _, name := range []string{"", "a/b", strings.Repeat("あ", xlsx.MaxSheetNameLength+1)} {
+			_, err := xlsx.NewWriter(io.Discard, name)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("異常系: 対応していない型の値", func(t *testing.T) {
+		w, err := xlsx.NewWriter(io.Discard, "items")
+		require.NoError(t, err)
+
+		assert.Error(t, w.WriteRow(struct{}{}))
+		assert.Error(t, w.Close(), "エラーの後は書き出さない")
+	})
+}
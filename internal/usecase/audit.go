@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 監査記録に残す操作者・リクエストIDを ctx に保持するためのキー
+type (
+	actorKey     struct{}
+	requestIDKey struct{}
+)
+
+// 操作者が特定できない場合の値
+const AnonymousActor = "anonymous"
+
+// WithActor は操作者を ctx に設定する
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithRequestID はリクエストIDを ctx に設定する
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func actorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+func requestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// AuditCriteria は監査記録の絞り込み・ページング条件
+type AuditCriteria struct {
+	ItemID *int64
+	Actor  string
+	Action string
+	From   *time.Time // この日時を含む
+	To     *time.Time // この日時を含まない
+	Limit  int
+	Offset int
+}
+
+// Normalize はデフォルト値を補完し、条件の妥当性を検証する
+func (c AuditCriteria) Normalize() (AuditCriteria, error) {
+	if c.Limit == 0 {
+		c.Limit = DefaultLimit
+	}
+	if err := ValidateLimit(c.Limit); err != nil {
+		return c, err
+	}
+	if c.Offset < 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("offset", 0))
+	}
+
+	switch c.Action {
+	case "", entity.ItemEventCreate, entity.ItemEventUpdate, entity.ItemEventDelete, entity.ItemEventRestore:
+	default:
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("action", []string{entity.ItemEventCreate, entity.ItemEventUpdate, entity.ItemEventDelete, entity.ItemEventRestore}))
+	}
+
+	if c.From != nil && c.To != nil && !c.From.Before(*c.To) {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidRangeError("from", "to"))
+	}
+
+	return c, nil
+}
+
+// ItemEventList は監査記録の一覧取得の結果
+type ItemEventList struct {
+	Events []*entity.ItemEvent `json:"events"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type AuditUsecase interface {
+	GetItemHistory(ctx context.Context, itemID int64, criteria AuditCriteria) (*ItemEventList, error)
+	ListEvents(ctx context.Context, criteria AuditCriteria) (*ItemEventList, error)
+}
+
+type auditUsecase struct {
+	eventRepo ItemEventRepository
+}
+
+func NewAuditUsecase(eventRepo ItemEventRepository) AuditUsecase {
+	return &auditUsecase{
+		eventRepo: eventRepo,
+	}
+}
+
+func (u *auditUsecase) GetItemHistory(ctx context.Context, itemID int64, criteria AuditCriteria) (*ItemEventList, error) {
+	if itemID <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	criteria.ItemID = &itemID
+	return u.ListEvents(ctx, criteria)
+}
+
+func (u *auditUsecase) ListEvents(ctx context.Context, criteria AuditCriteria) (*ItemEventList, error) {
+	criteria, err := criteria.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := u.eventRepo.Count(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	events, err := u.eventRepo.Find(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve events: %w", err)
+	}
+	if events == nil {
+		events = []*entity.ItemEvent{}
+	}
+
+	return &ItemEventList{
+		Events: events,
+		Total:  total,
+		Limit:  criteria.Limit,
+		Offset: criteria.Offset,
+	}, nil
+}
+
+// recordEvent は変更前後の差分から監査記録を作成して保存する。
+// 呼び出し側のトランザクション内で実行することで、変更と記録の原子性を保つ
+func (u *itemUsecase) recordEvent(ctx context.Context, action string, itemID int64, before, after *entity.Item) error {
+	return recordItemEvent(ctx, u.eventRepo, action, itemID, before, after)
+}
+
+// recordItemEvent は recordEvent の本体。eventRepo が nil の場合は何もしない
+func recordItemEvent(ctx context.Context, eventRepo ItemEventRepository, action string, itemID int64, before, after *entity.Item) error {
+	if eventRepo == nil {
+		return nil
+	}
+
+	event := &entity.ItemEvent{
+		ItemID:    itemID,
+		Action:    action,
+		Actor:     actorFrom(ctx),
+		RequestID: requestIDFrom(ctx),
+		Changes:   entity.DiffItems(before, after),
+		CreatedAt: time.Now(),
+	}
+	if err := eventRepo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", action, err)
+	}
+
+	return nil
+}
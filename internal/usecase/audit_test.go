@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// MockItemEventRepository はtestify/mockを使用した監査記録のモックリポジトリ
+type MockItemEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemEventRepository) Append(ctx context.Context, event *entity.ItemEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockItemEventRepository) Find(ctx context.Context, criteria AuditCriteria) ([]*entity.ItemEvent, error) {
+	args := m.Called(ctx, criteria)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ItemEvent), args.Error(1)
+}
+
+func (m *MockItemEventRepository) Count(ctx context.Context, criteria AuditCriteria) (int, error) {
+	args := m.Called(ctx, criteria)
+	return args.Int(0), args.Error(1)
+}
+
+// recordingTransactor はトランザクションの結果（コミット / ロールバック）を記録する
+type recordingTransactor struct {
+	committed  int
+	rolledBack int
+}
+
+func (tx *recordingTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error, _ ...TxOptions) error {
+	if err := fn(ctx); err != nil {
+		tx.rolledBack++
+		return err
+	}
+	tx.committed++
+	return nil
+}
+
+func auditContext() context.Context {
+	ctx := WithActor(context.Background(), "tanaka")
+	return WithRequestID(ctx, "req-123")
+}
+
+func TestItemUsecase_AuditTrail(t *testing.T) {
+	t.Run("正常系: 作成時に全フィールドを記録", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		tx := &recordingTransactor{}
+
+		created, _ := entity.NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		created.ID = 10
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(created, nil)
+		eventRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *entity.ItemEvent) bool {
+			return e.ItemID == 10 &&
+				e.Action == entity.ItemEventCreate &&
+				e.Actor == "tanaka" &&
+				e.RequestID == "req-123" &&
+				len(e.Changes) == 6 &&
+				e.Changes["brand"] == entity.FieldChange{Before: nil, After: "ROLEX"} &&
+				e.Changes["brand_canonical"] == entity.FieldChange{Before: nil, After: "ROLEX"}
+		})).Return(nil)
+
+		u := NewItemUsecase(mockRepo, WithAuditTrail(eventRepo), WithTransactor(tx))
+		_, err := u.CreateItem(auditContext(), CreateItemInput{
+			Name: "ロレックス デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		mockRepo.AssertExpectations(t)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 部分更新時は変更したフィールドのみ記録", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		tx := &recordingTransactor{}
+
+		existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+		existing.ID = 1
+		existing.Version = 1
+		updated := *existing
+		updated.PurchasePrice = 1200000
+		updated.Version = 2
+
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+		mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item"), mock.Anything).Return(&updated, nil)
+		eventRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *entity.ItemEvent) bool {
+			return e.Action == entity.ItemEventUpdate &&
+				len(e.Changes) == 1 &&
+				e.Changes["purchase_price"] == entity.FieldChange{Before: 1000000, After: 1200000}
+		})).Return(nil)
+
+		u := NewItemUsecase(mockRepo, WithAuditTrail(eventRepo), WithTransactor(tx))
+		_, err := u.UpdateItemPartially(auditContext(), 1, UpdateItemInput{PurchasePrice: Set(1200000)}, []int64{AnyVersion})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 削除時は削除前の値を記録し、操作者未指定なら anonymous", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		tx := &recordingTransactor{}
+
+		existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+		existing.ID = 1
+		existing.Version = 4
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+		mockRepo.On("Delete", mock.Anything, int64(1), int64(4)).Return(nil)
+		eventRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *entity.ItemEvent) bool {
+			return e.Action == entity.ItemEventDelete &&
+				e.Actor == AnonymousActor &&
+				e.Changes["name"] == entity.FieldChange{Before: "旧時計", After: nil}
+		})).Return(nil)
+
+		u := NewItemUsecase(mockRepo, WithAuditTrail(eventRepo), WithTransactor(tx))
+		err := u.DeleteItem(context.Background(), 1, []int64{AnyVersion})
+
+		require.NoError(t, err)
+		eventRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 監査記録の保存に失敗したら変更もロールバック", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		tx := &recordingTransactor{}
+
+		created, _ := entity.NewItem("時計", "時計", "ROLEX", 1, "2023-01-15", nil, "", nil)
+		created.ID = 11
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(created, nil)
+		eventRepo.On("Append", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+
+		u := NewItemUsecase(mockRepo, WithAuditTrail(eventRepo), WithTransactor(tx))
+		item, err := u.CreateItem(auditContext(), CreateItemInput{
+			Name: "時計", Category: "時計", Brand: "ROLEX", PurchasePrice: 1, PurchaseDate: "2023-01-15",
+		})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		assert.Nil(t, item)
+		assert.Equal(t, 0, tx.committed)
+		assert.Equal(t, 1, tx.rolledBack)
+	})
+
+	t.Run("異常系: 変更に失敗した場合は記録しない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		tx := &recordingTransactor{}
+
+		mockRepo.On("Restore", mock.Anything, int64(5)).Return((*entity.Item)(nil), domainErrors.ErrItemNotFound)
+
+		u := NewItemUsecase(mockRepo, WithAuditTrail(eventRepo), WithTransactor(tx))
+		_, err := u.RestoreItem(auditContext(), 5)
+
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+		assert.Equal(t, 1, tx.rolledBack)
+		eventRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuditUsecase_ListEvents(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		criteria    AuditCriteria
+		setupMock   func(*MockItemEventRepository)
+		expectedErr error
+	}{
+		{
+			name:     "正常系: デフォルトの件数で取得",
+			criteria: AuditCriteria{Actor: "tanaka", From: &from, To: &to},
+			setupMock: func(m *MockItemEventRepository) {
+				want := AuditCriteria{Actor: "tanaka", From: &from, To: &to, Limit: DefaultLimit}
+				m.On("Count", mock.Anything, want).Return(1, nil)
+				m.On("Find", mock.Anything, want).Return([]*entity.ItemEvent{{ID: 1}}, nil)
+			},
+		},
+		{
+			name:        "異常系: 無効な操作種別",
+			criteria:    AuditCriteria{Action: "purge"},
+			setupMock:   func(m *MockItemEventRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: 期間が逆転",
+			criteria:    AuditCriteria{From: &to, To: &from},
+			setupMock:   func(m *MockItemEventRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:     "異常系: データベースエラー",
+			criteria: AuditCriteria{},
+			setupMock: func(m *MockItemEventRepository) {
+				m.On("Count", mock.Anything, mock.Anything).Return(0, domainErrors.ErrDatabaseError)
+			},
+			expectedErr: domainErrors.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventRepo := new(MockItemEventRepository)
+			tt.setupMock(eventRepo)
+
+			list, err := NewAuditUsecase(eventRepo).ListEvents(context.Background(), tt.criteria)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, list)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, list.Events, 1)
+				assert.Equal(t, 1, list.Total)
+			}
+			eventRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditUsecase_GetItemHistory(t *testing.T) {
+	eventRepo := new(MockItemEventRepository)
+	byItem := mock.MatchedBy(func(c AuditCriteria) bool { return c.ItemID != nil && *c.ItemID == 7 })
+	eventRepo.On("Count", mock.Anything, byItem).Return(0, nil)
+	eventRepo.On("Find", mock.Anything, byItem).Return(([]*entity.ItemEvent)(nil), nil)
+
+	u := NewAuditUsecase(eventRepo)
+	list, err := u.GetItemHistory(context.Background(), 7, AuditCriteria{})
+
+	require.NoError(t, err)
+	assert.NotNil(t, list.Events)
+	eventRepo.AssertExpectations(t)
+
+	_, err = u.GetItemHistory(context.Background(), 0, AuditCriteria{})
+	assert.True(t, errors.Is(err, domainErrors.ErrInvalidInput))
+}
@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 一括操作の種類
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update" // JSON Merge Patch による部分更新
+	BatchOpDelete = "delete" // ゴミ箱への移動
+)
+
+// 一括操作の方式
+const (
+	BatchModeAtomic  = "atomic"  // すべての操作を1つのトランザクションで実行し、失敗した操作が1つでもあれば何も反映しない
+	BatchModePartial = "partial" // 操作ごとに反映し、失敗した操作だけを飛ばす
+)
+
+// 一括操作の操作ごとの結果
+const (
+	BatchStatusSucceeded = "succeeded"
+	BatchStatusFailed    = "failed"
+	BatchStatusSkipped   = "skipped" // atomic で他の操作が失敗したため取り消した、または partial で中断したため実行しなかった
+)
+
+// MaxBatchOperations は1回の一括操作で指定できる操作の数の上限
+const MaxBatchOperations = 1000
+
+// atomic の一括操作で失敗した操作があった場合に、トランザクションをロールバックするためのエラー
+var errBatchRolledBack = errors.New("batch rolled back")
+
+// BatchOptions は一括操作の設定
+type BatchOptions struct {
+	Mode string // BatchModeAtomic（デフォルト）または BatchModePartial
+}
+
+// BatchOperation は一括操作の1件
+type BatchOperation struct {
+	Op      string          // BatchOpCreate、BatchOpUpdate、BatchOpDelete のいずれか
+	ID      int64           // update と delete の対象
+	Version int64           // update と delete で必須。対象の現在のバージョン（AnyVersion は指定できない）
+	Create  CreateItemInput // create の内容
+	Update  UpdateItemInput // update の内容
+}
+
+// BatchReport は一括操作の結果
+type BatchReport struct {
+	Mode      string         `json:"mode"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Skipped   int            `json:"skipped"`
+	Results   []*BatchResult `json:"results"`
+}
+
+// BatchResult は操作ごとの結果。操作と同じ順に並ぶ
+type BatchResult struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op"`
+	Status string       `json:"status"`
+	ID     *int64       `json:"id,omitempty"`
+	Item   *entity.Item `json:"item,omitempty"` // create と update の反映後のアイテム
+	// Err は失敗した理由（バリデーションエラー、ErrItemNotFound、ErrVersionMismatch など）
+	Err error `json:"-"`
+}
+
+// BatchItems はアイテムの作成・更新・削除をまとめて実行し、操作ごとの結果を返す。
+// 作成はすべて先にバリデーションし、通ったものを複数行の INSERT でまとめて登録してから、更新と削除を順に実行する
+// （更新と削除の対象は既存のアイテムのため、作成との順序は結果に影響しない）。
+// 操作のバリデーションエラーや対象が無い・バージョンが一致しないといった失敗は結果に含め、
+// 操作の指定の誤りは何も実行せずに、データベースのエラーの場合は中断してエラーを返す。
+// partial では中断までに反映した操作は残るため、中断した操作を失敗、残りを skipped とした結果もエラーと一緒に返す
+func (u *itemUsecase) BatchItems(ctx context.Context, operations []BatchOperation, options BatchOptions) (*BatchReport, error) {
+	if options.Mode == "" {
+		options.Mode = BatchModeAtomic
+	}
+	if options.Mode != BatchModeAtomic && options.Mode != BatchModePartial {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("mode", []string{BatchModeAtomic, BatchModePartial}))
+	}
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.RequiredError("operations"))
+	}
+	if len(operations) > MaxBatchOperations {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.TooManyError("operations", MaxBatchOperations))
+	}
+
+	report := &BatchReport{Mode: options.Mode, Results: make([]*BatchResult, len(operations))}
+	for i, op := range operations {
+		if err := validateBatchOperation(fmt.Sprintf("operations[%d]", i), op); err != nil {
+			return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+		}
+		report.Results[i] = &BatchResult{Index: i, Op: op.Op}
+	}
+
+	// 作成するアイテムを先にバリデーションする
+	var creates []*BatchResult
+	var items []*entity.Item
+	for i, op := range operations {
+		if op.Op != BatchOpCreate {
+			continue
+		}
+		item, err := u.newItem(ctx, op.Create)
+		if err != nil {
+			if !isBatchOperationError(err) {
+				return nil, err
+			}
+			report.fail(report.Results[i], err)
+			continue
+		}
+		creates = append(creates, report.Results[i])
+		items = append(items, item)
+	}
+
+	var err error
+	if options.Mode == BatchModePartial {
+		// 作成はバリデーションを通ったものを1つのトランザクションでまとめて登録する
+		if len(items) > 0 {
+			err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+				return u.createBatchItems(ctx, report, creates, items)
+			})
+			if err != nil {
+				report.abort(creates, err)
+			}
+		}
+		for i, op := range operations {
+			if err != nil {
+				break
+			}
+			if op.Op == BatchOpCreate {
+				continue
+			}
+			err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+				return u.writeBatchItem(ctx, report, report.Results[i], op)
+			})
+			if isBatchOperationError(err) {
+				report.fail(report.Results[i], err)
+				err = nil
+			}
+			if err != nil {
+				report.abort(report.Results[i:i+1], err)
+			}
+		}
+		if err != nil {
+			// 中断までに反映した操作は確定しているため、索引にも反映してから返す
+			u.indexBatchResults(report)
+			return report, err
+		}
+	} else {
+		err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+			if err := u.createBatchItems(ctx, report, creates, items); err != nil {
+				return err
+			}
+			for i, op := range operations {
+				if op.Op == BatchOpCreate {
+					continue
+				}
+				// 失敗した操作の後も、ロールバックする前にすべての操作の結果を確かめる
+				err := u.writeBatchItem(ctx, report, report.Results[i], op)
+				if isBatchOperationError(err) {
+					report.fail(report.Results[i], err)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if report.Failed > 0 {
+				return errBatchRolledBack
+			}
+			return nil
+		})
+		if errors.Is(err, errBatchRolledBack) {
+			err = nil
+			for _, result := range report.Results {
+				if result.Status == BatchStatusSucceeded {
+					result.Status, result.Item = BatchStatusSkipped, nil
+					if result.Op == BatchOpCreate {
+						result.ID = nil
+					}
+					report.Succeeded--
+					report.Skipped++
+				}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.indexBatchResults(report)
+
+	return report, nil
+}
+
+// indexBatchResults は成功した操作の結果を索引へ反映する
+func (u *itemUsecase) indexBatchResults(report *BatchReport) {
+	for _, result := range report.Results {
+		if result.Status != BatchStatusSucceeded {
+			continue
+		}
+		if result.Op == BatchOpDelete {
+			u.unindexItem(*result.ID)
+		} else {
+			u.indexItem(result.Item)
+		}
+	}
+}
+
+// validateBatchOperation は操作の種類と、更新・削除の対象の指定を検証する
+func validateBatchOperation(field string, op BatchOperation) error {
+	switch op.Op {
+	case BatchOpCreate:
+		return nil
+	case BatchOpUpdate, BatchOpDelete:
+		if op.ID <= 0 {
+			return entity.RequiredError(field + ".id")
+		}
+		if op.Version <= 0 {
+			return entity.RequiredError(field + ".version")
+		}
+		return nil
+	default:
+		return entity.InvalidEnumError(field+".op", []string{BatchOpCreate, BatchOpUpdate, BatchOpDelete})
+	}
+}
+
+// isBatchOperationError は err が一括操作を中断せずに、その操作だけを失敗とするエラーかどうかを返す
+func isBatchOperationError(err error) bool {
+	for _, target := range []error{
+		domainErrors.ErrInvalidInput,
+		domainErrors.ErrItemNotFound,
+		domainErrors.ErrVersionMismatch,
+		domainErrors.ErrBrandNotFound,
+		domainErrors.ErrCategoryNotFound,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// createBatchItems は items をまとめて登録して監査記録を残し、results に結果を設定する。
+// 呼び出し側でトランザクションを張ること
+func (u *itemUsecase) createBatchItems(ctx context.Context, report *BatchReport, results []*BatchResult, items []*entity.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	created, err := u.itemRepo.CreateMany(ctx, items)
+	if err != nil {
+		return fmt.Errorf("failed to create items: %w", err)
+	}
+	for i, item := range created {
+		if err := u.recordEvent(ctx, entity.ItemEventCreate, item.ID, nil, item); err != nil {
+			return err
+		}
+		results[i].Status, results[i].ID, results[i].Item = BatchStatusSucceeded, &item.ID, item
+		report.Succeeded++
+	}
+	return nil
+}
+
+// writeBatchItem は更新または削除の操作を実行し、result に結果を設定する。
+// 呼び出し側でトランザクションを張ること
+func (u *itemUsecase) writeBatchItem(ctx context.Context, report *BatchReport, result *BatchResult, op BatchOperation) error {
+	id := op.ID
+	result.ID = &id
+
+	var item *entity.Item
+	var err error
+	if op.Op == BatchOpDelete {
+		err = u.deleteItem(ctx, op.ID, []int64{op.Version})
+	} else {
+		item, err = u.updateItemPartially(ctx, op.ID, op.Update, []int64{op.Version})
+	}
+	if err != nil {
+		return err
+	}
+	result.Status, result.Item = BatchStatusSucceeded, item
+	report.Succeeded++
+	return nil
+}
+
+// fail は result を失敗として数える
+func (r *BatchReport) fail(result *BatchResult, err error) {
+	result.Status, result.Err = BatchStatusFailed, err
+	r.Failed++
+}
+
+// abort は partial で中断した操作の結果を err で失敗とし、まだ実行していない操作を skipped として数える。
+// 中断した操作のトランザクションはロールバックしているため、成功として数えていたものも取り消す
+func (r *BatchReport) abort(aborted []*BatchResult, err error) {
+	for _, result := range aborted {
+		if result.Status == BatchStatusSucceeded {
+			r.Succeeded--
+		}
+		if result.Op == BatchOpCreate {
+			result.ID = nil
+		}
+		result.Item = nil
+		r.fail(result, err)
+	}
+	for _, result := range r.Results {
+		if result.Status == "" {
+			result.Status = BatchStatusSkipped
+			r.Skipped++
+		}
+	}
+}
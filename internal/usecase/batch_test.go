@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+func TestItemUsecase_BatchItems(t *testing.T) {
+	newItem := func(id int64, name string) *entity.Item {
+		item, _ := entity.NewItem(name, "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		item.ID, item.Version = id, 1
+		return item
+	}
+	createOp := func(name string) BatchOperation {
+		return BatchOperation{Op: BatchOpCreate, Create: CreateItemInput{
+			Name: name, Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15",
+		}}
+	}
+	renameOp := func(id, version int64, name string) BatchOperation {
+		return BatchOperation{Op: BatchOpUpdate, ID: id, Version: version, Update: UpdateItemInput{Name: Set(name)}}
+	}
+	// 作成2件・更新・削除を受け付けるリポジトリ
+	newRepo := func() *MockItemRepository {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(items []*entity.Item) bool { return len(items) == 2 })).
+			Return([]*entity.Item{newItem(10, "デイトナ"), newItem(11, "サブマリーナ")}, nil)
+		mockRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(items []*entity.Item) bool { return len(items) == 1 })).
+			Return([]*entity.Item{newItem(10, "デイトナ")}, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(newItem(1, "エクスプローラー"), nil)
+		mockRepo.On("FindByID", mock.Anything, int64(2)).Return(newItem(2, "ヨットマスター"), nil)
+		mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.Anything, []entity.ItemField{entity.ItemFieldName}).
+			Return(func() *entity.Item { item := newItem(1, "エクスプローラー II"); item.Version = 2; return item }(), nil)
+		mockRepo.On("Delete", mock.Anything, int64(2), int64(1)).Return(nil)
+		return mockRepo
+	}
+	statuses := func(report *BatchReport) []string {
+		var s []string
+		for _, result := range report.Results {
+			s = append(s, result.Op+"/"+result.Status)
+		}
+		return s
+	}
+
+	t.Run("正常系: atomic ですべての操作が成功すれば1つのトランザクションで反映する", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+		index.On("Put", mock.Anything).Times(3)
+		index.On("Remove", int64(2)).Once()
+		operations := []BatchOperation{createOp("デイトナ"), renameOp(1, 1, "エクスプローラー II"), {Op: BatchOpDelete, ID: 2, Version: 1}, createOp("サブマリーナ")}
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx), WithItemIndex(index)).BatchItems(context.Background(), operations, BatchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, BatchModeAtomic, report.Mode)
+		assert.Equal(t, []string{"create/succeeded", "update/succeeded", "delete/succeeded", "create/succeeded"}, statuses(report))
+		assert.Equal(t, [3]int{4, 0, 0}, [3]int{report.Succeeded, report.Failed, report.Skipped})
+		assert.Equal(t, int64(11), *report.Results[3].ID, "作成したアイテムは操作の順に対応する")
+		assert.Equal(t, "エクスプローラー II", report.Results[1].Item.Name)
+		assert.Nil(t, report.Results[2].Item)
+		assert.Equal(t, 1, tx.committed)
+		mockRepo.AssertNumberOfCalls(t, "CreateMany", 1)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("正常系: atomic では失敗した操作があれば何も反映せず、すべての失敗を返す", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+		operations := []BatchOperation{createOp("デイトナ"), createOp(""), renameOp(1, 2, "エクスプローラー II"), {Op: BatchOpDelete, ID: 2, Version: 1}}
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx), WithItemIndex(index)).BatchItems(context.Background(), operations, BatchOptions{Mode: BatchModeAtomic})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create/skipped", "create/failed", "update/failed", "delete/skipped"}, statuses(report))
+		assert.Equal(t, [3]int{0, 2, 2}, [3]int{report.Succeeded, report.Failed, report.Skipped})
+		assert.Nil(t, report.Results[0].ID, "取り消した作成には ID を返さない")
+		assert.Equal(t, int64(2), *report.Results[3].ID)
+		assert.ErrorIs(t, report.Results[1].Err, domainErrors.ErrInvalidInput)
+		assert.ErrorIs(t, report.Results[2].Err, domainErrors.ErrVersionMismatch)
+		assert.Equal(t, 1, tx.rolledBack)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+		index.AssertNotCalled(t, "Remove", mock.Anything)
+	})
+
+	t.Run("正常系: partial では失敗した操作だけを飛ばす", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+		index.On("Put", mock.Anything).Once()
+		index.On("Remove", int64(2)).Once()
+		operations := []BatchOperation{createOp("デイトナ"), createOp(""), renameOp(1, 2, "エクスプローラー II"), {Op: BatchOpDelete, ID: 2, Version: 1}}
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx), WithItemIndex(index)).BatchItems(context.Background(), operations, BatchOptions{Mode: BatchModePartial})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"create/succeeded", "create/failed", "update/failed", "delete/succeeded"}, statuses(report))
+		assert.Equal(t, [3]int{2, 2, 0}, [3]int{report.Succeeded, report.Failed, report.Skipped})
+		assert.Equal(t, int64(10), *report.Results[0].ID)
+		assert.Equal(t, 2, tx.committed, "作成をまとめて1回、削除で1回確定する")
+		assert.Equal(t, 1, tx.rolledBack)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 操作の指定の誤りでは何も実行しない", func(t *testing.T) {
+		tooMany := make([]BatchOperation, MaxBatchOperations+1)
+		for i := range tooMany {
+			tooMany[i] = createOp("デイトナ")
+		}
+		tests := []struct {
+			name       string
+			operations []BatchOperation
+			options    BatchOptions
+		}{
+			{name: "不正な方式", operations: []BatchOperation{createOp("デイトナ")}, options: BatchOptions{Mode: "all"}},
+			{name: "操作が無い", operations: nil},
+			{name: "操作が多すぎる", operations: tooMany},
+			{name: "不正な操作の種類", operations: []BatchOperation{createOp("デイトナ"), {Op: "upsert"}}},
+			{name: "更新のバージョンが無い", operations: []BatchOperation{{Op: BatchOpUpdate, ID: 1}}},
+			{name: "削除の ID が無い", operations: []BatchOperation{{Op: BatchOpDelete, Version: 1}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockItemRepository)
+
+				_, err := NewItemUsecase(mockRepo).BatchItems(context.Background(), tt.operations, tt.options)
+
+				assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+				mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("異常系: データベースエラーでは中断する", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("CreateMany", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+		tx := &recordingTransactor{}
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx)).BatchItems(context.Background(),
+			[]BatchOperation{createOp("デイトナ"), {Op: BatchOpDelete, ID: 2, Version: 1}}, BatchOptions{Mode: BatchModePartial})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		require.NotNil(t, report)
+		assert.Equal(t, []string{"create/failed", "delete/skipped"}, statuses(report))
+		assert.Equal(t, [3]int{0, 1, 1}, [3]int{report.Succeeded, report.Failed, report.Skipped})
+		assert.ErrorIs(t, report.Results[0].Err, domainErrors.ErrDatabaseError)
+		assert.Equal(t, 1, tx.rolledBack)
+	})
+
+	t.Run("異常系: partial でも更新中のデータベースエラーでは残りを実行せず、反映した操作を索引へ反映して結果と一緒に返す", func(t *testing.T) {
+		mockRepo := newRepo()
+		mockRepo.On("FindByID", mock.Anything, int64(3)).Return(nil, domainErrors.ErrDatabaseError)
+		index := new(MockItemIndex)
+		index.On("Put", mock.MatchedBy(func(item *entity.Item) bool { return item.ID == 10 || item.ID == 1 })).Twice()
+		operations := []BatchOperation{createOp("デイトナ"), renameOp(1, 1, "エクスプローラー II"), renameOp(3, 1, "a"), {Op: BatchOpDelete, ID: 2, Version: 1}}
+
+		report, err := NewItemUsecase(mockRepo, WithItemIndex(index)).BatchItems(context.Background(), operations, BatchOptions{Mode: BatchModePartial})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		require.NotNil(t, report)
+		assert.Equal(t, []string{"create/succeeded", "update/succeeded", "update/failed", "delete/skipped"}, statuses(report))
+		assert.Equal(t, [3]int{2, 1, 1}, [3]int{report.Succeeded, report.Failed, report.Skipped})
+		assert.ErrorIs(t, report.Results[2].Err, domainErrors.ErrDatabaseError)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		index.AssertExpectations(t)
+		index.AssertNotCalled(t, "Remove", mock.Anything)
+	})
+
+	t.Run("異常系: atomic のデータベースエラーでは結果を返さない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("CreateMany", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+
+		report, err := NewItemUsecase(mockRepo).BatchItems(context.Background(), []BatchOperation{createOp("デイトナ")}, BatchOptions{})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		assert.Nil(t, report)
+	})
+}
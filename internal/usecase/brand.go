@@ -0,0 +1,391 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// BrandInput はブランドの登録・置換の内容
+type BrandInput struct {
+	Name    string   `json:"name"`
+	Country string   `json:"country"`
+	Aliases []string `json:"aliases"`
+}
+
+// BrandResolution はブランド名をカタログで解決した結果。
+// 一致するブランドがあれば Brand、無ければ表記の近いブランドの正式名を Suggestions に返す
+type BrandResolution struct {
+	Query       string        `json:"query"`
+	Brand       *entity.Brand `json:"brand"`
+	Suggestions []string      `json:"suggestions"`
+}
+
+// BrandResolutionMode はアイテムのブランドがカタログに無い場合の扱い
+type BrandResolutionMode string
+
+const (
+	// BrandResolutionLenient はカタログに無いブランドも自由入力として受け付ける（brand_id は null）
+	BrandResolutionLenient BrandResolutionMode = "lenient"
+	// BrandResolutionStrict はカタログに無いブランドを候補付きのバリデーションエラーにする
+	BrandResolutionStrict BrandResolutionMode = "strict"
+)
+
+// 候補として返すブランドの最大数
+const maxBrandSuggestions = 5
+
+type BrandUsecase interface {
+	ListBrands(ctx context.Context) ([]*entity.Brand, error)
+	GetBrand(ctx context.Context, id int64) (*entity.Brand, error)
+	CreateBrand(ctx context.Context, input BrandInput) (*entity.Brand, error)
+	ReplaceBrand(ctx context.Context, id int64, input BrandInput) (*entity.Brand, error)
+	DeleteBrand(ctx context.Context, id int64) error
+	ResolveBrand(ctx context.Context, name string) (*BrandResolution, error)
+}
+
+type brandUsecase struct {
+	brandRepo BrandRepository
+	itemRepo  ItemRepository
+	eventRepo ItemEventRepository
+	index     ItemIndex
+	tx        Transactor
+}
+
+// BrandUsecaseOption は brandUsecase の任意設定
+type BrandUsecaseOption func(*brandUsecase)
+
+// WithBrandAuditTrail は正式名の変更で紐づくアイテムの brand_canonical を書き換えるたびに、アイテムごとの監査記録を eventRepo へ保存する。
+// 記録は書き換えと同一トランザクションで実行される
+func WithBrandAuditTrail(eventRepo ItemEventRepository) BrandUsecaseOption {
+	return func(u *brandUsecase) {
+		u.eventRepo = eventRepo
+	}
+}
+
+// WithBrandItemIndex は正式名の変更で brand_canonical を書き換えたアイテムを、変更の確定後に index へ反映する
+func WithBrandItemIndex(index ItemIndex) BrandUsecaseOption {
+	return func(u *brandUsecase) {
+		u.index = index
+	}
+}
+
+// NewBrandUsecase はブランドカタログの usecase を返す。
+// 登録・更新は名前と別名の重複確認を含めて tx のトランザクションで実行する（nil の場合はトランザクションを使わない）。
+// 正式名の変更で brand_canonical が変わるアイテムは itemRepo で1件ずつ書き込む
+func NewBrandUsecase(brandRepo BrandRepository, itemRepo ItemRepository, tx Transactor, opts ...BrandUsecaseOption) BrandUsecase {
+	if tx == nil {
+		tx = noopTransactor{}
+	}
+	u := &brandUsecase{
+		brandRepo: brandRepo,
+		itemRepo:  itemRepo,
+		tx:        tx,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+func (u *brandUsecase) ListBrands(ctx context.Context) ([]*entity.Brand, error) {
+	brands, err := u.brandRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve brands: %w", err)
+	}
+	if brands == nil {
+		brands = []*entity.Brand{}
+	}
+
+	return brands, nil
+}
+
+func (u *brandUsecase) GetBrand(ctx context.Context, id int64) (*entity.Brand, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	brand, err := u.brandRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrBrandNotFound) {
+			return nil, domainErrors.ErrBrandNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve brand: %w", err)
+	}
+
+	return brand, nil
+}
+
+func (u *brandUsecase) CreateBrand(ctx context.Context, input BrandInput) (*entity.Brand, error) {
+	brand, err := entity.NewBrand(input.Name, input.Country, input.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+	}
+
+	var created *entity.Brand
+	err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+		created, err = u.brandRepo.Create(ctx, brand)
+		if err != nil {
+			return brandWriteError("create", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (u *brandUsecase) ReplaceBrand(ctx context.Context, id int64, input BrandInput) (*entity.Brand, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	var updated *entity.Brand
+	var rebranded []*entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := u.GetBrand(ctx, id)
+		if err != nil {
+			return err
+		}
+		canonical := existing.NameCanonical
+
+		if err := existing.Update(input.Name, input.Country, input.Aliases); err != nil {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+		}
+
+		updated, err = u.brandRepo.Update(ctx, existing)
+		if err != nil {
+			return brandWriteError("update", err)
+		}
+
+		if updated.NameCanonical != canonical {
+			if rebranded, err = u.rebrandItems(ctx, updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.indexItems(rebranded)
+
+	return updated, nil
+}
+
+// rebrandItems はブランドに紐づくアイテム（ゴミ箱にあるものを含む）の brand_canonical を新しい正式名の正規形に書き換え、
+// アイテムごとに監査記録を残して書き換え後のアイテムを返す。呼び出し側でトランザクションを張ること
+func (u *brandUsecase) rebrandItems(ctx context.Context, brand *entity.Brand) ([]*entity.Item, error) {
+	items, err := u.itemRepo.FindByBrand(ctx, brand.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	rebranded := make([]*entity.Item, 0, len(items))
+	for _, before := range items {
+		after := *before
+		after.BrandCanonical = brand.NameCanonical
+		updated, err := u.itemRepo.Rebrand(ctx, &after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebrand item %d: %w", before.ID, err)
+		}
+		if err := recordItemEvent(ctx, u.eventRepo, entity.ItemEventUpdate, updated.ID, before, updated); err != nil {
+			return nil, err
+		}
+		rebranded = append(rebranded, updated)
+	}
+	return rebranded, nil
+}
+
+// 変更の確定後に、書き換えたアイテムを索引へ反映する（ゴミ箱にあるアイテムは Put が索引に含めない）
+func (u *brandUsecase) indexItems(items []*entity.Item) {
+	if u.index == nil {
+		return
+	}
+	for _, item := range items {
+		u.index.Put(item)
+	}
+}
+
+func (u *brandUsecase) DeleteBrand(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidInput
+	}
+
+	return u.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.brandRepo.Delete(ctx, id); err != nil {
+			return brandWriteError("delete", err)
+		}
+		return nil
+	})
+}
+
+func (u *brandUsecase) ResolveBrand(ctx context.Context, name string) (*BrandResolution, error) {
+	if entity.CanonicalBrand(name) == "" {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.RequiredError("name"))
+	}
+
+	brand, suggestions, err := resolveBrand(ctx, u.brandRepo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BrandResolution{
+		Query:       entity.NormalizeText(name),
+		Brand:       brand,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// 書き込み時のエラーのうち、クライアントに意味のあるもの（存在しない・重複・使用中）はそのまま返す
+func brandWriteError(action string, err error) error {
+	switch {
+	case errors.Is(err, domainErrors.ErrBrandNotFound):
+		return domainErrors.ErrBrandNotFound
+	case errors.Is(err, domainErrors.ErrDuplicateEntry):
+		return fmt.Errorf("%w: name or alias is already registered for another brand", domainErrors.ErrDuplicateEntry)
+	case errors.Is(err, domainErrors.ErrInUse):
+		return fmt.Errorf("%w: %w", domainErrors.ErrInUse, entity.InUseError("items"))
+	default:
+		return fmt.Errorf("failed to %s brand: %w", action, err)
+	}
+}
+
+// resolveBrand は name の正規形に一致するブランドを返す。
+// 一致しない場合は nil と、表記の近いブランドの正式名を返す
+func resolveBrand(ctx context.Context, repo BrandRepository, name string) (*entity.Brand, []string, error) {
+	key := entity.CanonicalBrand(name)
+
+	brand, err := repo.FindByKey(ctx, key)
+	if err == nil {
+		return brand, []string{}, nil
+	}
+	if !errors.Is(err, domainErrors.ErrBrandNotFound) {
+		return nil, nil, fmt.Errorf("failed to resolve brand: %w", err)
+	}
+
+	brands, err := repo.FindAll(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve brands: %w", err)
+	}
+
+	return nil, suggestBrands(brands, key), nil
+}
+
+// suggestBrands は正式名・別名の正規形と key の編集距離が近いブランドの正式名を、近い順に最大 maxBrandSuggestions 件返す。
+// 許容する距離は key の文字数の 1/3（最低 1）で、どちらかがもう一方を含む場合は距離 1 とみなす
+func suggestBrands(brands []*entity.Brand, key string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	keyRunes := []rune(key)
+	maxDistance := len(keyRunes) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	var candidates []candidate
+	for _, brand := range brands {
+		best := -1
+		for _, k := range brand.Keys() {
+			d := levenshtein(keyRunes, []rune(k))
+			if d > 1 && len(keyRunes) > 1 && (strings.Contains(k, key) || strings.Contains(key, k)) {
+				d = 1
+			}
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		if best >= 0 && best <= maxDistance {
+			candidates = append(candidates, candidate{name: brand.Name, distance: best})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := []string{}
+	for _, c := range candidates {
+		if len(suggestions) == maxBrandSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// levenshtein は a と b の編集距離（挿入・削除・置換の回数）を文字単位で求める
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// resolveItemBrand はアイテムのブランドをカタログで解決し、一致すれば brand_id と正式名の正規形を設定する。
+// 一致しない場合、strict ではバリデーションエラー（候補付き）、lenient では自由入力のまま brand_id を外す
+func (u *itemUsecase) resolveItemBrand(ctx context.Context, item *entity.Item) error {
+	if u.brandRepo == nil {
+		return nil
+	}
+
+	brand, suggestions, err := resolveBrand(ctx, u.brandRepo, item.Brand)
+	if err != nil {
+		return err
+	}
+
+	if brand != nil {
+		item.BrandID = &brand.ID
+		item.BrandCanonical = brand.NameCanonical
+		return nil
+	}
+
+	item.BrandID = nil
+	if u.brandMode == BrandResolutionStrict {
+		return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput,
+			entity.ValidationErrors{entity.NotRegisteredError(string(entity.ItemFieldBrand), suggestions)})
+	}
+	return nil
+}
+
+// resolveCriteriaBrand はブランドでの絞り込みをカタログで解決し、別名で指定された場合も同じブランドのアイテムを返すようにする
+func (u *itemUsecase) resolveCriteriaBrand(ctx context.Context, criteria ItemCriteria) (ItemCriteria, error) {
+	if u.brandRepo == nil || criteria.BrandCanonical == "" {
+		return criteria, nil
+	}
+
+	brand, err := u.brandRepo.FindByKey(ctx, criteria.BrandCanonical)
+	switch {
+	case err == nil:
+		criteria.BrandCanonical = brand.NameCanonical
+	case !errors.Is(err, domainErrors.ErrBrandNotFound):
+		return criteria, fmt.Errorf("failed to resolve brand: %w", err)
+	}
+
+	return criteria, nil
+}
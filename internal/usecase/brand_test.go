@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// MockBrandRepository はtestify/mockを使用したブランドのモックリポジトリ
+type MockBrandRepository struct {
+	mock.Mock
+}
+
+func (m *MockBrandRepository) FindAll(ctx context.Context) ([]*entity.Brand, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) FindByID(ctx context.Context, id int64) (*entity.Brand, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) FindByKey(ctx context.Context, key string) (*entity.Brand, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) Create(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	args := m.Called(ctx, brand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) Update(ctx context.Context, brand *entity.Brand) (*entity.Brand, error) {
+	args := m.Called(ctx, brand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Brand), args.Error(1)
+}
+
+func (m *MockBrandRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func testBrand(t *testing.T, id int64, name, country string, aliases ...string) *entity.Brand {
+	t.Helper()
+	brand, err := entity.NewBrand(name, country, aliases)
+	require.NoError(t, err)
+	brand.ID = id
+	return brand
+}
+
+func testCatalog(t *testing.T) []*entity.Brand {
+	t.Helper()
+	return []*entity.Brand{
+		testBrand(t, 1, "ROLEX", "CH", "ロレックス"),
+		testBrand(t, 2, "HERMÈS", "FR", "エルメス"),
+		testBrand(t, 3, "Christian Louboutin", "FR", "ルブタン", "Louboutin"),
+		testBrand(t, 4, "OMEGA", "CH", "オメガ"),
+	}
+}
+
+func TestSuggestBrands(t *testing.T) {
+	catalog := testCatalog(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "正常系: 1文字違い", input: "ROLX", expected: []string{"ROLEX"}},
+		{name: "正常系: 別名のカタカナの誤記", input: "ロレックズ", expected: []string{"ROLEX"}},
+		{name: "正常系: 別名の一部", input: "エルメ", expected: []string{"HERMÈS"}},
+		{name: "正常系: 正式名の一部", input: "CHRISTIAN", expected: []string{"Christian Louboutin"}},
+		{name: "正常系: 近いものが無い", input: "PATEK PHILIPPE", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, suggestBrands(catalog, entity.CanonicalBrand(tt.input)))
+		})
+	}
+}
+
+func TestBrandUsecase_ResolveBrand(t *testing.T) {
+	catalog := testCatalog(t)
+
+	tests := []struct {
+		name                string
+		input               string
+		setupMock           func(*MockBrandRepository)
+		expectedBrandID     int64
+		expectedSuggestions []string
+		expectedErr         error
+	}{
+		{
+			name:  "正常系: 別名で一致",
+			input: "ﾛﾚｯｸｽ",
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "ロレックス").Return(catalog[0], nil)
+			},
+			expectedBrandID:     1,
+			expectedSuggestions: []string{},
+		},
+		{
+			name:  "正常系: 一致しない場合は候補を返す",
+			input: "Hermez",
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "HERMEZ").Return(nil, domainErrors.ErrBrandNotFound)
+				m.On("FindAll", mock.Anything).Return(catalog, nil)
+			},
+			expectedSuggestions: []string{"HERMÈS"},
+		},
+		{
+			name:        "異常系: 名前が空",
+			input:       " ",
+			setupMock:   func(m *MockBrandRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:  "異常系: データベースエラー",
+			input: "ROLEX",
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "ROLEX").Return(nil, domainErrors.ErrDatabaseError)
+			},
+			expectedErr: domainErrors.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBrandRepository)
+			tt.setupMock(mockRepo)
+			u := NewBrandUsecase(mockRepo, nil, nil)
+
+			resolution, err := u.ResolveBrand(context.Background(), tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, resolution)
+			} else {
+				require.NoError(t, err)
+				if tt.expectedBrandID != 0 {
+					require.NotNil(t, resolution.Brand)
+					assert.Equal(t, tt.expectedBrandID, resolution.Brand.ID)
+				} else {
+					assert.Nil(t, resolution.Brand)
+				}
+				assert.Equal(t, tt.expectedSuggestions, resolution.Suggestions)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBrandUsecase_CreateBrand(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       BrandInput
+		setupMock   func(*MockBrandRepository)
+		expectedErr error
+	}{
+		{
+			name:  "正常系: ブランドを作成",
+			input: BrandInput{Name: "OMEGA", Country: "ch", Aliases: []string{"オメガ"}},
+			setupMock: func(m *MockBrandRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(b *entity.Brand) bool {
+					return b.NameCanonical == "OMEGA" && b.Country == "CH"
+				})).Return(testBrand(t, 1, "OMEGA", "CH", "オメガ"), nil)
+			},
+		},
+		{
+			name:        "異常系: 無効な入力",
+			input:       BrandInput{Name: "", Country: "Switzerland"},
+			setupMock:   func(m *MockBrandRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:  "異常系: 名前や別名が重複",
+			input: BrandInput{Name: "Rolex"},
+			setupMock: func(m *MockBrandRepository) {
+				m.On("Create", mock.Anything, mock.AnythingOfType("*entity.Brand")).Return(nil, domainErrors.ErrDuplicateEntry)
+			},
+			expectedErr: domainErrors.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBrandRepository)
+			tt.setupMock(mockRepo)
+			tx := &recordingTransactor{}
+			u := NewBrandUsecase(mockRepo, nil, tx)
+
+			brand, err := u.CreateBrand(context.Background(), tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, brand)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), brand.ID)
+				assert.Equal(t, 1, tx.committed)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBrandUsecase_DeleteBrand(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int64
+		setupMock   func(*MockBrandRepository)
+		expectedErr error
+	}{
+		{
+			name: "正常系: ブランドを削除",
+			id:   1,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("Delete", mock.Anything, int64(1)).Return(nil)
+			},
+		},
+		{
+			name: "異常系: アイテムが紐づいている",
+			id:   1,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("Delete", mock.Anything, int64(1)).Return(domainErrors.ErrInUse)
+			},
+			expectedErr: domainErrors.ErrInUse,
+		},
+		{
+			name: "異常系: 存在しない",
+			id:   999,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("Delete", mock.Anything, int64(999)).Return(domainErrors.ErrBrandNotFound)
+			},
+			expectedErr: domainErrors.ErrBrandNotFound,
+		},
+		{
+			name:        "異常系: 無効なID",
+			id:          0,
+			setupMock:   func(m *MockBrandRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBrandRepository)
+			tt.setupMock(mockRepo)
+			u := NewBrandUsecase(mockRepo, nil, nil)
+
+			err := u.DeleteBrand(context.Background(), tt.id)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBrandUsecase_ReplaceBrand_Rebrand(t *testing.T) {
+	// ROLEX に紐づくアイテム2件（1件はゴミ箱にある）
+	newLinkedItems := func() []*entity.Item {
+		brandID := int64(1)
+		deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		return []*entity.Item{
+			{ID: 10, Name: "デイトナ", Category: "時計", Brand: "ロレックス", BrandCanonical: "ROLEX", BrandID: &brandID, Version: 1},
+			{ID: 11, Name: "サブマリーナ", Category: "時計", Brand: "Rolex", BrandCanonical: "ROLEX", BrandID: &brandID, Version: 3, DeletedAt: &deletedAt},
+		}
+	}
+	newBrandRepo := func(input BrandInput) *MockBrandRepository {
+		brandRepo := new(MockBrandRepository)
+		brandRepo.On("FindByID", mock.Anything, int64(1)).Return(testBrand(t, 1, "ROLEX", "CH", "ロレックス"), nil)
+		brandRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Brand")).
+			Return(testBrand(t, 1, input.Name, input.Country, input.Aliases...), nil)
+		return brandRepo
+	}
+	setupItems := func(itemRepo *MockItemRepository) {
+		itemRepo.On("FindByBrand", mock.Anything, int64(1)).Return(newLinkedItems(), nil)
+		for _, item := range newLinkedItems() {
+			rebranded := *item
+			rebranded.BrandCanonical, rebranded.Version = "ROLEX SA", item.Version+1
+			itemRepo.On("Rebrand", mock.Anything, mock.MatchedBy(func(in *entity.Item) bool {
+				return in.ID == item.ID && in.Version == item.Version && in.BrandCanonical == "ROLEX SA"
+			})).Return(&rebranded, nil)
+		}
+	}
+	renamed := BrandInput{Name: "Rolex SA", Country: "CH", Aliases: []string{"ロレックス", "ROLEX"}}
+
+	t.Run("正常系: 正式名を変更すると紐づくアイテムごとにバージョンを上げて監査記録を残し、確定後に索引へ反映する", func(t *testing.T) {
+		brandRepo := newBrandRepo(renamed)
+		itemRepo := new(MockItemRepository)
+		setupItems(itemRepo)
+		eventRepo := new(MockItemEventRepository)
+		for _, id := range []int64{10, 11} {
+			eventRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *entity.ItemEvent) bool {
+				return e.ItemID == id &&
+					e.Action == entity.ItemEventUpdate &&
+					e.Actor == "tanaka" &&
+					len(e.Changes) == 1 &&
+					e.Changes["brand_canonical"] == entity.FieldChange{Before: "ROLEX", After: "ROLEX SA"}
+			})).Return(nil).Once()
+		}
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+		index.On("Put", mock.MatchedBy(func(item *entity.Item) bool { return item.BrandCanonical == "ROLEX SA" })).Twice()
+
+		u := NewBrandUsecase(brandRepo, itemRepo, tx, WithBrandAuditTrail(eventRepo), WithBrandItemIndex(index))
+		_, err := u.ReplaceBrand(auditContext(), 1, renamed)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		itemRepo.AssertNumberOfCalls(t, "Rebrand", 2)
+		eventRepo.AssertExpectations(t)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 正式名の正規形が変わらなければアイテムは書き換えない", func(t *testing.T) {
+		input := BrandInput{Name: "Rolex", Country: "CH", Aliases: []string{"ロレックス"}}
+		brandRepo := newBrandRepo(input)
+		itemRepo := new(MockItemRepository)
+		eventRepo := new(MockItemEventRepository)
+		index := new(MockItemIndex)
+
+		u := NewBrandUsecase(brandRepo, itemRepo, nil, WithBrandAuditTrail(eventRepo), WithBrandItemIndex(index))
+		_, err := u.ReplaceBrand(context.Background(), 1, input)
+
+		require.NoError(t, err)
+		itemRepo.AssertNotCalled(t, "FindByBrand", mock.Anything, mock.Anything)
+		eventRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+	})
+
+	t.Run("異常系: 監査記録の保存に失敗した場合はロールバックし、索引も更新しない", func(t *testing.T) {
+		brandRepo := newBrandRepo(renamed)
+		itemRepo := new(MockItemRepository)
+		setupItems(itemRepo)
+		eventRepo := new(MockItemEventRepository)
+		eventRepo.On("Append", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+
+		u := NewBrandUsecase(brandRepo, itemRepo, tx, WithBrandAuditTrail(eventRepo), WithBrandItemIndex(index))
+		_, err := u.ReplaceBrand(auditContext(), 1, renamed)
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		assert.Equal(t, 1, tx.rolledBack)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+	})
+}
+
+func TestItemUsecase_BrandResolution(t *testing.T) {
+	catalog := testCatalog(t)
+	input := CreateItemInput{
+		Name:          "デイトナ",
+		Category:      "時計",
+		PurchasePrice: 1500000,
+		PurchaseDate:  "2023-01-15",
+	}
+
+	tests := []struct {
+		name              string
+		brand             string
+		mode              BrandResolutionMode
+		setupMock         func(*MockBrandRepository)
+		expectedBrandID   *int64
+		expectedCanonical string
+		expectedErrors    entity.ValidationErrors
+	}{
+		{
+			name:  "正常系: 別名で入力されたブランドを正式名に紐づける",
+			brand: "ロレックス",
+			mode:  BrandResolutionStrict,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "ロレックス").Return(catalog[0], nil)
+			},
+			expectedBrandID:   &catalog[0].ID,
+			expectedCanonical: "ROLEX",
+		},
+		{
+			name:  "正常系: lenient ではカタログに無いブランドも受け付ける",
+			brand: "Patek Philippe",
+			mode:  BrandResolutionLenient,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "PATEK PHILIPPE").Return(nil, domainErrors.ErrBrandNotFound)
+				m.On("FindAll", mock.Anything).Return(catalog, nil)
+			},
+			expectedCanonical: "PATEK PHILIPPE",
+		},
+		{
+			name:  "異常系: strict ではカタログに無いブランドを候補付きのエラーにする",
+			brand: "ROLEKS",
+			mode:  BrandResolutionStrict,
+			setupMock: func(m *MockBrandRepository) {
+				m.On("FindByKey", mock.Anything, "ROLEKS").Return(nil, domainErrors.ErrBrandNotFound)
+				m.On("FindAll", mock.Anything).Return(catalog, nil)
+			},
+			expectedErrors: entity.ValidationErrors{entity.NotRegisteredError("brand", []string{"ROLEX"})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			mockBrandRepo := new(MockBrandRepository)
+			tt.setupMock(mockBrandRepo)
+			var created *entity.Item
+			if tt.expectedErrors == nil {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).
+					Run(func(args mock.Arguments) { created = args.Get(1).(*entity.Item) }).
+					Return(&entity.Item{ID: 1}, nil)
+			}
+			u := NewItemUsecase(mockRepo, WithBrandCatalog(mockBrandRepo, tt.mode))
+
+			in := input
+			in.Brand = tt.brand
+			_, err := u.CreateItem(context.Background(), in)
+
+			if tt.expectedErrors != nil {
+				assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+				var validationErrs entity.ValidationErrors
+				require.True(t, errors.As(err, &validationErrs))
+				assert.Equal(t, tt.expectedErrors, validationErrs)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.brand, created.Brand)
+				assert.Equal(t, tt.expectedBrandID, created.BrandID)
+				assert.Equal(t, tt.expectedCanonical, created.BrandCanonical)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockBrandRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_ListItems_BrandAlias(t *testing.T) {
+	catalog := testCatalog(t)
+	mockRepo := new(MockItemRepository)
+	mockBrandRepo := new(MockBrandRepository)
+	mockBrandRepo.On("FindByKey", mock.Anything, "ルブタン").Return(catalog[2], nil)
+	resolved := mock.MatchedBy(func(c ItemCriteria) bool {
+		return c.Brand == "ルブタン" && c.BrandCanonical == "CHRISTIAN LOUBOUTIN"
+	})
+	mockRepo.On("CountByCriteria", mock.Anything, resolved).Return(0, nil)
+	mockRepo.On("FindByCriteria", mock.Anything, resolved).Return([]*entity.Item{}, nil)
+	u := NewItemUsecase(mockRepo, WithBrandCatalog(mockBrandRepo, BrandResolutionLenient))
+
+	_, err := u.ListItems(context.Background(), ItemCriteria{Brand: "ルブタン"})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockBrandRepo.AssertExpectations(t)
+}
@@ -0,0 +1,597 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// CategoryInput はカテゴリーの登録・置換の内容。Active を省略した場合は有効として扱い、
+// ParentID を省略した場合は最上位のカテゴリーになる。Attributes は子孫のカテゴリーにも引き継がれる
+type CategoryInput struct {
+	Name          string                       `json:"name"`
+	ParentID      *int64                       `json:"parent_id"`
+	DisplayNameJa string                       `json:"display_name_ja"`
+	DisplayNameEn string                       `json:"display_name_en"`
+	Attributes    []entity.AttributeDefinition `json:"attributes"`
+	SortOrder     int                          `json:"sort_order"`
+	Active        *bool                        `json:"active"`
+}
+
+func (in CategoryInput) active() bool {
+	return in.Active == nil || *in.Active
+}
+
+type CategoryUsecase interface {
+	ListCategories(ctx context.Context) ([]*entity.Category, error)
+	GetCategory(ctx context.Context, id int64) (*entity.Category, error)
+	CreateCategory(ctx context.Context, input CategoryInput) (*entity.Category, error)
+	ReplaceCategory(ctx context.Context, id int64, input CategoryInput) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, id int64, reassignTo string) (*CategoryDeletion, error)
+}
+
+// CategoryDeletion はカテゴリーの削除で移動したアイテムの報告
+type CategoryDeletion struct {
+	ReassignedTo string           `json:"reassigned_to"`
+	Items        []ReassignedItem `json:"items"`
+}
+
+// ReassignedItem は削除したカテゴリーから移動したアイテム。RemovedAttributes は移動先のカテゴリーで定義されていない、
+// または値が移動先の定義に合わないために取り除いた属性のキー
+type ReassignedItem struct {
+	ID                int64    `json:"id"`
+	Version           int64    `json:"version"`
+	RemovedAttributes []string `json:"removed_attributes"`
+}
+
+type categoryUsecase struct {
+	categoryRepo CategoryRepository
+	itemRepo     ItemRepository
+	eventRepo    ItemEventRepository
+	index        ItemIndex
+	registry     *CategoryRegistry
+	tx           Transactor
+}
+
+// CategoryUsecaseOption は categoryUsecase の任意設定
+type CategoryUsecaseOption func(*categoryUsecase)
+
+// WithCategoryAuditTrail は名前の変更や削除でアイテムを移動するたびに、アイテムごとの監査記録を eventRepo へ保存する。
+// 記録は移動と同一トランザクションで実行される
+func WithCategoryAuditTrail(eventRepo ItemEventRepository) CategoryUsecaseOption {
+	return func(u *categoryUsecase) {
+		u.eventRepo = eventRepo
+	}
+}
+
+// WithCategoryItemIndex は名前の変更や削除で移動したアイテムを、変更の確定後に index へ反映する
+func WithCategoryItemIndex(index ItemIndex) CategoryUsecaseOption {
+	return func(u *categoryUsecase) {
+		u.index = index
+	}
+}
+
+// NewCategoryUsecase はカテゴリー管理の usecase を返す。
+// 名前の変更や削除で移動するアイテムは itemRepo で1件ずつ書き込み、
+// 変更が確定するたびに registry を読み込み直す（nil の場合は何もしない）
+func NewCategoryUsecase(categoryRepo CategoryRepository, itemRepo ItemRepository, registry *CategoryRegistry, tx Transactor, opts ...CategoryUsecaseOption) CategoryUsecase {
+	if tx == nil {
+		tx = noopTransactor{}
+	}
+	u := &categoryUsecase{
+		categoryRepo: categoryRepo,
+		itemRepo:     itemRepo,
+		registry:     registry,
+		tx:           tx,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+func (u *categoryUsecase) ListCategories(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := u.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	if categories == nil {
+		categories = []*entity.Category{}
+	}
+
+	return categories, nil
+}
+
+func (u *categoryUsecase) GetCategory(ctx context.Context, id int64) (*entity.Category, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	category, err := u.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrCategoryNotFound) {
+			return nil, domainErrors.ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve category: %w", err)
+	}
+
+	return category, nil
+}
+
+func (u *categoryUsecase) CreateCategory(ctx context.Context, input CategoryInput) (*entity.Category, error) {
+	category, err := entity.NewCategory(input.Name, input.DisplayNameJa, input.DisplayNameEn, input.ParentID, input.Attributes, input.SortOrder, input.active())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+	}
+
+	var created *entity.Category
+	err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+		if category.ParentID != nil {
+			tree, err := u.loadTree(ctx)
+			if err != nil {
+				return err
+			}
+			if err := u.validateParent(ctx, tree, 0, *category.ParentID); err != nil {
+				return err
+			}
+		}
+
+		created, err = u.categoryRepo.Create(ctx, category)
+		if err != nil {
+			return categoryWriteError("create", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+
+	return created, nil
+}
+
+func (u *categoryUsecase) ReplaceCategory(ctx context.Context, id int64, input CategoryInput) (*entity.Category, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	var updated *entity.Category
+	var moved []*entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := u.GetCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+		name := existing.Name
+
+		if err := existing.Update(input.Name, input.DisplayNameJa, input.DisplayNameEn, input.ParentID, input.Attributes, input.SortOrder, input.active()); err != nil {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+		}
+
+		if existing.ParentID != nil {
+			tree, err := u.loadTree(ctx)
+			if err != nil {
+				return err
+			}
+			if err := u.validateParent(ctx, tree, id, *existing.ParentID); err != nil {
+				return err
+			}
+		}
+
+		updated, err = u.categoryRepo.Update(ctx, existing)
+		if err != nil {
+			return categoryWriteError("update", err)
+		}
+		if updated.Name != name {
+			if moved, err = u.moveItems(ctx, name, updated.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+	u.indexItems(moved)
+
+	return updated, nil
+}
+
+// DeleteCategory はカテゴリーを削除する。アイテムが属している場合は reassignTo（アイテムを指定できる別のカテゴリー）へ移動してから削除し、
+// reassignTo が空の場合は ErrInUse を返す。移動するアイテムの属性は移動先のカテゴリーの定義に合うものだけを残し、
+// 移動先で必須の属性を持たないアイテムがある場合は ErrInUse を返す。子カテゴリーを持つカテゴリーは削除できない
+func (u *categoryUsecase) DeleteCategory(ctx context.Context, id int64, reassignTo string) (*CategoryDeletion, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+	reassignTo = entity.NormalizeText(reassignTo)
+
+	deletion := &CategoryDeletion{ReassignedTo: reassignTo, Items: []ReassignedItem{}}
+	var moved []*entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		tree, err := u.loadTree(ctx)
+		if err != nil {
+			return err
+		}
+		category, ok := tree.FindByID(id)
+		if !ok {
+			return domainErrors.ErrCategoryNotFound
+		}
+		if !tree.IsLeaf(id) {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInUse, entity.InUseError("subcategories"))
+		}
+		if reassignTo != "" {
+			if err := validateReassignTarget(tree, id, reassignTo); err != nil {
+				return err
+			}
+			target, _ := tree.FindByName(reassignTo)
+			if moved, deletion.Items, err = u.reassignItems(ctx, category.Name, reassignTo, tree.AttributeSchema(target)); err != nil {
+				return err
+			}
+		}
+
+		if err := u.categoryRepo.Delete(ctx, id); err != nil {
+			return categoryWriteError("delete", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+	u.indexItems(moved)
+
+	return deletion, nil
+}
+
+// moveItems は from に属するアイテム（ゴミ箱にあるものを含む）を属性はそのままに to へ移動し、移動後のアイテムを返す。
+// 呼び出し側でトランザクションを張ること
+func (u *categoryUsecase) moveItems(ctx context.Context, from, to string) ([]*entity.Item, error) {
+	items, err := u.itemRepo.FindByCategory(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	moved := make([]*entity.Item, 0, len(items))
+	for _, before := range items {
+		after := *before
+		after.Category = to
+		updated, err := u.moveItem(ctx, before, &after)
+		if err != nil {
+			return nil, err
+		}
+		moved = append(moved, updated)
+	}
+	return moved, nil
+}
+
+// reassignItems は from に属するアイテム（ゴミ箱にあるものを含む）を to へ移動し、属性は schema（移動先の定義）に合うものだけを残す。
+// 移動後のアイテムと移動の報告を返し、schema で必須の属性を持たないアイテムがある場合は何も移動せずに ErrInUse を返す。
+// 呼び出し側でトランザクションを張ること
+func (u *categoryUsecase) reassignItems(ctx context.Context, from, to string, schema []entity.AttributeDefinition) ([]*entity.Item, []ReassignedItem, error) {
+	items, err := u.itemRepo.FindByCategory(ctx, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+
+	moves := make([]*entity.Item, len(items))
+	removed := make([][]string, len(items))
+	var missing []string
+	for i, before := range items {
+		after := *before
+		after.Category = to
+		after.Attributes, removed[i] = before.Attributes.ConformTo(schema)
+		for _, d := range schema {
+			if _, ok := after.Attributes[d.Key]; d.Required && !ok && !slices.Contains(missing, d.Key) {
+				missing = append(missing, d.Key)
+			}
+		}
+		moves[i] = &after
+	}
+	if len(missing) > 0 {
+		// 理由には最初に見つかった不足している属性を示し、すべてのキーはメッセージに残す
+		return nil, nil, fmt.Errorf("%w: %w: items cannot be moved to %s without its required attributes: %s",
+			domainErrors.ErrInUse, entity.RequiredError("attributes."+missing[0]), to, strings.Join(missing, ", "))
+	}
+
+	moved := make([]*entity.Item, 0, len(items))
+	reassigned := make([]ReassignedItem, 0, len(items))
+	for i, before := range items {
+		updated, err := u.moveItem(ctx, before, moves[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		moved = append(moved, updated)
+		reassigned = append(reassigned, ReassignedItem{ID: updated.ID, Version: updated.Version, RemovedAttributes: removed[i]})
+	}
+	return moved, reassigned, nil
+}
+
+// moveItem は before を after のカテゴリーと属性に書き換えて監査記録を残し、書き換え後のアイテムを返す
+func (u *categoryUsecase) moveItem(ctx context.Context, before, after *entity.Item) (*entity.Item, error) {
+	updated, err := u.itemRepo.Recategorize(ctx, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move item %d: %w", before.ID, err)
+	}
+	if err := recordItemEvent(ctx, u.eventRepo, entity.ItemEventUpdate, updated.ID, before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// 変更の確定後に、移動したアイテムを索引へ反映する（ゴミ箱にあるアイテムは Put が索引に含めない）
+func (u *categoryUsecase) indexItems(items []*entity.Item) {
+	if u.index == nil {
+		return
+	}
+	for _, item := range items {
+		u.index.Put(item)
+	}
+}
+
+func (u *categoryUsecase) loadTree(ctx context.Context) (*entity.CategoryTree, error) {
+	categories, err := u.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	return entity.NewCategoryTree(categories), nil
+}
+
+// 親は登録済みで、id のカテゴリー自身やその子孫ではないこと（id は新規登録の場合 0）。
+// アイテムは葉にのみ属するため、アイテムが属しているカテゴリーは親にできない
+func (u *categoryUsecase) validateParent(ctx context.Context, tree *entity.CategoryTree, id, parentID int64) error {
+	parent, ok := tree.FindByID(parentID)
+	if !ok {
+		return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput,
+			entity.ValidationErrors{entity.NotRegisteredError("parent_id", nil)})
+	}
+	if id != 0 && (parentID == id || tree.IsDescendant(parentID, id)) {
+		return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput,
+			entity.ValidationErrors{entity.CircularReferenceError("parent_id")})
+	}
+
+	count, err := u.categoryRepo.CountItems(ctx, parent.Name)
+	if err != nil {
+		return fmt.Errorf("failed to count items: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %w", domainErrors.ErrInUse, entity.InUseError("items"))
+	}
+	return nil
+}
+
+// 移動先は削除するカテゴリー以外の、アイテムを指定できるカテゴリーであること
+func validateReassignTarget(tree *entity.CategoryTree, id int64, reassignTo string) error {
+	allowed := []string{}
+	for _, name := range tree.AssignableNames() {
+		if category, _ := tree.FindByName(name); category.ID != id {
+			allowed = append(allowed, name)
+		}
+	}
+	if slices.Contains(allowed, reassignTo) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput,
+		entity.ValidationErrors{entity.InvalidEnumError("reassign_to", allowed)})
+}
+
+// 変更の確定後にキャッシュを読み込み直す。
+// 失敗しても変更自体は確定しているため、エラーは返さず定期的な読み込み直し（scheduler.CategoryRefresher）に任せる
+func (u *categoryUsecase) reload(ctx context.Context) {
+	if u.registry == nil {
+		return
+	}
+	_ = u.registry.Load(ctx)
+}
+
+// 書き込み時のエラーのうち、クライアントに意味のあるもの（存在しない・重複・使用中）はそのまま返す
+func categoryWriteError(action string, err error) error {
+	switch {
+	case errors.Is(err, domainErrors.ErrCategoryNotFound):
+		return domainErrors.ErrCategoryNotFound
+	case errors.Is(err, domainErrors.ErrDuplicateEntry):
+		return fmt.Errorf("%w: category name is already registered", domainErrors.ErrDuplicateEntry)
+	case errors.Is(err, domainErrors.ErrInUse):
+		return fmt.Errorf("%w: %w", domainErrors.ErrInUse, entity.InUseError("items"))
+	default:
+		return fmt.Errorf("failed to %s category: %w", action, err)
+	}
+}
+
+// 子を持つカテゴリーで絞り込む場合は、子孫のカテゴリーに属するアイテムも対象にする
+func (u *itemUsecase) resolveCriteriaCategory(criteria ItemCriteria) ItemCriteria {
+	criteria.Categories = nil
+	if u.registry == nil || criteria.Category == "" {
+		return criteria
+	}
+
+	tree := u.registry.Tree()
+	category, ok := tree.FindByName(entity.NormalizeText(criteria.Category))
+	if !ok || tree.IsLeaf(category.ID) {
+		return criteria
+	}
+	criteria.Categories = []string{category.Name}
+	for _, descendant := range tree.Descendants(category.ID) {
+		criteria.Categories = append(criteria.Categories, descendant.Name)
+	}
+	return criteria
+}
+
+// 属性での絞り込みの値を、カテゴリーの属性の定義の型で解釈してアイテムの値と同じ形式にそろえる。
+// どのカテゴリーにも定義されていないキーや、型に合わない値は ErrInvalidInput
+func (u *itemUsecase) resolveCriteriaAttributes(criteria ItemCriteria) (ItemCriteria, error) {
+	if len(criteria.Attributes) == 0 || u.registry == nil {
+		return criteria, nil
+	}
+
+	types := u.registry.attributeTypes(criteria.Category)
+	attributes := make(map[string]string, len(criteria.Attributes))
+	for key, raw := range criteria.Attributes {
+		t, ok := types[key]
+		if !ok {
+			return criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.UnknownFieldError("attr."+key))
+		}
+		value, err := entity.ParseAttributeValue(t, raw)
+		if err != nil {
+			return criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidTypeError("attr."+key, t))
+		}
+		attributes[key] = value
+	}
+	criteria.Attributes = attributes
+	return criteria, nil
+}
+
+// summarizeByTree はカテゴリーごとの件数 counts を tree の祖先へ積み上げる。
+// 有効なカテゴリーは 0 件でも含め、無効なカテゴリーや tree に無いカテゴリーはアイテムがある場合のみ含める
+func summarizeByTree(tree *entity.CategoryTree, counts map[string]int, total int) *CategorySummary {
+	summary := &CategorySummary{
+		Categories: make(map[string]int),
+		Tree:       []*CategorySummaryNode{},
+		Total:      total,
+	}
+
+	var build func(categories []*entity.Category) []*CategorySummaryNode
+	build = func(categories []*entity.Category) []*CategorySummaryNode {
+		var nodes []*CategorySummaryNode
+		for _, category := range categories {
+			node := &CategorySummaryNode{
+				Name:     category.Name,
+				Count:    counts[category.Name],
+				Children: build(tree.Children(category.ID)),
+			}
+			node.Total = node.Count
+			for _, child := range node.Children {
+				node.Total += child.Total
+			}
+			if !category.Active && node.Total == 0 {
+				continue
+			}
+			summary.Categories[node.Name] = node.Total
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	if roots := build(tree.Roots()); roots != nil {
+		summary.Tree = roots
+	}
+
+	for name, count := range counts {
+		if _, ok := tree.FindByName(name); !ok {
+			summary.Categories[name] = count
+		}
+	}
+	return summary
+}
+
+// CategoryRegistry はカテゴリーテーブルの内容を保持するキャッシュで、entity.CategoryLookup を実装する。
+// アイテムのバリデーションのたびにデータベースを参照しないよう、カテゴリーの変更時と一定間隔で Load により読み込み直す
+type CategoryRegistry struct {
+	repo CategoryRepository
+
+	mu         sync.RWMutex
+	categories []*entity.Category
+	tree       *entity.CategoryTree
+	assignable []string
+}
+
+// NewCategoryRegistry は repo を読み込むキャッシュを返す。使用前に Load を呼ぶこと
+func NewCategoryRegistry(repo CategoryRepository) *CategoryRegistry {
+	return &CategoryRegistry{
+		repo:       repo,
+		tree:       entity.NewCategoryTree(nil),
+		assignable: []string{},
+	}
+}
+
+// Load はカテゴリーを読み込み直す。失敗した場合は以前の内容を保持する
+func (r *CategoryRegistry) Load(ctx context.Context) error {
+	categories, err := r.repo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load categories: %w", err)
+	}
+	tree := entity.NewCategoryTree(categories)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categories = categories
+	r.tree = tree
+	r.assignable = tree.AssignableNames()
+	return nil
+}
+
+// Categories は読み込んだすべてのカテゴリー（無効なものを含む）を表示順で返す
+func (r *CategoryRegistry) Categories() []*entity.Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := make([]*entity.Category, len(r.categories))
+	for i, category := range r.categories {
+		c := *category
+		categories[i] = &c
+	}
+	return categories
+}
+
+// Tree は読み込んだカテゴリーの木を返す。返した木は読み込み直しても変わらないため、変更しないこと
+func (r *CategoryRegistry) Tree() *entity.CategoryTree {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tree
+}
+
+// IsValidCategory は name がアイテムに指定できるカテゴリーかどうかを返す
+func (r *CategoryRegistry) IsValidCategory(name string) bool {
+	tree := r.Tree()
+	category, ok := tree.FindByName(name)
+	return ok && tree.IsAssignable(category)
+}
+
+// CategoryNames はアイテムに指定できるカテゴリーの名前を表示順で返す
+func (r *CategoryRegistry) CategoryNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string{}, r.assignable...)
+}
+
+// AttributeSchema は name のカテゴリーに属するアイテムが持てる属性の定義（祖先から引き継いだものを含む）を返す
+func (r *CategoryRegistry) AttributeSchema(name string) []entity.AttributeDefinition {
+	tree := r.Tree()
+	category, ok := tree.FindByName(name)
+	if !ok {
+		return nil
+	}
+	return tree.AttributeSchema(category)
+}
+
+// attributeTypes は属性のキーごとの型を返す。category が空の場合は全カテゴリーの定義を対象にし、
+// category に子孫がある場合は子孫のカテゴリーの定義も含める（同じキーで型が異なる場合は先に見つけたもの）
+func (r *CategoryRegistry) attributeTypes(category string) map[string]entity.AttributeType {
+	tree := r.Tree()
+	var targets []*entity.Category
+	if c, ok := tree.FindByName(entity.NormalizeText(category)); ok {
+		targets = append([]*entity.Category{c}, tree.Descendants(c.ID)...)
+	} else {
+		for _, root := range tree.Roots() {
+			targets = append(targets, root)
+			targets = append(targets, tree.Descendants(root.ID)...)
+		}
+	}
+
+	types := map[string]entity.AttributeType{}
+	for _, c := range targets {
+		for _, d := range tree.AttributeSchema(c) {
+			if _, ok := types[d.Key]; !ok {
+				types[d.Key] = d.Type
+			}
+		}
+	}
+	return types
+}
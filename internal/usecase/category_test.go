@@ -0,0 +1,778 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// MockCategoryRepository はtestify/mockを使用したカテゴリーのモックリポジトリ
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) FindAll(ctx context.Context) ([]*entity.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id int64) (*entity.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) CountItems(ctx context.Context, name string) (int, error) {
+	args := m.Called(ctx, name)
+	return args.Int(0), args.Error(1)
+}
+
+func testCategories() []*entity.Category {
+	return []*entity.Category{
+		{ID: 1, Name: "時計", DisplayNameJa: "時計", DisplayNameEn: "Watches", SortOrder: 10, Active: true},
+		{ID: 2, Name: "アート", DisplayNameJa: "アート", DisplayNameEn: "Art", SortOrder: 20, Active: true},
+		{ID: 3, Name: "車", DisplayNameJa: "車", DisplayNameEn: "Cars", SortOrder: 30, Active: false},
+	}
+}
+
+// 時計(1) の下に腕時計(4)・懐中時計(5) を持つカテゴリー
+func testCategoryTree() []*entity.Category {
+	watches := int64(1)
+	return append(testCategories(),
+		&entity.Category{ID: 4, Name: "腕時計", ParentID: &watches, DisplayNameJa: "腕時計", DisplayNameEn: "Wristwatches", SortOrder: 10, Active: true},
+		&entity.Category{ID: 5, Name: "懐中時計", ParentID: &watches, DisplayNameJa: "懐中時計", DisplayNameEn: "Pocket watches", SortOrder: 20, Active: true},
+	)
+}
+
+func TestCategoryRegistry(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockCategoryRepository)
+	mockRepo.On("FindAll", mock.Anything).Return(testCategories(), nil).Once()
+	mockRepo.On("FindAll", mock.Anything).Return(nil, domainErrors.ErrDatabaseError).Once()
+
+	registry := NewCategoryRegistry(mockRepo)
+	assert.False(t, registry.IsValidCategory("時計"), "読み込み前は有効なカテゴリーが無い")
+
+	require.NoError(t, registry.Load(ctx))
+	assert.True(t, registry.IsValidCategory("アート"))
+	assert.False(t, registry.IsValidCategory("車"), "無効なカテゴリーは指定できない")
+	assert.False(t, registry.IsValidCategory("バッグ"))
+	assert.Equal(t, []string{"時計", "アート"}, registry.CategoryNames())
+	assert.Len(t, registry.Categories(), 3)
+
+	// 読み込みに失敗した場合は以前の内容を使い続ける
+	assert.ErrorIs(t, registry.Load(ctx), domainErrors.ErrDatabaseError)
+	assert.Equal(t, []string{"時計", "アート"}, registry.CategoryNames())
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCategoryUsecase_CreateCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          CategoryInput
+		setupMock      func(*MockCategoryRepository)
+		expectedActive bool
+		expectedErr    error
+	}{
+		{
+			name:  "正常系: active を省略すると有効",
+			input: CategoryInput{Name: "アート", DisplayNameJa: "アート", DisplayNameEn: "Art", SortOrder: 60},
+			setupMock: func(m *MockCategoryRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(c *entity.Category) bool {
+					return c.Name == "アート" && c.Active
+				})).Return(&entity.Category{ID: 6, Name: "アート", Active: true}, nil)
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+			},
+			expectedActive: true,
+		},
+		{
+			name:        "異常系: 無効な入力",
+			input:       CategoryInput{Name: "アート"},
+			setupMock:   func(m *MockCategoryRepository) {},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:  "異常系: 名前が重複",
+			input: CategoryInput{Name: "時計", DisplayNameJa: "時計", DisplayNameEn: "Watches"},
+			setupMock: func(m *MockCategoryRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDuplicateEntry)
+			},
+			expectedErr: domainErrors.ErrDuplicateEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCategoryRepository)
+			tt.setupMock(mockRepo)
+			registry := NewCategoryRegistry(mockRepo)
+			u := NewCategoryUsecase(mockRepo, new(MockItemRepository), registry, nil)
+
+			category, err := u.CreateCategory(context.Background(), tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, category)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedActive, category.Active)
+				// 作成後にキャッシュを読み込み直す
+				assert.True(t, registry.IsValidCategory("アート"))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUsecase_CreateCategory_Parent(t *testing.T) {
+	parentID := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name           string
+		parentID       *int64
+		setupMock      func(*MockCategoryRepository)
+		expectedErr    error
+		expectedFields entity.ValidationErrors
+	}{
+		{
+			name:     "正常系: アイテムの無いカテゴリーの子として登録",
+			parentID: parentID(1),
+			setupMock: func(m *MockCategoryRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+				m.On("CountItems", mock.Anything, "時計").Return(0, nil)
+				m.On("Create", mock.Anything, mock.MatchedBy(func(c *entity.Category) bool {
+					return c.ParentID != nil && *c.ParentID == 1
+				})).Return(&entity.Category{ID: 6, Name: "置時計", ParentID: parentID(1), Active: true}, nil)
+			},
+		},
+		{
+			name:     "異常系: 親カテゴリーが存在しない",
+			parentID: parentID(99),
+			setupMock: func(m *MockCategoryRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+			},
+			expectedErr:    domainErrors.ErrInvalidInput,
+			expectedFields: entity.ValidationErrors{entity.NotRegisteredError("parent_id", nil)},
+		},
+		{
+			name:     "異常系: 親カテゴリーにアイテムが属している",
+			parentID: parentID(2),
+			setupMock: func(m *MockCategoryRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+				m.On("CountItems", mock.Anything, "アート").Return(3, nil)
+			},
+			expectedErr: domainErrors.ErrInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCategoryRepository)
+			tt.setupMock(mockRepo)
+			u := NewCategoryUsecase(mockRepo, new(MockItemRepository), nil, nil)
+
+			category, err := u.CreateCategory(context.Background(), CategoryInput{
+				Name: "置時計", DisplayNameJa: "置時計", DisplayNameEn: "Clocks", ParentID: tt.parentID,
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, category)
+				if tt.expectedFields != nil {
+					var validationErrs entity.ValidationErrors
+					require.True(t, errors.As(err, &validationErrs))
+					assert.Equal(t, tt.expectedFields, validationErrs)
+				}
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), *category.ParentID)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUsecase_ReplaceCategory_CircularParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int64
+		parentID int64
+	}{
+		{name: "異常系: 自身を親にする", id: 1, parentID: 1},
+		{name: "異常系: 子孫を親にする", id: 1, parentID: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCategoryRepository)
+			mockRepo.On("FindByID", mock.Anything, tt.id).Return(testCategoryTree()[0], nil)
+			mockRepo.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+			u := NewCategoryUsecase(mockRepo, new(MockItemRepository), nil, nil)
+
+			_, err := u.ReplaceCategory(context.Background(), tt.id, CategoryInput{
+				Name: "時計", DisplayNameJa: "時計", DisplayNameEn: "Watches", ParentID: &tt.parentID,
+			})
+
+			assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+			var validationErrs entity.ValidationErrors
+			require.True(t, errors.As(err, &validationErrs))
+			assert.Equal(t, entity.ValidationErrors{entity.CircularReferenceError("parent_id")}, validationErrs)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUsecase_DeleteCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             int64
+		reassignTo     string
+		setupMock      func(*MockCategoryRepository, *MockItemRepository)
+		expectedErr    error
+		expectedFields entity.ValidationErrors
+	}{
+		{
+			name:       "正常系: アイテムを移動して削除",
+			id:         2,
+			reassignTo: " 時計 ",
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+				items.On("FindByCategory", mock.Anything, "アート").Return([]*entity.Item{}, nil)
+				m.On("Delete", mock.Anything, int64(2)).Return(nil)
+			},
+		},
+		{
+			name: "異常系: アイテムが属している",
+			id:   2,
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+				m.On("Delete", mock.Anything, int64(2)).Return(domainErrors.ErrInUse)
+			},
+			expectedErr: domainErrors.ErrInUse,
+		},
+		{
+			name:       "異常系: 移動先が無効なカテゴリー",
+			id:         2,
+			reassignTo: "車",
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+			},
+			expectedErr:    domainErrors.ErrInvalidInput,
+			expectedFields: entity.ValidationErrors{entity.InvalidEnumError("reassign_to", []string{"時計"})},
+		},
+		{
+			name:       "異常系: 移動先が削除するカテゴリー自身",
+			id:         2,
+			reassignTo: "アート",
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+			},
+			expectedErr:    domainErrors.ErrInvalidInput,
+			expectedFields: entity.ValidationErrors{entity.InvalidEnumError("reassign_to", []string{"時計"})},
+		},
+		{
+			name:       "異常系: 存在しないカテゴリー",
+			id:         99,
+			reassignTo: "時計",
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategories(), nil)
+			},
+			expectedErr: domainErrors.ErrCategoryNotFound,
+		},
+		{
+			name: "異常系: 子カテゴリーを持つ",
+			id:   1,
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+			},
+			expectedErr: domainErrors.ErrInUse,
+		},
+		{
+			name:       "異常系: 移動先が子カテゴリーを持つ",
+			id:         2,
+			reassignTo: "時計",
+			setupMock: func(m *MockCategoryRepository, items *MockItemRepository) {
+				m.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+			},
+			expectedErr:    domainErrors.ErrInvalidInput,
+			expectedFields: entity.ValidationErrors{entity.InvalidEnumError("reassign_to", []string{"腕時計", "懐中時計"})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCategoryRepository)
+			itemRepo := new(MockItemRepository)
+			tt.setupMock(mockRepo, itemRepo)
+			u := NewCategoryUsecase(mockRepo, itemRepo, nil, nil)
+
+			_, err := u.DeleteCategory(context.Background(), tt.id, tt.reassignTo)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				if tt.expectedFields != nil {
+					var validationErrs entity.ValidationErrors
+					require.True(t, errors.As(err, &validationErrs))
+					assert.Equal(t, tt.expectedFields, validationErrs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+			itemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUsecase_MoveItems(t *testing.T) {
+	// アートに属するアイテム2件（1件はゴミ箱にある）を時計へ移動する
+	newArtItems := func() []*entity.Item {
+		deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		return []*entity.Item{
+			{ID: 10, Name: "版画", Category: "アート", Brand: "HERMES", PurchasePrice: 100000, PurchaseDate: "2023-01-15", Version: 1},
+			{ID: 11, Name: "油絵", Category: "アート", Brand: "HERMES", PurchasePrice: 200000, PurchaseDate: "2023-01-15", Version: 3, DeletedAt: &deletedAt},
+		}
+	}
+	setupItems := func(itemRepo *MockItemRepository, to string) {
+		itemRepo.On("FindByCategory", mock.Anything, "アート").Return(newArtItems(), nil)
+		for _, item := range newArtItems() {
+			moved := *item
+			moved.Category, moved.Version = to, item.Version+1
+			itemRepo.On("Recategorize", mock.Anything, mock.MatchedBy(func(in *entity.Item) bool { return in.ID == item.ID && in.Category == to })).
+				Return(&moved, nil)
+		}
+	}
+	expectEvents := func(eventRepo *MockItemEventRepository, to string) {
+		for _, id := range []int64{10, 11} {
+			eventRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *entity.ItemEvent) bool {
+				return e.ItemID == id &&
+					e.Action == entity.ItemEventUpdate &&
+					e.Actor == "tanaka" &&
+					len(e.Changes) == 1 &&
+					e.Changes["category"] == entity.FieldChange{Before: "アート", After: to}
+			})).Return(nil).Once()
+		}
+	}
+
+	t.Run("正常系: 名前の変更で移動したアイテムごとに監査記録を残し、確定後に索引へ反映する", func(t *testing.T) {
+		categoryRepo := new(MockCategoryRepository)
+		categoryRepo.On("FindByID", mock.Anything, int64(2)).Return(testCategories()[1], nil)
+		categoryRepo.On("Update", mock.Anything, mock.AnythingOfType("*entity.Category")).
+			Return(&entity.Category{ID: 2, Name: "美術品", DisplayNameJa: "美術品", DisplayNameEn: "Art", SortOrder: 20, Active: true}, nil)
+		itemRepo := new(MockItemRepository)
+		setupItems(itemRepo, "美術品")
+		eventRepo := new(MockItemEventRepository)
+		expectEvents(eventRepo, "美術品")
+		tx := &recordingTransactor{}
+
+		index := new(MockItemIndex)
+		index.On("Put", mock.MatchedBy(func(item *entity.Item) bool { return item.Category == "美術品" })).Twice()
+
+		u := NewCategoryUsecase(categoryRepo, itemRepo, nil, tx, WithCategoryAuditTrail(eventRepo), WithCategoryItemIndex(index))
+		_, err := u.ReplaceCategory(auditContext(), 2, CategoryInput{Name: "美術品", DisplayNameJa: "美術品", DisplayNameEn: "Art", SortOrder: 20})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		itemRepo.AssertNumberOfCalls(t, "Recategorize", 2)
+		eventRepo.AssertExpectations(t)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 削除時の付け替えで移動したアイテムごとに監査記録を残し、確定後に索引へ反映する", func(t *testing.T) {
+		categoryRepo := new(MockCategoryRepository)
+		categoryRepo.On("FindAll", mock.Anything).Return(testCategories(), nil)
+		categoryRepo.On("Delete", mock.Anything, int64(2)).Return(nil)
+		itemRepo := new(MockItemRepository)
+		setupItems(itemRepo, "時計")
+		eventRepo := new(MockItemEventRepository)
+		expectEvents(eventRepo, "時計")
+		tx := &recordingTransactor{}
+
+		index := new(MockItemIndex)
+		index.On("Put", mock.MatchedBy(func(item *entity.Item) bool { return item.Category == "時計" })).Twice()
+
+		u := NewCategoryUsecase(categoryRepo, itemRepo, nil, tx, WithCategoryAuditTrail(eventRepo), WithCategoryItemIndex(index))
+		_, err := u.DeleteCategory(auditContext(), 2, "時計")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		itemRepo.AssertNumberOfCalls(t, "Recategorize", 2)
+		eventRepo.AssertExpectations(t)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 監査記録の保存に失敗した場合は移動も削除も索引の更新もしない", func(t *testing.T) {
+		categoryRepo := new(MockCategoryRepository)
+		categoryRepo.On("FindAll", mock.Anything).Return(testCategories(), nil)
+		itemRepo := new(MockItemRepository)
+		setupItems(itemRepo, "時計")
+		eventRepo := new(MockItemEventRepository)
+		eventRepo.On("Append", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+		tx := &recordingTransactor{}
+
+		index := new(MockItemIndex)
+
+		u := NewCategoryUsecase(categoryRepo, itemRepo, nil, tx, WithCategoryAuditTrail(eventRepo), WithCategoryItemIndex(index))
+		_, err := u.DeleteCategory(auditContext(), 2, "時計")
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+		assert.Equal(t, 1, tx.rolledBack)
+		categoryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCategoryUsecase_DeleteCategory_Attributes(t *testing.T) {
+	// 腕時計(4) に属するアイテム。band は腕時計だけ、movement と case_size は時計から引き継いだ属性
+	wristwatches := func() []*entity.Item {
+		return []*entity.Item{
+			{ID: 10, Name: "デイトナ", Category: "腕時計", Version: 1, Attributes: entity.Attributes{"movement": "自動巻き", "band": "オイスター"}},
+			{ID: 11, Name: "ノーチラス", Category: "腕時計", Version: 2},
+		}
+	}
+	// 懐中時計で movement を必須にしたカテゴリー
+	requiredMovement := func() []*entity.Category {
+		categories := testAttributeCategories()
+		categories[4].Attributes = []entity.AttributeDefinition{{Key: "movement", Type: entity.AttributeTypeString, Required: true}}
+		return categories
+	}
+
+	tests := []struct {
+		name        string
+		categories  []*entity.Category
+		reassignTo  string
+		expected    map[int64]entity.Attributes
+		expectedErr error
+		// expectedErr の理由として返すバリデーションエラーのフィールド
+		expectedField string
+		expectedRes   []ReassignedItem
+	}{
+		{
+			name:       "正常系: 移動先で定義されていない属性を取り除いて報告する",
+			categories: testAttributeCategories(),
+			reassignTo: "懐中時計",
+			expected:   map[int64]entity.Attributes{10: {"movement": "自動巻き"}, 11: nil},
+			expectedRes: []ReassignedItem{
+				{ID: 10, Version: 2, RemovedAttributes: []string{"band"}},
+				{ID: 11, Version: 3, RemovedAttributes: []string{}},
+			},
+		},
+		{
+			name:       "正常系: 属性を定義していないカテゴリーへの移動ではすべて取り除く",
+			categories: testAttributeCategories(),
+			reassignTo: "アート",
+			expected:   map[int64]entity.Attributes{10: nil, 11: nil},
+			expectedRes: []ReassignedItem{
+				{ID: 10, Version: 2, RemovedAttributes: []string{"band", "movement"}},
+				{ID: 11, Version: 3, RemovedAttributes: []string{}},
+			},
+		},
+		{
+			name:          "異常系: 移動先で必須の属性を持たないアイテムがある",
+			categories:    requiredMovement(),
+			reassignTo:    "懐中時計",
+			expectedErr:   domainErrors.ErrInUse,
+			expectedField: "attributes.movement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryRepo := new(MockCategoryRepository)
+			categoryRepo.On("FindAll", mock.Anything).Return(tt.categories, nil)
+			categoryRepo.On("Delete", mock.Anything, int64(4)).Return(nil)
+			itemRepo := new(MockItemRepository)
+			itemRepo.On("FindByCategory", mock.Anything, "腕時計").Return(wristwatches(), nil)
+			for id, attributes := range tt.expected {
+				moved := &entity.Item{ID: id, Category: tt.reassignTo, Attributes: attributes, Version: id - 8}
+				itemRepo.On("Recategorize", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+					return item.ID == id && item.Category == tt.reassignTo && assert.ObjectsAreEqual(attributes, item.Attributes)
+				})).Return(moved, nil).Once()
+			}
+
+			u := NewCategoryUsecase(categoryRepo, itemRepo, nil, nil)
+			deletion, err := u.DeleteCategory(context.Background(), 4, tt.reassignTo)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				var reason entity.FieldError
+				require.ErrorAs(t, err, &reason)
+				assert.Equal(t, tt.expectedField, reason.Field)
+				assert.Equal(t, entity.ValidationRequired, reason.Code)
+				itemRepo.AssertNotCalled(t, "Recategorize", mock.Anything, mock.Anything)
+				categoryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.reassignTo, deletion.ReassignedTo)
+			assert.Equal(t, tt.expectedRes, deletion.Items)
+			itemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_GetCategorySummary_Registry(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCategoryRepo.On("FindAll", mock.Anything).Return(testCategories(), nil)
+	registry := NewCategoryRegistry(mockCategoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	mockRepo := new(MockItemRepository)
+	mockRepo.On("GetSummaryByCategory", mock.Anything).Return(map[string]int{"時計": 2, "車": 1}, nil)
+	u := NewItemUsecase(mockRepo, WithCategoryRegistry(registry))
+
+	summary, err := u.GetCategorySummary(context.Background())
+
+	require.NoError(t, err)
+	// 有効なカテゴリーは 0 件でも含め、無効にしたカテゴリーもアイテムがあれば含める
+	assert.Equal(t, map[string]int{"時計": 2, "アート": 0, "車": 1}, summary.Categories)
+	assert.Equal(t, 3, summary.Total)
+}
+
+func TestItemUsecase_GetCategorySummary_Tree(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCategoryRepo.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+	registry := NewCategoryRegistry(mockCategoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	mockRepo := new(MockItemRepository)
+	mockRepo.On("GetSummaryByCategory", mock.Anything).Return(map[string]int{"腕時計": 2, "懐中時計": 1, "家電": 1}, nil)
+	u := NewItemUsecase(mockRepo, WithCategoryRegistry(registry))
+
+	summary, err := u.GetCategorySummary(context.Background())
+
+	require.NoError(t, err)
+	// 親のカテゴリーには子孫の件数を積み上げ、カテゴリーに無い名前も件数があれば含める
+	assert.Equal(t, map[string]int{"時計": 3, "腕時計": 2, "懐中時計": 1, "アート": 0, "家電": 1}, summary.Categories)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, []*CategorySummaryNode{
+		{Name: "時計", Count: 0, Total: 3, Children: []*CategorySummaryNode{
+			{Name: "腕時計", Count: 2, Total: 2},
+			{Name: "懐中時計", Count: 1, Total: 1},
+		}},
+		{Name: "アート", Count: 0, Total: 0},
+	}, summary.Tree)
+}
+
+func TestItemUsecase_ListItems_CategoryDescendants(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCategoryRepo.On("FindAll", mock.Anything).Return(testCategoryTree(), nil)
+	registry := NewCategoryRegistry(mockCategoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	tests := []struct {
+		name       string
+		category   string
+		categories []string
+	}{
+		{name: "正常系: 親カテゴリーは子孫を含む", category: "時計", categories: []string{"時計", "腕時計", "懐中時計"}},
+		{name: "正常系: 子を持たないカテゴリーはそのまま", category: "腕時計"},
+		{name: "正常系: 登録されていないカテゴリーはそのまま", category: "家電"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			matcher := mock.MatchedBy(func(c ItemCriteria) bool {
+				return c.Category == tt.category && assert.ObjectsAreEqual(tt.categories, c.Categories)
+			})
+			mockRepo.On("CountByCriteria", mock.Anything, matcher).Return(0, nil)
+			mockRepo.On("FindByCriteria", mock.Anything, matcher).Return([]*entity.Item{}, nil)
+			u := NewItemUsecase(mockRepo, WithCategoryRegistry(registry))
+
+			_, err := u.ListItems(context.Background(), ItemCriteria{Category: tt.category})
+
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// testCategoryTree の時計に属性を定義し、腕時計で属性を追加したカテゴリー
+func testAttributeCategories() []*entity.Category {
+	categories := testCategoryTree()
+	categories[0].Attributes = []entity.AttributeDefinition{
+		{Key: "movement", Type: entity.AttributeTypeEnum, Options: []string{"自動巻き", "手巻き", "クォーツ"}},
+		{Key: "case_size", Type: entity.AttributeTypeNumber, Unit: "mm"},
+	}
+	categories[3].Attributes = []entity.AttributeDefinition{
+		{Key: "band", Type: entity.AttributeTypeString},
+	}
+	return categories
+}
+
+func TestCategoryRegistry_AttributeSchema(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	mockRepo.On("FindAll", mock.Anything).Return(testAttributeCategories(), nil)
+	registry := NewCategoryRegistry(mockRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	keys := func(schema []entity.AttributeDefinition) []string {
+		result := []string{}
+		for _, d := range schema {
+			result = append(result, d.Key)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"movement", "case_size", "band"}, keys(registry.AttributeSchema("腕時計")), "祖先の定義を引き継ぐ")
+	assert.Equal(t, []string{"movement", "case_size"}, keys(registry.AttributeSchema("懐中時計")))
+	assert.Empty(t, registry.AttributeSchema("アート"))
+	assert.Empty(t, registry.AttributeSchema("家電"))
+}
+
+func TestItemUsecase_UpdateItemPartially_Attributes(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCategoryRepo.On("FindAll", mock.Anything).Return(testAttributeCategories(), nil)
+	registry := NewCategoryRegistry(mockCategoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	tests := []struct {
+		name        string
+		input       UpdateItemInput
+		expected    entity.Attributes
+		expectedErr error
+	}{
+		{
+			name: "正常系: キーごとに上書きし、null のキーは削除する",
+			input: UpdateItemInput{Attributes: Set(entity.Attributes{
+				"case_size": nil,
+				"band":      "レザー",
+			})},
+			expected: entity.Attributes{"movement": "自動巻き", "band": "レザー"},
+		},
+		{
+			name:     "正常系: null はすべての属性を削除する",
+			input:    UpdateItemInput{Attributes: Null[entity.Attributes]()},
+			expected: nil,
+		},
+		{
+			name:        "異常系: 定義されていない属性",
+			input:       UpdateItemInput{Attributes: Set(entity.Attributes{"color": "黒"})},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := entity.NewItem("ロレックス サブマリーナ", "腕時計", "ROLEX", 1000000, "2023-01-15",
+				entity.Attributes{"movement": "自動巻き", "case_size": 40.0}, "", registry)
+			require.NoError(t, err)
+			existing.ID = 1
+			original := existing.Attributes
+
+			mockRepo := new(MockItemRepository)
+			mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item"), []entity.ItemField{entity.ItemFieldAttributes}).
+				Return(existing, nil).Maybe()
+			u := NewItemUsecase(mockRepo, WithCategoryRegistry(registry))
+
+			updated, err := u.UpdateItemPartially(context.Background(), 1, tt.input, []int64{AnyVersion})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, updated.Attributes)
+			assert.Equal(t, entity.Attributes{"movement": "自動巻き", "case_size": 40.0}, original, "変更前の属性は書き換えない")
+		})
+	}
+}
+
+func TestItemUsecase_ListItems_Attributes(t *testing.T) {
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockCategoryRepo.On("FindAll", mock.Anything).Return(testAttributeCategories(), nil)
+	registry := NewCategoryRegistry(mockCategoryRepo)
+	require.NoError(t, registry.Load(context.Background()))
+
+	tests := []struct {
+		name        string
+		criteria    ItemCriteria
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			name:     "正常系: 値を属性の型に合わせた形式にそろえる",
+			criteria: ItemCriteria{Attributes: map[string]string{"case_size": "40.0", "movement": " 自動巻き "}},
+			expected: map[string]string{"case_size": "40", "movement": "自動巻き"},
+		},
+		{
+			name:     "正常系: 子孫のカテゴリーで定義された属性",
+			criteria: ItemCriteria{Category: "時計", Attributes: map[string]string{"band": "レザー"}},
+			expected: map[string]string{"band": "レザー"},
+		},
+		{
+			name:     "正常系: 値が空の条件は無視する",
+			criteria: ItemCriteria{Attributes: map[string]string{"band": ""}},
+		},
+		{
+			name:        "異常系: 定義されていない属性",
+			criteria:    ItemCriteria{Category: "懐中時計", Attributes: map[string]string{"band": "レザー"}},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: 型に合わない値",
+			criteria:    ItemCriteria{Attributes: map[string]string{"case_size": "40mm"}},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			matcher := mock.MatchedBy(func(c ItemCriteria) bool {
+				return assert.ObjectsAreEqual(tt.expected, c.Attributes)
+			})
+			mockRepo.On("CountByCriteria", mock.Anything, matcher).Return(0, nil).Maybe()
+			mockRepo.On("FindByCriteria", mock.Anything, matcher).Return([]*entity.Item{}, nil).Maybe()
+			u := NewItemUsecase(mockRepo, WithCategoryRegistry(registry))
+
+			_, err := u.ListItems(context.Background(), tt.criteria)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
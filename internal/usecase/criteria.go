@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 並び替えに使用できるキー
+const (
+	SortCreatedAt     = "created_at"
+	SortPurchaseDate  = "purchase_date"
+	SortPurchasePrice = "purchase_price"
+	SortName          = "name"
+	SortDeletedAt     = "deleted_at" // ゴミ箱の一覧でのみ使用可能
+)
+
+// 並び順
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// タグでの絞り込みの一致条件
+const (
+	TagMatchAll = "all" // すべてのタグを持つアイテム（AND）
+	TagMatchAny = "any" // いずれかのタグを持つアイテム（OR）
+)
+
+// ページングのデフォルト値と上限
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ValidateLimit は取得件数が1以上 MaxLimit 以下であることを検証する。
+// 各条件の Normalize は 0 を「指定なし」として DefaultLimit に置き換えるため、
+// クエリパラメータなどで明示的に指定された値は Normalize の前にこれで検証する（limit=0 も範囲外になる）
+func ValidateLimit(limit int) error {
+	if limit < 1 || limit > MaxLimit {
+		return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfBoundsError("limit", 1, MaxLimit))
+	}
+	return nil
+}
+
+// ItemCriteria は一覧取得時の絞り込み・並び替え・ページング条件
+type ItemCriteria struct {
+	Category         string
+	Categories       []string // Category とその子孫のカテゴリー。Category が子を持つ場合に usecase が設定し、空の場合は Category で絞り込む
+	Brand            string
+	BrandCanonical   string // Brand の正規形。Normalize が求め、カタログで解決できた場合は正式名の正規形に置き換える
+	PurchaseDateFrom string // YYYY-MM-DD（この日を含む）
+	PurchaseDateTo   string // YYYY-MM-DD（この日を含む）
+	MinPrice         *int
+	MaxPrice         *int
+	Attributes       map[string]string // 属性のキーと値（すべて一致するアイテムに絞り込む）。値は usecase が属性の型に合わせた形式にそろえる
+	Tags             []string          // タグ（entity.NormalizeTags で正規化する）
+	TagMatch         string            // Tags の一致条件（TagMatchAll または TagMatchAny）。Tags がある場合の既定は TagMatchAll
+	Sort             string
+	Order            string
+	Limit            int
+	Offset           int
+	Trashed          bool // true の場合はゴミ箱にあるアイテムを対象とする
+}
+
+// Normalize はデフォルト値を補完し、条件の妥当性を検証する
+func (c ItemCriteria) Normalize() (ItemCriteria, error) {
+	if c.Sort == "" {
+		c.Sort = SortCreatedAt
+	}
+	if c.Order == "" {
+		c.Order = OrderDesc
+	}
+	if c.Limit == 0 {
+		c.Limit = DefaultLimit
+	}
+	c.BrandCanonical = entity.CanonicalBrand(c.Brand)
+	c.Attributes = normalizeAttributeFilters(c.Attributes)
+	c.Tags = entity.NormalizeTags(c.Tags)
+	if len(c.Tags) > 0 && c.TagMatch == "" {
+		c.TagMatch = TagMatchAll
+	}
+
+	switch {
+	case c.Sort == SortCreatedAt, c.Sort == SortPurchaseDate, c.Sort == SortPurchasePrice, c.Sort == SortName:
+	case c.Sort == SortDeletedAt && c.Trashed:
+	default:
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("sort", []string{SortCreatedAt, SortPurchaseDate, SortPurchasePrice, SortName}))
+	}
+
+	if c.Order != OrderAsc && c.Order != OrderDesc {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("order", []string{OrderAsc, OrderDesc}))
+	}
+
+	if c.TagMatch != "" && c.TagMatch != TagMatchAll && c.TagMatch != TagMatchAny {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("tag_match", []string{TagMatchAll, TagMatchAny}))
+	}
+
+	if err := ValidateLimit(c.Limit); err != nil {
+		return c, err
+	}
+
+	if c.Offset < 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("offset", 0))
+	}
+
+	if c.PurchaseDateFrom != "" && !isDate(c.PurchaseDateFrom) {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidFormatError("purchase_date_from", "YYYY-MM-DD"))
+	}
+	if c.PurchaseDateTo != "" && !isDate(c.PurchaseDateTo) {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidFormatError("purchase_date_to", "YYYY-MM-DD"))
+	}
+	if c.PurchaseDateFrom != "" && c.PurchaseDateTo != "" && c.PurchaseDateFrom > c.PurchaseDateTo {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidRangeError("purchase_date_from", "purchase_date_to"))
+	}
+
+	if c.MinPrice != nil && *c.MinPrice < 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("min_price", 0))
+	}
+	if c.MaxPrice != nil && *c.MaxPrice < 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("max_price", 0))
+	}
+	if c.MinPrice != nil && c.MaxPrice != nil && *c.MinPrice > *c.MaxPrice {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidRangeError("min_price", "max_price"))
+	}
+
+	return c, nil
+}
+
+// SearchCriteria は全文検索の条件
+type SearchCriteria struct {
+	Query  string
+	Terms  []string // Query を entity.SearchTerms で区切った検索語。Normalize が求める
+	Limit  int
+	Offset int
+}
+
+// Normalize はデフォルト値を補完し、検索語を求めて条件の妥当性を検証する
+func (c SearchCriteria) Normalize() (SearchCriteria, error) {
+	if c.Limit == 0 {
+		c.Limit = DefaultLimit
+	}
+	c.Terms = entity.SearchTerms(c.Query)
+
+	if len(c.Terms) == 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.RequiredError("q"))
+	}
+	if entity.CharLength(entity.NormalizeText(c.Query)) > entity.MaxSearchQueryLength {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.TooLongError("q", entity.MaxSearchQueryLength))
+	}
+
+	if err := ValidateLimit(c.Limit); err != nil {
+		return c, err
+	}
+
+	if c.Offset < 0 {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("offset", 0))
+	}
+
+	return c, nil
+}
+
+// 属性での絞り込みのキーと値を NormalizeText で正規化し、値が空の条件を取り除く
+func normalizeAttributeFilters(attributes map[string]string) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(attributes))
+	for key, value := range attributes {
+		if value = entity.NormalizeText(value); value != "" {
+			normalized[entity.NormalizeText(key)] = value
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+func TestValidateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		wantErr bool
+	}{
+		{name: "正常系: 下限", limit: 1},
+		{name: "正常系: 上限", limit: MaxLimit},
+		{name: "異常系: 明示的な 0 は既定値にしない", limit: 0, wantErr: true},
+		{name: "異常系: 負の値", limit: -1, wantErr: true},
+		{name: "異常系: 上限を超える", limit: MaxLimit + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimit(tt.limit)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// CursorKey はキーセットページングの位置を表す (created_at, id) の組
+type CursorKey struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// カーソルに埋め込む絞り込み条件
+type cursorFilters struct {
+	Category         string `json:"category,omitempty"`
+	Brand            string `json:"brand,omitempty"`
+	PurchaseDateFrom string `json:"purchase_date_from,omitempty"`
+	PurchaseDateTo   string `json:"purchase_date_to,omitempty"`
+	MinPrice         *int   `json:"min_price,omitempty"`
+	MaxPrice         *int   `json:"max_price,omitempty"`
+	Attributes       string `json:"attributes,omitempty"` // 属性での絞り込み（キー順のクエリ文字列。比較できるよう文字列にする）
+	Tags             string `json:"tags,omitempty"`       // タグでの絞り込み（tag と match のクエリ文字列）
+}
+
+type cursorPayload struct {
+	CreatedAt time.Time     `json:"created_at"`
+	ID        int64         `json:"id"`
+	Order     string        `json:"order"`
+	Filters   cursorFilters `json:"filters"`
+}
+
+// CursorCodec はカーソルを HMAC-SHA256 で署名した不透明なトークンに変換する
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec は secret で署名するコーデックを返す。secret が空の場合はランダムな鍵を生成する
+func NewCursorCodec(secret []byte) *CursorCodec {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("failed to generate cursor secret: %v", err))
+		}
+	}
+	return &CursorCodec{secret: secret}
+}
+
+// Encode は位置と条件をトークン化する
+func (c *CursorCodec) Encode(key CursorKey, criteria ItemCriteria) (string, error) {
+	payload, err := json.Marshal(cursorPayload{
+		CreatedAt: key.CreatedAt.UTC(),
+		ID:        key.ID,
+		Order:     criteria.Order,
+		Filters:   filtersOf(criteria),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(c.sign(body)), nil
+}
+
+// Decode はトークンの署名を検証し、位置と条件を criteria に反映して返す。
+// criteria に絞り込み条件が指定されている場合はトークンの条件と一致しなければならない
+func (c *CursorCodec) Decode(token string, criteria ItemCriteria) (CursorKey, ItemCriteria, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+	}
+
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, c.sign(body)) {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+	}
+
+	requested := filtersOf(criteria)
+	if requested != (cursorFilters{}) && !sameFilters(requested, payload.Filters) {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.MismatchError("cursor", "filters"))
+	}
+	if criteria.Order != "" && criteria.Order != payload.Order {
+		return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.MismatchError("cursor", "order"))
+	}
+
+	criteria.Category = payload.Filters.Category
+	criteria.Brand = payload.Filters.Brand
+	criteria.PurchaseDateFrom = payload.Filters.PurchaseDateFrom
+	criteria.PurchaseDateTo = payload.Filters.PurchaseDateTo
+	criteria.MinPrice = payload.Filters.MinPrice
+	criteria.MaxPrice = payload.Filters.MaxPrice
+	criteria.Attributes = nil
+	if payload.Filters.Attributes != "" {
+		values, err := url.ParseQuery(payload.Filters.Attributes)
+		if err != nil {
+			return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+		}
+		criteria.Attributes = make(map[string]string, len(values))
+		for key := range values {
+			criteria.Attributes[key] = values.Get(key)
+		}
+	}
+	criteria.Tags, criteria.TagMatch = nil, ""
+	if payload.Filters.Tags != "" {
+		values, err := url.ParseQuery(payload.Filters.Tags)
+		if err != nil {
+			return CursorKey{}, criteria, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidError("cursor"))
+		}
+		criteria.Tags = values["tag"]
+		criteria.TagMatch = values.Get("match")
+	}
+	criteria.Order = payload.Order
+
+	return CursorKey{CreatedAt: payload.CreatedAt, ID: payload.ID}, criteria, nil
+}
+
+func (c *CursorCodec) sign(body string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+func filtersOf(criteria ItemCriteria) cursorFilters {
+	return cursorFilters{
+		Category:         criteria.Category,
+		Brand:            criteria.Brand,
+		PurchaseDateFrom: criteria.PurchaseDateFrom,
+		PurchaseDateTo:   criteria.PurchaseDateTo,
+		MinPrice:         criteria.MinPrice,
+		MaxPrice:         criteria.MaxPrice,
+		Attributes:       encodeAttributeFilters(criteria.Attributes),
+		Tags:             encodeTagFilters(criteria.Tags, criteria.TagMatch),
+	}
+}
+
+// encodeAttributeFilters は属性での絞り込みをキー順のクエリ文字列にする
+func encodeAttributeFilters(attributes map[string]string) string {
+	values := url.Values{}
+	for key, value := range attributes {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// encodeTagFilters はタグでの絞り込みをクエリ文字列にする。タグは正規化済みで辞書順に並んでいること
+func encodeTagFilters(tags []string, match string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	if match == "" {
+		match = TagMatchAll
+	}
+	return url.Values{"tag": tags, "match": {match}}.Encode()
+}
+
+// ポインタのフィールドは値で比較する
+func sameFilters(a, b cursorFilters) bool {
+	return a.Category == b.Category &&
+		a.Brand == b.Brand &&
+		a.PurchaseDateFrom == b.PurchaseDateFrom &&
+		a.PurchaseDateTo == b.PurchaseDateTo &&
+		equalIntPtr(a.MinPrice, b.MinPrice) &&
+		equalIntPtr(a.MaxPrice, b.MaxPrice) &&
+		a.Attributes == b.Attributes &&
+		a.Tags == b.Tags
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
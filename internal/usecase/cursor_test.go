@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// keysetRepository は FindByCursor だけを実データで再現するテスト用リポジトリ
+type keysetRepository struct {
+	MockItemRepository
+
+	mu     sync.Mutex
+	items  []*entity.Item
+	nextID int64
+	clock  time.Time
+}
+
+func newKeysetRepository(n int) *keysetRepository {
+	r := &keysetRepository{clock: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	for i := 0; i < n; i++ {
+		r.insert("時計")
+	}
+	return r
+}
+
+// insert は MySQL の TIMESTAMP と同様に秒精度の created_at を付与する。
+// 同一秒に複数件入るよう 2 件ごとに時計を進める
+func (r *keysetRepository) insert(category string) *entity.Item {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	if r.nextID%2 == 0 {
+		r.clock = r.clock.Add(time.Second)
+	}
+	item, _ := entity.NewItem("アイテム", category, "ブランド", 1000, "2023-01-01", nil, "", nil)
+	item.ID = r.nextID
+	item.CreatedAt = r.clock
+	r.items = append(r.items, item)
+	return item
+}
+
+func (r *keysetRepository) delete(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, item := range r.items {
+		if item.ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *keysetRepository) FindByCursor(ctx context.Context, criteria ItemCriteria, after *CursorKey) ([]*entity.Item, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	desc := criteria.Order != OrderAsc
+	less := func(a, b *entity.Item) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+
+	var matched []*entity.Item
+	for _, item := range r.items {
+		if criteria.Category != "" && item.Category != criteria.Category {
+			continue
+		}
+		if after != nil {
+			key := &entity.Item{ID: after.ID, CreatedAt: after.CreatedAt}
+			if desc && !less(item, key) || !desc && !less(key, item) {
+				continue
+			}
+		}
+		copied := *item
+		matched = append(matched, &copied)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if desc {
+			return less(matched[j], matched[i])
+		}
+		return less(matched[i], matched[j])
+	})
+
+	if len(matched) > criteria.Limit+1 {
+		matched = matched[:criteria.Limit+1]
+	}
+	hasMore := len(matched) > criteria.Limit
+	if hasMore {
+		matched = matched[:criteria.Limit]
+	}
+	return matched, hasMore, nil
+}
+
+// 全ページを辿り、取得した ID を順に返す
+func collectAllPages(t *testing.T, u ItemUsecase, criteria ItemCriteria, between func()) []int64 {
+	t.Helper()
+
+	var ids []int64
+	cursor := ""
+	for i := 0; i < 1000; i++ {
+		page, err := u.ListItemsByCursor(context.Background(), criteria, cursor)
+		require.NoError(t, err)
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if !page.HasMore {
+			assert.Empty(t, page.NextCursor)
+			return ids
+		}
+		require.NotEmpty(t, page.NextCursor)
+		cursor = page.NextCursor
+		// 2 ページ目以降は絞り込み条件をカーソルから復元する
+		criteria = ItemCriteria{Limit: criteria.Limit}
+		if between != nil {
+			between()
+		}
+	}
+	t.Fatal("pagination did not terminate")
+	return nil
+}
+
+func assertUnique(t *testing.T, ids []int64) {
+	t.Helper()
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		assert.False(t, seen[id], "id %d returned twice", id)
+		seen[id] = true
+	}
+}
+
+func TestItemUsecase_ListItemsByCursor_Stability(t *testing.T) {
+	t.Run("正常系: ページ間の挿入で重複・欠落が発生しない（降順）", func(t *testing.T) {
+		repo := newKeysetRepository(25)
+		u := NewItemUsecase(repo, WithCursorSecret([]byte("secret")))
+
+		ids := collectAllPages(t, u, ItemCriteria{Limit: 4}, func() {
+			repo.insert("時計")
+			repo.insert("時計")
+		})
+
+		assertUnique(t, ids)
+		// 降順では新規行は先頭側に入るため、最初のページ取得時点の 25 件がちょうど返る
+		assert.Len(t, ids, 25)
+		for id := int64(1); id <= 25; id++ {
+			assert.Contains(t, ids, id)
+		}
+	})
+
+	t.Run("正常系: 昇順では後から挿入された行も末尾で取得できる", func(t *testing.T) {
+		repo := newKeysetRepository(10)
+		u := NewItemUsecase(repo)
+
+		inserted := 0
+		ids := collectAllPages(t, u, ItemCriteria{Order: OrderAsc, Limit: 3}, func() {
+			if inserted < 3 {
+				repo.insert("時計")
+				inserted++
+			}
+		})
+
+		assertUnique(t, ids)
+		assert.Len(t, ids, 13)
+		assert.True(t, sort.SliceIsSorted(ids, func(i, j int) bool { return ids[i] < ids[j] }))
+	})
+
+	t.Run("正常系: 取得済みの行が削除されても後続ページが欠落しない", func(t *testing.T) {
+		repo := newKeysetRepository(12)
+		u := NewItemUsecase(repo)
+
+		deleted := int64(12)
+		ids := collectAllPages(t, u, ItemCriteria{Limit: 5}, func() {
+			// 直近に返した先頭側の行を削除する
+			repo.delete(deleted)
+			deleted--
+		})
+
+		assertUnique(t, ids)
+		assert.Len(t, ids, 12)
+	})
+
+	t.Run("正常系: 並行して挿入が続いても既存行を一度ずつ取得する", func(t *testing.T) {
+		repo := newKeysetRepository(50)
+		u := NewItemUsecase(repo)
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					repo.insert("時計")
+				}
+			}
+		}()
+
+		ids := collectAllPages(t, u, ItemCriteria{Limit: 7}, nil)
+		close(stop)
+		wg.Wait()
+
+		assertUnique(t, ids)
+		for id := int64(1); id <= 50; id++ {
+			assert.Contains(t, ids, id)
+		}
+	})
+
+	t.Run("正常系: 絞り込み条件がカーソルに引き継がれる", func(t *testing.T) {
+		repo := newKeysetRepository(6)
+		for i := 0; i < 5; i++ {
+			repo.insert("バッグ")
+		}
+		u := NewItemUsecase(repo)
+
+		ids := collectAllPages(t, u, ItemCriteria{Category: "バッグ", Limit: 2}, nil)
+
+		assert.ElementsMatch(t, []int64{7, 8, 9, 10, 11}, ids)
+	})
+}
+
+func TestItemUsecase_ListItemsByCursor_InvalidCursor(t *testing.T) {
+	repo := newKeysetRepository(5)
+	u := NewItemUsecase(repo, WithCursorSecret([]byte("secret")))
+
+	first, err := u.ListItemsByCursor(context.Background(), ItemCriteria{Category: "時計", Limit: 2}, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextCursor)
+
+	tests := []struct {
+		name     string
+		usecase  ItemUsecase
+		criteria ItemCriteria
+		cursor   string
+	}{
+		{
+			name:    "異常系: 形式が不正なカーソル",
+			usecase: u,
+			cursor:  "not-a-cursor",
+		},
+		{
+			name:    "異常系: 改ざんされたカーソル",
+			usecase: u,
+			cursor:  "x" + first.NextCursor,
+		},
+		{
+			name:    "異常系: 異なる鍵で署名されたカーソル",
+			usecase: NewItemUsecase(repo, WithCursorSecret([]byte("other"))),
+			cursor:  first.NextCursor,
+		},
+		{
+			name:     "異常系: カーソルと異なる絞り込み条件",
+			usecase:  u,
+			criteria: ItemCriteria{Category: "バッグ"},
+			cursor:   first.NextCursor,
+		},
+		{
+			name:     "異常系: created_at 以外の並び替え",
+			usecase:  u,
+			criteria: ItemCriteria{Sort: SortPurchasePrice},
+		},
+		{
+			name:     "異常系: offset との併用",
+			usecase:  u,
+			criteria: ItemCriteria{Offset: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.usecase.ListItemsByCursor(context.Background(), tt.criteria, tt.cursor)
+
+			assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+			assert.Nil(t, page)
+		})
+	}
+}
+
+func TestCursorCodec_Attributes(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	key := CursorKey{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: 10}
+	attributes := map[string]string{"movement": "自動巻き", "case_size": "40"}
+
+	token, err := codec.Encode(key, ItemCriteria{Order: OrderDesc, Attributes: attributes})
+	require.NoError(t, err)
+
+	// 絞り込み条件を省略した場合はカーソルの条件を引き継ぐ
+	_, decoded, err := codec.Decode(token, ItemCriteria{})
+	require.NoError(t, err)
+	assert.Equal(t, attributes, decoded.Attributes)
+
+	_, _, err = codec.Decode(token, ItemCriteria{Attributes: map[string]string{"case_size": "40", "movement": "自動巻き"}})
+	assert.NoError(t, err, "キーの順序は問わない")
+
+	_, _, err = codec.Decode(token, ItemCriteria{Attributes: map[string]string{"movement": "手巻き"}})
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+}
+
+func TestCursorCodec_Tags(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	key := CursorKey{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: 10}
+
+	token, err := codec.Encode(key, ItemCriteria{Order: OrderDesc, Tags: []string{"wedding gifts", "投資用"}, TagMatch: TagMatchAny})
+	require.NoError(t, err)
+
+	_, decoded, err := codec.Decode(token, ItemCriteria{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wedding gifts", "投資用"}, decoded.Tags)
+	assert.Equal(t, TagMatchAny, decoded.TagMatch)
+
+	_, _, err = codec.Decode(token, ItemCriteria{Tags: []string{"wedding gifts", "投資用"}})
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidInput, "一致条件が異なる")
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"Aicon-assignment/internal/domain/entity"
+)
+
+// ExportItems は一覧と同じ条件に一致するゴミ箱に無いすべてのアイテムを、並び順どおりに1件ずつ fn に渡す。
+// ページング（Limit・Offset）は使わない。条件の誤りは fn を呼ぶ前に返し、fn がエラーを返した場合はそこで中断する
+func (u *itemUsecase) ExportItems(ctx context.Context, criteria ItemCriteria, fn func(item *entity.Item) error) error {
+	criteria.Trashed = false
+	criteria.Limit, criteria.Offset = 0, 0
+	criteria, err := criteria.Normalize()
+	if err != nil {
+		return err
+	}
+	criteria, err = u.resolveCriteriaBrand(ctx, criteria)
+	if err != nil {
+		return err
+	}
+	criteria = u.resolveCriteriaCategory(criteria)
+	criteria, err = u.resolveCriteriaAttributes(criteria)
+	if err != nil {
+		return err
+	}
+
+	if err := u.itemRepo.ForEachByCriteria(ctx, criteria, fn); err != nil {
+		return fmt.Errorf("failed to export items: %w", err)
+	}
+
+	return nil
+}
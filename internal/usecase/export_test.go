@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+func TestItemUsecase_ExportItems(t *testing.T) {
+	t.Run("正常系: ページングとゴミ箱の指定を除いた条件で、すべてのアイテムを順に渡す", func(t *testing.T) {
+		item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+		item2, _ := entity.NewItem("時計2", "時計", "Rolex", 500000, "2023-01-02", nil, "", nil)
+		mockRepo := new(MockItemRepository)
+		want := ItemCriteria{Brand: "rolex", BrandCanonical: "ROLEX", Sort: SortPurchasePrice, Order: OrderAsc, Limit: DefaultLimit}
+		mockRepo.On("ForEachByCriteria", mock.Anything, want, mock.Anything).Return([]*entity.Item{item1, item2}, nil)
+
+		var names []string
+		err := NewItemUsecase(mockRepo).ExportItems(context.Background(), ItemCriteria{
+			Brand: "rolex", Sort: SortPurchasePrice, Order: OrderAsc, Limit: 1, Offset: 10, Trashed: true,
+		}, func(item *entity.Item) error {
+			names = append(names, item.Name)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"時計1", "時計2"}, names)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 不正な条件ではリポジトリを呼ばない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		err := NewItemUsecase(mockRepo).ExportItems(context.Background(), ItemCriteria{Sort: "unknown"}, func(*entity.Item) error { return nil })
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "ForEachByCriteria", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("ForEachByCriteria", mock.Anything, mock.Anything, mock.Anything).Return([]*entity.Item{}, domainErrors.ErrDatabaseError)
+
+		err := NewItemUsecase(mockRepo).ExportItems(context.Background(), ItemCriteria{}, func(*entity.Item) error { return nil })
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
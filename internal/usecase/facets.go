@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 購入価格のファセットの区切り（円）。0〜99,999、100,000〜499,999、…、5,000,000〜 の5つに分ける
+var priceFacetBoundaries = []int{100000, 500000, 1000000, 5000000}
+
+var errItemIndexNotConfigured = errors.New("item index is not configured")
+
+// FacetCriteria はファセット付きの絞り込みの条件。一覧（ItemCriteria）の条件に加えて全文検索の検索語で絞り込める
+type FacetCriteria struct {
+	ItemCriteria
+	Query string
+	Terms []string // Query を entity.SearchTerms で区切った検索語。Normalize が求め、空の場合は検索語で絞り込まない
+}
+
+// Normalize はデフォルト値を補完し、検索語を求めて条件の妥当性を検証する。ゴミ箱のアイテムは対象にしない
+func (c FacetCriteria) Normalize() (FacetCriteria, error) {
+	c.Trashed = false
+	criteria, err := c.ItemCriteria.Normalize()
+	c.ItemCriteria = criteria
+	if err != nil {
+		return c, err
+	}
+
+	c.Terms = entity.SearchTerms(c.Query)
+	if entity.CharLength(entity.NormalizeText(c.Query)) > entity.MaxSearchQueryLength {
+		return c, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.TooLongError("q", entity.MaxSearchQueryLength))
+	}
+
+	return c, nil
+}
+
+// FacetResult は絞り込んだアイテムの1ページ分と、絞り込んだアイテム全体のファセット
+type FacetResult struct {
+	Items  []*entity.Item
+	Total  int
+	Limit  int
+	Offset int
+	Facets Facets
+}
+
+// Facets は値ごとのアイテム数。各値は一覧の絞り込み条件（category, brand, purchase_date_from/to, min_price/max_price）にそのまま使える
+type Facets struct {
+	Category     []FacetCount  `json:"category"`      // 件数の多い順（同数は値の順）
+	Brand        []FacetCount  `json:"brand"`         // 正規形ごとに数え、最も多い表記を値とする。件数の多い順
+	PurchaseYear []FacetCount  `json:"purchase_year"` // 新しい年から
+	PriceRange   []PriceBucket `json:"price_range"`   // 安い順。該当が無い区間も含める
+}
+
+// FacetCount はファセットの値とアイテム数
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PriceBucket は購入価格の区間（Min 以上 Max 以下。Max が nil の場合は上限なし）とアイテム数
+type PriceBucket struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max"`
+	Count int  `json:"count"`
+}
+
+func (u *itemUsecase) GetFacets(ctx context.Context, criteria FacetCriteria) (*FacetResult, error) {
+	if u.index == nil {
+		return nil, errItemIndexNotConfigured
+	}
+
+	criteria, err := criteria.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	criteria.ItemCriteria, err = u.resolveCriteriaBrand(ctx, criteria.ItemCriteria)
+	if err != nil {
+		return nil, err
+	}
+	criteria.ItemCriteria = u.resolveCriteriaCategory(criteria.ItemCriteria)
+	criteria.ItemCriteria, err = u.resolveCriteriaAttributes(criteria.ItemCriteria)
+	if err != nil {
+		return nil, err
+	}
+
+	items := u.index.Find(criteria)
+	start := min(criteria.Offset, len(items))
+	end := min(start+criteria.Limit, len(items))
+
+	return &FacetResult{
+		Items:  append([]*entity.Item{}, items[start:end]...),
+		Total:  len(items),
+		Limit:  criteria.Limit,
+		Offset: criteria.Offset,
+		Facets: countFacets(items),
+	}, nil
+}
+
+// RebuildIndex はゴミ箱に無いすべてのアイテムを読み込んで索引を作り直し、索引のアイテム数を返す
+func (u *itemUsecase) RebuildIndex(ctx context.Context) (int, error) {
+	if u.index == nil {
+		return 0, errItemIndexNotConfigured
+	}
+
+	count, err := u.index.Rebuild(func() ([]*entity.Item, error) {
+		return u.itemRepo.FindAll(ctx)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild item index: %w", err)
+	}
+
+	return count, nil
+}
+
+// 書き込みの確定後に、変更したアイテムを索引へ反映する
+func (u *itemUsecase) indexItem(item *entity.Item) {
+	if u.index != nil {
+		u.index.Put(item)
+	}
+}
+
+func (u *itemUsecase) unindexItem(id int64) {
+	if u.index != nil {
+		u.index.Remove(id)
+	}
+}
+
+// countFacets は items をカテゴリー・ブランド・購入年・購入価格の区間ごとに数える
+func countFacets(items []*entity.Item) Facets {
+	categories := newFacetCounter()
+	brands := newFacetCounter()
+	years := newFacetCounter()
+	prices := make([]PriceBucket, len(priceFacetBoundaries)+1)
+	for i := range prices {
+		if i > 0 {
+			prices[i].Min = priceFacetBoundaries[i-1]
+		}
+		if i < len(priceFacetBoundaries) {
+			upper := priceFacetBoundaries[i] - 1
+			prices[i].Max = &upper
+		}
+	}
+
+	for _, item := range items {
+		categories.add(item.Category, item.Category)
+		brands.add(item.BrandCanonical, item.Brand)
+		if len(item.PurchaseDate) >= 4 {
+			year := item.PurchaseDate[:4]
+			years.add(year, year)
+		}
+		i, _ := slices.BinarySearch(priceFacetBoundaries, item.PurchasePrice+1)
+		prices[i].Count++
+	}
+
+	purchaseYears := years.counts()
+	slices.SortFunc(purchaseYears, func(a, b FacetCount) int { return cmp.Compare(b.Value, a.Value) })
+
+	return Facets{
+		Category:     categories.counts(),
+		Brand:        brands.counts(),
+		PurchaseYear: purchaseYears,
+		PriceRange:   prices,
+	}
+}
+
+// facetCounter はキーごとの件数と、キーごとに最も多い表記を数える
+type facetCounter struct {
+	keys   []string
+	total  map[string]int
+	labels map[string]map[string]int
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{total: make(map[string]int), labels: make(map[string]map[string]int)}
+}
+
+func (c *facetCounter) add(key, label string) {
+	if _, ok := c.total[key]; !ok {
+		c.keys = append(c.keys, key)
+		c.labels[key] = make(map[string]int)
+	}
+	c.total[key]++
+	c.labels[key][label]++
+}
+
+// counts は件数の多い順（同数は値の順）に返す。値はキーごとに最も多い表記（同数は先に並ぶもの）
+func (c *facetCounter) counts() []FacetCount {
+	counts := make([]FacetCount, 0, len(c.keys))
+	for _, key := range c.keys {
+		var value string
+		best := 0
+		for label, n := range c.labels[key] {
+			if n > best || n == best && label < value {
+				value, best = label, n
+			}
+		}
+		counts = append(counts, FacetCount{Value: value, Count: c.total[key]})
+	}
+	slices.SortFunc(counts, func(a, b FacetCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return counts
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// MockItemIndex はtestify/mockを使用したファセットの索引のモック
+type MockItemIndex struct {
+	mock.Mock
+}
+
+func (m *MockItemIndex) Rebuild(load func() ([]*entity.Item, error)) (int, error) {
+	args := m.Called(load)
+	items, err := load()
+	if err != nil {
+		return 0, err
+	}
+	return len(items), args.Error(0)
+}
+
+func (m *MockItemIndex) Put(item *entity.Item) {
+	m.Called(item)
+}
+
+func (m *MockItemIndex) Remove(id int64) {
+	m.Called(id)
+}
+
+func (m *MockItemIndex) Find(criteria FacetCriteria) []*entity.Item {
+	args := m.Called(criteria)
+	return args.Get(0).([]*entity.Item)
+}
+
+func TestFacetCriteria_Normalize(t *testing.T) {
+	t.Run("正常系: 一覧の条件を補完し、検索語を求める", func(t *testing.T) {
+		criteria, err := FacetCriteria{ItemCriteria: ItemCriteria{Trashed: true}, Query: "ﾛﾚｯｸｽ"}.Normalize()
+
+		require.NoError(t, err)
+		assert.Equal(t, ItemCriteria{Sort: SortCreatedAt, Order: OrderDesc, Limit: DefaultLimit}, criteria.ItemCriteria)
+		assert.Equal(t, []string{"ろれっくす"}, criteria.Terms)
+	})
+
+	t.Run("正常系: 検索語は省略できる", func(t *testing.T) {
+		criteria, err := FacetCriteria{}.Normalize()
+
+		require.NoError(t, err)
+		assert.Nil(t, criteria.Terms)
+	})
+
+	t.Run("異常系: 検索語が長すぎる", func(t *testing.T) {
+		_, err := FacetCriteria{Query: strings.Repeat("あ", entity.MaxSearchQueryLength+1)}.Normalize()
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 一覧の条件が不正", func(t *testing.T) {
+		_, err := FacetCriteria{ItemCriteria: ItemCriteria{Sort: SortDeletedAt}}.Normalize()
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func TestItemUsecase_GetFacets(t *testing.T) {
+	newItem := func(name, category, brand string, price int, date string) *entity.Item {
+		item, err := entity.NewItem(name, category, brand, price, date, nil, "", nil)
+		require.NoError(t, err)
+		return item
+	}
+	items := []*entity.Item{
+		newItem("デイトナ", "時計", "ROLEX", 1500000, "2023-01-15"),
+		newItem("サブマリーナ", "時計", "Rolex", 1000000, "2022-05-01"),
+		newItem("エクスプローラー", "時計", "ROLEX", 999999, "2023-07-01"),
+		newItem("ケリー", "バッグ", "HERMÈS", 99999, "2021-03-03"),
+	}
+
+	t.Run("正常系: 1ページ分のアイテムと絞り込み結果全体のファセットを返す", func(t *testing.T) {
+		index := new(MockItemIndex)
+		index.On("Find", mock.MatchedBy(func(c FacetCriteria) bool {
+			return c.Limit == 2 && c.Offset == 1 && slices.Equal(c.Terms, []string{"時計"})
+		})).Return(items)
+
+		result, err := NewItemUsecase(new(MockItemRepository), WithItemIndex(index)).GetFacets(context.Background(), FacetCriteria{
+			ItemCriteria: ItemCriteria{Limit: 2, Offset: 1},
+			Query:        "時計",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, items[1:3], result.Items)
+		assert.Equal(t, 4, result.Total)
+		assert.Equal(t, []FacetCount{{Value: "時計", Count: 3}, {Value: "バッグ", Count: 1}}, result.Facets.Category)
+		assert.Equal(t, []FacetCount{{Value: "ROLEX", Count: 3}, {Value: "HERMÈS", Count: 1}}, result.Facets.Brand, "正規形ごとに数え、最も多い表記を値とする")
+		assert.Equal(t, []FacetCount{{Value: "2023", Count: 2}, {Value: "2022", Count: 1}, {Value: "2021", Count: 1}}, result.Facets.PurchaseYear)
+		assert.Equal(t, []PriceBucket{
+			{Min: 0, Max: ptrInt(99999), Count: 1},
+			{Min: 100000, Max: ptrInt(499999), Count: 0},
+			{Min: 500000, Max: ptrInt(999999), Count: 1},
+			{Min: 1000000, Max: ptrInt(4999999), Count: 2},
+			{Min: 5000000, Max: nil, Count: 0},
+		}, result.Facets.PriceRange)
+	})
+
+	t.Run("正常系: 該当なしでも空配列を返す", func(t *testing.T) {
+		index := new(MockItemIndex)
+		index.On("Find", mock.Anything).Return([]*entity.Item{})
+
+		result, err := NewItemUsecase(new(MockItemRepository), WithItemIndex(index)).GetFacets(context.Background(), FacetCriteria{
+			ItemCriteria: ItemCriteria{Offset: 10},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.Item{}, result.Items)
+		assert.Equal(t, []FacetCount{}, result.Facets.Category)
+		assert.Len(t, result.Facets.PriceRange, 5)
+	})
+
+	t.Run("異常系: 不正な条件では索引を参照しない", func(t *testing.T) {
+		index := new(MockItemIndex)
+
+		_, err := NewItemUsecase(new(MockItemRepository), WithItemIndex(index)).GetFacets(context.Background(), FacetCriteria{
+			ItemCriteria: ItemCriteria{Limit: MaxLimit + 1},
+		})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		index.AssertNotCalled(t, "Find", mock.Anything)
+	})
+
+	t.Run("異常系: 索引が設定されていない", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).GetFacets(context.Background(), FacetCriteria{})
+		assert.Error(t, err)
+	})
+}
+
+func TestItemUsecase_ItemIndexSync(t *testing.T) {
+	input := CreateItemInput{Name: "デイトナ", Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15"}
+
+	t.Run("正常系: 書き込みが確定したアイテムを索引へ反映する", func(t *testing.T) {
+		created, _ := entity.NewItem("デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		created.ID = 1
+		created.Version = 1
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(created, nil)
+		mockRepo.On("FindByID", mock.Anything, int64(1)).Return(created, nil)
+		mockRepo.On("Delete", mock.Anything, int64(1), int64(1)).Return(nil)
+		index := new(MockItemIndex)
+		index.On("Put", created).Once()
+		index.On("Remove", int64(1)).Once()
+		u := NewItemUsecase(mockRepo, WithItemIndex(index))
+
+		_, err := u.CreateItem(context.Background(), input)
+		require.NoError(t, err)
+		require.NoError(t, u.DeleteItem(context.Background(), 1, []int64{1}))
+
+		index.AssertExpectations(t)
+	})
+
+	t.Run("異常系: ロールバックした書き込みは反映しない", func(t *testing.T) {
+		created, _ := entity.NewItem("デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(created, nil)
+		eventRepo := new(MockItemEventRepository)
+		eventRepo.On("Append", mock.Anything, mock.Anything).Return(domainErrors.ErrDatabaseError)
+		index := new(MockItemIndex)
+		u := NewItemUsecase(mockRepo, WithTransactor(&recordingTransactor{}), WithAuditTrail(eventRepo), WithItemIndex(index))
+
+		_, err := u.CreateItem(context.Background(), input)
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+	})
+}
+
+func TestItemUsecase_RebuildIndex(t *testing.T) {
+	t.Run("正常系: ゴミ箱に無いすべてのアイテムで作り直す", func(t *testing.T) {
+		item, _ := entity.NewItem("デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindAll", mock.Anything).Return([]*entity.Item{item}, nil)
+		index := new(MockItemIndex)
+		index.On("Rebuild", mock.Anything).Return(nil)
+
+		count, err := NewItemUsecase(mockRepo, WithItemIndex(index)).RebuildIndex(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("FindAll", mock.Anything).Return(([]*entity.Item)(nil), domainErrors.ErrDatabaseError)
+		index := new(MockItemIndex)
+		index.On("Rebuild", mock.Anything).Return(nil)
+
+		_, err := NewItemUsecase(mockRepo, WithItemIndex(index)).RebuildIndex(context.Background())
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// 取り込みの方式
+const (
+	ImportModeAtomic     = "atomic"      // すべての行を1つのトランザクションで登録し、失敗した行が1つでもあれば何も登録しない
+	ImportModeBestEffort = "best_effort" // 1行ずつ登録し、失敗した行だけを飛ばす
+)
+
+// 取り込みの行ごとの結果
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// 行を登録しなかった理由
+const (
+	ImportReasonEmptyRow         = "empty_row"         // すべての列が空
+	ImportReasonRolledBack       = "rolled_back"       // atomic で他の行が失敗したため登録を取り消した
+	ImportReasonValidationFailed = "validation_failed" // Errors にバリデーションエラーがある
+)
+
+const (
+	// atomic で1回に取り込める行数。登録する行はトランザクションを張る前にすべてメモリに読み込む
+	MaxAtomicImportRows = 10000
+	// ImportReport.Rows に含める行の結果の上限。超えた行は件数にだけ数える
+	MaxImportReportRows = 1000
+)
+
+// ImportOptions は取り込みの設定
+type ImportOptions struct {
+	Mode   string // ImportModeAtomic（デフォルト）または ImportModeBestEffort
+	DryRun bool   // true の場合は検証のみ行い、登録しない
+}
+
+// ImportRow は取り込むファイルの1行を CreateItemInput に読み替えたもの
+type ImportRow struct {
+	Line       int                     // 行が始まるファイルの行番号（1始まり）
+	Input      CreateItemInput         // Input.Attributes は使わず、属性は Attributes で渡す
+	Attributes map[string]string       // 属性のキーと値。値はカテゴリーの定義の型に合わせて変換する
+	Errors     entity.ValidationErrors // 読み替えられなかった値（数値でない購入価格など）
+	Empty      bool                    // すべての列が空
+}
+
+// ImportSource は取り込む行を先頭から1行ずつ返す。残りが無い場合は io.EOF を返す
+type ImportSource interface {
+	Next() (*ImportRow, error)
+}
+
+// ImportReport は取り込みの結果
+type ImportReport struct {
+	Mode    string             `json:"mode"`
+	DryRun  bool               `json:"dry_run"`
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Rows    []*ImportRowResult `json:"rows"`
+	// Rows が MaxImportReportRows 行で打ち切られた場合は true
+	RowsTruncated bool `json:"rows_truncated"`
+}
+
+// ImportRowResult は行ごとの結果。DryRun の場合、登録できる行は ID なしで created になる
+type ImportRowResult struct {
+	Line   int                     `json:"line"`
+	Status string                  `json:"status"`
+	ID     *int64                  `json:"id,omitempty"`
+	Reason string                  `json:"reason,omitempty"`
+	Errors entity.ValidationErrors `json:"errors,omitempty"`
+}
+
+// ImportItems は source の行を1行ずつ検証してアイテムとして登録し、行ごとの結果を返す。
+// 行のバリデーションエラーは結果に含め、読み込みやデータベースのエラーの場合は取り込みを中断してエラーを返す
+// （best_effort では中断までに登録した行は残る）。atomic ではすべての行を読み終えてからトランザクションを張る
+func (u *itemUsecase) ImportItems(ctx context.Context, source ImportSource, options ImportOptions) (*ImportReport, error) {
+	if options.Mode == "" {
+		options.Mode = ImportModeAtomic
+	}
+	if options.Mode != ImportModeAtomic && options.Mode != ImportModeBestEffort {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("mode", []string{ImportModeAtomic, ImportModeBestEffort}))
+	}
+
+	report := &ImportReport{Mode: options.Mode, DryRun: options.DryRun, Rows: []*ImportRowResult{}}
+	var created []*entity.Item
+
+	var err error
+	switch {
+	case options.Mode == ImportModeAtomic:
+		created, err = u.importAtomically(ctx, source, report, options.DryRun)
+	case options.DryRun:
+		err = u.importRows(ctx, source, report, func(ctx context.Context, item *entity.Item, result *ImportRowResult) error {
+			return nil
+		})
+	default:
+		err = u.importRows(ctx, source, report, func(ctx context.Context, item *entity.Item, result *ImportRowResult) error {
+			var createdItem *entity.Item
+			err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+				var err error
+				createdItem, err = u.insertImportedItem(ctx, item)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			created = append(created, createdItem)
+			result.ID = &createdItem.ID
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range created {
+		u.indexItem(item)
+	}
+
+	return report, nil
+}
+
+// importAtomically はすべての行を読み込んで検証した後、失敗した行が無ければ1つのトランザクションで登録する。
+// 本文を読む間はトランザクションを張らないよう、登録する行は MaxAtomicImportRows 行までメモリに保持する
+func (u *itemUsecase) importAtomically(ctx context.Context, source ImportSource, report *ImportReport, dryRun bool) ([]*entity.Item, error) {
+	type pendingRow struct {
+		item   *entity.Item
+		result *ImportRowResult
+	}
+	var pending []pendingRow
+	err := u.importRows(ctx, source, report, func(ctx context.Context, item *entity.Item, result *ImportRowResult) error {
+		if len(pending) >= MaxAtomicImportRows {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.TooManyError("rows", MaxAtomicImportRows))
+		}
+		pending = append(pending, pendingRow{item: item, result: result})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 失敗した行があれば、登録できる行も取り消しとして返す
+	if report.Failed > 0 {
+		for _, row := range pending {
+			row.result.Status, row.result.Reason = ImportStatusSkipped, ImportReasonRolledBack
+		}
+		report.Skipped += report.Created
+		report.Created = 0
+		return nil, nil
+	}
+	if dryRun {
+		return nil, nil
+	}
+
+	created := make([]*entity.Item, 0, len(pending))
+	err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+		for _, row := range pending {
+			createdItem, err := u.insertImportedItem(ctx, row.item)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", row.result.Line, err)
+			}
+			created = append(created, createdItem)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range pending {
+		row.result.ID = &created[i].ID
+	}
+	return created, nil
+}
+
+// importRows は source の行を検証して report に結果を加え、登録できる行を create に渡す。
+// create は登録した場合に result.ID を設定する。結果は MaxImportReportRows 行まで report.Rows に含める
+func (u *itemUsecase) importRows(ctx context.Context, source ImportSource, report *ImportReport, create func(ctx context.Context, item *entity.Item, result *ImportRowResult) error) error {
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &ImportRowResult{Line: row.Line}
+		if len(report.Rows) < MaxImportReportRows {
+			report.Rows = append(report.Rows, result)
+		} else {
+			report.RowsTruncated = true
+		}
+		if row.Empty {
+			result.Status, result.Reason = ImportStatusSkipped, ImportReasonEmptyRow
+			report.Skipped++
+			continue
+		}
+
+		item, errs, err := u.prepareImportRow(ctx, row)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		if len(errs) > 0 {
+			result.Status, result.Reason, result.Errors = ImportStatusFailed, ImportReasonValidationFailed, errs
+			report.Failed++
+			continue
+		}
+
+		if err := create(ctx, item, result); err != nil {
+			return fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		result.Status = ImportStatusCreated
+		report.Created++
+	}
+}
+
+// prepareImportRow は行をアイテムにしてバリデーションし、ブランドをカタログで解決する。
+// 読み替えられなかった値と同じフィールドのバリデーションエラーは重複して返さない
+func (u *itemUsecase) prepareImportRow(ctx context.Context, row *ImportRow) (*entity.Item, entity.ValidationErrors, error) {
+	errs := slices.Clone(row.Errors)
+	input := row.Input
+	attributes, attributeErrs := entity.ParseAttributes(input.Category, row.Attributes, u.categories())
+	errs = append(errs, attributeErrs...)
+
+	item, err := entity.NewItem(input.Name, input.Category, input.Brand, input.PurchasePrice, input.PurchaseDate, attributes, input.Notes, u.categories())
+	if err == nil {
+		err = item.SetTags(input.Tags)
+	}
+	if err == nil && len(errs) == 0 {
+		err = u.resolveItemBrand(ctx, item)
+	}
+
+	var validationErrs entity.ValidationErrors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return nil, nil, err
+	}
+	for _, fe := range validationErrs {
+		if !slices.ContainsFunc(errs, func(e entity.FieldError) bool { return e.Field == fe.Field }) {
+			errs = append(errs, fe)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+
+	return item, nil, nil
+}
+
+// insertImportedItem は取り込んだアイテムを登録して監査記録を残す。呼び出し側でトランザクションを張ること
+func (u *itemUsecase) insertImportedItem(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	createdItem, err := u.itemRepo.Create(ctx, item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create item: %w", err)
+	}
+	if err := u.recordEvent(ctx, entity.ItemEventCreate, createdItem.ID, nil, createdItem); err != nil {
+		return nil, err
+	}
+	return createdItem, nil
+}
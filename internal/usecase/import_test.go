@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+)
+
+// sliceImportSource は rows を順に返す ImportSource。rows を返し終えた後は err（nil なら io.EOF）を返す
+type sliceImportSource struct {
+	rows []*ImportRow
+	err  error
+}
+
+func (s *sliceImportSource) Next() (*ImportRow, error) {
+	if len(s.rows) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+func TestItemUsecase_ImportItems(t *testing.T) {
+	validRow := func(line int, name string) *ImportRow {
+		return &ImportRow{Line: line, Input: CreateItemInput{
+			Name: name, Category: "時計", Brand: "ROLEX", PurchasePrice: 1500000, PurchaseDate: "2023-01-15",
+		}}
+	}
+	invalidRow := func(line int) *ImportRow {
+		return &ImportRow{Line: line, Input: CreateItemInput{Name: "価格が不正", Category: "時計", Brand: "ROLEX", PurchaseDate: "2023-01-15"},
+			Errors: entity.ValidationErrors{entity.InvalidTypeError("purchase_price", entity.AttributeTypeInteger)}}
+	}
+	source := func() *sliceImportSource {
+		return &sliceImportSource{rows: []*ImportRow{validRow(2, "デイトナ"), {Line: 3, Empty: true}, invalidRow(4), validRow(5, "サブマリーナ")}}
+	}
+	newRepo := func() *MockItemRepository {
+		mockRepo := new(MockItemRepository)
+		for i, name := range []string{"デイトナ", "サブマリーナ"} {
+			created, _ := entity.NewItem(name, "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+			created.ID = int64(i + 1)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool { return item.Name == name })).Return(created, nil)
+		}
+		return mockRepo
+	}
+	statuses := func(report *ImportReport) []string {
+		var s []string
+		for _, row := range report.Rows {
+			s = append(s, row.Status+"/"+row.Reason)
+		}
+		return s
+	}
+
+	t.Run("正常系: atomic では失敗した行があれば何も登録しない", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx), WithItemIndex(index)).ImportItems(context.Background(), source(), ImportOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, ImportModeAtomic, report.Mode)
+		assert.Equal(t, []string{"skipped/rolled_back", "skipped/empty_row", "failed/validation_failed", "skipped/rolled_back"}, statuses(report))
+		assert.Equal(t, entity.ValidationErrors{entity.InvalidTypeError("purchase_price", entity.AttributeTypeInteger)}, report.Rows[2].Errors, "同じフィールドのエラーは重複しない")
+		assert.Equal(t, [3]int{0, 3, 1}, [3]int{report.Created, report.Skipped, report.Failed})
+		assert.Equal(t, [2]int{0, 0}, [2]int{tx.committed, tx.rolledBack}, "検証で失敗した場合はトランザクションを張らない")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		index.AssertNotCalled(t, "Put", mock.Anything)
+	})
+
+	t.Run("正常系: atomic ですべての行が正しければ登録する", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+		index := new(MockItemIndex)
+		index.On("Put", mock.Anything).Twice()
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx), WithItemIndex(index)).ImportItems(context.Background(),
+			&sliceImportSource{rows: []*ImportRow{validRow(2, "デイトナ"), validRow(3, "サブマリーナ")}}, ImportOptions{Mode: ImportModeAtomic})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		require.NotNil(t, report.Rows[1].ID)
+		assert.Equal(t, int64(2), *report.Rows[1].ID)
+		assert.Equal(t, 1, tx.committed)
+		index.AssertExpectations(t)
+	})
+
+	t.Run("正常系: best_effort では失敗した行だけを飛ばす", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+
+		report, err := NewItemUsecase(mockRepo, WithTransactor(tx)).ImportItems(context.Background(), source(), ImportOptions{Mode: ImportModeBestEffort})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"created/", "skipped/empty_row", "failed/validation_failed", "created/"}, statuses(report))
+		assert.Equal(t, [3]int{2, 1, 1}, [3]int{report.Created, report.Skipped, report.Failed})
+		assert.Equal(t, 2, tx.committed, "1行ずつ確定する")
+	})
+
+	t.Run("正常系: dry_run では検証のみ行う", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		report, err := NewItemUsecase(mockRepo).ImportItems(context.Background(), source(), ImportOptions{Mode: ImportModeBestEffort, DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []string{"created/", "skipped/empty_row", "failed/validation_failed", "created/"}, statuses(report))
+		assert.Nil(t, report.Rows[0].ID)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: エンティティのバリデーションエラーを行の結果に含める", func(t *testing.T) {
+		row := validRow(2, "")
+		row.Attributes = map[string]string{"color": "黒"}
+
+		report, err := NewItemUsecase(new(MockItemRepository)).ImportItems(context.Background(), &sliceImportSource{rows: []*ImportRow{row}}, ImportOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, ImportStatusFailed, report.Rows[0].Status)
+		assert.Contains(t, report.Rows[0].Errors, entity.RequiredError("name"))
+	})
+
+	t.Run("異常系: 不正な方式", func(t *testing.T) {
+		_, err := NewItemUsecase(new(MockItemRepository)).ImportItems(context.Background(), source(), ImportOptions{Mode: "all"})
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+
+	t.Run("異常系: 読み込みに失敗した場合は中断する", func(t *testing.T) {
+		mockRepo := newRepo()
+		tx := &recordingTransactor{}
+
+		_, err := NewItemUsecase(mockRepo, WithTransactor(tx)).ImportItems(context.Background(),
+			&sliceImportSource{rows: []*ImportRow{validRow(2, "デイトナ")}, err: domainErrors.ErrInvalidInput}, ImportOptions{})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Equal(t, [2]int{0, 0}, [2]int{tx.committed, tx.rolledBack}, "本文を読み終えるまでトランザクションを張らない")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("正常系: atomic では本文を読み終えてからトランザクションを張る", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		tx := &recordingTransactor{}
+		src := &sliceImportSource{rows: []*ImportRow{validRow(2, "デイトナ"), validRow(3, "サブマリーナ")}}
+		mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			assert.Empty(t, src.rows, "登録を始める前にすべての行を読んでいる")
+		}).Return(&entity.Item{ID: 1}, nil)
+
+		_, err := NewItemUsecase(mockRepo, WithTransactor(tx)).ImportItems(context.Background(), src, ImportOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.committed)
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("正常系: 行の結果は上限までにして、件数はすべて数える", func(t *testing.T) {
+		rows := make([]*ImportRow, MaxImportReportRows+1)
+		for i := range rows {
+			rows[i] = validRow(i+2, "デイトナ")
+		}
+
+		report, err := NewItemUsecase(new(MockItemRepository)).ImportItems(context.Background(), &sliceImportSource{rows: rows}, ImportOptions{DryRun: true})
+
+		require.NoError(t, err)
+		assert.Len(t, report.Rows, MaxImportReportRows)
+		assert.True(t, report.RowsTruncated)
+		assert.Equal(t, MaxImportReportRows+1, report.Created)
+	})
+
+	t.Run("異常系: atomic で上限を超える行は取り込まない", func(t *testing.T) {
+		rows := make([]*ImportRow, MaxAtomicImportRows+1)
+		for i := range rows {
+			rows[i] = validRow(i+2, "デイトナ")
+		}
+		tx := &recordingTransactor{}
+
+		_, err := NewItemUsecase(new(MockItemRepository), WithTransactor(tx)).ImportItems(context.Background(), &sliceImportSource{rows: rows}, ImportOptions{})
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		assert.Equal(t, [2]int{0, 0}, [2]int{tx.committed, tx.rolledBack})
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+
+		_, err := NewItemUsecase(mockRepo).ImportItems(context.Background(), source(), ImportOptions{Mode: ImportModeBestEffort})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
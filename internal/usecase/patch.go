@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// PatchField は JSON Merge Patch (RFC 7396) における1フィールドの指定状態。
+// キーが無ければ変更なし、null なら値の削除、それ以外は値の置き換えを表す
+type PatchField[T any] struct {
+	Present bool // リクエストにキーが含まれていたか
+	Null    bool // null が指定されたか
+	Value   T
+}
+
+// Set は値を置き換える PatchField を返す
+func Set[T any](v T) PatchField[T] {
+	return PatchField[T]{Present: true, Value: v}
+}
+
+// Null は値の削除を表す PatchField を返す
+func Null[T any]() PatchField[T] {
+	return PatchField[T]{Present: true, Null: true}
+}
+
+// UnmarshalJSON はキーが存在する場合にのみ呼ばれるため、ここで Present を立てる
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Present = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
@@ -2,27 +2,155 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"Aicon-assignment/internal/domain/entity"
 )
 
 // ItemRepository defines the interface for item data access
 type ItemRepository interface {
+	// Read methods exclude trashed items unless the criteria asks for them.
+
 	// FindAll retrieves all items
 	FindAll(ctx context.Context) ([]*entity.Item, error)
 
+	// FindByCriteria retrieves items matching the criteria, sorted and paged
+	FindByCriteria(ctx context.Context, criteria ItemCriteria) ([]*entity.Item, error)
+
+	// CountByCriteria returns the number of items matching the criteria, ignoring paging
+	CountByCriteria(ctx context.Context, criteria ItemCriteria) (int, error)
+
+	// ForEachByCriteria calls fn for every item matching the criteria, sorted like FindByCriteria but ignoring paging.
+	// Items are read in batches so the whole result is never held in memory; an error returned by fn stops the iteration
+	ForEachByCriteria(ctx context.Context, criteria ItemCriteria, fn func(item *entity.Item) error) error
+
+	// FindByCursor retrieves up to criteria.Limit items ordered by (created_at, id) after the given key.
+	// It fetches one extra row to report whether more items follow
+	FindByCursor(ctx context.Context, criteria ItemCriteria, after *CursorKey) ([]*entity.Item, bool, error)
+
+	// Search retrieves items whose name, brand or notes contain every term of criteria.Terms,
+	// most relevant first and paged, together with the number of matching items ignoring paging
+	Search(ctx context.Context, criteria SearchCriteria) ([]*entity.Item, int, error)
+
 	// FindByID retrieves an item by ID
 	FindByID(ctx context.Context, id int64) (*entity.Item, error)
 
-	// Create creates a new item and returns it with the generated ID
+	// Create creates a new item with its tags and returns it with the generated ID
 	Create(ctx context.Context, item *entity.Item) (*entity.Item, error)
 
-	// Update updates items name, brand, purchase_price
-	UpdatePartially(ctx context.Context, id int64, item *entity.Item) (*entity.Item, error)
+	// CreateMany creates items with their tags using multi-row inserts and
+	// returns them with the generated IDs, in the given order
+	CreateMany(ctx context.Context, items []*entity.Item) ([]*entity.Item, error)
 
-	// Delete deletes an item by ID
-	Delete(ctx context.Context, id int64) error
+	// Update replaces all fields of item, provided item.Version still matches the stored version
+	Update(ctx context.Context, item *entity.Item) (*entity.Item, error)
+
+	// UpdatePartially writes only the given fields of item, including zero values,
+	// provided item.Version still matches the stored version
+	UpdatePartially(ctx context.Context, id int64, item *entity.Item, fields []entity.ItemField) (*entity.Item, error)
+
+	// Delete moves an item to the trash, provided version still matches the stored version
+	Delete(ctx context.Context, id int64, version int64) error
+
+	// FindByCategory retrieves every item in the category, including trashed ones, ordered by ID
+	FindByCategory(ctx context.Context, category string) ([]*entity.Item, error)
+
+	// Recategorize moves an item, including a trashed one, to item.Category and replaces its attributes
+	// with item.Attributes, provided item.Version still matches the stored version
+	Recategorize(ctx context.Context, item *entity.Item) (*entity.Item, error)
+
+	// FindByBrand retrieves every item linked to the brand, including trashed ones, ordered by ID
+	FindByBrand(ctx context.Context, brandID int64) ([]*entity.Item, error)
+
+	// Rebrand sets the brand_canonical of an item, including a trashed one, to item.BrandCanonical,
+	// provided item.Version still matches the stored version
+	Rebrand(ctx context.Context, item *entity.Item) (*entity.Item, error)
+
+	// Restore moves a trashed item back out of the trash
+	Restore(ctx context.Context, id int64) (*entity.Item, error)
+
+	// PurgeDeletedBefore permanently removes items trashed before the given time and returns how many were removed
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
 
 	// GetSummaryByCategory returns item counts grouped by category (bonus feature)
 	GetSummaryByCategory(ctx context.Context) (map[string]int, error)
+
+	// GetTagCounts returns the number of items per tag, excluding trashed items and unused tags,
+	// ordered by count descending and then by name
+	GetTagCounts(ctx context.Context) ([]entity.TagCount, error)
+}
+
+// ItemIndex defines the interface for the in-process index of items outside the trash used for faceted search.
+// The item usecase updates it after its writes are committed; changes made elsewhere appear after the next Rebuild
+type ItemIndex interface {
+	// Rebuild replaces the indexed items with the ones returned by load and returns how many items are indexed.
+	// Items put or removed while load runs take precedence over the loaded ones
+	Rebuild(load func() ([]*entity.Item, error)) (int, error)
+
+	// Put adds or replaces an item. A trashed item is removed instead, and an older version than the indexed one is ignored
+	Put(item *entity.Item)
+
+	// Remove removes an item
+	Remove(id int64)
+
+	// Find returns every indexed item matching criteria, sorted by criteria.Sort and criteria.Order without paging.
+	// The returned items are shared with the index and must not be modified
+	Find(criteria FacetCriteria) []*entity.Item
+}
+
+// ItemEventRepository defines the interface for the item audit trail
+type ItemEventRepository interface {
+	// Append stores an audit event
+	Append(ctx context.Context, event *entity.ItemEvent) error
+
+	// Find retrieves events matching the criteria, newest first
+	Find(ctx context.Context, criteria AuditCriteria) ([]*entity.ItemEvent, error)
+
+	// Count returns the number of events matching the criteria, ignoring paging
+	Count(ctx context.Context, criteria AuditCriteria) (int, error)
+}
+
+// BrandRepository defines the interface for the brand catalog
+type BrandRepository interface {
+	// FindAll retrieves all brands with their aliases, ordered by canonical name
+	FindAll(ctx context.Context) ([]*entity.Brand, error)
+
+	// FindByID retrieves a brand by ID
+	FindByID(ctx context.Context, id int64) (*entity.Brand, error)
+
+	// FindByKey retrieves the brand whose canonical name or one of whose canonical aliases equals key
+	FindByKey(ctx context.Context, key string) (*entity.Brand, error)
+
+	// Create creates a new brand with its aliases and returns it with the generated ID
+	Create(ctx context.Context, brand *entity.Brand) (*entity.Brand, error)
+
+	// Update replaces the name, country and aliases of a brand. Items linked to the brand are left as they are
+	// when the canonical name changes; the caller updates them with ItemRepository.Rebrand
+	Update(ctx context.Context, brand *entity.Brand) (*entity.Brand, error)
+
+	// Delete removes a brand and its aliases. It fails with ErrInUse while items are linked to the brand
+	Delete(ctx context.Context, id int64) error
+}
+
+// CategoryRepository defines the interface for the item categories
+type CategoryRepository interface {
+	// FindAll retrieves all categories, including inactive ones, ordered by sort order
+	FindAll(ctx context.Context) ([]*entity.Category, error)
+
+	// FindByID retrieves a category by ID
+	FindByID(ctx context.Context, id int64) (*entity.Category, error)
+
+	// Create creates a new category and returns it with the generated ID.
+	// It fails with ErrDuplicateEntry when another category has the same name
+	Create(ctx context.Context, category *entity.Category) (*entity.Category, error)
+
+	// Update replaces all fields of a category. Items in the category are left as they are
+	// when the name changes; the caller moves them to the new name with ItemRepository.Recategorize
+	Update(ctx context.Context, category *entity.Category) (*entity.Category, error)
+
+	// Delete removes a category. It fails with ErrInUse while items (including trashed ones) are in the category
+	Delete(ctx context.Context, id int64) error
+
+	// CountItems counts items (including trashed ones) in the category with the given name
+	CountItems(ctx context.Context, name string) (int, error)
 }
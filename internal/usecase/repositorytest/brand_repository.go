@@ -0,0 +1,242 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// BrandRepositories はブランドのテストに使うリポジトリ。ItemRepository は同じデータベースを参照すること
+type BrandRepositories struct {
+	Brands usecase.BrandRepository
+	Items  usecase.ItemRepository
+}
+
+// RunBrandRepositoryTests は usecase.BrandRepository の振る舞いを検証する。
+// newRepos はサブテストごとに呼ばれ、ブランドもアイテムも空のリポジトリを返さなければならない
+func RunBrandRepositoryTests(t *testing.T, newRepos func(t *testing.T) BrandRepositories) {
+	t.Run("CreateAndFind", func(t *testing.T) { testBrandCreateAndFind(t, newRepos(t)) })
+	t.Run("Update", func(t *testing.T) { testBrandUpdate(t, newRepos(t)) })
+	t.Run("Rebrand", func(t *testing.T) { testRebrand(t, newRepos(t)) })
+	t.Run("Delete", func(t *testing.T) { testBrandDelete(t, newRepos(t)) })
+}
+
+func createBrand(t *testing.T, repo usecase.BrandRepository, name, country string, aliases ...string) *entity.Brand {
+	t.Helper()
+	brand, err := entity.NewBrand(name, country, aliases)
+	require.NoError(t, err)
+	created, err := repo.Create(context.Background(), brand)
+	require.NoError(t, err)
+	return created
+}
+
+func testBrandCreateAndFind(t *testing.T, repos BrandRepositories) {
+	ctx := context.Background()
+	repo := repos.Brands
+
+	rolex := createBrand(t, repo, "ROLEX", "CH", "ロレックス", "Rolex Watch")
+	assert.NotZero(t, rolex.ID)
+	assert.Equal(t, "ROLEX", rolex.NameCanonical)
+	assert.Equal(t, []string{"ロレックス", "Rolex Watch"}, rolex.Aliases)
+	assert.False(t, rolex.CreatedAt.IsZero())
+	hermes := createBrand(t, repo, "HERMÈS", "FR")
+	assert.Equal(t, []string{}, hermes.Aliases)
+
+	found, err := repo.FindByID(ctx, rolex.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ROLEX", found.Name)
+	assert.Equal(t, "CH", found.Country)
+	assert.Equal(t, []string{"ロレックス", "Rolex Watch"}, found.Aliases)
+
+	_, err = repo.FindByID(ctx, rolex.ID+hermes.ID)
+	assert.ErrorIs(t, err, domainErrors.ErrBrandNotFound)
+
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "HERMÈS", all[0].Name)
+	assert.Equal(t, "ROLEX", all[1].Name)
+	assert.Equal(t, []string{"ロレックス", "Rolex Watch"}, all[1].Aliases)
+
+	tests := []struct {
+		name       string
+		key        string
+		expectedID int64
+		expectErr  error
+	}{
+		{name: "正常系: 正式名の正規形", key: "HERMES", expectedID: hermes.ID},
+		{name: "正常系: 別名の正規形", key: "ロレックス", expectedID: rolex.ID},
+		{name: "正常系: ラテン文字の別名", key: "ROLEX WATCH", expectedID: rolex.ID},
+		{name: "異常系: 登録されていない", key: "OMEGA", expectErr: domainErrors.ErrBrandNotFound},
+		{name: "異常系: 正規形でない値とは一致しない", key: "Rolex", expectErr: domainErrors.ErrBrandNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brand, err := repo.FindByKey(ctx, tt.key)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedID, brand.ID)
+		})
+	}
+
+	t.Run("異常系: 名前や別名が他のブランドと重複する", func(t *testing.T) {
+		duplicate, err := entity.NewBrand("Rolex", "", nil)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, duplicate)
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+
+		duplicate, err = entity.NewBrand("OMEGA", "CH", []string{"エルメス", "hermes"})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, duplicate)
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+}
+
+func testBrandUpdate(t *testing.T, repos BrandRepositories) {
+	ctx := context.Background()
+	repo := repos.Brands
+
+	brand := createBrand(t, repo, "Louboutin", "", "ルブタン")
+	other := createBrand(t, repo, "Apple", "US")
+	item := createItem(t, repos.Items, "パンプス", "靴", "ルブタン", 150000, "2023-04-05")
+	item.BrandID = &brand.ID
+	item.BrandCanonical = brand.NameCanonical
+	item, err := repos.Items.Update(ctx, item)
+	require.NoError(t, err)
+
+	require.NoError(t, brand.Update("Christian Louboutin", "fr", []string{"クリスチャン・ルブタン", "ルブタン", "Louboutin"}))
+	updated, err := repo.Update(ctx, brand)
+	require.NoError(t, err)
+	assert.Equal(t, "Christian Louboutin", updated.Name)
+	assert.Equal(t, "CHRISTIAN LOUBOUTIN", updated.NameCanonical)
+	assert.Equal(t, "FR", updated.Country)
+	assert.Equal(t, []string{"クリスチャン・ルブタン", "ルブタン", "Louboutin"}, updated.Aliases)
+
+	// 名前が変わっても別名で引ける
+	found, err := repo.FindByKey(ctx, "LOUBOUTIN")
+	require.NoError(t, err)
+	assert.Equal(t, brand.ID, found.ID)
+
+	// 紐づくアイテムはそのまま（書き換えは呼び出し側が ItemRepository.Rebrand で行う）
+	linked, err := repos.Items.FindByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "LOUBOUTIN", linked.BrandCanonical)
+	assert.Equal(t, item.Version, linked.Version)
+	require.NotNil(t, linked.BrandID)
+	assert.Equal(t, brand.ID, *linked.BrandID)
+
+	t.Run("異常系: 他のブランドの名前を別名にする", func(t *testing.T) {
+		require.NoError(t, other.Update("Apple", "US", []string{"ルブタン"}))
+		_, err := repo.Update(ctx, other)
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+
+	t.Run("異常系: 存在しないブランド", func(t *testing.T) {
+		missing := *brand
+		missing.ID = brand.ID + other.ID
+		_, err := repo.Update(ctx, &missing)
+		assert.ErrorIs(t, err, domainErrors.ErrBrandNotFound)
+	})
+}
+
+func testRebrand(t *testing.T, repos BrandRepositories) {
+	ctx := context.Background()
+
+	brand := createBrand(t, repos.Brands, "Louboutin", "")
+	other := createBrand(t, repos.Brands, "Apple", "US")
+	link := func(item *entity.Item, brand *entity.Brand) *entity.Item {
+		t.Helper()
+		item.BrandID = &brand.ID
+		item.BrandCanonical = brand.NameCanonical
+		linked, err := repos.Items.Update(ctx, item)
+		require.NoError(t, err)
+		return linked
+	}
+	pumps := link(createItem(t, repos.Items, "パンプス", "靴", "Louboutin", 150000, "2023-04-05"), brand)
+	trashed := link(createItem(t, repos.Items, "スニーカー", "靴", "ルブタン", 120000, "2023-05-01"), brand)
+	require.NoError(t, repos.Items.Delete(ctx, trashed.ID, trashed.Version))
+	link(createItem(t, repos.Items, "iPhone", "その他", "Apple", 150000, "2023-06-01"), other)
+	createItem(t, repos.Items, "ノーブランド", "その他", "", 1000, "2023-07-01")
+
+	t.Run("正常系: ブランドに紐づくアイテムをゴミ箱にあるものも含めて ID 順に返す", func(t *testing.T) {
+		items, err := repos.Items.FindByBrand(ctx, brand.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"パンプス", "スニーカー"}, itemNames(items))
+		assert.NotNil(t, items[1].DeletedAt)
+
+		items, err = repos.Items.FindByBrand(ctx, brand.ID+other.ID)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("正常系: ゴミ箱にあるアイテムも書き換え、バージョンが上がる", func(t *testing.T) {
+		items, err := repos.Items.FindByBrand(ctx, brand.ID)
+		require.NoError(t, err)
+		for _, item := range items {
+			item.BrandCanonical = "CHRISTIAN LOUBOUTIN"
+			rebranded, err := repos.Items.Rebrand(ctx, item)
+			require.NoError(t, err)
+			assert.Equal(t, "CHRISTIAN LOUBOUTIN", rebranded.BrandCanonical)
+			assert.Equal(t, item.Brand, rebranded.Brand)
+			assert.Equal(t, item.Version+1, rebranded.Version)
+			assert.Equal(t, item.DeletedAt != nil, rebranded.DeletedAt != nil)
+		}
+
+		items, err = repos.Items.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Brand: "Christian Louboutin"}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"パンプス"}, itemNames(items))
+	})
+
+	t.Run("異常系: バージョン不一致", func(t *testing.T) {
+		_, err := repos.Items.Rebrand(ctx, &entity.Item{ID: pumps.ID, BrandCanonical: "LOUBOUTIN", Version: pumps.Version})
+		assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		_, err := repos.Items.Rebrand(ctx, &entity.Item{ID: 99999, BrandCanonical: "LOUBOUTIN", Version: 1})
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func testBrandDelete(t *testing.T, repos BrandRepositories) {
+	ctx := context.Background()
+	repo := repos.Brands
+
+	unused := createBrand(t, repo, "OMEGA", "CH", "オメガ")
+	used := createBrand(t, repo, "ROLEX", "CH")
+	item := createItem(t, repos.Items, "デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	item.BrandID = &used.ID
+	item, err := repos.Items.Update(ctx, item)
+	require.NoError(t, err)
+
+	t.Run("正常系: 別名ごと削除する", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, unused.ID))
+		_, err := repo.FindByID(ctx, unused.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrBrandNotFound)
+		_, err = repo.FindByKey(ctx, "オメガ")
+		assert.ErrorIs(t, err, domainErrors.ErrBrandNotFound)
+	})
+
+	t.Run("異常系: アイテムが紐づいている", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(ctx, used.ID), domainErrors.ErrInUse)
+	})
+
+	t.Run("異常系: ゴミ箱のアイテムが紐づいている", func(t *testing.T) {
+		require.NoError(t, repos.Items.Delete(ctx, item.ID, item.Version))
+		assert.ErrorIs(t, repo.Delete(ctx, used.ID), domainErrors.ErrInUse)
+	})
+
+	t.Run("異常系: 存在しないブランド", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(ctx, unused.ID), domainErrors.ErrBrandNotFound)
+	})
+}
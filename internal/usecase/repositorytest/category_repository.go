@@ -0,0 +1,258 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// CategoryRepositories はカテゴリーのテストに使うリポジトリ。ItemRepository は同じデータベースを参照すること
+type CategoryRepositories struct {
+	Categories usecase.CategoryRepository
+	Items      usecase.ItemRepository
+}
+
+// RunCategoryRepositoryTests は usecase.CategoryRepository の振る舞いを検証する。
+// newRepos はサブテストごとに呼ばれ、既定のカテゴリー（entity.ValidCategories）のみを持ち、アイテムが空のリポジトリを返さなければならない
+func RunCategoryRepositoryTests(t *testing.T, newRepos func(t *testing.T) CategoryRepositories) {
+	t.Run("CreateAndFind", func(t *testing.T) { testCategoryCreateAndFind(t, newRepos(t)) })
+	t.Run("Update", func(t *testing.T) { testCategoryUpdate(t, newRepos(t)) })
+	t.Run("Delete", func(t *testing.T) { testCategoryDelete(t, newRepos(t)) })
+	t.Run("Hierarchy", func(t *testing.T) { testCategoryHierarchy(t, newRepos(t)) })
+	t.Run("Attributes", func(t *testing.T) { testCategoryAttributes(t, newRepos(t)) })
+}
+
+func createCategory(t *testing.T, repo usecase.CategoryRepository, name, displayNameEn string, sortOrder int) *entity.Category {
+	t.Helper()
+	category, err := entity.NewCategory(name, name, displayNameEn, nil, nil, sortOrder, true)
+	require.NoError(t, err)
+	created, err := repo.Create(context.Background(), category)
+	require.NoError(t, err)
+	return created
+}
+
+func categoryNames(categories []*entity.Category) []string {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = category.Name
+	}
+	return names
+}
+
+func testCategoryCreateAndFind(t *testing.T, repos CategoryRepositories) {
+	ctx := context.Background()
+	repo := repos.Categories
+
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ValidCategories, categoryNames(all))
+	assert.Equal(t, "Watches", all[0].DisplayNameEn)
+	assert.True(t, all[0].Active)
+
+	art := createCategory(t, repo, "アート", "Art", 35)
+	assert.NotZero(t, art.ID)
+	assert.False(t, art.CreatedAt.IsZero())
+
+	inactive, err := entity.NewCategory("車", "車", "Cars", nil, nil, 5, false)
+	require.NoError(t, err)
+	car, err := repo.Create(ctx, inactive)
+	require.NoError(t, err)
+	assert.False(t, car.Active)
+
+	found, err := repo.FindByID(ctx, art.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "アート", found.Name)
+	assert.Equal(t, "Art", found.DisplayNameEn)
+	assert.Equal(t, 35, found.SortOrder)
+	assert.True(t, found.Active)
+
+	_, err = repo.FindByID(ctx, art.ID+car.ID)
+	assert.ErrorIs(t, err, domainErrors.ErrCategoryNotFound)
+
+	all, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"車", "時計", "バッグ", "ジュエリー", "アート", "靴", "その他"}, categoryNames(all))
+
+	t.Run("異常系: 名前が重複", func(t *testing.T) {
+		duplicate, err := entity.NewCategory("アート", "美術品", "Fine Art", nil, nil, 0, true)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, duplicate)
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+}
+
+func testCategoryUpdate(t *testing.T, repos CategoryRepositories) {
+	ctx := context.Background()
+	repo := repos.Categories
+
+	art := createCategory(t, repo, "アート", "Art", 60)
+	item := createItem(t, repos.Items, "版画", "アート", "Brand", 10000, "2023-01-01")
+
+	require.NoError(t, art.Update("美術品", "美術品", "Fine Art", nil, nil, 15, false))
+	updated, err := repo.Update(ctx, art)
+	require.NoError(t, err)
+	assert.Equal(t, "美術品", updated.Name)
+	assert.Equal(t, "Fine Art", updated.DisplayNameEn)
+	assert.Equal(t, 15, updated.SortOrder)
+	assert.False(t, updated.Active)
+
+	// アイテムの移動は呼び出し側が ItemRepository.Recategorize で行う
+	stored, err := repos.Items.FindByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "アート", stored.Category)
+	assert.Equal(t, item.Version, stored.Version)
+
+	t.Run("異常系: 他のカテゴリーと同じ名前", func(t *testing.T) {
+		require.NoError(t, updated.Update("時計", "時計", "Watches", nil, nil, 15, true))
+		_, err := repo.Update(ctx, updated)
+		assert.ErrorIs(t, err, domainErrors.ErrDuplicateEntry)
+	})
+
+	t.Run("異常系: 存在しない", func(t *testing.T) {
+		missing := *art
+		missing.ID = art.ID + 1000
+		_, err := repo.Update(ctx, &missing)
+		assert.ErrorIs(t, err, domainErrors.ErrCategoryNotFound)
+	})
+}
+
+func testCategoryDelete(t *testing.T, repos CategoryRepositories) {
+	ctx := context.Background()
+	repo := repos.Categories
+
+	art := createCategory(t, repo, "アート", "Art", 60)
+	item := createItem(t, repos.Items, "版画", "アート", "Brand", 10000, "2023-01-01")
+	trashed := createItem(t, repos.Items, "彫刻", "アート", "Brand", 20000, "2023-01-01")
+	require.NoError(t, repos.Items.Delete(ctx, trashed.ID, trashed.Version))
+
+	t.Run("正常系: ゴミ箱のアイテムも数える", func(t *testing.T) {
+		count, err := repo.CountItems(ctx, "アート")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("異常系: アイテムが属している", func(t *testing.T) {
+		err := repo.Delete(ctx, art.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrInUse)
+	})
+
+	t.Run("異常系: ゴミ箱のアイテムだけが属している", func(t *testing.T) {
+		moved := *item
+		moved.Category = "その他"
+		_, err := repos.Items.Recategorize(ctx, &moved)
+		require.NoError(t, err)
+
+		err = repo.Delete(ctx, art.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrInUse)
+	})
+
+	t.Run("正常系: アイテムが無くなれば削除できる", func(t *testing.T) {
+		items, err := repos.Items.FindByCategory(ctx, "アート")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		items[0].Category = "その他"
+		_, err = repos.Items.Recategorize(ctx, items[0])
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, art.ID))
+		_, err = repo.FindByID(ctx, art.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrCategoryNotFound)
+	})
+
+	t.Run("正常系: アイテムが無ければそのまま削除", func(t *testing.T) {
+		empty := createCategory(t, repo, "車", "Cars", 70)
+		require.NoError(t, repo.Delete(ctx, empty.ID))
+	})
+
+	t.Run("異常系: 存在しない", func(t *testing.T) {
+		err := repo.Delete(ctx, art.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrCategoryNotFound)
+	})
+}
+
+func testCategoryHierarchy(t *testing.T, repos CategoryRepositories) {
+	ctx := context.Background()
+	repo := repos.Categories
+
+	art := createCategory(t, repo, "アート", "Art", 60)
+	child, err := entity.NewCategory("版画", "版画", "Prints", &art.ID, nil, 10, true)
+	require.NoError(t, err)
+	prints, err := repo.Create(ctx, child)
+	require.NoError(t, err)
+	require.NotNil(t, prints.ParentID)
+	assert.Equal(t, art.ID, *prints.ParentID)
+
+	found, err := repo.FindByID(ctx, art.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.ParentID)
+
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	tree := entity.NewCategoryTree(all)
+	assert.Equal(t, []string{"版画"}, categoryNames(tree.Children(art.ID)))
+
+	// 最上位へ移す
+	require.NoError(t, prints.Update("版画", "版画", "Prints", nil, nil, 10, true))
+	updated, err := repo.Update(ctx, prints)
+	require.NoError(t, err)
+	assert.Nil(t, updated.ParentID)
+
+	// 再び子にする
+	require.NoError(t, updated.Update("版画", "版画", "Prints", &art.ID, nil, 10, true))
+	updated, err = repo.Update(ctx, updated)
+	require.NoError(t, err)
+	require.NotNil(t, updated.ParentID)
+	assert.Equal(t, art.ID, *updated.ParentID)
+}
+
+func testCategoryAttributes(t *testing.T, repos CategoryRepositories) {
+	ctx := context.Background()
+	repo := repos.Categories
+
+	attributes := []entity.AttributeDefinition{
+		{Key: "artist", Type: entity.AttributeTypeString, Required: true},
+		{Key: "technique", Type: entity.AttributeTypeEnum, Options: []string{"油彩", "水彩", "版画"}},
+		{Key: "width", Type: entity.AttributeTypeNumber, Unit: "cm"},
+	}
+	category, err := entity.NewCategory("アート", "アート", "Art", nil, attributes, 60, true)
+	require.NoError(t, err)
+
+	t.Run("正常系: 属性の定義を定義順に保存する", func(t *testing.T) {
+		created, err := repo.Create(ctx, category)
+		require.NoError(t, err)
+		assert.Equal(t, attributes, created.Attributes)
+
+		all, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		for _, c := range all {
+			if c.ID == created.ID {
+				assert.Equal(t, attributes, c.Attributes)
+			} else if c.Name == "バッグ" {
+				assert.Empty(t, c.Attributes)
+			}
+		}
+		category = created
+	})
+
+	t.Run("正常系: 更新で属性の定義を置き換える", func(t *testing.T) {
+		replaced := []entity.AttributeDefinition{{Key: "year", Type: entity.AttributeTypeInteger}}
+		require.NoError(t, category.Update("アート", "アート", "Art", nil, replaced, 60, true))
+
+		updated, err := repo.Update(ctx, category)
+		require.NoError(t, err)
+		assert.Equal(t, replaced, updated.Attributes)
+	})
+
+	t.Run("正常系: 削除すると属性の定義も消える", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, category.ID))
+
+		recreated := createCategory(t, repo, "アート", "Art", 60)
+		assert.Empty(t, recreated.Attributes)
+	})
+}
@@ -0,0 +1,103 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	"Aicon-assignment/internal/usecase"
+)
+
+// RunItemEventRepositoryTests は usecase.ItemEventRepository の振る舞いを検証する。
+// newRepo はサブテストごとに呼ばれ、空のリポジトリを返さなければならない
+func RunItemEventRepositoryTests(t *testing.T, newRepo func(t *testing.T) usecase.ItemEventRepository) {
+	t.Run("AppendAndFind", func(t *testing.T) { testAppendAndFind(t, newRepo(t)) })
+}
+
+func testAppendAndFind(t *testing.T, repo usecase.ItemEventRepository) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []*entity.ItemEvent{
+		{ItemID: 1, Action: entity.ItemEventCreate, Actor: "tanaka", RequestID: "req-1", CreatedAt: base,
+			Changes: map[string]entity.FieldChange{"name": {Before: nil, After: "デイトナ"}}},
+		{ItemID: 1, Action: entity.ItemEventUpdate, Actor: "suzuki", RequestID: "req-2", CreatedAt: base.Add(time.Hour),
+			Changes: map[string]entity.FieldChange{"purchase_price": {Before: float64(100), After: float64(200)}}},
+		{ItemID: 2, Action: entity.ItemEventCreate, Actor: "tanaka", RequestID: "req-3", CreatedAt: base.Add(2 * time.Hour),
+			Changes: map[string]entity.FieldChange{"name": {Before: nil, After: "バーキン"}}},
+	}
+	for _, event := range events {
+		require.NoError(t, repo.Append(ctx, event))
+		assert.NotZero(t, event.ID)
+	}
+
+	itemID := int64(1)
+	from, to := base.Add(30*time.Minute), base.Add(2*time.Hour)
+	tests := []struct {
+		name             string
+		criteria         usecase.AuditCriteria
+		expectedRequests []string
+		expectedTotal    int
+	}{
+		{
+			name:             "正常系: アイテムの履歴を新しい順に取得",
+			criteria:         usecase.AuditCriteria{ItemID: &itemID},
+			expectedRequests: []string{"req-2", "req-1"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "正常系: 操作者と操作で絞り込み",
+			criteria:         usecase.AuditCriteria{Actor: "tanaka", Action: entity.ItemEventCreate},
+			expectedRequests: []string{"req-3", "req-1"},
+			expectedTotal:    2,
+		},
+		{
+			name:             "正常系: 期間は from を含み to を含まない",
+			criteria:         usecase.AuditCriteria{From: &from, To: &to},
+			expectedRequests: []string{"req-2"},
+			expectedTotal:    1,
+		},
+		{
+			name:             "正常系: ページング",
+			criteria:         usecase.AuditCriteria{Limit: 1, Offset: 1},
+			expectedRequests: []string{"req-2"},
+			expectedTotal:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria, err := tt.criteria.Normalize()
+			require.NoError(t, err)
+
+			found, err := repo.Find(ctx, criteria)
+			require.NoError(t, err)
+			total, err := repo.Count(ctx, criteria)
+			require.NoError(t, err)
+
+			requests := []string{}
+			for _, e := range found {
+				requests = append(requests, e.RequestID)
+			}
+			assert.Equal(t, tt.expectedRequests, requests)
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+
+	t.Run("正常系: 変更内容と日時が保存される", func(t *testing.T) {
+		criteria, err := usecase.AuditCriteria{ItemID: &itemID}.Normalize()
+		require.NoError(t, err)
+		found, err := repo.Find(ctx, criteria)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+
+		assert.Equal(t, "suzuki", found[0].Actor)
+		assert.Equal(t, entity.ItemEventUpdate, found[0].Action)
+		assert.Equal(t, map[string]entity.FieldChange{"purchase_price": {Before: float64(100), After: float64(200)}}, found[0].Changes)
+		assert.True(t, base.Add(time.Hour).Equal(found[0].CreatedAt), "created_at = %s", found[0].CreatedAt)
+	})
+}
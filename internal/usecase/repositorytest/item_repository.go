@@ -0,0 +1,966 @@
+// Package repositorytest は usecase のリポジトリ実装が満たすべき振る舞いのテストスイート。
+// すべての実装（MySQL・SQLite・メモリなど）が同じスイートを通ることで、実装間の差異を防ぐ
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Aicon-assignment/internal/domain/entity"
+	domainErrors "Aicon-assignment/internal/domain/errors"
+	"Aicon-assignment/internal/usecase"
+)
+
+// RunItemRepositoryTests は usecase.ItemRepository の振る舞いを検証する。
+// newRepo はサブテストごとに呼ばれ、空のリポジトリを返さなければならない
+func RunItemRepositoryTests(t *testing.T, newRepo func(t *testing.T) usecase.ItemRepository) {
+	t.Run("CreateAndFind", func(t *testing.T) { testCreateAndFind(t, newRepo(t)) })
+	t.Run("CreateMany", func(t *testing.T) { testCreateMany(t, newRepo(t)) })
+	t.Run("FindAll", func(t *testing.T) { testFindAll(t, newRepo(t)) })
+	t.Run("FindByCriteria", func(t *testing.T) { testFindByCriteria(t, newRepo(t)) })
+	t.Run("FindByCursor", func(t *testing.T) { testFindByCursor(t, newRepo(t)) })
+	t.Run("ForEachByCriteria", func(t *testing.T) { testForEachByCriteria(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepo(t)) })
+	t.Run("UpdatePartially", func(t *testing.T) { testUpdatePartially(t, newRepo(t)) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newRepo(t)) })
+	t.Run("Recategorize", func(t *testing.T) { testRecategorize(t, newRepo(t)) })
+	t.Run("GetSummaryByCategory", func(t *testing.T) { testGetSummaryByCategory(t, newRepo(t)) })
+	t.Run("ConcurrentWrites", func(t *testing.T) { testConcurrentWrites(t, newRepo(t)) })
+	t.Run("Attributes", func(t *testing.T) { testAttributes(t, newRepo(t)) })
+	t.Run("Tags", func(t *testing.T) { testTags(t, newRepo(t)) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, newRepo(t)) })
+}
+
+// createItem は usecase と同じく BrandCanonical を設定してアイテムを作成する
+func createItem(t *testing.T, repo usecase.ItemRepository, name, category, brand string, price int, date string) *entity.Item {
+	t.Helper()
+	item, err := repo.Create(context.Background(), &entity.Item{
+		Name:           name,
+		Category:       category,
+		Brand:          brand,
+		BrandCanonical: entity.CanonicalBrand(brand),
+		PurchasePrice:  price,
+		PurchaseDate:   date,
+	})
+	require.NoError(t, err)
+	return item
+}
+
+func itemNames(items []*entity.Item) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func normalize(t *testing.T, criteria usecase.ItemCriteria) usecase.ItemCriteria {
+	t.Helper()
+	normalized, err := criteria.Normalize()
+	require.NoError(t, err)
+	return normalized
+}
+
+func testCreateAndFind(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: 作成したアイテムを取得できる", func(t *testing.T) {
+		created := createItem(t, repo, "ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+
+		found, err := repo.FindByID(ctx, created.ID)
+
+		require.NoError(t, err)
+		assert.NotZero(t, found.ID)
+		assert.Equal(t, "ロレックス デイトナ", found.Name)
+		assert.Equal(t, "時計", found.Category)
+		assert.Equal(t, "ROLEX", found.Brand)
+		assert.Equal(t, "ROLEX", found.BrandCanonical)
+		assert.Equal(t, 1500000, found.PurchasePrice)
+		assert.Equal(t, "2023-01-15", found.PurchaseDate)
+		assert.Equal(t, int64(1), found.Version)
+		assert.WithinDuration(t, time.Now(), found.CreatedAt, time.Minute)
+		assert.Nil(t, found.DeletedAt)
+	})
+
+	t.Run("正常系: ID は作成ごとに異なる", func(t *testing.T) {
+		a := createItem(t, repo, "a", "その他", "Brand", 1000, "2023-01-01")
+		b := createItem(t, repo, "b", "その他", "Brand", 1000, "2023-01-01")
+
+		assert.NotEqual(t, a.ID, b.ID)
+	})
+
+	t.Run("正常系: 返されたアイテムを変更しても保存済みのデータは変わらない", func(t *testing.T) {
+		created := createItem(t, repo, "元の名前", "その他", "Brand", 1000, "2023-01-01")
+		created.Name = "書き換え"
+
+		found, err := repo.FindByID(ctx, created.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "元の名前", found.Name)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		_, err := repo.FindByID(ctx, 999999)
+
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func testCreateMany(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: 指定した順に作成し、属性とタグも保存する", func(t *testing.T) {
+		// 既存のタグと新しいタグが混ざるようにする
+		existing, err := repo.Create(ctx, &entity.Item{
+			Name: "既存", Category: "時計", Brand: "ROLEX", BrandCanonical: "ROLEX",
+			PurchasePrice: 1000, PurchaseDate: "2023-01-01", Tags: entity.Tags{"投資用"},
+		})
+		require.NoError(t, err)
+
+		// 複数行の INSERT の分割を跨ぐ件数にする
+		const n = 1200
+		items := make([]*entity.Item, n)
+		for i := range items {
+			items[i] = &entity.Item{
+				Name: fmt.Sprintf("item-%04d", i), Category: "時計", Brand: "ROLEX", BrandCanonical: "ROLEX",
+				PurchasePrice: 1000 + i, PurchaseDate: "2023-01-15",
+			}
+			if i%100 == 0 {
+				items[i].Attributes = entity.Attributes{"movement": "自動巻き", "case_size": 40.0}
+				items[i].Tags = entity.Tags{fmt.Sprintf("ロット%d", i/100), "投資用"}
+			}
+		}
+
+		created, err := repo.CreateMany(ctx, items)
+
+		require.NoError(t, err)
+		require.Len(t, created, n)
+		seen := map[int64]bool{existing.ID: true}
+		for i, item := range created {
+			assert.Equal(t, items[i].Name, item.Name)
+			assert.False(t, seen[item.ID], "ID が重複している: %d", item.ID)
+			seen[item.ID] = true
+			assert.Equal(t, int64(1), item.Version)
+			assert.Equal(t, items[i].Tags, item.Tags)
+		}
+
+		found, err := repo.FindByID(ctx, created[300].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "item-0300", found.Name)
+		assert.Equal(t, 1300, found.PurchasePrice)
+		assert.Equal(t, entity.Attributes{"movement": "自動巻き", "case_size": 40.0}, found.Attributes)
+		assert.Equal(t, entity.Tags{"ロット3", "投資用"}, found.Tags)
+
+		byAttribute, err := repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Attributes: map[string]string{"movement": "自動巻き"}, Limit: 100}))
+		require.NoError(t, err)
+		assert.Len(t, byAttribute, n/100)
+
+		byTag, err := repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Tags: []string{"投資用"}, Limit: 100}))
+		require.NoError(t, err)
+		assert.Len(t, byTag, n/100+1)
+	})
+
+	t.Run("正常系: 返されたアイテムを変更しても保存済みのデータは変わらない", func(t *testing.T) {
+		input := &entity.Item{Name: "元の名前", Category: "その他", Brand: "Brand", BrandCanonical: "BRAND", PurchasePrice: 1000, PurchaseDate: "2023-01-01"}
+
+		created, err := repo.CreateMany(ctx, []*entity.Item{input})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		created[0].Name = "書き換え"
+		input.Name = "入力の書き換え"
+
+		found, err := repo.FindByID(ctx, created[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "元の名前", found.Name)
+	})
+
+	t.Run("正常系: 空の場合は何も作成しない", func(t *testing.T) {
+		created, err := repo.CreateMany(ctx, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, created)
+	})
+}
+
+func testFindAll(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: ゴミ箱のアイテムを除いてすべて返す", func(t *testing.T) {
+		createItem(t, repo, "a", "その他", "Brand", 1000, "2023-01-01")
+		b := createItem(t, repo, "b", "その他", "Brand", 1000, "2023-01-01")
+		createItem(t, repo, "c", "その他", "Brand", 1000, "2023-01-01")
+		require.NoError(t, repo.Delete(ctx, b.ID, b.Version))
+
+		items, err := repo.FindAll(ctx)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "c"}, itemNames(items))
+	})
+}
+
+func testFindByCriteria(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	createItem(t, repo, "デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	createItem(t, repo, "サブマリーナ", "時計", "ROLEX", 1200000, "2023-06-01")
+	createItem(t, repo, "バーキン", "バッグ", "HERMÈS", 2000000, "2023-02-20")
+	createItem(t, repo, "アップルウォッチ", "その他", "Apple", 50000, "2023-05-12")
+
+	minPrice, maxPrice := 100000, 1500000
+	tests := []struct {
+		name          string
+		criteria      usecase.ItemCriteria
+		expectedNames []string
+		expectedTotal int
+	}{
+		{
+			name:          "正常系: カテゴリーで絞り込み、価格の昇順",
+			criteria:      usecase.ItemCriteria{Category: "時計", Sort: usecase.SortPurchasePrice, Order: usecase.OrderAsc},
+			expectedNames: []string{"サブマリーナ", "デイトナ"},
+			expectedTotal: 2,
+		},
+		{
+			name: "正常系: 複数のカテゴリーのいずれかに属する",
+			criteria: usecase.ItemCriteria{
+				Category: "時計", Categories: []string{"時計", "その他"},
+				Sort: usecase.SortPurchasePrice, Order: usecase.OrderAsc,
+			},
+			expectedNames: []string{"アップルウォッチ", "サブマリーナ", "デイトナ"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "正常系: ブランドは大文字小文字を区別しない",
+			criteria:      usecase.ItemCriteria{Brand: "rolex", Sort: usecase.SortPurchaseDate, Order: usecase.OrderDesc},
+			expectedNames: []string{"サブマリーナ", "デイトナ"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "正常系: ブランドはアクセント記号や全角を区別しない",
+			criteria:      usecase.ItemCriteria{Brand: "ｈｅｒｍｅｓ"},
+			expectedNames: []string{"バーキン"},
+			expectedTotal: 1,
+		},
+		{
+			name: "正常系: 購入日の範囲は両端を含む",
+			criteria: usecase.ItemCriteria{
+				PurchaseDateFrom: "2023-02-20", PurchaseDateTo: "2023-06-01",
+				Sort: usecase.SortPurchaseDate, Order: usecase.OrderAsc,
+			},
+			expectedNames: []string{"バーキン", "アップルウォッチ", "サブマリーナ"},
+			expectedTotal: 3,
+		},
+		{
+			name: "正常系: 価格の範囲は両端を含む",
+			criteria: usecase.ItemCriteria{
+				MinPrice: &minPrice, MaxPrice: &maxPrice,
+				Sort: usecase.SortPurchasePrice, Order: usecase.OrderDesc,
+			},
+			expectedNames: []string{"デイトナ", "サブマリーナ"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "正常系: ページング",
+			criteria:      usecase.ItemCriteria{Sort: usecase.SortPurchasePrice, Order: usecase.OrderDesc, Limit: 2, Offset: 1},
+			expectedNames: []string{"デイトナ", "サブマリーナ"},
+			expectedTotal: 4,
+		},
+		{
+			name:          "正常系: 範囲外のオフセットは空",
+			criteria:      usecase.ItemCriteria{Offset: 10},
+			expectedNames: []string{},
+			expectedTotal: 4,
+		},
+		{
+			name:          "正常系: 該当なし",
+			criteria:      usecase.ItemCriteria{Category: "靴"},
+			expectedNames: []string{},
+			expectedTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := normalize(t, tt.criteria)
+
+			items, err := repo.FindByCriteria(ctx, criteria)
+			require.NoError(t, err)
+			total, err := repo.CountByCriteria(ctx, criteria)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedNames, itemNames(items))
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func testFindByCursor(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	// 同じ時刻に作成されて created_at が重複しても、id で順序が決まる
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		createItem(t, repo, name, "その他", "Brand", 1000, "2023-01-01")
+	}
+
+	collect := func(t *testing.T, criteria usecase.ItemCriteria) ([]string, int) {
+		var (
+			seen  []string
+			after *usecase.CursorKey
+			pages int
+		)
+		for {
+			items, hasMore, err := repo.FindByCursor(ctx, criteria, after)
+			require.NoError(t, err)
+			seen = append(seen, itemNames(items)...)
+			pages++
+			if !hasMore {
+				return seen, pages
+			}
+			require.NotEmpty(t, items)
+			last := items[len(items)-1]
+			after = &usecase.CursorKey{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+	}
+
+	t.Run("正常系: 新しい順に全件を重複・欠落なく辿れる", func(t *testing.T) {
+		seen, pages := collect(t, normalize(t, usecase.ItemCriteria{Limit: 2}))
+
+		assert.Equal(t, []string{"e", "d", "c", "b", "a"}, seen)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("正常系: 古い順に辿れる", func(t *testing.T) {
+		seen, _ := collect(t, normalize(t, usecase.ItemCriteria{Limit: 3, Order: usecase.OrderAsc}))
+
+		assert.Equal(t, []string{"a", "b", "c", "d", "e"}, seen)
+	})
+
+	t.Run("正常系: 件数がちょうど limit の場合は次ページなし", func(t *testing.T) {
+		items, hasMore, err := repo.FindByCursor(ctx, normalize(t, usecase.ItemCriteria{Limit: 5}), nil)
+
+		require.NoError(t, err)
+		assert.Len(t, items, 5)
+		assert.False(t, hasMore)
+	})
+}
+
+func testForEachByCriteria(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	// 実装が何件ずつ読んでも区切りをまたぐよう、並び替えの値が重複するアイテムを多めに作成する
+	const count = 1200
+	for i := range count {
+		createItem(t, repo, fmt.Sprintf("item%04d", i), "その他", "Brand", (i%3)*1000, "2023-01-01")
+	}
+	tagged := createItem(t, repo, "tagged", "時計", "ROLEX", 5000, "2023-02-01")
+	tagged.Tags = entity.Tags{"新品"}
+	_, err := repo.UpdatePartially(ctx, tagged.ID, tagged, []entity.ItemField{entity.ItemFieldTags})
+	require.NoError(t, err)
+	trashed := createItem(t, repo, "trashed", "その他", "Brand", 1000, "2023-01-01")
+	require.NoError(t, repo.Delete(ctx, trashed.ID, trashed.Version))
+
+	collect := func(t *testing.T, criteria usecase.ItemCriteria) []*entity.Item {
+		t.Helper()
+		var items []*entity.Item
+		require.NoError(t, repo.ForEachByCriteria(ctx, criteria, func(item *entity.Item) error {
+			items = append(items, item)
+			return nil
+		}))
+		return items
+	}
+
+	t.Run("正常系: 条件に一致するすべてのアイテムを FindByCriteria と同じ順に重複・欠落なく辿る", func(t *testing.T) {
+		items := collect(t, normalize(t, usecase.ItemCriteria{Sort: usecase.SortPurchasePrice, Order: usecase.OrderAsc, Limit: 1, Offset: 5}))
+
+		require.Len(t, items, count+1, "ページングは無視する")
+		seen := make(map[int64]bool, len(items))
+		for i, item := range items {
+			assert.False(t, seen[item.ID], "重複: %s", item.Name)
+			seen[item.ID] = true
+			if i > 0 {
+				prev := items[i-1]
+				assert.True(t, prev.PurchasePrice < item.PurchasePrice || prev.PurchasePrice == item.PurchasePrice && prev.ID < item.ID,
+					"%s の後に %s", prev.Name, item.Name)
+			}
+		}
+		assert.Equal(t, "tagged", items[len(items)-1].Name)
+		assert.Equal(t, entity.Tags{"新品"}, items[len(items)-1].Tags, "タグも読み込む")
+	})
+
+	t.Run("正常系: 新しい順に辿れる", func(t *testing.T) {
+		items := collect(t, normalize(t, usecase.ItemCriteria{}))
+
+		require.Len(t, items, count+1)
+		assert.Equal(t, "tagged", items[0].Name)
+		assert.Equal(t, "item0000", items[len(items)-1].Name)
+	})
+
+	t.Run("正常系: 条件で絞り込む", func(t *testing.T) {
+		items := collect(t, normalize(t, usecase.ItemCriteria{Category: "時計"}))
+		assert.Equal(t, []string{"tagged"}, itemNames(items))
+
+		items = collect(t, normalize(t, usecase.ItemCriteria{Trashed: true}))
+		assert.Equal(t, []string{"trashed"}, itemNames(items))
+	})
+
+	t.Run("異常系: fn がエラーを返すと中断する", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := repo.ForEachByCriteria(ctx, normalize(t, usecase.ItemCriteria{}), func(item *entity.Item) error {
+			calls++
+			return stop
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func testUpdate(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: バージョンが一致すれば全置換され、バージョンが上がる", func(t *testing.T) {
+		item := createItem(t, repo, "デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+		item.Name, item.PurchasePrice, item.PurchaseDate = "デイトナ 116500LN", 1800000, "2023-02-01"
+
+		updated, err := repo.Update(ctx, item)
+
+		require.NoError(t, err)
+		assert.Equal(t, "デイトナ 116500LN", updated.Name)
+		assert.Equal(t, 1800000, updated.PurchasePrice)
+		assert.Equal(t, "2023-02-01", updated.PurchaseDate)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.False(t, updated.UpdatedAt.Before(item.UpdatedAt))
+	})
+
+	t.Run("異常系: バージョン不一致", func(t *testing.T) {
+		item := createItem(t, repo, "バーキン", "バッグ", "HERMÈS", 2000000, "2023-02-20")
+		item.Version = 5
+
+		_, err := repo.Update(ctx, item)
+
+		assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		_, err := repo.Update(ctx, &entity.Item{ID: 999999, Name: "x", Version: 1})
+
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func testUpdatePartially(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: 指定したフィールドだけを更新し、ゼロ値も書き込む", func(t *testing.T) {
+		item := createItem(t, repo, "ネックレス", "ジュエリー", "Tiffany & Co.", 300000, "2023-03-10")
+
+		updated, err := repo.UpdatePartially(ctx, item.ID,
+			&entity.Item{PurchasePrice: 0, Name: "無視される", Version: item.Version},
+			[]entity.ItemField{entity.ItemFieldPurchasePrice})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ネックレス", updated.Name)
+		assert.Equal(t, 0, updated.PurchasePrice)
+		assert.Equal(t, item.Version+1, updated.Version)
+	})
+
+	t.Run("正常系: 複数フィールドの更新", func(t *testing.T) {
+		item := createItem(t, repo, "パンプス", "靴", "Christian Louboutin", 150000, "2023-04-05")
+
+		updated, err := repo.UpdatePartially(ctx, item.ID,
+			&entity.Item{Category: "その他", Brand: "Louboutin", BrandCanonical: "LOUBOUTIN", PurchaseDate: "2024-01-01", Version: item.Version},
+			[]entity.ItemField{entity.ItemFieldCategory, entity.ItemFieldBrand, entity.ItemFieldPurchaseDate})
+
+		require.NoError(t, err)
+		assert.Equal(t, "その他", updated.Category)
+		assert.Equal(t, "Louboutin", updated.Brand)
+		assert.Equal(t, "LOUBOUTIN", updated.BrandCanonical)
+		assert.Equal(t, "2024-01-01", updated.PurchaseDate)
+		assert.Equal(t, 150000, updated.PurchasePrice)
+	})
+
+	t.Run("異常系: バージョン不一致", func(t *testing.T) {
+		item := createItem(t, repo, "時計", "時計", "SEIKO", 50000, "2023-01-01")
+
+		_, err := repo.UpdatePartially(ctx, item.ID,
+			&entity.Item{Name: "x", Version: item.Version + 1},
+			[]entity.ItemField{entity.ItemFieldName})
+
+		assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		_, err := repo.UpdatePartially(ctx, 999999,
+			&entity.Item{Name: "x", Version: 1},
+			[]entity.ItemField{entity.ItemFieldName})
+
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("異常系: 更新するフィールドがない", func(t *testing.T) {
+		item := createItem(t, repo, "空", "その他", "Brand", 1000, "2023-01-01")
+
+		_, err := repo.UpdatePartially(ctx, item.ID, &entity.Item{Version: item.Version}, nil)
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+	})
+}
+
+func testRecategorize(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	watch := createItem(t, repo, "デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+	trashed := createItem(t, repo, "懐中時計", "時計", "Waltham", 80000, "2023-02-01")
+	createItem(t, repo, "バーキン", "バッグ", "HERMÈS", 2500000, "2023-03-10")
+	require.NoError(t, repo.Delete(ctx, trashed.ID, trashed.Version))
+
+	t.Run("正常系: カテゴリーのアイテムをゴミ箱にあるものも含めて ID 順に返す", func(t *testing.T) {
+		items, err := repo.FindByCategory(ctx, "時計")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"デイトナ", "懐中時計"}, itemNames(items))
+		assert.NotNil(t, items[1].DeletedAt)
+
+		items, err = repo.FindByCategory(ctx, "ジュエリー")
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("正常系: ゴミ箱にあるアイテムも移動し、バージョンが上がる", func(t *testing.T) {
+		items, err := repo.FindByCategory(ctx, "時計")
+		require.NoError(t, err)
+		for _, item := range items {
+			item.Category = "アンティーク"
+			moved, err := repo.Recategorize(ctx, item)
+			require.NoError(t, err)
+			assert.Equal(t, "アンティーク", moved.Category)
+			assert.Equal(t, item.Version+1, moved.Version)
+			assert.Equal(t, item.DeletedAt != nil, moved.DeletedAt != nil)
+		}
+
+		items, err = repo.FindByCategory(ctx, "時計")
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("正常系: 属性を置き換え、絞り込みの索引にも反映する", func(t *testing.T) {
+		item, err := repo.Create(ctx, &entity.Item{
+			Name: "エクスプローラー", Category: "腕時計", Brand: "ROLEX", BrandCanonical: "ROLEX", PurchasePrice: 900000,
+			PurchaseDate: "2023-06-01", Attributes: entity.Attributes{"movement": "自動巻き", "box": true},
+		})
+		require.NoError(t, err)
+
+		item.Category, item.Attributes = "その他", entity.Attributes{"box": true}
+		moved, err := repo.Recategorize(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, entity.Attributes{"box": true}, moved.Attributes)
+
+		items, err := repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Attributes: map[string]string{"movement": "自動巻き"}}))
+		require.NoError(t, err)
+		assert.Empty(t, items)
+		items, err = repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Attributes: map[string]string{"box": "true"}}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"エクスプローラー"}, itemNames(items))
+	})
+
+	t.Run("異常系: バージョン不一致", func(t *testing.T) {
+		_, err := repo.Recategorize(ctx, &entity.Item{ID: watch.ID, Category: "時計", Version: watch.Version})
+		assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+	})
+
+	t.Run("異常系: 存在しないアイテム", func(t *testing.T) {
+		_, err := repo.Recategorize(ctx, &entity.Item{ID: 99999, Category: "時計", Version: 1})
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+}
+
+func testTrash(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	kept := createItem(t, repo, "残す", "靴", "Christian Louboutin", 150000, "2023-04-05")
+	trashed := createItem(t, repo, "捨てる", "その他", "Apple", 50000, "2023-05-12")
+
+	t.Run("正常系: 削除するとゴミ箱に移動する", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, trashed.ID, trashed.Version))
+
+		_, err := repo.FindByID(ctx, trashed.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+
+		criteria := normalize(t, usecase.ItemCriteria{Trashed: true, Sort: usecase.SortDeletedAt})
+		items, err := repo.FindByCriteria(ctx, criteria)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "捨てる", items[0].Name)
+		require.NotNil(t, items[0].DeletedAt)
+		assert.WithinDuration(t, time.Now(), *items[0].DeletedAt, time.Minute)
+
+		count, err := repo.CountByCriteria(ctx, criteria)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("異常系: ゴミ箱のアイテムは更新・削除できない", func(t *testing.T) {
+		err := repo.Delete(ctx, trashed.ID, trashed.Version+1)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+
+		_, err = repo.Update(ctx, &entity.Item{ID: trashed.ID, Name: "x", Version: trashed.Version + 1})
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("異常系: バージョン不一致では削除できない", func(t *testing.T) {
+		err := repo.Delete(ctx, kept.ID, kept.Version+1)
+
+		assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+	})
+
+	t.Run("正常系: ゴミ箱から元に戻せる", func(t *testing.T) {
+		restored, err := repo.Restore(ctx, trashed.ID)
+
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, trashed.Version+2, restored.Version)
+
+		_, err = repo.Restore(ctx, trashed.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+	})
+
+	t.Run("正常系: 指定日時より前にゴミ箱に入ったアイテムだけを物理削除する", func(t *testing.T) {
+		current, err := repo.FindByID(ctx, trashed.ID)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, current.ID, current.Version))
+
+		purged, err := repo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = repo.Restore(ctx, trashed.ID)
+		assert.ErrorIs(t, err, domainErrors.ErrItemNotFound)
+		_, err = repo.FindByID(ctx, kept.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func testGetSummaryByCategory(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: 空の場合は空の集計", func(t *testing.T) {
+		summary, err := repo.GetSummaryByCategory(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, summary)
+	})
+
+	t.Run("正常系: ゴミ箱のアイテムを除いてカテゴリー別に数える", func(t *testing.T) {
+		createItem(t, repo, "a", "時計", "ROLEX", 1000, "2023-01-01")
+		createItem(t, repo, "b", "時計", "OMEGA", 1000, "2023-01-01")
+		createItem(t, repo, "c", "バッグ", "HERMÈS", 1000, "2023-01-01")
+		d := createItem(t, repo, "d", "靴", "Christian Louboutin", 1000, "2023-01-01")
+		require.NoError(t, repo.Delete(ctx, d.ID, d.Version))
+
+		summary, err := repo.GetSummaryByCategory(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"時計": 2, "バッグ": 1}, summary)
+	})
+}
+
+func testConcurrentWrites(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("正常系: 同じバージョンでの並行更新は1つだけが成功する", func(t *testing.T) {
+		item := createItem(t, repo, "並行", "その他", "Brand", 1000, "2023-01-01")
+
+		const writers = 8
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func(price int) {
+				defer wg.Done()
+				_, err := repo.UpdatePartially(ctx, item.ID,
+					&entity.Item{PurchasePrice: price, Version: item.Version},
+					[]entity.ItemField{entity.ItemFieldPurchasePrice})
+				if err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+					return
+				}
+				assert.ErrorIs(t, err, domainErrors.ErrVersionMismatch)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, succeeded)
+		found, err := repo.FindByID(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.Version+1, found.Version)
+	})
+}
+
+func testAttributes(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	create := func(name string, attributes entity.Attributes) *entity.Item {
+		t.Helper()
+		item, err := repo.Create(ctx, &entity.Item{
+			Name: name, Category: "時計", Brand: "ROLEX", BrandCanonical: "ROLEX",
+			PurchasePrice: 1000000, PurchaseDate: "2023-01-15", Attributes: attributes,
+		})
+		require.NoError(t, err)
+		return item
+	}
+	daytona := create("デイトナ", entity.Attributes{"movement": "自動巻き", "case_size": 40.0, "box": true})
+	create("オイスター", entity.Attributes{"movement": "自動巻き", "case_size": 36.0})
+	create("チェリーニ", entity.Attributes{"movement": "手巻き", "case_size": 39.5})
+	plain := create("属性なし", nil)
+
+	t.Run("正常系: 属性を保存して取得できる", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, daytona.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.Attributes{"movement": "自動巻き", "case_size": 40.0, "box": true}, found.Attributes)
+
+		found, err = repo.FindByID(ctx, plain.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Attributes)
+	})
+
+	t.Run("正常系: 属性で絞り込む", func(t *testing.T) {
+		tests := []struct {
+			attributes map[string]string
+			expected   []string
+		}{
+			{attributes: map[string]string{"movement": "自動巻き"}, expected: []string{"デイトナ", "オイスター"}},
+			{attributes: map[string]string{"movement": "自動巻き", "case_size": "36"}, expected: []string{"オイスター"}},
+			{attributes: map[string]string{"case_size": "39.5"}, expected: []string{"チェリーニ"}},
+			{attributes: map[string]string{"box": "true"}, expected: []string{"デイトナ"}},
+			{attributes: map[string]string{"movement": "クォーツ"}, expected: []string{}},
+		}
+		for _, tt := range tests {
+			criteria := normalize(t, usecase.ItemCriteria{Attributes: tt.attributes, Sort: usecase.SortName, Order: usecase.OrderDesc})
+
+			items, err := repo.FindByCriteria(ctx, criteria)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, itemNames(items), "%v", tt.attributes)
+
+			count, err := repo.CountByCriteria(ctx, criteria)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), count)
+		}
+	})
+
+	t.Run("正常系: 更新すると絞り込みにも反映される", func(t *testing.T) {
+		daytona.Attributes = entity.Attributes{"movement": "手巻き"}
+		updated, err := repo.UpdatePartially(ctx, daytona.ID, daytona, []entity.ItemField{entity.ItemFieldAttributes})
+		require.NoError(t, err)
+		assert.Equal(t, entity.Attributes{"movement": "手巻き"}, updated.Attributes)
+
+		items, err := repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Attributes: map[string]string{"box": "true"}}))
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		updated.Attributes = nil
+		updated, err = repo.Update(ctx, updated)
+		require.NoError(t, err)
+		assert.Empty(t, updated.Attributes)
+
+		items, err = repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Attributes: map[string]string{"movement": "手巻き"}}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"チェリーニ"}, itemNames(items))
+	})
+}
+
+func testTags(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	create := func(name string, tags entity.Tags) *entity.Item {
+		t.Helper()
+		item, err := repo.Create(ctx, &entity.Item{
+			Name: name, Category: "時計", Brand: "ROLEX", BrandCanonical: "ROLEX",
+			PurchasePrice: 1000000, PurchaseDate: "2023-01-15", Tags: tags,
+		})
+		require.NoError(t, err)
+		return item
+	}
+	daytona := create("デイトナ", entity.Tags{"投資用", "結婚祝い"})
+	create("オイスター", entity.Tags{"投資用"})
+	create("チェリーニ", entity.Tags{"売却予定"})
+	plain := create("タグなし", nil)
+
+	t.Run("正常系: タグを保存して取得できる", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, daytona.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.Tags{"投資用", "結婚祝い"}, found.Tags)
+
+		found, err = repo.FindByID(ctx, plain.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Tags)
+
+		all, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		for _, item := range all {
+			if item.ID == daytona.ID {
+				assert.Equal(t, entity.Tags{"投資用", "結婚祝い"}, item.Tags)
+			}
+		}
+	})
+
+	t.Run("正常系: タグで絞り込む", func(t *testing.T) {
+		tests := []struct {
+			tags     []string
+			match    string
+			expected []string
+		}{
+			{tags: []string{"投資用"}, expected: []string{"デイトナ", "オイスター"}},
+			{tags: []string{"投資用", "結婚祝い"}, match: usecase.TagMatchAll, expected: []string{"デイトナ"}},
+			{tags: []string{"結婚祝い", "売却予定"}, match: usecase.TagMatchAll, expected: []string{}},
+			{tags: []string{"結婚祝い", "売却予定"}, match: usecase.TagMatchAny, expected: []string{"デイトナ", "チェリーニ"}},
+			{tags: []string{"未使用"}, match: usecase.TagMatchAny, expected: []string{}},
+		}
+		for _, tt := range tests {
+			criteria := normalize(t, usecase.ItemCriteria{Tags: tt.tags, TagMatch: tt.match})
+
+			items, err := repo.FindByCriteria(ctx, criteria)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, itemNames(items), "%v %s", tt.tags, tt.match)
+
+			count, err := repo.CountByCriteria(ctx, criteria)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), count)
+
+			page, _, err := repo.FindByCursor(ctx, criteria, nil)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, itemNames(page))
+		}
+	})
+
+	t.Run("正常系: タグごとの件数を多い順に返す", func(t *testing.T) {
+		counts, err := repo.GetTagCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.TagCount{
+			{Name: "投資用", Count: 2},
+			{Name: "売却予定", Count: 1},
+			{Name: "結婚祝い", Count: 1},
+		}, counts)
+	})
+
+	t.Run("正常系: 更新するとタグの絞り込みと件数に反映される", func(t *testing.T) {
+		daytona.Tags = entity.Tags{"売却予定"}
+		updated, err := repo.UpdatePartially(ctx, daytona.ID, daytona, []entity.ItemField{entity.ItemFieldTags})
+		require.NoError(t, err)
+		assert.Equal(t, entity.Tags{"売却予定"}, updated.Tags)
+		assert.Equal(t, daytona.Version+1, updated.Version)
+
+		// 全置換ではタグを変更しない
+		updated.Name = "デイトナ 116500LN"
+		updated, err = repo.Update(ctx, updated)
+		require.NoError(t, err)
+		assert.Equal(t, entity.Tags{"売却予定"}, updated.Tags)
+
+		items, err := repo.FindByCriteria(ctx, normalize(t, usecase.ItemCriteria{Tags: []string{"結婚祝い"}}))
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		// ゴミ箱のアイテムは件数に含めない
+		require.NoError(t, repo.Delete(ctx, updated.ID, updated.Version))
+		counts, err := repo.GetTagCounts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.TagCount{
+			{Name: "売却予定", Count: 1},
+			{Name: "投資用", Count: 1},
+		}, counts)
+	})
+}
+
+func testSearch(t *testing.T, repo usecase.ItemRepository) {
+	ctx := context.Background()
+
+	create := func(name, brand, notes string) *entity.Item {
+		t.Helper()
+		item, err := repo.Create(ctx, &entity.Item{
+			Name: name, Category: "時計", Brand: brand, BrandCanonical: entity.CanonicalBrand(brand),
+			PurchasePrice: 1000000, PurchaseDate: "2023-01-15", Notes: notes,
+		})
+		require.NoError(t, err)
+		return item
+	}
+	daytona := create("ロレックス デイトナ", "ROLEX", "ロレックス正規店で購入。\n箱・保証書あり")
+	birkin := create("エルメス バーキン", "HERMÈS", "")
+	create("腕時計", "不明", "ロレックスに似ている")
+	trashed := create("ロレックス サブマリーナ", "ROLEX", "")
+	require.NoError(t, repo.Delete(ctx, trashed.ID, trashed.Version))
+
+	search := func(query string, limit, offset int) ([]string, int) {
+		t.Helper()
+		criteria, err := usecase.SearchCriteria{Query: query, Limit: limit, Offset: offset}.Normalize()
+		require.NoError(t, err)
+		items, total, err := repo.Search(ctx, criteria)
+		require.NoError(t, err)
+		return itemNames(items), total
+	}
+
+	t.Run("正常系: メモを保存して取得できる", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, daytona.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ロレックス正規店で購入。\n箱・保証書あり", found.Notes)
+	})
+
+	t.Run("正常系: 名前・ブランド・メモを部分一致で検索する", func(t *testing.T) {
+		tests := []struct {
+			query    string
+			expected []string
+		}{
+			{query: "hermes", expected: []string{"エルメス バーキン"}},
+			{query: "保証書 rolex", expected: []string{"ロレックス デイトナ"}},
+			{query: "時", expected: []string{"腕時計"}},
+			{query: "ロレックス 存在しない", expected: []string{}},
+		}
+		for _, tt := range tests {
+			names, total := search(tt.query, 20, 0)
+			assert.ElementsMatch(t, tt.expected, names, tt.query)
+			assert.Equal(t, len(tt.expected), total, tt.query)
+		}
+	})
+
+	t.Run("正常系: ひらがなでカタカナに一致し、関連度の高い順に並ぶ", func(t *testing.T) {
+		names, total := search("ろれっくす", 20, 0)
+		assert.Equal(t, []string{"ロレックス デイトナ", "腕時計"}, names, "ゴミ箱のアイテムは含めない")
+		assert.Equal(t, 2, total)
+
+		names, total = search("ろれっくす", 1, 1)
+		assert.Equal(t, []string{"腕時計"}, names)
+		assert.Equal(t, 2, total)
+	})
+
+	t.Run("正常系: 更新すると検索に反映される", func(t *testing.T) {
+		birkin.Notes = "ロレックスと一緒に保管"
+		updated, err := repo.UpdatePartially(ctx, birkin.ID, birkin, []entity.ItemField{entity.ItemFieldNotes})
+		require.NoError(t, err)
+		assert.Equal(t, "ロレックスと一緒に保管", updated.Notes)
+
+		names, _ := search("ろれっくす", 20, 0)
+		assert.ElementsMatch(t, []string{"ロレックス デイトナ", "腕時計", "エルメス バーキン"}, names)
+
+		// 全置換でもメモを書き換える
+		updated.Notes = ""
+		updated, err = repo.Update(ctx, updated)
+		require.NoError(t, err)
+		assert.Empty(t, updated.Notes)
+
+		names, _ = search("ろれっくす", 20, 0)
+		assert.ElementsMatch(t, []string{"ロレックス デイトナ", "腕時計"}, names)
+	})
+}
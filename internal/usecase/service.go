@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"Aicon-assignment/internal/domain/entity"
 	domainErrors "Aicon-assignment/internal/domain/errors"
@@ -10,40 +13,196 @@ import (
 
 type ItemUsecase interface {
 	GetAllItems(ctx context.Context) ([]*entity.Item, error)
+	ListItems(ctx context.Context, criteria ItemCriteria) (*ItemList, error)
+	ListItemsByCursor(ctx context.Context, criteria ItemCriteria, cursor string) (*ItemCursorPage, error)
+	SearchItems(ctx context.Context, criteria SearchCriteria) (*SearchResult, error)
 	GetItemByID(ctx context.Context, id int64) (*entity.Item, error)
 	CreateItem(ctx context.Context, input CreateItemInput) (*entity.Item, error)
-	UpdateItemPartially(ctx context.Context, id int64, input UpdateItemInput) (*entity.Item, error)
-	DeleteItem(ctx context.Context, id int64) error
+	ReplaceItem(ctx context.Context, id int64, input ReplaceItemInput, expectedVersions []int64) (*entity.Item, error)
+	UpdateItemPartially(ctx context.Context, id int64, input UpdateItemInput, expectedVersions []int64) (*entity.Item, error)
+	DeleteItem(ctx context.Context, id int64, expectedVersions []int64) error
 	GetCategorySummary(ctx context.Context) (*CategorySummary, error)
+	ListTrashedItems(ctx context.Context, criteria ItemCriteria) (*ItemList, error)
+	RestoreItem(ctx context.Context, id int64) (*entity.Item, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	AddItemTags(ctx context.Context, id int64, tags []string, expectedVersions []int64) (*entity.Item, error)
+	RemoveItemTags(ctx context.Context, id int64, tags []string, expectedVersions []int64) (*entity.Item, error)
+	GetTagCloud(ctx context.Context) ([]entity.TagCount, error)
+	GetFacets(ctx context.Context, criteria FacetCriteria) (*FacetResult, error)
+	RebuildIndex(ctx context.Context) (int, error)
+	ImportItems(ctx context.Context, source ImportSource, options ImportOptions) (*ImportReport, error)
+	ExportItems(ctx context.Context, criteria ItemCriteria, fn func(item *entity.Item) error) error
+	BatchItems(ctx context.Context, operations []BatchOperation, options BatchOptions) (*BatchReport, error)
 }
 
 type CreateItemInput struct {
-	Name          string `json:"name"`
-	Category      string `json:"category"`
-	Brand         string `json:"brand"`
-	PurchasePrice int    `json:"purchase_price"`
-	PurchaseDate  string `json:"purchase_date"`
+	Name          string            `json:"name"`
+	Category      string            `json:"category"`
+	Brand         string            `json:"brand"`
+	PurchasePrice int               `json:"purchase_price"`
+	PurchaseDate  string            `json:"purchase_date"`
+	Attributes    entity.Attributes `json:"attributes"`
+	Tags          []string          `json:"tags"`
+	Notes         string            `json:"notes"`
 }
 
+// AnyVersion は楽観的ロックでバージョンを問わないことを表す（If-Match: *）。
+// 書き込み系の expectedVersions は If-Match の ETag の一覧で、現在のバージョンがいずれかと一致すれば書き込む
+const AnyVersion int64 = 0
+
+// ReplaceItemInput は PUT による全置換の内容
+type ReplaceItemInput struct {
+	Name          string            `json:"name"`
+	Category      string            `json:"category"`
+	Brand         string            `json:"brand"`
+	PurchasePrice int               `json:"purchase_price"`
+	PurchaseDate  string            `json:"purchase_date"`
+	Attributes    entity.Attributes `json:"attributes"`
+	Notes         string            `json:"notes"`
+}
+
+// UpdateItemInput は JSON Merge Patch 形式の部分更新内容。
+// Attributes も Merge Patch として扱い、null はすべての属性の削除、値が null のキーはその属性の削除を表す
 type UpdateItemInput struct {
-	Name          *string `json:"name,omitempty"`
-	Brand         *string `json:"brand,omitempty"`
-	PurchasePrice *int    `json:"purchase_price,omitempty"`
+	Name          PatchField[string]            `json:"name"`
+	Category      PatchField[string]            `json:"category"`
+	Brand         PatchField[string]            `json:"brand"`
+	PurchasePrice PatchField[int]               `json:"purchase_price"`
+	PurchaseDate  PatchField[string]            `json:"purchase_date"`
+	Attributes    PatchField[entity.Attributes] `json:"attributes"`
+	Notes         PatchField[string]            `json:"notes"`
+}
+
+// ItemList は条件付き一覧取得の結果
+type ItemList struct {
+	Items  []*entity.Item `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// ItemCursorPage はカーソル方式の一覧取得の結果
+type ItemCursorPage struct {
+	Items      []*entity.Item `json:"items"`
+	Limit      int            `json:"limit"`
+	HasMore    bool           `json:"has_more"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
+// SearchResult は全文検索の結果。Items は関連度の高い順に並ぶ
+type SearchResult struct {
+	Query  string       `json:"query"`
+	Items  []*SearchHit `json:"items"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// SearchHit は検索に一致したアイテムと、一致した箇所
+type SearchHit struct {
+	*entity.Item
+	Highlights []entity.Highlight `json:"highlights"`
+}
+
+// CategorySummary はカテゴリーごとのアイテム数。
+// カテゴリーが階層になっている場合、Categories の件数は子孫のカテゴリーの件数を含み、Tree に階層ごとの件数が入る
 type CategorySummary struct {
-	Categories map[string]int `json:"categories"`
-	Total      int            `json:"total"`
+	Categories map[string]int         `json:"categories"`
+	Tree       []*CategorySummaryNode `json:"tree,omitempty"`
+	Total      int                    `json:"total"`
+}
+
+// CategorySummaryNode は階層ごとのアイテム数。Count はそのカテゴリーに直接属する件数、Total は子孫を含む件数
+type CategorySummaryNode struct {
+	Name     string                 `json:"name"`
+	Count    int                    `json:"count"`
+	Total    int                    `json:"total"`
+	Children []*CategorySummaryNode `json:"children,omitempty"`
 }
 
 type itemUsecase struct {
-	itemRepo ItemRepository
+	itemRepo  ItemRepository
+	eventRepo ItemEventRepository
+	tx        Transactor
+	cursors   *CursorCodec
+	brandRepo BrandRepository
+	brandMode BrandResolutionMode
+	registry  *CategoryRegistry
+	index     ItemIndex
+}
+
+// ItemUsecaseOption は itemUsecase の任意設定
+type ItemUsecaseOption func(*itemUsecase)
+
+// WithCursorSecret はカーソルの署名鍵を設定する。未設定の場合は起動ごとにランダムな鍵を使う
+func WithCursorSecret(secret []byte) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.cursors = NewCursorCodec(secret)
+	}
+}
+
+// WithTransactor は書き込み系の操作を tx のトランザクションで実行する
+func WithTransactor(tx Transactor) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.tx = tx
+	}
+}
+
+// WithAuditTrail は変更ごとに監査記録を eventRepo へ保存する。
+// WithTransactor と併用すると、変更と記録が同一トランザクションで実行される
+func WithAuditTrail(eventRepo ItemEventRepository) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.eventRepo = eventRepo
+	}
 }
 
-func NewItemUsecase(itemRepo ItemRepository) ItemUsecase {
-	return &itemUsecase{
+// WithBrandCatalog は作成・更新時にブランドを brandRepo のカタログで解決し、アイテムをカタログのブランドに紐づける。
+// カタログに無いブランドの扱いは mode で指定する。ブランドでの絞り込みも別名を正式名として扱う
+func WithBrandCatalog(brandRepo BrandRepository, mode BrandResolutionMode) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.brandRepo = brandRepo
+		u.brandMode = mode
+	}
+}
+
+// WithCategoryRegistry はアイテムのカテゴリーと属性を registry で検証し、カテゴリーの階層を registry から参照する。
+// 子を持つカテゴリーでの絞り込みは子孫のカテゴリーを含め、カテゴリー別の集計は祖先へ積み上げる。
+// 未設定の場合、カテゴリーは entity.ValidCategories で検証する
+func WithCategoryRegistry(registry *CategoryRegistry) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.registry = registry
+	}
+}
+
+// categories はアイテムのバリデーションで使うカテゴリーを返す。registry が無い場合は nil（entity.ValidCategories）
+func (u *itemUsecase) categories() entity.CategoryLookup {
+	if u.registry == nil {
+		return nil
+	}
+	return u.registry
+}
+
+// WithItemIndex はファセット付きの絞り込みに index を使用し、アイテムの書き込みが確定するたびに index へ反映する
+func WithItemIndex(index ItemIndex) ItemUsecaseOption {
+	return func(u *itemUsecase) {
+		u.index = index
+	}
+}
+
+func NewItemUsecase(itemRepo ItemRepository, opts ...ItemUsecaseOption) ItemUsecase {
+	u := &itemUsecase{
 		itemRepo: itemRepo,
 	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	if u.cursors == nil {
+		u.cursors = NewCursorCodec(nil)
+	}
+	if u.tx == nil {
+		u.tx = noopTransactor{}
+	}
+	return u
 }
 
 func (u *itemUsecase) GetAllItems(ctx context.Context) ([]*entity.Item, error) {
@@ -55,6 +214,149 @@ func (u *itemUsecase) GetAllItems(ctx context.Context) ([]*entity.Item, error) {
 	return items, nil
 }
 
+func (u *itemUsecase) ListItems(ctx context.Context, criteria ItemCriteria) (*ItemList, error) {
+	criteria.Trashed = false
+	return u.listItems(ctx, criteria)
+}
+
+func (u *itemUsecase) ListTrashedItems(ctx context.Context, criteria ItemCriteria) (*ItemList, error) {
+	criteria.Trashed = true
+	// ゴミ箱は削除日時の新しい順をデフォルトとする
+	if criteria.Sort == "" {
+		criteria.Sort = SortDeletedAt
+	}
+	return u.listItems(ctx, criteria)
+}
+
+func (u *itemUsecase) listItems(ctx context.Context, criteria ItemCriteria) (*ItemList, error) {
+	criteria, err := criteria.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	criteria, err = u.resolveCriteriaBrand(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+	criteria = u.resolveCriteriaCategory(criteria)
+	criteria, err = u.resolveCriteriaAttributes(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := u.itemRepo.CountByCriteria(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	items, err := u.itemRepo.FindByCriteria(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+	if items == nil {
+		items = []*entity.Item{}
+	}
+
+	return &ItemList{
+		Items:  items,
+		Total:  total,
+		Limit:  criteria.Limit,
+		Offset: criteria.Offset,
+	}, nil
+}
+
+func (u *itemUsecase) ListItemsByCursor(ctx context.Context, criteria ItemCriteria, cursor string) (*ItemCursorPage, error) {
+	// カーソル方式は idx_created_at に沿った (created_at, id) 順のみ対応
+	if criteria.Sort != "" && criteria.Sort != SortCreatedAt {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.InvalidEnumError("sort", []string{SortCreatedAt}))
+	}
+	if criteria.Offset != 0 {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.ConflictError("offset", "cursor"))
+	}
+
+	// トークンの条件と比較できるよう、属性の値とタグはデコードの前にそろえる
+	criteria.Attributes = normalizeAttributeFilters(criteria.Attributes)
+	criteria.Tags = entity.NormalizeTags(criteria.Tags)
+	criteria, err := u.resolveCriteriaAttributes(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *CursorKey
+	if cursor != "" {
+		key, decoded, err := u.cursors.Decode(cursor, criteria)
+		if err != nil {
+			return nil, err
+		}
+		after = &key
+		criteria = decoded
+	}
+
+	criteria, err = criteria.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	criteria, err = u.resolveCriteriaBrand(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+	criteria = u.resolveCriteriaCategory(criteria)
+
+	items, hasMore, err := u.itemRepo.FindByCursor(ctx, criteria, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve items: %w", err)
+	}
+	if items == nil {
+		items = []*entity.Item{}
+	}
+
+	page := &ItemCursorPage{
+		Items:   items,
+		Limit:   criteria.Limit,
+		HasMore: hasMore,
+	}
+	if hasMore && len(items) > 0 {
+		last := items[len(items)-1]
+		next, err := u.cursors.Encode(CursorKey{CreatedAt: last.CreatedAt, ID: last.ID}, criteria)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
+
+// SearchItems は名前・ブランド・メモを全文検索する。一致した箇所はリポジトリによらず entity.MatchSearch で求める
+func (u *itemUsecase) SearchItems(ctx context.Context, criteria SearchCriteria) (*SearchResult, error) {
+	criteria, err := criteria.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, err := u.itemRepo.Search(ctx, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+
+	hits := make([]*SearchHit, 0, len(items))
+	for _, item := range items {
+		match, _ := entity.MatchSearch(item, criteria.Terms)
+		highlights := match.Highlights
+		if highlights == nil {
+			highlights = []entity.Highlight{}
+		}
+		hits = append(hits, &SearchHit{Item: item, Highlights: highlights})
+	}
+
+	return &SearchResult{
+		Query:  criteria.Query,
+		Items:  hits,
+		Total:  total,
+		Limit:  criteria.Limit,
+		Offset: criteria.Offset,
+	}, nil
+}
+
 func (u *itemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item, error) {
 	if id <= 0 {
 		return nil, domainErrors.ErrInvalidInput
@@ -72,45 +374,245 @@ func (u *itemUsecase) GetItemByID(ctx context.Context, id int64) (*entity.Item,
 }
 
 func (u *itemUsecase) CreateItem(ctx context.Context, input CreateItemInput) (*entity.Item, error) {
-	// バリデーションして、新しいエンティティを作成
+	item, err := u.newItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdItem *entity.Item
+	err = u.tx.WithTx(ctx, func(ctx context.Context) error {
+		createdItem, err = u.itemRepo.Create(ctx, item)
+		if err != nil {
+			return fmt.Errorf("failed to create item: %w", err)
+		}
+		return u.recordEvent(ctx, entity.ItemEventCreate, createdItem.ID, nil, createdItem)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.indexItem(createdItem)
+
+	return createdItem, nil
+}
+
+// newItem は input をバリデーションして新しいエンティティを作成し、ブランドをカタログで解決する
+func (u *itemUsecase) newItem(ctx context.Context, input CreateItemInput) (*entity.Item, error) {
 	item, err := entity.NewItem(
 		input.Name,
 		input.Category,
 		input.Brand,
 		input.PurchasePrice,
 		input.PurchaseDate,
+		input.Attributes,
+		input.Notes,
+		u.categories(),
 	)
+	if err == nil {
+		err = item.SetTags(input.Tags)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+	}
+	if err := u.resolveItemBrand(ctx, item); err != nil {
+		return nil, err
 	}
+	return item, nil
+}
 
-	createdItem, err := u.itemRepo.Create(ctx, item)
+func (u *itemUsecase) DeleteItem(ctx context.Context, id int64, expectedVersions []int64) error {
+	if id <= 0 {
+		return domainErrors.ErrInvalidInput
+	}
+
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		return u.deleteItem(ctx, id, expectedVersions)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+		return err
 	}
+	u.unindexItem(id)
 
-	return createdItem, nil
+	return nil
 }
 
-func (u *itemUsecase) DeleteItem(ctx context.Context, id int64) error {
+// deleteItem はアイテムをゴミ箱に移動して監査記録を残す。呼び出し側でトランザクションを張ること
+func (u *itemUsecase) deleteItem(ctx context.Context, id int64, expectedVersions []int64) error {
+	existing, err := u.findForWrite(ctx, id, expectedVersions)
+	if err != nil {
+		return err
+	}
+
+	if err := u.itemRepo.Delete(ctx, id, existing.Version); err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+
+	return u.recordEvent(ctx, entity.ItemEventDelete, id, existing, nil)
+}
+
+func (u *itemUsecase) ReplaceItem(ctx context.Context, id int64, input ReplaceItemInput, expectedVersions []int64) (*entity.Item, error) {
 	if id <= 0 {
-		return domainErrors.ErrInvalidInput
+		return nil, domainErrors.ErrInvalidInput
 	}
 
-	_, err := u.itemRepo.FindByID(ctx, id)
+	var updatedItem *entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := u.findForWrite(ctx, id, expectedVersions)
+		if err != nil {
+			return err
+		}
+		before := *existing
+
+		// 全フィールドを置き換えてバリデーション
+		if err := existing.Update(input.Name, input.Category, input.Brand, input.PurchasePrice, input.PurchaseDate, input.Attributes, input.Notes, u.categories()); err != nil {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+		}
+		if err := u.resolveItemBrand(ctx, existing); err != nil {
+			return err
+		}
+
+		updatedItem, err = u.itemRepo.Update(ctx, existing)
+		if err != nil {
+			return fmt.Errorf("failed to update item: %w", err)
+		}
+
+		return u.recordEvent(ctx, entity.ItemEventUpdate, id, &before, updatedItem)
+	})
 	if err != nil {
-		if domainErrors.IsNotFoundError(err) {
-			return domainErrors.ErrItemNotFound
+		return nil, err
+	}
+	u.indexItem(updatedItem)
+
+	return updatedItem, nil
+}
+
+func (u *itemUsecase) RestoreItem(ctx context.Context, id int64) (*entity.Item, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	var item *entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		item, err = u.itemRepo.Restore(ctx, id)
+		if err != nil {
+			if domainErrors.IsNotFoundError(err) {
+				return domainErrors.ErrItemNotFound
+			}
+			return fmt.Errorf("failed to restore item: %w", err)
 		}
-		return fmt.Errorf("failed to check item existence: %w", err)
+		return u.recordEvent(ctx, entity.ItemEventRestore, id, nil, item)
+	})
+	if err != nil {
+		return nil, err
 	}
+	u.indexItem(item)
 
-	err = u.itemRepo.Delete(ctx, id)
+	return item, nil
+}
+
+// PurgeTrash はゴミ箱に入ってから retention 以上経過したアイテムを物理削除する
+func (u *itemUsecase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.OutOfRangeError("retention", 0))
+	}
+
+	purged, err := u.itemRepo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
 	if err != nil {
-		return fmt.Errorf("failed to delete item: %w", err)
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
 	}
 
-	return nil
+	return purged, nil
+}
+
+// AddItemTags はアイテムにタグを追加する。既に付いているタグは無視し、追加するものが無ければアイテムをそのまま返す
+func (u *itemUsecase) AddItemTags(ctx context.Context, id int64, tags []string, expectedVersions []int64) (*entity.Item, error) {
+	added := entity.NormalizeTags(tags)
+	if len(added) == 0 {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, entity.ValidationErrors{entity.RequiredError("tags")})
+	}
+
+	return u.updateItemTags(ctx, id, expectedVersions, func(current entity.Tags) []string {
+		return append(slices.Clone(current), added...)
+	})
+}
+
+// RemoveItemTags はアイテムからタグを外す。付いていないタグは無視し、外すものが無ければアイテムをそのまま返す
+func (u *itemUsecase) RemoveItemTags(ctx context.Context, id int64, tags []string, expectedVersions []int64) (*entity.Item, error) {
+	removed := entity.NormalizeTags(tags)
+
+	return u.updateItemTags(ctx, id, expectedVersions, func(current entity.Tags) []string {
+		return slices.DeleteFunc(slices.Clone(current), func(tag string) bool {
+			return slices.Contains(removed, tag)
+		})
+	})
+}
+
+// updateItemTags はアイテムのタグを change の結果で置き換え、変更があれば保存して監査記録を残す
+func (u *itemUsecase) updateItemTags(ctx context.Context, id int64, expectedVersions []int64, change func(current entity.Tags) []string) (*entity.Item, error) {
+	if id <= 0 {
+		return nil, domainErrors.ErrInvalidInput
+	}
+
+	var updatedItem *entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := u.findForWrite(ctx, id, expectedVersions)
+		if err != nil {
+			return err
+		}
+		before := *existing
+
+		if err := existing.SetTags(change(existing.Tags)); err != nil {
+			return fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, err)
+		}
+		if slices.Equal(existing.Tags, before.Tags) {
+			updatedItem = existing
+			return nil
+		}
+
+		updatedItem, err = u.itemRepo.UpdatePartially(ctx, id, existing, []entity.ItemField{entity.ItemFieldTags})
+		if err != nil {
+			return fmt.Errorf("failed to update item tags: %w", err)
+		}
+
+		return u.recordEvent(ctx, entity.ItemEventUpdate, id, &before, updatedItem)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.indexItem(updatedItem)
+
+	return updatedItem, nil
+}
+
+// GetTagCloud はゴミ箱にないアイテムに付いているタグを、アイテム数の多い順に返す
+func (u *itemUsecase) GetTagCloud(ctx context.Context) ([]entity.TagCount, error) {
+	counts, err := u.itemRepo.GetTagCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag counts: %w", err)
+	}
+	if counts == nil {
+		counts = []entity.TagCount{}
+	}
+
+	return counts, nil
+}
+
+// 更新・削除の対象を取得し、期待するバージョンのいずれかと一致するか確認する。
+// 取得したバージョンで条件付き書き込みを行うため、AnyVersion でも取得後の競合は検出できる
+func (u *itemUsecase) findForWrite(ctx context.Context, id int64, expectedVersions []int64) (*entity.Item, error) {
+	existing, err := u.itemRepo.FindByID(ctx, id)
+	if err != nil {
+		if domainErrors.IsNotFoundError(err) {
+			return nil, domainErrors.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve item: %w", err)
+	}
+
+	if !slices.Contains(expectedVersions, AnyVersion) && !slices.Contains(expectedVersions, existing.Version) {
+		return nil, domainErrors.ErrVersionMismatch
+	}
+
+	return existing, nil
 }
 
 func (u *itemUsecase) GetCategorySummary(ctx context.Context) (*CategorySummary, error) {
@@ -125,14 +627,24 @@ func (u *itemUsecase) GetCategorySummary(ctx context.Context) (*CategorySummary,
 		total += count
 	}
 
+	if u.registry != nil {
+		return summarizeByTree(u.registry.Tree(), categoryCounts, total), nil
+	}
+
 	summary := make(map[string]int)
-	for _, category := range entity.GetValidCategories() {
+	for _, category := range entity.ValidCategories {
 		if count, exists := categoryCounts[category]; exists {
 			summary[category] = count
 		} else {
 			summary[category] = 0
 		}
 	}
+	// 無効にしたカテゴリーなど、有効なカテゴリー以外に属するアイテムも集計に含める
+	for category, count := range categoryCounts {
+		if _, exists := summary[category]; !exists {
+			summary[category] = count
+		}
+	}
 
 	return &CategorySummary{
 		Categories: summary,
@@ -140,41 +652,118 @@ func (u *itemUsecase) GetCategorySummary(ctx context.Context) (*CategorySummary,
 	}, nil
 }
 
-func (u *itemUsecase) UpdateItemPartially(ctx context.Context, id int64, input UpdateItemInput) (*entity.Item, error) {
+func (u *itemUsecase) UpdateItemPartially(ctx context.Context, id int64, input UpdateItemInput, expectedVersions []int64) (*entity.Item, error) {
 	if id <= 0 {
 		return nil, domainErrors.ErrInvalidInput
 	}
 
-	// 既存アイテム取得
-	existing, err := u.itemRepo.FindByID(ctx, id)
+	var updatedItem *entity.Item
+	err := u.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updatedItem, err = u.updateItemPartially(ctx, id, input, expectedVersions)
+		return err
+	})
 	if err != nil {
-		if domainErrors.IsNotFoundError(err) {
-			return nil, domainErrors.ErrItemNotFound
-		}
-		return nil, fmt.Errorf("failed to retrieve item: %w", err)
+		return nil, err
 	}
+	u.indexItem(updatedItem)
 
-	// 更新部分のみ上書き
-	if input.Name != nil {
-		existing.Name = *input.Name
-	}
-	if input.Brand != nil {
-		existing.Brand = *input.Brand
+	return updatedItem, nil
+}
+
+// updateItemPartially は input のフィールドだけを更新して監査記録を残す。呼び出し側でトランザクションを張ること
+func (u *itemUsecase) updateItemPartially(ctx context.Context, id int64, input UpdateItemInput, expectedVersions []int64) (*entity.Item, error) {
+	// 既存アイテム取得
+	existing, err := u.findForWrite(ctx, id, expectedVersions)
+	if err != nil {
+		return nil, err
 	}
-	if input.PurchasePrice != nil {
-		existing.PurchasePrice = *input.PurchasePrice
+	before := *existing
+
+	fields, err := applyPatch(existing, input, u.categories())
+	if err != nil {
+		return nil, err
 	}
 
-	// バリデーション
-	if err := existing.Validate(); err != nil {
-		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidInput, err.Error())
+	// 変更対象が無い場合は何もしない（RFC 7396 の空パッチ）
+	if len(fields) == 0 {
+		return existing, nil
+	}
+	if slices.Contains(fields, entity.ItemFieldBrand) {
+		if err := u.resolveItemBrand(ctx, existing); err != nil {
+			return nil, err
+		}
 	}
 
 	// 更新
-	updatedItem, err := u.itemRepo.UpdatePartially(ctx, id, existing)
+	updatedItem, err := u.itemRepo.UpdatePartially(ctx, id, existing, fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update item: %w", err)
 	}
 
+	if err := u.recordEvent(ctx, entity.ItemEventUpdate, id, &before, updatedItem); err != nil {
+		return nil, err
+	}
 	return updatedItem, nil
 }
+
+// applyPatch は指定されたフィールドのみ item に上書きし（null は値の削除として扱う）、
+// 変更したフィールドを返す。上書き後の item を categories で検証し、通らない場合は entity.ValidationErrors を包んだエラーを返す
+func applyPatch(item *entity.Item, input UpdateItemInput, categories entity.CategoryLookup) ([]entity.ItemField, error) {
+	var fields []entity.ItemField
+	var errs entity.ValidationErrors
+	applyString := func(field entity.ItemField, patch PatchField[string], dst *string) {
+		if !patch.Present {
+			return
+		}
+		*dst = patch.Value
+		fields = append(fields, field)
+	}
+	applyString(entity.ItemFieldName, input.Name, &item.Name)
+	applyString(entity.ItemFieldCategory, input.Category, &item.Category)
+	applyString(entity.ItemFieldBrand, input.Brand, &item.Brand)
+	applyString(entity.ItemFieldPurchaseDate, input.PurchaseDate, &item.PurchaseDate)
+	applyString(entity.ItemFieldNotes, input.Notes, &item.Notes)
+	if input.PurchasePrice.Present {
+		if input.PurchasePrice.Null {
+			errs = append(errs, entity.RequiredError(string(entity.ItemFieldPurchasePrice)))
+		}
+		item.PurchasePrice = input.PurchasePrice.Value
+		fields = append(fields, entity.ItemFieldPurchasePrice)
+	}
+	if input.Attributes.Present {
+		// 変更前の item と値を共有しないよう複製してからキーごとに上書きする
+		attributes := item.Attributes.Clone()
+		if input.Attributes.Null {
+			attributes = nil
+		}
+		for key, value := range input.Attributes.Value {
+			if attributes == nil {
+				attributes = entity.Attributes{}
+			}
+			if value == nil {
+				delete(attributes, key)
+				continue
+			}
+			attributes[key] = value
+		}
+		item.Attributes = attributes
+		fields = append(fields, entity.ItemFieldAttributes)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	item.Normalize()
+
+	// バリデーション
+	var validationErrs entity.ValidationErrors
+	if errors.As(item.Validate(categories), &validationErrs) {
+		errs = append(errs, validationErrs...)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", domainErrors.ErrInvalidInput, errs)
+	}
+
+	return fields, nil
+}
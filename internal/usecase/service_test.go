@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,6 +25,46 @@ func (m *MockItemRepository) FindAll(ctx context.Context) ([]*entity.Item, error
 	return args.Get(0).([]*entity.Item), args.Error(1)
 }
 
+func (m *MockItemRepository) FindByCriteria(ctx context.Context, criteria ItemCriteria) ([]*entity.Item, error) {
+	args := m.Called(ctx, criteria)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) FindByCursor(ctx context.Context, criteria ItemCriteria, after *CursorKey) ([]*entity.Item, bool, error) {
+	args := m.Called(ctx, criteria, after)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]*entity.Item), args.Bool(1), args.Error(2)
+}
+
+func (m *MockItemRepository) Search(ctx context.Context, criteria SearchCriteria) ([]*entity.Item, int, error) {
+	args := m.Called(ctx, criteria)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*entity.Item), args.Int(1), args.Error(2)
+}
+
+func (m *MockItemRepository) CountByCriteria(ctx context.Context, criteria ItemCriteria) (int, error) {
+	args := m.Called(ctx, criteria)
+	return args.Int(0), args.Error(1)
+}
+
+// ForEachByCriteria は Return で指定したアイテムを順に fn に渡し、最後に Return で指定したエラーを返す
+func (m *MockItemRepository) ForEachByCriteria(ctx context.Context, criteria ItemCriteria, fn func(item *entity.Item) error) error {
+	args := m.Called(ctx, criteria, fn)
+	for _, item := range args.Get(0).([]*entity.Item) {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockItemRepository) FindByID(ctx context.Context, id int64) (*entity.Item, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -38,19 +81,80 @@ func (m *MockItemRepository) Create(ctx context.Context, item *entity.Item) (*en
 	return args.Get(0).(*entity.Item), args.Error(1)
 }
 
-func (m *MockItemRepository) UpdatePartially(ctx context.Context, id int64, item *entity.Item) (*entity.Item, error) {
-	args := m.Called(ctx, id, item)
+func (m *MockItemRepository) CreateMany(ctx context.Context, items []*entity.Item) ([]*entity.Item, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) UpdatePartially(ctx context.Context, id int64, item *entity.Item, fields []entity.ItemField) (*entity.Item, error) {
+	args := m.Called(ctx, id, item, fields)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Item), args.Error(1)
 }
 
-func (m *MockItemRepository) Delete(ctx context.Context, id int64) error {
-	args := m.Called(ctx, id)
+func (m *MockItemRepository) Update(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) Delete(ctx context.Context, id int64, version int64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
+func (m *MockItemRepository) FindByCategory(ctx context.Context, category string) ([]*entity.Item, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) Recategorize(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) FindByBrand(ctx context.Context, brandID int64) ([]*entity.Item, error) {
+	args := m.Called(ctx, brandID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) Rebrand(ctx context.Context, item *entity.Item) (*entity.Item, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) Restore(ctx context.Context, id int64) (*entity.Item, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockItemRepository) GetSummaryByCategory(ctx context.Context) (map[string]int, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -59,6 +163,14 @@ func (m *MockItemRepository) GetSummaryByCategory(ctx context.Context) (map[stri
 	return args.Get(0).(map[string]int), args.Error(1)
 }
 
+func (m *MockItemRepository) GetTagCounts(ctx context.Context) ([]entity.TagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.TagCount), args.Error(1)
+}
+
 func TestNewItemUsecase(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	usecase := NewItemUsecase(mockRepo)
@@ -76,8 +188,8 @@ func TestItemUsecase_GetAllItems(t *testing.T) {
 		{
 			name: "正常系: 複数のアイテムを取得",
 			setupMock: func(mockRepo *MockItemRepository) {
-				item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
-				item2, _ := entity.NewItem("バッグ1", "バッグ", "HERMÈS", 500000, "2023-01-02")
+				item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				item2, _ := entity.NewItem("バッグ1", "バッグ", "HERMÈS", 500000, "2023-01-02", nil, "", nil)
 				items := []*entity.Item{item1, item2}
 				mockRepo.On("FindAll", mock.Anything).Return(items, nil)
 			},
@@ -126,6 +238,171 @@ func TestItemUsecase_GetAllItems(t *testing.T) {
 	}
 }
 
+func TestItemUsecase_ListItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		criteria       ItemCriteria
+		setupMock      func(*MockItemRepository)
+		expectedCount  int
+		expectedTotal  int
+		expectedLimit  int
+		expectedOffset int
+		expectError    bool
+		expectedErr    error
+	}{
+		{
+			name:     "正常系: デフォルト条件で取得",
+			criteria: ItemCriteria{},
+			setupMock: func(mockRepo *MockItemRepository) {
+				item1, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				want := ItemCriteria{Sort: SortCreatedAt, Order: OrderDesc, Limit: DefaultLimit}
+				mockRepo.On("CountByCriteria", mock.Anything, want).Return(1, nil)
+				mockRepo.On("FindByCriteria", mock.Anything, want).Return([]*entity.Item{item1}, nil)
+			},
+			expectedCount: 1,
+			expectedTotal: 1,
+			expectedLimit: DefaultLimit,
+		},
+		{
+			name: "正常系: 絞り込みとページング",
+			criteria: ItemCriteria{
+				Category: "時計",
+				MinPrice: ptrInt(100),
+				Sort:     SortPurchasePrice,
+				Order:    OrderAsc,
+				Limit:    1,
+				Offset:   1,
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				item1, _ := entity.NewItem("時計2", "時計", "OMEGA", 300000, "2023-01-01", nil, "", nil)
+				mockRepo.On("CountByCriteria", mock.Anything, mock.AnythingOfType("usecase.ItemCriteria")).Return(3, nil)
+				mockRepo.On("FindByCriteria", mock.Anything, mock.AnythingOfType("usecase.ItemCriteria")).Return([]*entity.Item{item1}, nil)
+			},
+			expectedCount:  1,
+			expectedTotal:  3,
+			expectedLimit:  1,
+			expectedOffset: 1,
+		},
+		{
+			name:     "正常系: 該当なしでも空配列を返す",
+			criteria: ItemCriteria{Brand: "存在しないブランド"},
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("CountByCriteria", mock.Anything, mock.AnythingOfType("usecase.ItemCriteria")).Return(0, nil)
+				mockRepo.On("FindByCriteria", mock.Anything, mock.AnythingOfType("usecase.ItemCriteria")).Return(([]*entity.Item)(nil), nil)
+			},
+			expectedCount: 0,
+			expectedTotal: 0,
+			expectedLimit: DefaultLimit,
+		},
+		{
+			name:        "異常系: 無効なソートキー",
+			criteria:    ItemCriteria{Sort: "id; DROP TABLE items"},
+			setupMock:   func(mockRepo *MockItemRepository) {},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: ゴミ箱以外で削除日時による並び替え",
+			criteria:    ItemCriteria{Sort: SortDeletedAt},
+			setupMock:   func(mockRepo *MockItemRepository) {},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: limit が上限超過",
+			criteria:    ItemCriteria{Limit: MaxLimit + 1},
+			setupMock:   func(mockRepo *MockItemRepository) {},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: 価格範囲が逆転",
+			criteria:    ItemCriteria{MinPrice: ptrInt(200), MaxPrice: ptrInt(100)},
+			setupMock:   func(mockRepo *MockItemRepository) {},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:        "異常系: 無効な購入日形式",
+			criteria:    ItemCriteria{PurchaseDateFrom: "2023/01/01"},
+			setupMock:   func(mockRepo *MockItemRepository) {},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:     "異常系: データベースエラー",
+			criteria: ItemCriteria{},
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("CountByCriteria", mock.Anything, mock.AnythingOfType("usecase.ItemCriteria")).Return(0, domainErrors.ErrDatabaseError)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			tt.setupMock(mockRepo)
+			usecase := NewItemUsecase(mockRepo)
+
+			ctx := context.Background()
+			list, err := usecase.ListItems(ctx, tt.criteria)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+				assert.Nil(t, list)
+				mockRepo.AssertExpectations(t)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, list)
+			assert.NotNil(t, list.Items)
+			assert.Len(t, list.Items, tt.expectedCount)
+			assert.Equal(t, tt.expectedTotal, list.Total)
+			assert.Equal(t, tt.expectedLimit, list.Limit)
+			assert.Equal(t, tt.expectedOffset, list.Offset)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_ListTrashedItems(t *testing.T) {
+	t.Run("正常系: ゴミ箱を削除日時の新しい順で取得", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		deletedAt := time.Now()
+		item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+		item.DeletedAt = &deletedAt
+
+		want := ItemCriteria{Sort: SortDeletedAt, Order: OrderDesc, Limit: DefaultLimit, Trashed: true}
+		mockRepo.On("CountByCriteria", mock.Anything, want).Return(1, nil)
+		mockRepo.On("FindByCriteria", mock.Anything, want).Return([]*entity.Item{item}, nil)
+
+		list, err := NewItemUsecase(mockRepo).ListTrashedItems(context.Background(), ItemCriteria{})
+
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 1)
+		assert.Equal(t, 1, list.Total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 通常の一覧はゴミ箱を含めない", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		notTrashed := mock.MatchedBy(func(c ItemCriteria) bool { return !c.Trashed })
+		mockRepo.On("CountByCriteria", mock.Anything, notTrashed).Return(0, nil)
+		mockRepo.On("FindByCriteria", mock.Anything, notTrashed).Return([]*entity.Item{}, nil)
+
+		_, err := NewItemUsecase(mockRepo).ListItems(context.Background(), ItemCriteria{Trashed: true})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestItemUsecase_GetItemByID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -138,7 +415,7 @@ func TestItemUsecase_GetItemByID(t *testing.T) {
 			name: "正常系: 存在するアイテムを取得",
 			id:   1,
 			setupMock: func(mockRepo *MockItemRepository) {
-				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
 				item.ID = 1
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
 			},
@@ -216,7 +493,7 @@ func TestItemUsecase_CreateItem(t *testing.T) {
 				PurchaseDate:  "2023-01-15",
 			},
 			setupMock: func(mockRepo *MockItemRepository) {
-				createdItem, _ := entity.NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15")
+				createdItem, _ := entity.NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
 				createdItem.ID = 1
 				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.Item")).Return(createdItem, nil)
 			},
@@ -302,6 +579,7 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 	tests := []struct {
 		name        string
 		id          int64
+		version     int64
 		setupMock   func(*MockItemRepository)
 		expectError bool
 		expectedErr error
@@ -310,13 +588,54 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 			name: "正常系: 存在するアイテムを削除",
 			id:   1,
 			setupMock: func(mockRepo *MockItemRepository) {
-				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				item.ID = 1
+				item.Version = 3
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+				mockRepo.On("Delete", mock.Anything, int64(1), int64(3)).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:    "正常系: 期待するバージョンと一致する場合に削除",
+			id:      1,
+			version: 3,
+			setupMock: func(mockRepo *MockItemRepository) {
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
 				item.ID = 1
+				item.Version = 3
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
-				mockRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+				mockRepo.On("Delete", mock.Anything, int64(1), int64(3)).Return(nil)
 			},
 			expectError: false,
 		},
+		{
+			name:    "異常系: バージョン不一致",
+			id:      1,
+			version: 2,
+			setupMock: func(mockRepo *MockItemRepository) {
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				item.ID = 1
+				item.Version = 3
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+				// Deleteは呼ばれない
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrVersionMismatch,
+		},
+		{
+			name: "異常系: 取得後に他のクライアントが更新",
+			id:   1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				item.ID = 1
+				item.Version = 3
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
+				mockRepo.On("Delete", mock.Anything, int64(1), int64(3)).Return(domainErrors.ErrVersionMismatch)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrVersionMismatch,
+		},
 		{
 			name: "異常系: 存在しないアイテム",
 			id:   999,
@@ -347,10 +666,11 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 			name: "異常系: Deleteでデータベースエラー",
 			id:   1,
 			setupMock: func(mockRepo *MockItemRepository) {
-				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01")
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
 				item.ID = 1
+				item.Version = 1
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(item, nil)
-				mockRepo.On("Delete", mock.Anything, int64(1)).Return(domainErrors.ErrDatabaseError)
+				mockRepo.On("Delete", mock.Anything, int64(1), int64(1)).Return(domainErrors.ErrDatabaseError)
 			},
 			expectError: true,
 		},
@@ -363,7 +683,7 @@ func TestItemUsecase_DeleteItem(t *testing.T) {
 			usecase := NewItemUsecase(mockRepo)
 
 			ctx := context.Background()
-			err := usecase.DeleteItem(ctx, tt.id)
+			err := usecase.DeleteItem(ctx, tt.id, []int64{tt.version})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -461,6 +781,7 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 		name        string
 		id          int64
 		input       UpdateItemInput
+		version     int64
 		setupMock   func(*MockItemRepository)
 		expectError bool
 		expectedErr error
@@ -469,12 +790,12 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 			name: "正常系: name と purchase_price を更新",
 			id:   1,
 			input: UpdateItemInput{
-				Name:          ptr("新しい時計"),
-				PurchasePrice: ptrInt(2000000),
+				Name:          Set("新しい時計"),
+				PurchasePrice: Set(2000000),
 			},
 			setupMock: func(mockRepo *MockItemRepository) {
 				// 既存データ
-				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01")
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
 				existing.ID = 1
 
 				updated := *existing
@@ -482,15 +803,116 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 				updated.PurchasePrice = 2000000
 
 				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
-				mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item")).Return(&updated, nil)
+				fields := []entity.ItemField{entity.ItemFieldName, entity.ItemFieldPurchasePrice}
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+				mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item"), fields).Return(&updated, nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "正常系: purchase_price を 0 に更新",
+			id:   1,
+			input: UpdateItemInput{
+				PurchasePrice: Set(0),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+
+				updated := *existing
+				updated.PurchasePrice = 0
+
+				fields := []entity.ItemField{entity.ItemFieldPurchasePrice}
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+				mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.MatchedBy(func(item *entity.Item) bool {
+					return item.PurchasePrice == 0
+				}), fields).Return(&updated, nil)
+			},
+			expectError: false,
+		},
+		{
+			name: "正常系: category と purchase_date を更新",
+			id:   1,
+			input: UpdateItemInput{
+				Category:     Set("その他"),
+				PurchaseDate: Set("2024-03-01"),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+
+				updated := *existing
+				updated.Category = "その他"
+				updated.PurchaseDate = "2024-03-01"
+
+				fields := []entity.ItemField{entity.ItemFieldCategory, entity.ItemFieldPurchaseDate}
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+				mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item"), fields).Return(&updated, nil)
 			},
 			expectError: false,
 		},
+		{
+			name:  "正常系: 空のパッチは更新せず現在の値を返す",
+			id:    1,
+			input: UpdateItemInput{},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+				// UpdatePartially は呼ばれない
+			},
+			expectError: false,
+		},
+		{
+			name: "異常系: 必須フィールドに null を指定",
+			id:   1,
+			input: UpdateItemInput{
+				Name:          Null[string](),
+				PurchasePrice: Null[int](),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name: "異常系: 無効なカテゴリー",
+			id:   1,
+			input: UpdateItemInput{
+				Category: Set("無効なカテゴリー"),
+			},
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name: "異常系: バージョン不一致",
+			id:   1,
+			input: UpdateItemInput{
+				Name: Set("更新名"),
+			},
+			version: 1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				existing.Version = 2
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrVersionMismatch,
+		},
 		{
 			name: "異常系: 存在しないID",
 			id:   999,
 			input: UpdateItemInput{
-				Name: ptr("更新名"),
+				Name: Set("更新名"),
 			},
 			setupMock: func(mockRepo *MockItemRepository) {
 				mockRepo.On("FindByID", mock.Anything, int64(999)).Return((*entity.Item)(nil), domainErrors.ErrItemNotFound)
@@ -502,7 +924,7 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 			name: "異常系: 無効なID（0以下）",
 			id:   0,
 			input: UpdateItemInput{
-				Name: ptr("更新名"),
+				Name: Set("更新名"),
 			},
 			setupMock: func(mockRepo *MockItemRepository) {
 				// FindByID は呼ばれない
@@ -514,14 +936,14 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 			name: "異常系: Repository更新時にDBエラー",
 			id:   2,
 			input: UpdateItemInput{
-				Name: ptr("更新失敗"),
+				Name: Set("更新失敗"),
 			},
 			setupMock: func(mockRepo *MockItemRepository) {
-				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01")
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
 				existing.ID = 2
 
 				mockRepo.On("FindByID", mock.Anything, int64(2)).Return(existing, nil)
-				mockRepo.On("UpdatePartially", mock.Anything, int64(2), mock.AnythingOfType("*entity.Item")).Return((*entity.Item)(nil), domainErrors.ErrDatabaseError)
+				mockRepo.On("UpdatePartially", mock.Anything, int64(2), mock.AnythingOfType("*entity.Item"), mock.Anything).Return((*entity.Item)(nil), domainErrors.ErrDatabaseError)
 			},
 			expectError: true,
 			expectedErr: domainErrors.ErrDatabaseError,
@@ -535,7 +957,7 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 			usecase := NewItemUsecase(mockRepo)
 
 			ctx := context.Background()
-			result, err := usecase.UpdateItemPartially(ctx, tt.id, tt.input)
+			result, err := usecase.UpdateItemPartially(ctx, tt.id, tt.input, []int64{tt.version})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -546,11 +968,17 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				if tt.input.Name != nil {
-					assert.Equal(t, *tt.input.Name, result.Name)
+				if tt.input.Name.Present {
+					assert.Equal(t, tt.input.Name.Value, result.Name)
 				}
-				if tt.input.PurchasePrice != nil {
-					assert.Equal(t, *tt.input.PurchasePrice, result.PurchasePrice)
+				if tt.input.Category.Present {
+					assert.Equal(t, tt.input.Category.Value, result.Category)
+				}
+				if tt.input.PurchasePrice.Present {
+					assert.Equal(t, tt.input.PurchasePrice.Value, result.PurchasePrice)
+				}
+				if tt.input.PurchaseDate.Present {
+					assert.Equal(t, tt.input.PurchaseDate.Value, result.PurchaseDate)
 				}
 			}
 
@@ -559,11 +987,409 @@ func TestItemUsecase_UpdateItemPartially(t *testing.T) {
 	}
 }
 
-// --- ヘルパー関数 ---
-func ptr(s string) *string {
-	return &s
+func TestItemUsecase_ReplaceItem(t *testing.T) {
+	validInput := ReplaceItemInput{
+		Name:          "エルメス ケリー",
+		Category:      "バッグ",
+		Brand:         "HERMÈS",
+		PurchasePrice: 0,
+		PurchaseDate:  "2024-01-01",
+	}
+
+	tests := []struct {
+		name        string
+		id          int64
+		input       ReplaceItemInput
+		version     int64
+		setupMock   func(*MockItemRepository)
+		expectError bool
+		expectedErr error
+	}{
+		{
+			name:    "正常系: 全フィールドを置き換え",
+			id:      1,
+			input:   validInput,
+			version: 2,
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				existing.Version = 2
+
+				updated, _ := entity.NewItem("エルメス ケリー", "バッグ", "HERMÈS", 0, "2024-01-01", nil, "", nil)
+				updated.ID = 1
+				updated.Version = 3
+
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+				mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(item *entity.Item) bool {
+					// 条件付き更新には取得時のバージョンを渡す
+					return item.ID == 1 && item.Version == 2 && item.Name == "エルメス ケリー" && item.Category == "バッグ"
+				})).Return(updated, nil)
+			},
+		},
+		{
+			name:    "異常系: バージョン不一致",
+			id:      1,
+			input:   validInput,
+			version: 1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				existing.Version = 2
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrVersionMismatch,
+		},
+		{
+			name:    "異常系: 無効な入力",
+			id:      1,
+			input:   ReplaceItemInput{Name: "名前のみ"},
+			version: AnyVersion,
+			setupMock: func(mockRepo *MockItemRepository) {
+				existing, _ := entity.NewItem("旧時計", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				existing.ID = 1
+				existing.Version = 2
+				mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name:    "異常系: 存在しないID",
+			id:      999,
+			input:   validInput,
+			version: AnyVersion,
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("FindByID", mock.Anything, int64(999)).Return((*entity.Item)(nil), domainErrors.ErrItemNotFound)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrItemNotFound,
+		},
+		{
+			name:    "異常系: 無効なID（0以下）",
+			id:      0,
+			input:   validInput,
+			version: AnyVersion,
+			setupMock: func(mockRepo *MockItemRepository) {
+				// FindByID は呼ばれない
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			tt.setupMock(mockRepo)
+			usecase := NewItemUsecase(mockRepo)
+
+			ctx := context.Background()
+			result, err := usecase.ReplaceItem(ctx, tt.id, tt.input, []int64{tt.version})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, tt.input.Name, result.Name)
+				assert.Equal(t, tt.input.PurchasePrice, result.PurchasePrice)
+				assert.Equal(t, int64(3), result.Version)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
 }
 
+func TestItemUsecase_RestoreItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int64
+		setupMock   func(*MockItemRepository)
+		expectError bool
+		expectedErr error
+	}{
+		{
+			name: "正常系: ゴミ箱から復元",
+			id:   1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				item, _ := entity.NewItem("時計1", "時計", "ROLEX", 1000000, "2023-01-01", nil, "", nil)
+				item.ID = 1
+				mockRepo.On("Restore", mock.Anything, int64(1)).Return(item, nil)
+			},
+		},
+		{
+			name: "異常系: ゴミ箱に存在しない",
+			id:   999,
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("Restore", mock.Anything, int64(999)).Return((*entity.Item)(nil), domainErrors.ErrItemNotFound)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrItemNotFound,
+		},
+		{
+			name: "異常系: 無効なID（0以下）",
+			id:   0,
+			setupMock: func(mockRepo *MockItemRepository) {
+				// Restore は呼ばれない
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name: "異常系: データベースエラー",
+			id:   1,
+			setupMock: func(mockRepo *MockItemRepository) {
+				mockRepo.On("Restore", mock.Anything, int64(1)).Return((*entity.Item)(nil), domainErrors.ErrDatabaseError)
+			},
+			expectError: true,
+			expectedErr: domainErrors.ErrDatabaseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+			tt.setupMock(mockRepo)
+			usecase := NewItemUsecase(mockRepo)
+
+			item, err := usecase.RestoreItem(context.Background(), tt.id)
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, item)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, item)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_PurgeTrash(t *testing.T) {
+	t.Run("正常系: 保存期間より前に削除されたアイテムを物理削除", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		retention := 30 * 24 * time.Hour
+		before := time.Now().Add(-retention)
+		mockRepo.On("PurgeDeletedBefore", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+			return cutoff.Sub(before).Abs() < time.Minute
+		})).Return(int64(2), nil)
+
+		purged, err := NewItemUsecase(mockRepo).PurgeTrash(context.Background(), retention)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: 負の保存期間", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+
+		_, err := NewItemUsecase(mockRepo).PurgeTrash(context.Background(), -time.Hour)
+
+		assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("PurgeDeletedBefore", mock.Anything, mock.Anything).Return(int64(0), domainErrors.ErrDatabaseError)
+
+		_, err := NewItemUsecase(mockRepo).PurgeTrash(context.Background(), time.Hour)
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdateItemInput_UnmarshalJSON(t *testing.T) {
+	var input UpdateItemInput
+	err := json.Unmarshal([]byte(`{"name":"新しい時計","purchase_price":0,"brand":null}`), &input)
+	require.NoError(t, err)
+
+	assert.Equal(t, Set("新しい時計"), input.Name)
+	assert.Equal(t, Set(0), input.PurchasePrice)
+	assert.Equal(t, Null[string](), input.Brand)
+	assert.False(t, input.Category.Present)
+	assert.False(t, input.PurchaseDate.Present)
+}
+
+func TestItemUsecase_ItemTags(t *testing.T) {
+	newItem := func(t *testing.T) *entity.Item {
+		item, err := entity.NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "", nil)
+		require.NoError(t, err)
+		item.ID = 1
+		item.Version = 3
+		item.Tags = entity.Tags{"投資用"}
+		return item
+	}
+
+	tests := []struct {
+		name        string
+		update      func(u ItemUsecase) (*entity.Item, error)
+		expected    entity.Tags
+		saved       bool
+		expectedErr error
+	}{
+		{
+			name: "正常系: タグを正規化して追加する",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.AddItemTags(context.Background(), 1, []string{"Wedding　Gifts", "投資用"}, []int64{AnyVersion})
+			},
+			expected: entity.Tags{"wedding gifts", "投資用"},
+			saved:    true,
+		},
+		{
+			name: "正常系: 付いているタグの追加は保存しない",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.AddItemTags(context.Background(), 1, []string{" 投資用 "}, []int64{3})
+			},
+			expected: entity.Tags{"投資用"},
+		},
+		{
+			name: "正常系: タグを外す",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.RemoveItemTags(context.Background(), 1, []string{"投資用"}, []int64{AnyVersion})
+			},
+			expected: nil,
+			saved:    true,
+		},
+		{
+			name: "正常系: 付いていないタグは外さない",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.RemoveItemTags(context.Background(), 1, []string{"売却予定"}, []int64{AnyVersion})
+			},
+			expected: entity.Tags{"投資用"},
+		},
+		{
+			name: "異常系: 追加するタグが無い",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.AddItemTags(context.Background(), 1, []string{" "}, []int64{AnyVersion})
+			},
+			expectedErr: domainErrors.ErrInvalidInput,
+		},
+		{
+			name: "異常系: バージョン不一致",
+			update: func(u ItemUsecase) (*entity.Item, error) {
+				return u.AddItemTags(context.Background(), 1, []string{"売却予定"}, []int64{2})
+			},
+			expectedErr: domainErrors.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := newItem(t)
+			mockRepo := new(MockItemRepository)
+			mockRepo.On("FindByID", mock.Anything, int64(1)).Return(existing, nil).Maybe()
+			if tt.saved {
+				mockRepo.On("UpdatePartially", mock.Anything, int64(1), mock.AnythingOfType("*entity.Item"), []entity.ItemField{entity.ItemFieldTags}).
+					Return(existing, nil)
+			}
+
+			updated, err := tt.update(NewItemUsecase(mockRepo))
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "UpdatePartially", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, updated.Tags)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemUsecase_GetTagCloud(t *testing.T) {
+	t.Run("正常系: タグが無い場合は空の一覧", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("GetTagCounts", mock.Anything).Return(nil, nil)
+
+		counts, err := NewItemUsecase(mockRepo).GetTagCloud(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []entity.TagCount{}, counts)
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("GetTagCounts", mock.Anything).Return(nil, domainErrors.ErrDatabaseError)
+
+		_, err := NewItemUsecase(mockRepo).GetTagCloud(context.Background())
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
+
+func TestItemUsecase_SearchItems(t *testing.T) {
+	t.Run("正常系: 検索語を求めてリポジトリに渡し、一致した箇所を付ける", func(t *testing.T) {
+		item, _ := entity.NewItem("ロレックス デイトナ", "時計", "ROLEX", 1500000, "2023-01-15", nil, "正規店で購入", nil)
+		mockRepo := new(MockItemRepository)
+		want := SearchCriteria{Query: "ﾃﾞｲﾄﾅ 購入", Terms: []string{"でいとな", "購入"}, Limit: DefaultLimit}
+		mockRepo.On("Search", mock.Anything, want).Return([]*entity.Item{item}, 1, nil)
+
+		result, err := NewItemUsecase(mockRepo).SearchItems(context.Background(), SearchCriteria{Query: "ﾃﾞｲﾄﾅ 購入"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, DefaultLimit, result.Limit)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, []entity.Highlight{
+			{Field: "name", Fragment: "ロレックス デイトナ", Matches: []entity.TextRange{{Start: 6, End: 10}}},
+			{Field: "notes", Fragment: "正規店で購入", Matches: []entity.TextRange{{Start: 4, End: 6}}},
+		}, result.Items[0].Highlights)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("正常系: 該当なしでも空配列を返す", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Search", mock.Anything, mock.AnythingOfType("usecase.SearchCriteria")).Return(nil, 0, nil)
+
+		result, err := NewItemUsecase(mockRepo).SearchItems(context.Background(), SearchCriteria{Query: "存在しない"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []*SearchHit{}, result.Items)
+	})
+
+	invalid := []struct {
+		name     string
+		criteria SearchCriteria
+	}{
+		{name: "異常系: 検索語が空", criteria: SearchCriteria{Query: "　 "}},
+		{name: "異常系: 検索語が長すぎる", criteria: SearchCriteria{Query: strings.Repeat("あ", entity.MaxSearchQueryLength+1)}},
+		{name: "異常系: limit が上限超過", criteria: SearchCriteria{Query: "時計", Limit: MaxLimit + 1}},
+		{name: "異常系: offset が負", criteria: SearchCriteria{Query: "時計", Offset: -1}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockItemRepository)
+
+			_, err := NewItemUsecase(mockRepo).SearchItems(context.Background(), tt.criteria)
+
+			assert.ErrorIs(t, err, domainErrors.ErrInvalidInput)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		mockRepo := new(MockItemRepository)
+		mockRepo.On("Search", mock.Anything, mock.AnythingOfType("usecase.SearchCriteria")).Return(nil, 0, domainErrors.ErrDatabaseError)
+
+		_, err := NewItemUsecase(mockRepo).SearchItems(context.Background(), SearchCriteria{Query: "時計"})
+
+		assert.ErrorIs(t, err, domainErrors.ErrDatabaseError)
+	})
+}
+
+// --- ヘルパー関数 ---
 func ptrInt(i int) *int {
 	return &i
 }
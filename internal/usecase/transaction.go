@@ -0,0 +1,35 @@
+package usecase
+
+import "context"
+
+// IsolationLevel はトランザクション分離レベル
+type IsolationLevel int
+
+const (
+	IsolationDefault IsolationLevel = iota // ドライバ・DB のデフォルト
+	IsolationReadUncommitted
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+// TxOptions はトランザクションの開始オプション
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+}
+
+// Transactor は複数のリポジトリ操作を1つの作業単位として実行する。
+// fn に渡される ctx でリポジトリを呼び出すと、すべて同じトランザクションで実行される。
+// fn がエラーを返すかパニックした場合はロールバックされる。
+// トランザクション内で再度 WithTx を呼ぶとセーブポイントによる入れ子のトランザクションになる
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOptions) error
+}
+
+// トランザクションを張らない Transactor（NewItemUsecase のデフォルト）
+type noopTransactor struct{}
+
+func (noopTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error, _ ...TxOptions) error {
+	return fn(ctx)
+}